@@ -0,0 +1,98 @@
+package watcher
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/DaRealFreak/watcher-go/internal/api"
+	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
+	"github.com/DaRealFreak/watcher-go/internal/raven"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// addAPICommand adds the api sub command
+func (cli *CliApplication) addAPICommand() {
+	// serves the local HTTP/JSON control API until the process receives SIGINT/SIGTERM
+	apiCmd := &cobra.Command{
+		Use:   "api",
+		Short: "serves the local HTTP/JSON control API",
+		Long: "serves the local HTTP/JSON control API to manage items/accounts/OAuth2 clients/cookies " +
+			"and to trigger runs of items or modules.\n" +
+			"The API listens on " + api.DefaultAddress + " by default (api.address setting), " +
+			"every request requires an \"Authorization: Bearer <token>\" header with the api.token setting.\n" +
+			"If no token is configured a random token is generated and saved on the first start.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, stop := cli.getShutdownContext()
+			defer stop()
+
+			raven.CheckError(cli.newAPIServer().ListenAndServe(ctx, cli.getAPIAddress()))
+		},
+	}
+
+	apiCmd.Flags().StringVar(
+		&cli.config.API.Address,
+		"address", "",
+		"listening address of the control API (default is "+api.DefaultAddress+"), overrides the api.address setting",
+	)
+
+	cli.rootCmd.AddCommand(apiCmd)
+}
+
+// getShutdownContext returns a context which is done on SIGINT/SIGTERM and requests a graceful shutdown,
+// every item in progress finishes its current download queue item. A second signal exits immediately
+func (cli *CliApplication) getShutdownContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-ctx.Done()
+		// restore the default signal behaviour so a second signal terminates immediately
+		stop()
+		slog.Info("shutdown requested, finishing the current download queue items")
+		watcherHttp.GlobalShutdown.Request()
+	}()
+
+	return ctx, stop
+}
+
+// newAPIServer returns the control API server using the database and modules of the watcher application
+func (cli *CliApplication) newAPIServer() *api.Server {
+	return api.NewServer(cli.watcher.DbCon, cli.watcher.ModuleFactory, cli.watcher, cli.getAPIToken())
+}
+
+// getAPIToken returns the configured token of the control API.
+// If no token is configured yet a random token gets generated and saved in the configuration
+func (cli *CliApplication) getAPIToken() string {
+	if token := viper.GetString("api.token"); token != "" {
+		return token
+	}
+
+	tokenBytes := make([]byte, 32)
+	_, err := rand.Read(tokenBytes)
+	raven.CheckError(err)
+
+	token := hex.EncodeToString(tokenBytes)
+	viper.Set("api.token", token)
+	raven.CheckError(viper.WriteConfig())
+
+	slog.Info(fmt.Sprintf(
+		"generated a new control API token, saved as api.token in the configuration file %s", viper.ConfigFileUsed(),
+	))
+
+	return token
+}
+
+// getAPIAddress returns the passed address flag or the configured/default address of the control API
+func (cli *CliApplication) getAPIAddress() string {
+	if cli.config.API.Address != "" {
+		return cli.config.API.Address
+	}
+
+	return api.GetAddress()
+}
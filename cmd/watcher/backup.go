@@ -1,6 +1,11 @@
 package watcher
 
 import (
+	"fmt"
+	"strings"
+
+	watcherApp "github.com/DaRealFreak/watcher-go/internal/watcher"
+	"github.com/DaRealFreak/watcher-go/pkg/archive"
 	"github.com/spf13/cobra"
 )
 
@@ -9,8 +14,12 @@ func (cli *CliApplication) addBackupCommand() {
 	backupCmd := &cobra.Command{
 		Use:   "backup [archive name]",
 		Short: "generates a backup of the current settings and database file",
-		Long: "generates a zip/tar.gz file of the current settings and database file.\n" +
-			"It is possible to narrow it down to specific elements like accounts/items/OAuth2 clients/cookies/settings.",
+		Long: "generates a zip/tar/tar.gz/tar.zst file of the current settings and database file.\n" +
+			"It is possible to narrow it down to specific elements like accounts/items/OAuth2 clients/cookies/settings.\n" +
+			"Incremental backups only contain the changes since the latest backup in the directory of the archive,\n" +
+			"the {timestamp} placeholder in the archive name creates unique archive names for scheduled backups.\n" +
+			"Encrypted backups are AES-256 encrypted zip archives using the password from the " +
+			watcherApp.BackupPasswordEnv + " environment variable.",
 		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			cli.config.Backup.Database.Accounts.Enabled = true
@@ -24,6 +33,22 @@ func (cli *CliApplication) addBackupCommand() {
 
 	cli.addBackupArchiveFlags(backupCmd)
 	backupCmd.Flags().BoolVar(&cli.config.Backup.Database.SQL, "sql", false, "generate a .sql file")
+	backupCmd.Flags().BoolVar(
+		&cli.config.Backup.Incremental, "incremental", false,
+		"only back up the changes since the latest backup in the directory of the archive",
+	)
+	backupCmd.Flags().IntVar(
+		&cli.config.Backup.KeepDaily, "keep-daily", 0,
+		"remove older backups except for the latest backup of the passed amount of days",
+	)
+	backupCmd.Flags().IntVar(
+		&cli.config.Backup.KeepWeekly, "keep-weekly", 0,
+		"remove older backups except for the latest backup of the passed amount of weeks",
+	)
+	backupCmd.Flags().BoolVar(
+		&cli.config.Backup.Verify, "verify", false,
+		"restore the backup into a temporary database and compare it with the backed up tables",
+	)
 
 	backupCmd.AddCommand(cli.getBackupAccountsCommand())
 	backupCmd.AddCommand(cli.getBackupItemsCommand())
@@ -38,6 +63,14 @@ func (cli *CliApplication) addBackupArchiveFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&cli.config.Backup.Archive.Zip, "zip", false, "use a zip(.zip) archive")
 	cmd.Flags().BoolVar(&cli.config.Backup.Archive.Tar, "tar", false, "use a tar(.tar) archive")
 	cmd.Flags().BoolVar(&cli.config.Backup.Archive.Gzip, "gzip", false, "use a gzip(.tar.gz) archive")
+	cmd.Flags().StringVar(
+		&cli.config.Backup.Archive.Format, "format", "",
+		fmt.Sprintf("archive format, overrides the other archive flags (%s)", strings.Join(archive.Formats(), ", ")),
+	)
+	cmd.Flags().BoolVar(
+		&cli.config.Backup.Archive.Encrypt, "encrypt", false,
+		fmt.Sprintf("encrypt the archive with the password from the %s environment variable", watcherApp.BackupPasswordEnv),
+	)
 }
 
 // getBackupAccountsCommand returns the command for the backup accounts sub command
@@ -0,0 +1,110 @@
+package watcher
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/DaRealFreak/watcher-go/internal/metrics"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// addDaemonCommand adds the daemon sub command
+func (cli *CliApplication) addDaemonCommand() {
+	// keeps the modules loaded and updates the tracked items based on their schedules
+	daemonCmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "keeps running and updates tracked items based on their schedules",
+		Long: "keeps all modules loaded and updates the tracked items whenever their schedule is due.\n" +
+			"The schedule is resolved from the item (watcher update item --schedule), " +
+			"the module setting (Modules.<module>.schedule) and the global setting (daemon.schedule) in this order.\n" +
+			"On SIGINT/SIGTERM every item in progress finishes its current download queue item before exiting, " +
+			"a second signal exits immediately.\n" +
+			"With --api or the api.enabled setting the local control API is served alongside the daemon.\n" +
+			"With --metrics-address or the metrics.address setting the /metrics endpoint is served alongside the daemon.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, stop := cli.getShutdownContext()
+			defer stop()
+
+			if cli.config.API.Enabled || viper.GetBool("api.enabled") {
+				go func() {
+					if err := cli.newAPIServer().ListenAndServe(ctx, cli.getAPIAddress()); err != nil {
+						slog.Error(fmt.Sprintf("control API stopped: %s", err.Error()))
+					}
+				}()
+			}
+
+			if address := cli.getMetricsAddress(); address != "" {
+				go func() {
+					if err := metrics.ListenAndServe(ctx, address); err != nil {
+						slog.Error(fmt.Sprintf("metrics endpoint stopped: %s", err.Error()))
+					}
+				}()
+			}
+
+			cli.watcher.Daemon(ctx)
+		},
+	}
+
+	daemonCmd.Flags().StringVar(
+		&cli.config.Daemon.Schedule,
+		"schedule", "",
+		"default update interval of tracked items (f.e. 30m, 6h), overrides the daemon.schedule setting",
+	)
+	daemonCmd.Flags().StringVar(
+		&cli.config.Daemon.PollInterval,
+		"poll-interval", "",
+		"interval in which due tracked items are checked (f.e. 1m), overrides the daemon.poll_interval setting",
+	)
+	daemonCmd.Flags().BoolVar(
+		&cli.config.API.Enabled,
+		"api", false,
+		"serve the local control API alongside the daemon, overrides the api.enabled setting",
+	)
+	daemonCmd.Flags().StringVar(
+		&cli.config.Daemon.MetricsAddress,
+		"metrics-address", "",
+		"listening address of the /metrics endpoint (f.e. 127.0.0.1:9095), overrides the metrics.address setting",
+	)
+	daemonCmd.Flags().StringVar(
+		&cli.config.API.Address,
+		"api-address", "",
+		"listening address of the control API, overrides the api.address setting",
+	)
+	daemonCmd.Flags().StringSliceVarP(
+		&cli.config.Run.ModuleURL,
+		"url", "u", []string{},
+		"url of module you want to run",
+	)
+	daemonCmd.Flags().StringSliceVarP(
+		&cli.config.Run.DisableURL,
+		"disable", "x", []string{},
+		"url of module you want don't want to run",
+	)
+	daemonCmd.Flags().StringSliceVar(
+		&cli.config.Run.Tags,
+		"tag", []string{},
+		"only update items with any of the passed tags",
+	)
+	daemonCmd.Flags().StringSliceVar(
+		&cli.config.Run.ExcludeTags,
+		"exclude-tag", []string{},
+		"don't update items with any of the passed tags",
+	)
+	daemonCmd.Flags().BoolVarP(
+		&cli.config.Run.RunParallel,
+		"parallel", "p", false,
+		"run modules parallel",
+	)
+
+	cli.rootCmd.AddCommand(daemonCmd)
+}
+
+// getMetricsAddress returns the listening address of the metrics endpoint, the flag overrides the metrics.address setting
+func (cli *CliApplication) getMetricsAddress() string {
+	if cli.config.Daemon.MetricsAddress != "" {
+		return cli.config.Daemon.MetricsAddress
+	}
+
+	return metrics.GetAddress()
+}
@@ -0,0 +1,79 @@
+package watcher
+
+import (
+	"os"
+
+	"github.com/DaRealFreak/watcher-go/internal/database"
+	"github.com/spf13/cobra"
+)
+
+// skipAutoMigrateAnnotation marks commands which open the database without applying pending migrations,
+// the annotation applies to all sub commands as well
+const skipAutoMigrateAnnotation = "skip-auto-migrate"
+
+// addDatabaseCommand adds the db sub command
+func (cli *CliApplication) addDatabaseCommand() {
+	dbCmd := &cobra.Command{
+		Use:   "db",
+		Short: "manages the database",
+		Long:  "manages the database schema and its migrations.",
+		// the db commands show and manage the migrations themselves, so pending migrations must not be applied on start
+		Annotations: map[string]string{skipAutoMigrateAnnotation: "true"},
+	}
+
+	cli.rootCmd.AddCommand(dbCmd)
+	dbCmd.AddCommand(cli.getDatabaseMigrateCommand())
+}
+
+// getDatabaseMigrateCommand returns the command for the db migrate sub command
+func (cli *CliApplication) getDatabaseMigrateCommand() *cobra.Command {
+	var (
+		status bool
+		target int
+	)
+
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "applies or reverts the schema migrations",
+		Long: "applies all pending schema migrations or migrates the database to the passed schema version.\n" +
+			"Every migration runs in a transaction and all tables are dumped into an SQL file next to the database " +
+			"file before the schema gets changed.\n" +
+			"Pending migrations are applied automatically on start unless the database.auto_migrate setting is disabled.\n" +
+			"After migrating to an older version with --to, pending migrations are not applied automatically anymore " +
+			"until the database gets migrated to the latest version again.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if status {
+				cli.watcher.PrintMigrationStatus()
+				return
+			}
+
+			if !cmd.Flags().Changed("to") {
+				target = database.LatestSchemaVersion()
+			}
+
+			cli.watcher.MigrateDatabase(target)
+		},
+	}
+
+	migrateCmd.Flags().BoolVar(&status, "status", false, "lists the migrations and whether they are applied")
+	migrateCmd.Flags().IntVar(&target, "to", 0, "schema version to migrate to, defaults to the latest version")
+
+	return migrateCmd
+}
+
+// skipsAutoMigrate checks if the executed command or any of its parent commands opens the database
+// without applying pending migrations
+func (cli *CliApplication) skipsAutoMigrate() bool {
+	cmd, _, err := cli.rootCmd.Find(os.Args[1:])
+	if err != nil {
+		return false
+	}
+
+	for ; cmd != nil; cmd = cmd.Parent() {
+		if _, ok := cmd.Annotations[skipAutoMigrateAnnotation]; ok {
+			return true
+		}
+	}
+
+	return false
+}
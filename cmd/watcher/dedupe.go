@@ -0,0 +1,110 @@
+package watcher
+
+import (
+	"fmt"
+
+	"github.com/DaRealFreak/watcher-go/internal/raven"
+	"github.com/DaRealFreak/watcher-go/pkg/imaging/duplication"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// addDedupeCommand adds the dedupe sub command
+func (cli *CliApplication) addDedupeCommand() {
+	dedupeCmd := &cobra.Command{
+		Use:   "dedupe",
+		Short: "manages the content index of the downloaded files",
+		Long: "manages the content index containing the SHA-256 hash and size of every downloaded file.\n" +
+			"With the download.dedupe setting (skip, hardlink, symlink) downloads of byte-identical files " +
+			"already existing in the download directory are skipped or linked to the existing file.\n" +
+			"Visually similar pictures (f.e. re-compressed copies) can be found with the similar sub command.",
+	}
+
+	cli.rootCmd.AddCommand(dedupeCmd)
+	dedupeCmd.AddCommand(cli.getDedupeScanCommand())
+	dedupeCmd.AddCommand(cli.getDedupeSimilarCommand())
+}
+
+// getDedupeScanCommand returns the command for the dedupe scan sub command
+func (cli *CliApplication) getDedupeScanCommand() *cobra.Command {
+	var (
+		directory string
+		rehash    bool
+	)
+
+	scanCmd := &cobra.Command{
+		Use:   "scan",
+		Short: "adds the existing files to the content index",
+		Long: "hashes all files in the download directory which are not indexed yet, " +
+			"removes index entries of deleted files and displays the amount of byte-identical files",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if directory == "" {
+				directory = viper.GetString("download.directory")
+			}
+
+			if directory == "" {
+				return fmt.Errorf("no directory passed and no download directory configured")
+			}
+
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			result, err := cli.watcher.ScanContentHashes(directory, rehash)
+			raven.CheckError(err)
+
+			cli.watcher.PrintDedupeScanResult(result)
+		},
+	}
+
+	scanCmd.Flags().StringVarP(&directory, "directory", "d", "", "directory to scan, defaults to the download directory")
+	scanCmd.Flags().BoolVar(&rehash, "rehash", false, "hash already indexed files again")
+
+	return scanCmd
+}
+
+// getDedupeSimilarCommand returns the command for the dedupe similar sub command
+func (cli *CliApplication) getDedupeSimilarCommand() *cobra.Command {
+	var (
+		directory string
+		threshold int
+		rehash    bool
+	)
+
+	similarCmd := &cobra.Command{
+		Use:   "similar",
+		Short: "displays visually similar pictures",
+		Long: "calculates the perceptual hashes (pHash and dHash) of all pictures in the download directory " +
+			"and displays all pairs of pictures with a hamming distance below or equal to the threshold.\n" +
+			"The hashes are saved in the database, so only new or changed pictures have to be hashed again.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if threshold < 0 || threshold > duplication.HashBits {
+				return fmt.Errorf("threshold has to be between 0 and %d", duplication.HashBits)
+			}
+
+			if directory == "" {
+				directory = viper.GetString("download.directory")
+			}
+
+			if directory == "" {
+				return fmt.Errorf("no directory passed and no download directory configured")
+			}
+
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			pairs, err := cli.watcher.FindSimilarImages(directory, threshold, rehash)
+			raven.CheckError(err)
+
+			cli.watcher.PrintSimilarImages(pairs)
+		},
+	}
+
+	similarCmd.Flags().StringVarP(&directory, "directory", "d", "", "directory to scan, defaults to the download directory")
+	similarCmd.Flags().IntVarP(
+		&threshold, "threshold", "t", 8,
+		fmt.Sprintf("maximum hamming distance of the perceptual hashes (0-%d), lower values only match closer copies", duplication.HashBits),
+	)
+	similarCmd.Flags().BoolVar(&rehash, "rehash", false, "hash already indexed pictures again")
+
+	return similarCmd
+}
@@ -0,0 +1,74 @@
+package watcher
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/DaRealFreak/watcher-go/internal/raven"
+	watcherApp "github.com/DaRealFreak/watcher-go/internal/watcher"
+	"github.com/spf13/cobra"
+)
+
+// addDeleteCommand adds the delete sub command
+func (cli *CliApplication) addDeleteCommand() {
+	// general delete option
+	deleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "deletes an item from the database",
+		Long:  "option for the user to delete items from the database",
+	}
+
+	cli.rootCmd.AddCommand(deleteCmd)
+	deleteCmd.AddCommand(cli.getDeleteItemCommand())
+}
+
+// getDeleteItemCommand returns the command for the delete item sub command
+func (cli *CliApplication) getDeleteItemCommand() *cobra.Command {
+	var (
+		selector  watcherApp.ItemSelector
+		assumeYes bool
+	)
+
+	itemCmd := &cobra.Command{
+		Use:   "item",
+		Short: "deletes tracked items selected by ID, url or partial match",
+		Long: "deletes the selected tracked items from the database.\n" +
+			"Items are selected by their ID (--id), their url (--url) or in bulk by their module (--module) " +
+			"and/or a partial match of their url (--partial). Deletions have to be confirmed unless --yes is passed.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if selector.IsEmpty() {
+				return fmt.Errorf("either id, url, module or partial is required to select the items")
+			}
+
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			trackedItems, err := cli.watcher.SelectTrackedItems(selector)
+			raven.CheckError(err)
+
+			if len(trackedItems) == 0 {
+				fmt.Println("no tracked items selected")
+				return
+			}
+
+			if !assumeYes && !watcherApp.ConfirmTrackedItems(trackedItems, "Delete", os.Stdin, os.Stdout) {
+				return
+			}
+
+			cli.watcher.DeleteTrackedItems(trackedItems)
+		},
+	}
+
+	addItemSelectorFlags(itemCmd, &selector, &assumeYes)
+
+	return itemCmd
+}
+
+// addItemSelectorFlags adds the flags to select tracked items to the passed command
+func addItemSelectorFlags(cmd *cobra.Command, selector *watcherApp.ItemSelector, assumeYes *bool) {
+	cmd.Flags().IntSliceVar(&selector.IDs, "id", []int{}, "IDs of the tracked items")
+	cmd.Flags().StringArrayVarP(&selector.URLs, "url", "u", []string{}, "urls of the tracked items")
+	cmd.Flags().StringVarP(&selector.ModuleURL, "module", "m", "", "url or key of the module to select all items from")
+	cmd.Flags().StringVarP(&selector.Partial, "partial", "p", "", "selects all items containing the passed term in their url")
+	cmd.Flags().BoolVarP(assumeYes, "yes", "y", false, "skips the confirmation prompt")
+}
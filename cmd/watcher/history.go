@@ -0,0 +1,73 @@
+package watcher
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// addHistoryCommand adds the history sub command
+func (cli *CliApplication) addHistoryCommand() {
+	var limit int
+
+	// general history option, lists the latest runs
+	historyCmd := &cobra.Command{
+		Use:   "history",
+		Short: "displays the run history",
+		Long: "displays the latest runs with the amount of parsed/failed items and downloaded files/bytes.\n" +
+			"Use the sub commands to display the results of the single items or the items which are failing",
+		Run: func(cmd *cobra.Command, args []string) {
+			cli.watcher.ListRuns(limit)
+		},
+	}
+	historyCmd.Flags().IntVarP(&limit, "limit", "l", 20, "amount of displayed runs")
+
+	cli.rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(cli.getHistoryItemsCommand())
+	historyCmd.AddCommand(cli.getHistoryFailingCommand())
+}
+
+// getHistoryItemsCommand returns the command for the history items sub command
+func (cli *CliApplication) getHistoryItemsCommand() *cobra.Command {
+	var (
+		url        string
+		itemID     int
+		onlyFailed bool
+		limit      int
+	)
+
+	itemsCmd := &cobra.Command{
+		Use:   "items",
+		Short: "displays the results of parsed items",
+		Long:  "displays the start time, duration, downloaded files/bytes and errors of the latest parsed items",
+		Run: func(cmd *cobra.Command, args []string) {
+			cli.watcher.ListItemRuns(itemID, url, onlyFailed, limit)
+		},
+	}
+	itemsCmd.Flags().StringVarP(&url, "url", "u", "", "url of module")
+	itemsCmd.Flags().IntVar(&itemID, "id", 0, "only display the results of the item with the passed ID")
+	itemsCmd.Flags().BoolVarP(&onlyFailed, "failed", "f", false, "only display failed results")
+	itemsCmd.Flags().IntVarP(&limit, "limit", "l", 50, "amount of displayed results")
+
+	return itemsCmd
+}
+
+// getHistoryFailingCommand returns the command for the history failing sub command
+func (cli *CliApplication) getHistoryFailingCommand() *cobra.Command {
+	var (
+		url         string
+		minFailures int
+	)
+
+	failingCmd := &cobra.Command{
+		Use:   "failing",
+		Short: "displays the items whose last runs failed",
+		Long: "displays all tracked items whose last run failed " +
+			"with the amount of consecutive failures and the time of the last successful run",
+		Run: func(cmd *cobra.Command, args []string) {
+			cli.watcher.ListFailingItems(url, minFailures)
+		},
+	}
+	failingCmd.Flags().StringVarP(&url, "url", "u", "", "url of module")
+	failingCmd.Flags().IntVar(&minFailures, "min-failures", 1, "minimum amount of consecutive failures")
+
+	return failingCmd
+}
@@ -80,6 +80,7 @@ func (cli *CliApplication) getListItemsCommand() *cobra.Command {
 		url              string
 		partial          string
 		includeCompleted bool
+		tags             []string
 	)
 
 	itemCmd := &cobra.Command{
@@ -87,13 +88,14 @@ func (cli *CliApplication) getListItemsCommand() *cobra.Command {
 		Short: "displays all items",
 		Long:  "displays all items currently in the database",
 		Run: func(cmd *cobra.Command, args []string) {
-			cli.watcher.ListTrackedItems(url, includeCompleted, partial)
+			cli.watcher.ListTrackedItems(url, includeCompleted, partial, tags)
 		},
 	}
 
 	itemCmd.Flags().StringVarP(&url, "url", "u", "", "url of module")
 	itemCmd.Flags().StringVarP(&partial, "partial", "p", "", "part of the item term")
 	itemCmd.Flags().BoolVar(&includeCompleted, "include-completed", true, "should completed items be included in the list")
+	itemCmd.Flags().StringSliceVarP(&tags, "tag", "t", []string{}, "only list items with any of the passed tags")
 
 	return itemCmd
 }
@@ -123,7 +125,7 @@ func (cli *CliApplication) getListAllCommand() *cobra.Command {
 			cli.watcher.ListCookies(url)
 			fmt.Println("\n ")
 			fmt.Println("Tracked Items:")
-			cli.watcher.ListTrackedItems(url, true, partial)
+			cli.watcher.ListTrackedItems(url, true, partial, nil)
 		},
 	}
 
@@ -3,6 +3,7 @@ package watcher
 
 import (
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	formatter "github.com/DaRealFreak/colored-nested-formatter/v2"
 	"github.com/DaRealFreak/watcher-go/internal/configuration"
 	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
+	"github.com/DaRealFreak/watcher-go/internal/logging"
 	"github.com/DaRealFreak/watcher-go/internal/raven"
 	"github.com/DaRealFreak/watcher-go/internal/update"
 	"github.com/DaRealFreak/watcher-go/internal/version"
@@ -25,6 +27,7 @@ type CliApplication struct {
 	watcher *watcherApp.Watcher
 	rootCmd *cobra.Command
 	config  *configuration.AppConfiguration
+	logFile io.Closer
 }
 
 // NewWatcherApplication returns the main command using cobra
@@ -49,10 +52,19 @@ func NewWatcherApplication() *CliApplication {
 	app.addAddCommand()
 	app.addImportCommand()
 	app.addListCommand()
+	app.addSearchCommand()
 	app.addRunCommand()
+	app.addDaemonCommand()
+	app.addAPICommand()
+	app.addHistoryCommand()
+	app.addTagCommand()
 	app.addUpdateCommand()
+	app.addDeleteCommand()
 	app.addBackupCommand()
 	app.addRestoreCommand()
+	app.addDedupeCommand()
+	app.addSecretsCommand()
+	app.addDatabaseCommand()
 	app.addModulesCommand()
 	app.addConfigCommand()
 	app.addCrawljobCommand()
@@ -118,6 +130,31 @@ func (cli *CliApplication) addPersistentFlags() {
 		"log-timestamp-passed-time", false,
 		"uses the passed time since the program is running in seconds instead of a formatted time",
 	)
+	cli.rootCmd.PersistentFlags().StringVar(
+		&cli.config.Cli.LogFormat,
+		"log-format", logging.FormatPretty,
+		"format of the log output (json, text, pretty)",
+	)
+	cli.rootCmd.PersistentFlags().StringVar(
+		&cli.config.Cli.LogFile,
+		"log-file", "",
+		"additionally writes the log to the passed file, pretty output is written as json",
+	)
+	cli.rootCmd.PersistentFlags().IntVar(
+		&cli.config.Cli.LogFileMaxSize,
+		"log-file-max-size", 10,
+		"size in megabytes after which the log file gets rotated (0 disables size based rotation)",
+	)
+	cli.rootCmd.PersistentFlags().IntVar(
+		&cli.config.Cli.LogFileMaxBackups,
+		"log-file-max-backups", 5,
+		"amount of rotated log files to keep",
+	)
+	cli.rootCmd.PersistentFlags().BoolVar(
+		&cli.config.Cli.LogFilePerRun,
+		"log-file-per-run", false,
+		"rotates the log file on every start, resulting in one log file per run",
+	)
 
 	_ = viper.BindPFlag("Database.Path", cli.rootCmd.PersistentFlags().Lookup("database"))
 }
@@ -135,6 +172,10 @@ func (cli *CliApplication) Execute() {
 	if cli.watcher != nil {
 		cli.watcher.DbCon.CloseConnection()
 	}
+
+	if cli.logFile != nil {
+		_ = cli.logFile.Close()
+	}
 }
 
 // initWatcher initializes everything the CLI application needs
@@ -167,27 +208,63 @@ func (cli *CliApplication) initWatcher() {
 	// initialize the lease coordinator that hands out per-module
 	// reservations against that budget
 	watcherHttp.InitGlobalLeases()
+	// initialize the shutdown gate used by the daemon to finish the current download before exiting
+	watcherHttp.InitGlobalShutdown()
+	// initialize the download counter used for the run history
+	watcherHttp.InitGlobalDownloadStats()
+	// initialize the global bandwidth limit, module limits are registered when the modules get loaded
+	bandwidth, err := watcherHttp.LoadBandwidthConfig("download.bandwidth")
+	if initErr := watcherHttp.InitGlobalBandwidth(bandwidth); err == nil {
+		err = initErr
+	}
+
+	if err != nil {
+		slog.Warn(fmt.Sprintf("ignoring invalid download.bandwidth setting: %s", err.Error()))
+	}
+
+	// initialize the dry run recorder, checked by the sessions before downloading files
+	watcherHttp.InitGlobalDryRun(cli.config.Run.DryRun)
 
 	// initialize the watcher now after we parsed the configuration
+	cli.config.SkipAutoMigrate = cli.skipsAutoMigrate()
 	cli.watcher = watcherApp.NewWatcher(cli.config)
 
+	// initialize the content index, populated by the sessions after every completed download
+	duplicatePolicy, err := watcherHttp.ParseDuplicatePolicy(viper.GetString("download.dedupe"))
+	if err != nil {
+		slog.Warn(fmt.Sprintf("ignoring download.dedupe setting: %s", err.Error()))
+	}
+
+	watcherHttp.InitGlobalContentIndex(cli.watcher.DbCon, duplicatePolicy, viper.GetString("download.directory"))
+
 	// save the configuration and check for errors
 	raven.CheckError(viper.WriteConfig())
 }
 
 // initLogger initializes the logger
 func (cli *CliApplication) initLogger() {
-	handler := formatter.NewHandler(colorable.NewColorableStdout(), &formatter.Handler{
-		DisableColors:            cli.config.Cli.DisableColors,
-		ForceColors:              cli.config.Cli.ForceColors,
-		DisableTimestamp:         cli.config.Cli.DisableTimestamp,
-		UseUppercaseLevel:        cli.config.Cli.UseUppercaseLevel,
-		UseTimePassedAsTimestamp: cli.config.Cli.UseTimePassedAsTimestamp,
-		TimestampFormat:          time.StampMilli,
-		PadAllLogEntries:         true,
-		Level:                    cli.parseSlogLevel(cli.config.LogLevel),
+	logFile, err := logging.Setup(colorable.NewColorableStdout(), logging.Options{
+		Format: cli.config.Cli.LogFormat,
+		Level:  cli.parseSlogLevel(cli.config.LogLevel),
+		Pretty: &formatter.Handler{
+			DisableColors:            cli.config.Cli.DisableColors,
+			ForceColors:              cli.config.Cli.ForceColors,
+			DisableTimestamp:         cli.config.Cli.DisableTimestamp,
+			UseUppercaseLevel:        cli.config.Cli.UseUppercaseLevel,
+			UseTimePassedAsTimestamp: cli.config.Cli.UseTimePassedAsTimestamp,
+			TimestampFormat:          time.StampMilli,
+			PadAllLogEntries:         true,
+		},
+		File:           cli.config.Cli.LogFile,
+		FileMaxSize:    int64(cli.config.Cli.LogFileMaxSize) * 1024 * 1024,
+		FileMaxBackups: cli.config.Cli.LogFileMaxBackups,
+		FilePerRun:     cli.config.Cli.LogFilePerRun,
 	})
-	slog.SetDefault(slog.New(handler))
+	cli.logFile = logFile
+
+	if err != nil {
+		slog.Warn(fmt.Sprintf("unable to set up logging completely: %s", err.Error()))
+	}
 }
 
 func (cli *CliApplication) parseSlogLevel(level string) slog.Level {
@@ -10,17 +10,24 @@ func (cli *CliApplication) addRestoreCommand() {
 		Use:   "restore [archive name]",
 		Short: "restores the current settings/database from the passed backup archive",
 		Long: "uses the passed archive file to restore the backed up setting/database file.\n" +
-			"It is possible to narrow it down to specific elements like accounts/items/OAuth2 clients/cookies/settings.",
+			"It is possible to narrow it down to specific elements like accounts/items/OAuth2 clients/cookies/settings.\n" +
+			"Incremental backups are restored by replaying all backups of their chain starting with the full backup.",
 		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			cli.config.Restore.Database.Accounts.Enabled = true
 			cli.config.Restore.Database.Items.Enabled = true
 			cli.config.Restore.Database.OAuth2Clients.Enabled = true
+			cli.config.Restore.Database.Cookies.Enabled = true
 			cli.config.Restore.Settings = true
 			cli.watcher.Restore(args[0], cli.config)
 		},
 	}
 
+	restoreCmd.Flags().BoolVar(
+		&cli.config.Restore.Verify, "verify", false,
+		"only restore the backup into a temporary database to verify it",
+	)
+
 	restoreCmd.AddCommand(cli.getRestoreAccountsCommand())
 	restoreCmd.AddCommand(cli.getRestoreItemsCommand())
 	restoreCmd.AddCommand(cli.getRestoreOAuthClientsCommand())
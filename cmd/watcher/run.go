@@ -1,6 +1,9 @@
 package watcher
 
 import (
+	"fmt"
+
+	watcherApp "github.com/DaRealFreak/watcher-go/internal/watcher"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -12,7 +15,25 @@ func (cli *CliApplication) addRunCommand() {
 		Use:   "run [items]",
 		Short: "update all tracked items or directly passed items",
 		Long: "update all tracked items if no direct items are passed.\n" +
-			"If items are directly passed only these will be updated.",
+			"If items are directly passed only these will be updated.\n" +
+			"With --order the items are updated by favorite status, least recent modification, " +
+			"least recent run or priority (watcher update item --priority), " +
+			"--limit and --time-budget stop the run after the passed amount of items or the passed duration.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cli.config.Run.DryRunFormat != "text" && cli.config.Run.DryRunFormat != "json" {
+				return fmt.Errorf("unsupported dry run format \"%s\", expected text or json", cli.config.Run.DryRunFormat)
+			}
+
+			if _, err := watcherApp.ParseOrder(cli.config.Run.Order); err != nil {
+				return err
+			}
+
+			if cli.config.Run.Limit < 0 || cli.config.Run.TimeBudget < 0 {
+				return fmt.Errorf("limit and time budget can't be negative")
+			}
+
+			return nil
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			cli.config.Run.Items = args
 			cli.watcher.Run()
@@ -34,6 +55,16 @@ func (cli *CliApplication) addRunCommand() {
 		"disable", "x", []string{},
 		"url of module you want don't want to run",
 	)
+	runCmd.Flags().StringSliceVar(
+		&cli.config.Run.Tags,
+		"tag", []string{},
+		"only update items with any of the passed tags",
+	)
+	runCmd.Flags().StringSliceVar(
+		&cli.config.Run.ExcludeTags,
+		"exclude-tag", []string{},
+		"don't update items with any of the passed tags",
+	)
 	runCmd.Flags().BoolVarP(
 		&cli.config.Run.RunParallel,
 		"parallel", "p", false,
@@ -42,7 +73,7 @@ func (cli *CliApplication) addRunCommand() {
 	runCmd.Flags().BoolVarP(
 		&cli.config.Run.Force,
 		"force", "f", false,
-		"forces to ignore previous progress and blacklisted terms",
+		"forces to ignore previous progress, blacklisted terms, failure backoffs and quarantines",
 	)
 	runCmd.Flags().BoolVarP(
 		&cli.config.Run.ResetProgress,
@@ -50,6 +81,34 @@ func (cli *CliApplication) addRunCommand() {
 		"forces to ignore previous progress",
 	)
 
+	runCmd.Flags().BoolVar(
+		&cli.config.Run.DryRun,
+		"dry-run", false,
+		"only resolve the download queues and print the files which would be downloaded, without updating the progress",
+	)
+	runCmd.Flags().StringVar(
+		&cli.config.Run.DryRunFormat,
+		"dry-run-format", "text",
+		"output format of the dry run (text, json)",
+	)
+
+	runCmd.Flags().StringVar(
+		&cli.config.Run.Order,
+		"order", "",
+		"comma separated order of the updated items (default, favorite, least-recent, last-run, priority), "+
+			"overrides the run.order setting",
+	)
+	runCmd.Flags().IntVar(
+		&cli.config.Run.Limit,
+		"limit", 0,
+		"maximum amount of items to update in this run",
+	)
+	runCmd.Flags().DurationVar(
+		&cli.config.Run.TimeBudget,
+		"time-budget", 0,
+		"wall-clock budget of the run (f.e. 2h), items in progress are finished but no new items are started afterwards",
+	)
+
 	_ = viper.BindPFlag("download.directory", runCmd.Flags().Lookup("directory"))
 
 	cli.rootCmd.AddCommand(runCmd)
@@ -0,0 +1,23 @@
+package watcher
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// addSearchCommand adds the search sub command
+func (cli *CliApplication) addSearchCommand() {
+	searchCmd := &cobra.Command{
+		Use:   "search [terms]",
+		Short: "searches the notes of tracked items",
+		Long: "displays all tracked items whose notes or generated notes contain all passed terms.\n" +
+			"The search uses the full-text index of the notes, so the terms are matched as words (or prefixes of words)\n" +
+			"case-insensitive and terms consisting of multiple words have to appear as phrase in the notes.\n" +
+			"Notes can be set with watcher update item --notes",
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cli.watcher.SearchTrackedItems(args)
+		},
+	}
+
+	cli.rootCmd.AddCommand(searchCmd)
+}
@@ -0,0 +1,47 @@
+package watcher
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// addSecretsCommand adds the secrets sub command
+func (cli *CliApplication) addSecretsCommand() {
+	secretsCmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "manages the encryption of the stored credentials",
+		Long: "manages the encryption of the account passwords, OAuth2 client secrets/tokens and cookie values.\n" +
+			"The credentials are encrypted with AES-GCM using the key from the WATCHER_SECRETS_KEY environment variable, " +
+			"the key file configured in the secrets.keyfile setting or a key derived (argon2id) from the " +
+			"WATCHER_SECRETS_PASSPHRASE environment variable.",
+	}
+
+	cli.rootCmd.AddCommand(secretsCmd)
+	secretsCmd.AddCommand(cli.getSecretsEncryptCommand())
+	secretsCmd.AddCommand(cli.getSecretsGenerateKeyCommand())
+}
+
+// getSecretsEncryptCommand returns the command for the secrets encrypt sub command
+func (cli *CliApplication) getSecretsEncryptCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "encrypt",
+		Short: "encrypts all plaintext credentials in the database",
+		Long: "encrypts all credentials in the database which are not encrypted yet with the configured secrets key. " +
+			"Credentials added or updated afterward are encrypted automatically.",
+		Run: func(cmd *cobra.Command, args []string) {
+			cli.watcher.EncryptSecrets()
+		},
+	}
+}
+
+// getSecretsGenerateKeyCommand returns the command for the secrets generate-key sub command
+func (cli *CliApplication) getSecretsGenerateKeyCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "generate-key [key file]",
+		Short: "generates a new random secrets key file",
+		Long:  "generates a new random 256 bit secrets key and writes it into the passed file, existing files are not overwritten.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cli.watcher.GenerateSecretsKey(args[0])
+		},
+	}
+}
@@ -0,0 +1,68 @@
+package watcher
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// addTagCommand adds the tag sub command
+func (cli *CliApplication) addTagCommand() {
+	// general tag option, lists all tags
+	tagCmd := &cobra.Command{
+		Use:   "tag",
+		Short: "manages the tags of tracked items",
+		Long: "manages the tags of tracked items.\n" +
+			"Tagged items can be updated separately with watcher run --tag [tag] or skipped with --exclude-tag [tag]",
+	}
+
+	cli.rootCmd.AddCommand(tagCmd)
+	tagCmd.AddCommand(cli.getTagAddCommand())
+	tagCmd.AddCommand(cli.getTagRemoveCommand())
+	tagCmd.AddCommand(cli.getTagListCommand())
+}
+
+// getTagAddCommand returns the command for the tag add sub command
+func (cli *CliApplication) getTagAddCommand() *cobra.Command {
+	addCmd := &cobra.Command{
+		Use:   "add [tag] [urls]",
+		Short: "adds the tag to the passed items",
+		Long:  "assigns the tag to the passed items, tags are case-insensitive",
+		Args:  cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cli.watcher.AddTag(args[0], args[1:])
+		},
+	}
+
+	return addCmd
+}
+
+// getTagRemoveCommand returns the command for the tag remove sub command
+func (cli *CliApplication) getTagRemoveCommand() *cobra.Command {
+	removeCmd := &cobra.Command{
+		Use:   "remove [tag] [urls]",
+		Short: "removes the tag from the passed items",
+		Long:  "removes the tag from the passed items",
+		Args:  cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cli.watcher.RemoveTag(args[0], args[1:])
+		},
+	}
+
+	return removeCmd
+}
+
+// getTagListCommand returns the command for the tag list sub command
+func (cli *CliApplication) getTagListCommand() *cobra.Command {
+	var url string
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "displays all tags",
+		Long:  "displays all tags with the amount of tagged items or the tags of the passed item",
+		Run: func(cmd *cobra.Command, args []string) {
+			cli.watcher.ListTags(url)
+		},
+	}
+	listCmd.Flags().StringVarP(&url, "url", "u", "", "url of the item to display the tags of")
+
+	return listCmd
+}
@@ -2,10 +2,15 @@ package watcher
 
 import (
 	"fmt"
+	"log/slog"
+	"os"
+	"time"
 
 	"github.com/DaRealFreak/watcher-go/internal/raven"
 	"github.com/DaRealFreak/watcher-go/internal/update"
+	watcherApp "github.com/DaRealFreak/watcher-go/internal/watcher"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // addUpdateCommand adds the update sub command
@@ -221,44 +226,135 @@ func (cli *CliApplication) getDisableOAuthClientCommand() *cobra.Command {
 // getUpdateItemCommand returns the command for the update item sub command
 func (cli *CliApplication) getUpdateItemCommand() *cobra.Command {
 	var (
-		url       string
+		selector  watcherApp.ItemSelector
+		assumeYes bool
 		current   string
+		newURL    string
 		subFolder string
+		favorite  bool
+		complete  bool
+		schedule  string
+		priority  int
+		notes     string
+		// set if the deprecated --current flag got passed
+		deprecatedCurrent bool
 	)
 
 	itemCmd := &cobra.Command{
 		Use:   "item",
-		Short: "updates the saved current item",
-		Long:  "updates the saved current item of an item in the database, creates an entry if it doesn't exist yet",
+		Short: "updates tracked items selected by ID, url or partial match",
+		Long: "updates the selected tracked items in the database.\n" +
+			"Items are selected by their ID (--id), their url (--url) or in bulk by their module (--module) " +
+			"and/or a partial match of their url (--partial). Bulk updates have to be confirmed unless --yes is passed.\n" +
+			"If only urls are passed entries are created for items which aren't tracked yet.",
 		PreRunE: func(cmd *cobra.Command, args []string) error {
-			if !cmd.Flags().Changed("current") && !cmd.Flags().Changed("subfolder") {
-				return fmt.Errorf("either current or subfolder is required as argument")
+			if deprecatedCurrent {
+				slog.Warn("flag --current is deprecated, use --set-current-item instead")
+			}
+
+			changed := false
+			for _, flag := range []string{
+				"set-current-item", "new-url", "subfolder", "favorite", "complete", "schedule", "priority", "notes",
+			} {
+				changed = changed || cmd.Flags().Changed(flag)
+			}
+
+			if !changed {
+				return fmt.Errorf(
+					"either set-current-item, new-url, subfolder, favorite, complete, schedule, priority or notes " +
+						"is required as argument",
+				)
+			}
+
+			if selector.IsEmpty() {
+				return fmt.Errorf("either id, url, module or partial is required to select the items")
+			}
+
+			if schedule != "" {
+				if duration, err := time.ParseDuration(schedule); err != nil || duration <= 0 {
+					return fmt.Errorf("schedule has to be a positive duration like 30m or 12h")
+				}
 			}
 
 			return nil
 		},
 		Run: func(cmd *cobra.Command, args []string) {
-			module := cli.watcher.ModuleFactory.GetModuleFromURI(url)
-			normalizedUri, err := module.ModuleInterface.AddItem(url)
+			selector.CreateMissing = len(selector.IDs) == 0 && !selector.IsBulk()
+
+			trackedItems, err := cli.watcher.SelectTrackedItems(selector)
 			raven.CheckError(err)
-			trackedItems := cli.watcher.DbCon.GetAllOrCreateTrackedItemIgnoreSubFolder(normalizedUri, module)
-			for _, trackedItem := range trackedItems {
-				if cmd.Flags().Changed("current") {
-					cli.watcher.DbCon.UpdateTrackedItem(trackedItem, current)
-				}
 
-				if cmd.Flags().Changed("subfolder") {
-					cli.watcher.DbCon.ChangeTrackedItemSubFolder(trackedItem, subFolder)
-				}
+			if len(trackedItems) == 0 {
+				fmt.Println("no tracked items selected")
+				return
 			}
+
+			// urls are unique per item, so the url can't be changed for multiple items at once
+			if cmd.Flags().Changed("new-url") && len(trackedItems) > 1 {
+				raven.CheckError(fmt.Errorf(
+					"new-url can only be used for a single item, %d items are selected", len(trackedItems),
+				))
+			}
+
+			if selector.IsBulk() && !assumeYes && !watcherApp.ConfirmTrackedItems(trackedItems, "Update", os.Stdin, os.Stdout) {
+				return
+			}
+
+			var changes watcherApp.ItemChanges
+			if cmd.Flags().Changed("set-current-item") {
+				changes.CurrentItem = &current
+			}
+
+			if cmd.Flags().Changed("new-url") {
+				changes.URI = &newURL
+			}
+
+			if cmd.Flags().Changed("subfolder") {
+				changes.SubFolder = &subFolder
+			}
+
+			if cmd.Flags().Changed("favorite") {
+				changes.Favorite = &favorite
+			}
+
+			if cmd.Flags().Changed("complete") {
+				changes.Complete = &complete
+			}
+
+			if cmd.Flags().Changed("schedule") {
+				changes.Schedule = &schedule
+			}
+
+			if cmd.Flags().Changed("priority") {
+				changes.Priority = &priority
+			}
+
+			if cmd.Flags().Changed("notes") {
+				changes.Notes = &notes
+			}
+
+			raven.CheckError(cli.watcher.UpdateTrackedItems(trackedItems, changes))
 		},
 	}
 
-	itemCmd.Flags().StringVarP(&url, "url", "u", "", "url of tracked item you want to update (required)")
-	itemCmd.Flags().StringVarP(&current, "current", "c", "", "current item in case you don't want to download older items")
+	addItemSelectorFlags(itemCmd, &selector, &assumeYes)
+	itemCmd.Flags().StringVarP(&current, "set-current-item", "c", "", "sets the current item to rewind or advance the progress")
+	// --current is the deprecated name of --set-current-item, kept for existing scripts
+	itemCmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "current" {
+			deprecatedCurrent = true
+			name = "set-current-item"
+		}
+
+		return pflag.NormalizedName(name)
+	})
+	itemCmd.Flags().StringVar(&newURL, "new-url", "", "changes the url of the selected items")
 	itemCmd.Flags().StringVarP(&subFolder, "subfolder", "f", "", "subfolder path for additional grouping")
-
-	_ = itemCmd.MarkFlagRequired("url")
+	itemCmd.Flags().BoolVar(&favorite, "favorite", false, "favorite status of the items")
+	itemCmd.Flags().BoolVar(&complete, "complete", false, "complete status of the items, completed items are not checked anymore")
+	itemCmd.Flags().StringVar(&schedule, "schedule", "", "update interval in daemon mode (f.e. 12h), empty to use the module/global schedule")
+	itemCmd.Flags().IntVar(&priority, "priority", 0, "priority of the item, higher priorities are updated first with run --order priority")
+	itemCmd.Flags().StringVar(&notes, "notes", "", "free-form notes of the item, empty to remove the notes")
 
 	// enable/disable status
 	itemCmd.AddCommand(cli.getEnableItemCommand())
@@ -268,6 +364,9 @@ func (cli *CliApplication) getUpdateItemCommand() *cobra.Command {
 	itemCmd.AddCommand(cli.getFavoriteItemCommand())
 	itemCmd.AddCommand(cli.getUnfavoriteItemCommand())
 
+	// failure/quarantine status
+	itemCmd.AddCommand(cli.getResetItemCommand())
+
 	return itemCmd
 }
 
@@ -349,6 +448,36 @@ func (cli *CliApplication) getDisableItemCommand() *cobra.Command {
 	return enableCmd
 }
 
+// getResetItemCommand returns the command for the update item reset sub command
+func (cli *CliApplication) getResetItemCommand() *cobra.Command {
+	resetCmd := &cobra.Command{
+		Use:   "reset [urls]",
+		Short: "resets the failures and quarantine status of passed items",
+		Long: "resets the consecutive failures and lifts the quarantine of the passed items, " +
+			"causing them to get checked again on the next run",
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			for _, url := range args {
+				module := cli.watcher.ModuleFactory.GetModuleFromURI(url)
+				normalizedUri, err := module.ModuleInterface.AddItem(url)
+				raven.CheckError(err)
+				// only reset already tracked items, resetting an unknown url must not start tracking it
+				trackedItems := cli.watcher.DbCon.GetTrackedItemsIgnoreSubFolder(normalizedUri, module)
+				if len(trackedItems) == 0 {
+					slog.Warn(fmt.Sprintf("no tracked item found for uri %s, skipping", url))
+					continue
+				}
+
+				for _, trackedItem := range trackedItems {
+					cli.watcher.DbCon.ChangeTrackedItemQuarantineStatus(trackedItem, false)
+				}
+			}
+		},
+	}
+
+	return resetCmd
+}
+
 // getFavoriteItemCommand returns the command for the update item favorite sub command
 func (cli *CliApplication) getFavoriteItemCommand() *cobra.Command {
 	favoriteCmd := &cobra.Command{
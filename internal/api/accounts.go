@@ -0,0 +1,124 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/DaRealFreak/watcher-go/internal/models"
+)
+
+// Account is the JSON representation of an account, the password is never exposed
+type Account struct {
+	ID       int    `json:"id"`
+	Module   string `json:"module"`
+	User     string `json:"user"`
+	Disabled bool   `json:"disabled"`
+}
+
+// CreateAccountRequest is the request body to add a new account
+type CreateAccountRequest struct {
+	Module   string `json:"module"`
+	User     string `json:"user"`
+	Password string `json:"password"`
+}
+
+// UpdateAccountRequest is the request body to update an account, only passed fields are updated
+type UpdateAccountRequest struct {
+	Module   string  `json:"module"`
+	User     string  `json:"user"`
+	Password *string `json:"password"`
+	Disabled *bool   `json:"disabled"`
+}
+
+func (s *Server) registerAccountRoutes() {
+	s.mux.HandleFunc("GET /api/v1/accounts", s.listAccounts)
+	s.mux.HandleFunc("POST /api/v1/accounts", s.createAccount)
+	s.mux.HandleFunc("PATCH /api/v1/accounts", s.updateAccount)
+	s.mux.HandleFunc("DELETE /api/v1/accounts", s.deleteAccount)
+}
+
+// newAccount converts the passed account into its JSON representation
+func newAccount(account *models.Account) Account {
+	return Account{
+		ID:       account.ID,
+		Module:   account.Module,
+		User:     account.Username,
+		Disabled: account.Disabled,
+	}
+}
+
+// listAccounts lists all enabled accounts, optionally limited to the "module" query parameter
+func (s *Server) listAccounts(w http.ResponseWriter, r *http.Request) {
+	module, err := s.getOptionalModule(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	accounts := make([]Account, 0)
+	for _, account := range s.db.GetAllAccounts(module) {
+		accounts = append(accounts, newAccount(account))
+	}
+
+	writeJSON(w, http.StatusOK, accounts)
+}
+
+// createAccount adds the passed account if the user doesn't exist for the module already
+func (s *Server) createAccount(w http.ResponseWriter, r *http.Request) {
+	var request CreateAccountRequest
+	if err := readJSON(r, &request); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if request.User == "" || request.Password == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("user and password are required"))
+		return
+	}
+
+	module, err := s.getModule(request.Module)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	account := s.db.GetFirstOrCreateAccount(request.User, request.Password, module)
+	writeJSON(w, http.StatusCreated, newAccount(account))
+}
+
+// updateAccount updates the password and/or the disabled status of the passed user/module
+func (s *Server) updateAccount(w http.ResponseWriter, r *http.Request) {
+	var request UpdateAccountRequest
+	if err := readJSON(r, &request); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	module, err := s.getModule(request.Module)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if request.Password != nil {
+		s.db.UpdateAccount(request.User, *request.Password, module)
+	}
+
+	if request.Disabled != nil {
+		s.db.UpdateAccountDisabledStatus(request.User, *request.Disabled, module)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deleteAccount deletes the account of the "module" and "user" query parameters
+func (s *Server) deleteAccount(w http.ResponseWriter, r *http.Request) {
+	module, err := s.getModule(r.URL.Query().Get("module"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.db.DeleteAccount(r.URL.Query().Get("user"), module)
+	w.WriteHeader(http.StatusNoContent)
+}
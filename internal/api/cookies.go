@@ -0,0 +1,151 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/models"
+)
+
+// Cookie is the JSON representation of a cookie, the value is never exposed
+type Cookie struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Expiration *time.Time `json:"expiration,omitempty"`
+	Module     string     `json:"module"`
+	Disabled   bool       `json:"disabled"`
+}
+
+// CreateCookieRequest is the request body to add a new cookie
+type CreateCookieRequest struct {
+	Module     string `json:"module"`
+	Name       string `json:"name"`
+	Value      string `json:"value"`
+	Expiration string `json:"expiration"`
+}
+
+// UpdateCookieRequest is the request body to update a cookie, only passed fields are updated
+type UpdateCookieRequest struct {
+	Module     string  `json:"module"`
+	Name       string  `json:"name"`
+	Value      *string `json:"value"`
+	Expiration *string `json:"expiration"`
+	Disabled   *bool   `json:"disabled"`
+}
+
+func (s *Server) registerCookieRoutes() {
+	s.mux.HandleFunc("GET /api/v1/cookies", s.listCookies)
+	s.mux.HandleFunc("POST /api/v1/cookies", s.createCookie)
+	s.mux.HandleFunc("PATCH /api/v1/cookies", s.updateCookie)
+	s.mux.HandleFunc("DELETE /api/v1/cookies", s.deleteCookie)
+}
+
+// newCookie converts the passed cookie into its JSON representation
+func newCookie(cookie *models.Cookie) Cookie {
+	jsonCookie := Cookie{
+		ID:       cookie.ID,
+		Name:     cookie.Name,
+		Module:   cookie.Module,
+		Disabled: cookie.Disabled,
+	}
+
+	if cookie.Expiration.Valid {
+		jsonCookie.Expiration = &cookie.Expiration.Time
+	}
+
+	return jsonCookie
+}
+
+// listCookies lists all enabled and not expired cookies, optionally limited to the "module" query parameter
+func (s *Server) listCookies(w http.ResponseWriter, r *http.Request) {
+	module, err := s.getOptionalModule(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	cookies := make([]Cookie, 0)
+	for _, cookie := range s.db.GetAllCookies(module) {
+		cookies = append(cookies, newCookie(cookie))
+	}
+
+	writeJSON(w, http.StatusOK, cookies)
+}
+
+// createCookie adds the passed cookie if it doesn't exist for the module already
+func (s *Server) createCookie(w http.ResponseWriter, r *http.Request) {
+	var request CreateCookieRequest
+	if err := readJSON(r, &request); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if request.Name == "" || request.Value == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("name and value are required"))
+		return
+	}
+
+	module, err := s.getModule(request.Module)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	cookie := s.db.GetFirstOrCreateCookie(request.Name, request.Value, request.Expiration, module)
+	writeJSON(w, http.StatusCreated, newCookie(cookie))
+}
+
+// updateCookie updates the value/expiration and/or the disabled status of the cookie.
+// A value or expiration which is not passed is kept from the current cookie
+func (s *Server) updateCookie(w http.ResponseWriter, r *http.Request) {
+	var request UpdateCookieRequest
+	if err := readJSON(r, &request); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	module, err := s.getModule(request.Module)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if request.Value != nil || request.Expiration != nil {
+		var value, expiration string
+		if cookie := s.db.GetCookie(request.Name, module); cookie != nil {
+			value = cookie.Value
+			if cookie.Expiration.Valid {
+				expiration = cookie.Expiration.Time.Format(time.RFC3339)
+			}
+		}
+
+		if request.Value != nil {
+			value = *request.Value
+		}
+
+		if request.Expiration != nil {
+			expiration = *request.Expiration
+		}
+
+		s.db.UpdateCookie(request.Name, value, expiration, module)
+	}
+
+	if request.Disabled != nil {
+		s.db.UpdateCookieDisabledStatus(request.Name, *request.Disabled, module)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deleteCookie deletes the cookie of the "module" and "name" query parameters
+func (s *Server) deleteCookie(w http.ResponseWriter, r *http.Request) {
+	module, err := s.getModule(r.URL.Query().Get("module"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.db.DeleteCookie(r.URL.Query().Get("name"), module)
+	w.WriteHeader(http.StatusNoContent)
+}
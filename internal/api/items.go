@@ -0,0 +1,232 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/models"
+)
+
+// TrackedItem is the JSON representation of a tracked item
+type TrackedItem struct {
+	ID             int        `json:"id"`
+	URI            string     `json:"uri"`
+	SubFolder      string     `json:"sub_folder"`
+	CurrentItem    string     `json:"current_item"`
+	Module         string     `json:"module"`
+	LastModified   *time.Time `json:"last_modified,omitempty"`
+	Favorite       bool       `json:"favorite"`
+	Complete       bool       `json:"complete"`
+	Notes          string     `json:"notes"`
+	GeneratedNotes string     `json:"generated_notes"`
+	Schedule       string     `json:"schedule"`
+	Priority       int        `json:"priority"`
+	Failures       int        `json:"failures"`
+	Quarantined    bool       `json:"quarantined"`
+}
+
+// CreateTrackedItemRequest is the request body to add a new tracked item
+type CreateTrackedItemRequest struct {
+	URI         string `json:"uri"`
+	SubFolder   string `json:"sub_folder"`
+	CurrentItem string `json:"current_item"`
+}
+
+// UpdateTrackedItemRequest is the request body to update a tracked item, only passed fields are updated
+type UpdateTrackedItemRequest struct {
+	URI         *string `json:"uri"`
+	SubFolder   *string `json:"sub_folder"`
+	CurrentItem *string `json:"current_item"`
+	Favorite    *bool   `json:"favorite"`
+	Complete    *bool   `json:"complete"`
+	Notes       *string `json:"notes"`
+	Schedule    *string `json:"schedule"`
+	Priority    *int    `json:"priority"`
+	// Quarantined set to false also resets the failures of the item
+	Quarantined *bool `json:"quarantined"`
+}
+
+func (s *Server) registerItemRoutes() {
+	s.mux.HandleFunc("GET /api/v1/items", s.listItems)
+	s.mux.HandleFunc("POST /api/v1/items", s.createItem)
+	s.mux.HandleFunc("GET /api/v1/items/{id}", s.getItem)
+	s.mux.HandleFunc("PATCH /api/v1/items/{id}", s.updateItem)
+	s.mux.HandleFunc("DELETE /api/v1/items/{id}", s.deleteItem)
+}
+
+// newTrackedItem converts the passed tracked item into its JSON representation
+func newTrackedItem(item *models.TrackedItem) TrackedItem {
+	trackedItem := TrackedItem{
+		ID:             item.ID,
+		URI:            item.URI,
+		SubFolder:      item.SubFolder,
+		CurrentItem:    item.CurrentItem,
+		Module:         item.Module,
+		Favorite:       item.Favorite,
+		Complete:       item.Complete,
+		Notes:          item.Notes,
+		GeneratedNotes: item.GeneratedNotes,
+		Schedule:       item.Schedule,
+		Priority:       item.Priority,
+		Failures:       item.Failures,
+		Quarantined:    item.Quarantined,
+	}
+
+	if item.LastModified.Valid {
+		trackedItem.LastModified = &item.LastModified.Time
+	}
+
+	return trackedItem
+}
+
+// listItems lists all tracked items, optionally limited to the "module" and "include_completed" query parameters
+func (s *Server) listItems(w http.ResponseWriter, r *http.Request) {
+	module, err := s.getOptionalModule(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	includeCompleted := true
+	if param := r.URL.Query().Get("include_completed"); param != "" {
+		if includeCompleted, err = strconv.ParseBool(param); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid include_completed value: %w", err))
+			return
+		}
+	}
+
+	items := make([]TrackedItem, 0)
+	for _, item := range s.db.GetTrackedItems(module, includeCompleted) {
+		items = append(items, newTrackedItem(item))
+	}
+
+	writeJSON(w, http.StatusOK, items)
+}
+
+// createItem adds the passed URI to the tracked items if it isn't tracked already
+func (s *Server) createItem(w http.ResponseWriter, r *http.Request) {
+	var request CreateTrackedItemRequest
+	if err := readJSON(r, &request); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	module, err := s.getModule(request.URI)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	normalizedURI, err := module.ModuleInterface.AddItem(request.URI)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	item := s.db.GetFirstOrCreateTrackedItem(normalizedURI, request.SubFolder, module)
+	if request.CurrentItem != "" {
+		s.db.UpdateTrackedItem(item, request.CurrentItem)
+	}
+
+	writeJSON(w, http.StatusCreated, newTrackedItem(s.db.GetTrackedItem(item.ID)))
+}
+
+// getItem returns the tracked item of the passed ID
+func (s *Server) getItem(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.getTrackedItemFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newTrackedItem(item))
+}
+
+// updateItem updates the passed fields of the tracked item of the passed ID
+func (s *Server) updateItem(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.getTrackedItemFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var request UpdateTrackedItemRequest
+	if err := readJSON(r, &request); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if request.Schedule != nil && *request.Schedule != "" {
+		if duration, err := time.ParseDuration(*request.Schedule); err != nil || duration <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("schedule has to be a positive duration like 30m or 12h"))
+			return
+		}
+	}
+
+	if request.URI != nil {
+		s.db.ChangeTrackedItemUri(item, *request.URI)
+	}
+
+	if request.SubFolder != nil {
+		s.db.ChangeTrackedItemSubFolder(item, *request.SubFolder)
+	}
+
+	if request.CurrentItem != nil {
+		s.db.UpdateTrackedItem(item, *request.CurrentItem)
+	}
+
+	if request.Favorite != nil {
+		s.db.ChangeTrackedItemFavoriteStatus(item, *request.Favorite)
+	}
+
+	// updating the current item resets the complete status, so the complete status is applied afterwards
+	if request.Complete != nil {
+		s.db.ChangeTrackedItemCompleteStatus(item, *request.Complete)
+	}
+
+	if request.Notes != nil {
+		s.db.UpdateTrackedItemNotes(item, *request.Notes)
+	}
+
+	if request.Schedule != nil {
+		s.db.ChangeTrackedItemSchedule(item, *request.Schedule)
+	}
+
+	if request.Priority != nil {
+		s.db.ChangeTrackedItemPriority(item, *request.Priority)
+	}
+
+	if request.Quarantined != nil {
+		s.db.ChangeTrackedItemQuarantineStatus(item, *request.Quarantined)
+	}
+
+	writeJSON(w, http.StatusOK, newTrackedItem(s.db.GetTrackedItem(item.ID)))
+}
+
+// deleteItem deletes the tracked item of the passed ID
+func (s *Server) deleteItem(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.getTrackedItemFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	s.db.DeleteTrackedItem(item)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getTrackedItemFromPath returns the tracked item of the "id" path value or writes the error response
+func (s *Server) getTrackedItemFromPath(w http.ResponseWriter, r *http.Request) (*models.TrackedItem, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid item ID \"%s\"", r.PathValue("id")))
+		return nil, false
+	}
+
+	item := s.db.GetTrackedItem(id)
+	if item == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no tracked item found with ID %d", id))
+		return nil, false
+	}
+
+	return item, true
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/DaRealFreak/watcher-go/internal/models"
+)
+
+// OAuthClient is the JSON representation of an OAuth2 client, secrets and tokens are never exposed
+type OAuthClient struct {
+	ID       int    `json:"id"`
+	ClientID string `json:"client_id"`
+	Module   string `json:"module"`
+	Disabled bool   `json:"disabled"`
+}
+
+// CreateOAuthClientRequest is the request body to add a new OAuth2 client
+type CreateOAuthClientRequest struct {
+	Module       string `json:"module"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// UpdateOAuthClientRequest is the request body to update an OAuth2 client, only passed fields are updated
+type UpdateOAuthClientRequest struct {
+	Module       string  `json:"module"`
+	ClientID     string  `json:"client_id"`
+	ClientSecret *string `json:"client_secret"`
+	AccessToken  *string `json:"access_token"`
+	RefreshToken *string `json:"refresh_token"`
+	Disabled     *bool   `json:"disabled"`
+}
+
+func (s *Server) registerOAuthClientRoutes() {
+	s.mux.HandleFunc("GET /api/v1/oauth-clients", s.listOAuthClients)
+	s.mux.HandleFunc("POST /api/v1/oauth-clients", s.createOAuthClient)
+	s.mux.HandleFunc("PATCH /api/v1/oauth-clients", s.updateOAuthClient)
+	s.mux.HandleFunc("DELETE /api/v1/oauth-clients", s.deleteOAuthClient)
+}
+
+// newOAuthClient converts the passed OAuth2 client into its JSON representation
+func newOAuthClient(oAuthClient *models.OAuthClient) OAuthClient {
+	return OAuthClient{
+		ID:       oAuthClient.ID,
+		ClientID: oAuthClient.ClientID,
+		Module:   oAuthClient.Module,
+		Disabled: oAuthClient.Disabled,
+	}
+}
+
+// listOAuthClients lists all enabled OAuth2 clients, optionally limited to the "module" query parameter
+func (s *Server) listOAuthClients(w http.ResponseWriter, r *http.Request) {
+	module, err := s.getOptionalModule(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	oAuthClients := make([]OAuthClient, 0)
+	for _, oAuthClient := range s.db.GetAllOAuthClients(module) {
+		oAuthClients = append(oAuthClients, newOAuthClient(oAuthClient))
+	}
+
+	writeJSON(w, http.StatusOK, oAuthClients)
+}
+
+// createOAuthClient adds the passed OAuth2 client if it doesn't exist for the module already
+func (s *Server) createOAuthClient(w http.ResponseWriter, r *http.Request) {
+	var request CreateOAuthClientRequest
+	if err := readJSON(r, &request); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if request.ClientID == "" && request.AccessToken == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("either client_id or access_token is required"))
+		return
+	}
+
+	module, err := s.getModule(request.Module)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	oAuthClient := s.db.GetFirstOrCreateOAuthClient(
+		request.ClientID, request.ClientSecret, request.AccessToken, request.RefreshToken, module,
+	)
+	writeJSON(w, http.StatusCreated, newOAuthClient(oAuthClient))
+}
+
+// updateOAuthClient updates the passed credentials and/or the disabled status of the OAuth2 client.
+// Credentials which are not passed are kept from the currently enabled OAuth2 client
+func (s *Server) updateOAuthClient(w http.ResponseWriter, r *http.Request) {
+	var request UpdateOAuthClientRequest
+	if err := readJSON(r, &request); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	module, err := s.getModule(request.Module)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	current := models.OAuthClient{ClientID: request.ClientID}
+	for _, oAuthClient := range s.db.GetAllOAuthClients(module) {
+		if oAuthClient.ClientID == request.ClientID {
+			current = *oAuthClient
+			break
+		}
+	}
+
+	if request.ClientSecret != nil {
+		current.ClientSecret = *request.ClientSecret
+	}
+
+	if request.AccessToken != nil {
+		current.AccessToken = *request.AccessToken
+	}
+
+	if request.RefreshToken != nil {
+		current.RefreshToken = *request.RefreshToken
+	}
+
+	if request.ClientSecret != nil || request.AccessToken != nil || request.RefreshToken != nil {
+		s.db.UpdateOAuthClient(
+			current.ClientID, current.ClientSecret, current.AccessToken, current.RefreshToken, module,
+		)
+	}
+
+	if request.Disabled != nil {
+		s.db.UpdateOAuthClientDisabledStatus(current.ClientID, current.AccessToken, *request.Disabled, module)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deleteOAuthClient deletes the OAuth2 client of the "module" and "client_id" query parameters
+func (s *Server) deleteOAuthClient(w http.ResponseWriter, r *http.Request) {
+	module, err := s.getModule(r.URL.Query().Get("module"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.db.DeleteOAuthClient(r.URL.Query().Get("client_id"), module)
+	w.WriteHeader(http.StatusNoContent)
+}
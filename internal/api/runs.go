@@ -0,0 +1,143 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/models"
+)
+
+// run states of triggered runs
+const (
+	RunStateRunning  = "running"
+	RunStateFinished = "finished"
+)
+
+// RunStatus is the JSON representation of a run triggered through the control API
+type RunStatus struct {
+	ID         int        `json:"id"`
+	State      string     `json:"state"`
+	Module     string     `json:"module,omitempty"`
+	ItemID     int        `json:"item_id,omitempty"`
+	Items      int        `json:"items"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// CreateRunRequest is the request body to trigger a run of either a single tracked item or a module
+type CreateRunRequest struct {
+	ItemID int    `json:"item_id"`
+	Module string `json:"module"`
+}
+
+func (s *Server) registerRunRoutes() {
+	s.mux.HandleFunc("GET /api/v1/runs", s.listRuns)
+	s.mux.HandleFunc("POST /api/v1/runs", s.createRun)
+	s.mux.HandleFunc("GET /api/v1/runs/{id}", s.getRun)
+}
+
+// listRuns lists the status of all runs triggered since the server got started
+func (s *Server) listRuns(w http.ResponseWriter, _ *http.Request) {
+	s.runsMutex.Lock()
+	defer s.runsMutex.Unlock()
+
+	runs := make([]RunStatus, 0, len(s.runs))
+	for _, run := range s.runs {
+		runs = append(runs, *run)
+	}
+
+	writeJSON(w, http.StatusOK, runs)
+}
+
+// getRun returns the status of the run of the passed ID
+func (s *Server) getRun(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid run ID \"%s\"", r.PathValue("id")))
+		return
+	}
+
+	s.runsMutex.Lock()
+	defer s.runsMutex.Unlock()
+
+	if id < 1 || id > len(s.runs) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no run found with ID %d", id))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, *s.runs[id-1])
+}
+
+// createRun triggers a run of the passed tracked item or of all not completed items of the passed module.
+// Only one run can be triggered at a time, the run itself is executed in the background
+func (s *Server) createRun(w http.ResponseWriter, r *http.Request) {
+	var request CreateRunRequest
+	if err := readJSON(r, &request); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	run := &RunStatus{
+		State:     RunStateRunning,
+		ItemID:    request.ItemID,
+		StartedAt: time.Now(),
+	}
+
+	var items []*models.TrackedItem
+
+	switch {
+	case request.ItemID != 0 && request.Module != "":
+		writeError(w, http.StatusBadRequest, fmt.Errorf("either item_id or module can be passed, not both"))
+		return
+	case request.ItemID != 0:
+		item := s.db.GetTrackedItem(request.ItemID)
+		if item == nil {
+			writeError(w, http.StatusNotFound, fmt.Errorf("no tracked item found with ID %d", request.ItemID))
+			return
+		}
+
+		run.Module = item.Module
+		items = append(items, item)
+	case request.Module != "":
+		module, err := s.getModule(request.Module)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		run.Module = module.ModuleKey()
+		items = s.db.GetTrackedItems(module, false)
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("either item_id or module is required"))
+		return
+	}
+
+	run.Items = len(items)
+
+	s.runsMutex.Lock()
+	if len(s.runs) > 0 && s.runs[len(s.runs)-1].State == RunStateRunning {
+		s.runsMutex.Unlock()
+		writeError(w, http.StatusConflict, fmt.Errorf("run %d is still in progress", len(s.runs)))
+		return
+	}
+
+	s.runs = append(s.runs, run)
+	run.ID = len(s.runs)
+	response := *run
+	s.runsMutex.Unlock()
+
+	go func() {
+		s.runner.RunItems(s.ctx, items)
+
+		s.runsMutex.Lock()
+		defer s.runsMutex.Unlock()
+
+		finishedAt := time.Now()
+		run.State = RunStateFinished
+		run.FinishedAt = &finishedAt
+	}()
+
+	writeJSON(w, http.StatusAccepted, response)
+}
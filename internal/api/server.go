@@ -0,0 +1,226 @@
+// Package api implements the local HTTP/JSON control API of the watcher process
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/models"
+	"github.com/DaRealFreak/watcher-go/internal/modules"
+	"github.com/spf13/viper"
+)
+
+// DefaultAddress is the default listening address of the control API, only reachable from the local machine
+const DefaultAddress = "127.0.0.1:8095"
+
+// Runner updates the passed tracked items, implemented by the watcher application
+type Runner interface {
+	RunItems(ctx context.Context, trackedItems []*models.TrackedItem)
+}
+
+// Server is the HTTP/JSON control API exposing the database and run functionality of the watcher
+type Server struct {
+	db      models.DatabaseInterface
+	factory *modules.ModuleFactory
+	runner  Runner
+	token   string
+	mux     *http.ServeMux
+	// remoteAccess is set if the server listens on a non-loopback address, requests to other hosts are accepted then
+	remoteAccess bool
+
+	// runs contains the status of all runs triggered since the server got started
+	runsMutex sync.Mutex
+	runs      []*RunStatus
+	ctx       context.Context
+}
+
+// ErrorResponse is the JSON response body of failed requests
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewServer returns a control API server using the passed database, module factory and runner.
+// If token is not empty every request requires an "Authorization: Bearer <token>" header
+func NewServer(db models.DatabaseInterface, factory *modules.ModuleFactory, runner Runner, token string) *Server {
+	s := &Server{
+		db:      db,
+		factory: factory,
+		runner:  runner,
+		token:   token,
+		mux:     http.NewServeMux(),
+		ctx:     context.Background(),
+	}
+
+	s.registerItemRoutes()
+	s.registerAccountRoutes()
+	s.registerOAuthClientRoutes()
+	s.registerCookieRoutes()
+	s.registerRunRoutes()
+
+	return s
+}
+
+// GetAddress returns the configured listening address of the control API or the default address
+func GetAddress() string {
+	if address := viper.GetString("api.address"); address != "" {
+		return address
+	}
+
+	return DefaultAddress
+}
+
+// ServeHTTP implements the http.Handler interface, rejects requests of other websites
+// and checks the authorization token if configured
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := s.checkRequestOrigin(r); err != nil {
+		writeError(w, http.StatusForbidden, err)
+		return
+	}
+
+	if s.token != "" && !s.isAuthorized(r) {
+		writeError(w, http.StatusUnauthorized, fmt.Errorf("missing or invalid authorization token"))
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// isAuthorized checks the bearer token of the passed request in constant time to not leak the token through timings
+func (s *Server) isAuthorized(r *http.Request) bool {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	return found && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+// checkRequestOrigin protects the API against requests of websites opened in the browser of the user.
+// Requests to non-loopback hosts (DNS rebinding) are only accepted if the server listens on a non-loopback address,
+// requests with the Origin header of another site (cross-site requests) are always rejected
+func (s *Server) checkRequestOrigin(r *http.Request) error {
+	if !s.remoteAccess && !isLoopbackHost(r.Host) {
+		return fmt.Errorf("invalid host \"%s\", the control API only accepts requests to loopback addresses", r.Host)
+	}
+
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if originURL, err := url.Parse(origin); err != nil || originURL.Host != r.Host {
+			return fmt.Errorf("cross-origin requests from \"%s\" are not allowed", origin)
+		}
+	}
+
+	return nil
+}
+
+// isLoopbackHost checks if the passed host with optional port is "localhost" or a loopback IP address
+func isLoopbackHost(host string) bool {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+// ListenAndServe serves the control API on the passed address until the passed context is done.
+// Triggered runs are started with the passed context, so they stop after the item in progress
+func (s *Server) ListenAndServe(ctx context.Context, address string) error {
+	if !isLoopbackHost(address) {
+		s.remoteAccess = true
+		slog.Warn(fmt.Sprintf("control API is listening on non-loopback address %s", address))
+	}
+
+	s.ctx = ctx
+	server := &http.Server{
+		Addr:              address,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info(fmt.Sprintf("control API listening on http://%s", address))
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// getModule returns the module matching the passed module key or URL
+func (s *Server) getModule(module string) (*models.Module, error) {
+	if m := s.factory.GetModule(module); m != nil {
+		return m, nil
+	}
+
+	if module != "" && s.factory.CanParse(module) {
+		return s.factory.GetModuleFromURI(module), nil
+	}
+
+	return nil, fmt.Errorf("no module registered for \"%s\"", module)
+}
+
+// getOptionalModule returns the module of the "module" query parameter or nil if not passed
+func (s *Server) getOptionalModule(r *http.Request) (models.ModuleInterface, error) {
+	moduleParam := r.URL.Query().Get("module")
+	if moduleParam == "" {
+		return nil, nil
+	}
+
+	return s.getModule(moduleParam)
+}
+
+// readJSON decodes the request body into the passed value
+func readJSON(r *http.Request, v interface{}) error {
+	// only JSON bodies are accepted, browsers can send other content types like text/plain cross-site without a preflight
+	contentType := r.Header.Get("Content-Type")
+	if mediaType, _, err := mime.ParseMediaType(contentType); err != nil || mediaType != "application/json" {
+		return fmt.Errorf("unsupported content type \"%s\", expected application/json", contentType)
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+
+	return nil
+}
+
+// writeJSON writes the passed value as JSON response with the passed status code
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Warn(fmt.Sprintf("unable to write control API response: %s", err.Error()))
+	}
+}
+
+// writeError writes the passed error as JSON response with the passed status code
+func writeError(w http.ResponseWriter, statusCode int, err error) {
+	writeJSON(w, statusCode, ErrorResponse{Error: err.Error()})
+}
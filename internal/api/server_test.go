@@ -0,0 +1,273 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/database"
+	"github.com/DaRealFreak/watcher-go/internal/models"
+	"github.com/DaRealFreak/watcher-go/internal/modules"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+
+	// import for side effects for factory
+	_ "github.com/DaRealFreak/watcher-go/internal/modules/sankakucomplex"
+)
+
+const testModule = "chan.sankakucomplex.com"
+
+// nolint: gochecknoglobals
+var dbIO *database.DbIO
+
+// stubRunner records the items passed to RunItems instead of running the modules
+type stubRunner struct {
+	mutex sync.Mutex
+	items []*models.TrackedItem
+}
+
+func (r *stubRunner) RunItems(_ context.Context, trackedItems []*models.TrackedItem) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.items = append(r.items, trackedItems...)
+}
+
+// TestMain creates a temporary database for the API tests and removes it again afterwards
+func TestMain(m *testing.M) {
+	f, err := os.CreateTemp("", "*.db")
+	if err != nil {
+		panic("couldn't create temporary database file for unit tests: " + err.Error())
+	}
+
+	// close the file, set the database path and remove the temporary file
+	_ = f.Close()
+	viper.Set("Database.Path", f.Name())
+	database.RemoveDatabase()
+
+	dbIO = database.NewConnection()
+
+	code := m.Run()
+
+	dbIO.CloseConnection()
+	database.RemoveDatabase()
+	os.Exit(code)
+}
+
+// doRequest sends the passed request to the server and decodes the JSON response into v if not nil
+func doRequest(t *testing.T, server *httptest.Server, method string, path string, body interface{}, v interface{}) int {
+	var reader bytes.Buffer
+	if body != nil {
+		assert.New(t).NoError(json.NewEncoder(&reader).Encode(body))
+	}
+
+	req, err := http.NewRequest(method, server.URL+path, &reader)
+	assert.New(t).NoError(err)
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := server.Client().Do(req)
+	assert.New(t).NoError(err)
+
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if v != nil {
+		assert.New(t).NoError(json.NewDecoder(res.Body).Decode(v))
+	}
+
+	return res.StatusCode
+}
+
+func TestItems(t *testing.T) {
+	server := httptest.NewServer(NewServer(dbIO, modules.GetModuleFactory(), &stubRunner{}, ""))
+	defer server.Close()
+
+	var item TrackedItem
+	status := doRequest(t, server, http.MethodPost, "/api/v1/items", CreateTrackedItemRequest{
+		URI:       "https://chan.sankakucomplex.com/?tags=api_test",
+		SubFolder: "api",
+	}, &item)
+	assert.New(t).Equal(http.StatusCreated, status)
+	assert.New(t).Equal(testModule, item.Module)
+	assert.New(t).Equal("api", item.SubFolder)
+
+	current, schedule, favorite := "12345", "12h", true
+	status = doRequest(t, server, http.MethodPatch, fmt.Sprintf("/api/v1/items/%d", item.ID), UpdateTrackedItemRequest{
+		CurrentItem: &current,
+		Schedule:    &schedule,
+		Favorite:    &favorite,
+	}, &item)
+	assert.New(t).Equal(http.StatusOK, status)
+	assert.New(t).Equal(current, item.CurrentItem)
+	assert.New(t).Equal(schedule, item.Schedule)
+	assert.New(t).True(item.Favorite)
+
+	invalidSchedule := "daily"
+	status = doRequest(t, server, http.MethodPatch, fmt.Sprintf("/api/v1/items/%d", item.ID), UpdateTrackedItemRequest{
+		Schedule: &invalidSchedule,
+	}, nil)
+	assert.New(t).Equal(http.StatusBadRequest, status)
+
+	var items []TrackedItem
+	status = doRequest(t, server, http.MethodGet, "/api/v1/items?module="+testModule, nil, &items)
+	assert.New(t).Equal(http.StatusOK, status)
+	assert.New(t).Len(items, 1)
+
+	status = doRequest(t, server, http.MethodDelete, fmt.Sprintf("/api/v1/items/%d", item.ID), nil, nil)
+	assert.New(t).Equal(http.StatusNoContent, status)
+
+	status = doRequest(t, server, http.MethodGet, fmt.Sprintf("/api/v1/items/%d", item.ID), nil, nil)
+	assert.New(t).Equal(http.StatusNotFound, status)
+}
+
+func TestAccounts(t *testing.T) {
+	server := httptest.NewServer(NewServer(dbIO, modules.GetModuleFactory(), &stubRunner{}, ""))
+	defer server.Close()
+
+	status := doRequest(t, server, http.MethodPost, "/api/v1/accounts", CreateAccountRequest{
+		Module:   testModule,
+		User:     "api_user",
+		Password: "api_pass",
+	}, nil)
+	assert.New(t).Equal(http.StatusCreated, status)
+
+	var accounts []Account
+	doRequest(t, server, http.MethodGet, "/api/v1/accounts?module="+testModule, nil, &accounts)
+	assert.New(t).Len(accounts, 1)
+	assert.New(t).Equal("api_user", accounts[0].User)
+
+	disabled := true
+	status = doRequest(t, server, http.MethodPatch, "/api/v1/accounts", UpdateAccountRequest{
+		Module:   testModule,
+		User:     "api_user",
+		Disabled: &disabled,
+	}, nil)
+	assert.New(t).Equal(http.StatusNoContent, status)
+
+	doRequest(t, server, http.MethodGet, "/api/v1/accounts?module="+testModule, nil, &accounts)
+	assert.New(t).Len(accounts, 0)
+
+	status = doRequest(t, server, http.MethodDelete, "/api/v1/accounts?module="+testModule+"&user=api_user", nil, nil)
+	assert.New(t).Equal(http.StatusNoContent, status)
+
+	status = doRequest(t, server, http.MethodPost, "/api/v1/accounts", CreateAccountRequest{
+		Module: "unknown.module",
+		User:   "api_user",
+	}, nil)
+	assert.New(t).Equal(http.StatusBadRequest, status)
+}
+
+func TestRuns(t *testing.T) {
+	runner := &stubRunner{}
+	server := httptest.NewServer(NewServer(dbIO, modules.GetModuleFactory(), runner, ""))
+	defer server.Close()
+
+	item := dbIO.GetFirstOrCreateTrackedItem(
+		"https://chan.sankakucomplex.com/?tags=api_run", "", modules.GetModuleFactory().GetModule(testModule),
+	)
+
+	var run RunStatus
+	status := doRequest(t, server, http.MethodPost, "/api/v1/runs", CreateRunRequest{ItemID: item.ID}, &run)
+	assert.New(t).Equal(http.StatusAccepted, status)
+	assert.New(t).Equal(1, run.ID)
+	assert.New(t).Equal(1, run.Items)
+
+	// the stub runner returns immediately, so the run finishes right away
+	assert.New(t).Eventually(func() bool {
+		doRequest(t, server, http.MethodGet, "/api/v1/runs/1", nil, &run)
+		return run.State == RunStateFinished
+	}, time.Second, 10*time.Millisecond)
+
+	runner.mutex.Lock()
+	assert.New(t).Len(runner.items, 1)
+	assert.New(t).Equal(item.ID, runner.items[0].ID)
+	runner.mutex.Unlock()
+
+	status = doRequest(t, server, http.MethodPost, "/api/v1/runs", CreateRunRequest{}, nil)
+	assert.New(t).Equal(http.StatusBadRequest, status)
+
+	status = doRequest(t, server, http.MethodGet, "/api/v1/runs/2", nil, nil)
+	assert.New(t).Equal(http.StatusNotFound, status)
+}
+
+func TestAuthorization(t *testing.T) {
+	server := httptest.NewServer(NewServer(dbIO, modules.GetModuleFactory(), &stubRunner{}, "secret"))
+	defer server.Close()
+
+	status := doRequest(t, server, http.MethodGet, "/api/v1/items", nil, nil)
+	assert.New(t).Equal(http.StatusUnauthorized, status)
+
+	expected := map[string]int{
+		"Bearer secret":  http.StatusOK,
+		"Bearer secret2": http.StatusUnauthorized,
+		"Bearer secre":   http.StatusUnauthorized,
+		"secret":         http.StatusUnauthorized,
+		"Bearer ":        http.StatusUnauthorized,
+	}
+
+	for authorization, expectedStatus := range expected {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v1/items", nil)
+		assert.New(t).NoError(err)
+		req.Header.Set("Authorization", authorization)
+
+		res, err := server.Client().Do(req)
+		assert.New(t).NoError(err)
+		_ = res.Body.Close()
+		assert.New(t).Equal(expectedStatus, res.StatusCode, authorization)
+	}
+}
+
+func TestCrossSiteRequests(t *testing.T) {
+	runner := &stubRunner{}
+	server := httptest.NewServer(NewServer(dbIO, modules.GetModuleFactory(), runner, ""))
+	defer server.Close()
+
+	sendRun := func(header http.Header, host string) int {
+		req, err := http.NewRequest(
+			http.MethodPost, server.URL+"/api/v1/runs", bytes.NewBufferString(`{"module":"`+testModule+`"}`),
+		)
+		assert.New(t).NoError(err)
+
+		req.Header = header
+		if host != "" {
+			req.Host = host
+		}
+
+		res, err := server.Client().Do(req)
+		assert.New(t).NoError(err)
+		_ = res.Body.Close()
+
+		return res.StatusCode
+	}
+
+	// simple cross-site requests without a preflight can't send JSON bodies
+	assert.New(t).Equal(http.StatusBadRequest, sendRun(http.Header{"Content-Type": {"text/plain"}}, ""))
+	// requests of other websites
+	assert.New(t).Equal(http.StatusForbidden, sendRun(http.Header{
+		"Content-Type": {"application/json"},
+		"Origin":       {"https://example.com"},
+	}, ""))
+	// DNS rebinding attacks resolve a foreign host name to the loopback address
+	assert.New(t).Equal(http.StatusForbidden, sendRun(http.Header{"Content-Type": {"application/json"}}, "attacker.example.com"))
+
+	runner.mutex.Lock()
+	assert.New(t).Empty(runner.items)
+	runner.mutex.Unlock()
+
+	assert.New(t).True(isLoopbackHost("localhost:8095"))
+	assert.New(t).True(isLoopbackHost("[::1]:8095"))
+	assert.New(t).True(isLoopbackHost("127.0.0.1"))
+	assert.New(t).False(isLoopbackHost("0.0.0.0:8095"))
+	assert.New(t).False(isLoopbackHost(":8095"))
+}
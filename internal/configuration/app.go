@@ -1,11 +1,15 @@
 package configuration
 
+import "time"
+
 // AppConfiguration contains the persistent configurations/settings across all commands
 type AppConfiguration struct {
 	ConfigurationFile string
 	LogLevel          string
 	// database file location
 	Database string
+	// SkipAutoMigrate opens the database without applying pending migrations, set for the db commands
+	SkipAutoMigrate bool
 	// backup options
 	Backup struct {
 		BackupSettings
@@ -13,10 +17,23 @@ type AppConfiguration struct {
 			Zip  bool
 			Tar  bool
 			Gzip bool
+			// Format is the name of a registered archive format (f.e. "tar.zst"), taking precedence over the toggles
+			Format string
+			// Encrypt protects the archive with the password from the WATCHER_BACKUP_PASSWORD environment variable
+			Encrypt bool
 		}
+		// Incremental only backs up the rows changed since the previous backup in the directory of the archive
+		Incremental bool
+		// KeepDaily and KeepWeekly remove older backups except for the latest backup of the last days/weeks
+		KeepDaily  int
+		KeepWeekly int
+		// Verify restores the backup into a temporary database and compares it with the backed up tables
+		Verify bool
 	}
 	Restore struct {
 		BackupSettings
+		// Verify only restores the backup into a temporary database instead of the current database
+		Verify bool
 	}
 	// cli specific options
 	Cli struct {
@@ -25,10 +42,33 @@ type AppConfiguration struct {
 		DisableTimestamp         bool
 		UseUppercaseLevel        bool
 		UseTimePassedAsTimestamp bool
+		// LogFormat is the format of the log output (pretty, text or json)
+		LogFormat string
+		// LogFile additionally writes all log records to the file, rotated after LogFileMaxSize megabytes
+		LogFile           string
+		LogFileMaxSize    int
+		LogFileMaxBackups int
+		// LogFilePerRun rotates the log file on every start, resulting in one log file per run
+		LogFilePerRun bool
 	}
 	// sentry toggles
 	EnableSentry  bool
 	DisableSentry bool
+	// daemon specific options
+	Daemon struct {
+		// Schedule overrides the global daemon.schedule setting, module and item schedules still take precedence
+		Schedule     string
+		PollInterval string
+		// MetricsAddress overrides the metrics.address setting
+		MetricsAddress string
+	}
+	// control API specific options
+	API struct {
+		// Enabled starts the control API alongside the daemon, overrides the api.enabled setting
+		Enabled bool
+		// Address overrides the api.address setting
+		Address string
+	}
 	// run specific options
 	Run struct {
 		Force             bool
@@ -38,6 +78,17 @@ type AppConfiguration struct {
 		DownloadDirectory string
 		ModuleURL         []string
 		DisableURL        []string
+		// Tags limits the run to items with any of the tags, items with any of the ExcludeTags are skipped
+		Tags        []string
+		ExcludeTags []string
+		// DryRun only resolves the download queues without downloading files or updating the progress
+		DryRun       bool
+		DryRunFormat string
+		// Order is the comma separated run order (favorite, least-recent, last-run, priority)
+		Order string
+		// Limit stops the run after the passed amount of items, TimeBudget stops starting new items after the duration
+		Limit      int
+		TimeBudget time.Duration
 		// ProxyConnectionLimits caps simultaneous in-flight HTTP requests per
 		// (proxy username, host eTLD+1) pool. A list (not a map) is used so
 		// domain names containing dots survive Viper's nested-key flattening.
@@ -10,7 +10,7 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 )
 
-func (db *DbIO) createAccountsTable(connection *sql.DB) (err error) {
+func createAccountsTable(connection executor) (err error) {
 	sqlStatement := `
 		CREATE TABLE accounts
 		(
@@ -28,7 +28,7 @@ func (db *DbIO) createAccountsTable(connection *sql.DB) (err error) {
 
 // GetAccount retrieves the first not disabled account of the passed module
 func (db *DbIO) GetAccount(module models.ModuleInterface) *models.Account {
-	stmt, err := db.connection.Prepare("SELECT * FROM accounts WHERE NOT disabled AND module = ? ORDER BY uid")
+	stmt, err := db.prepare("SELECT * FROM accounts WHERE NOT disabled AND module = ? ORDER BY uid")
 	raven.CheckError(err)
 
 	rows, err := stmt.Query(module.ModuleKey())
@@ -41,6 +41,8 @@ func (db *DbIO) GetAccount(module models.ModuleInterface) *models.Account {
 		err = rows.Scan(&account.ID, &account.Username, &account.Password, &account.Module, &account.Disabled)
 		raven.CheckError(err)
 
+		account.Password = db.decryptSecret(account.Password)
+
 		return &account
 	}
 
@@ -56,13 +58,13 @@ func (db *DbIO) GetAllAccounts(module models.ModuleInterface) (accounts []*model
 	)
 
 	if module != nil {
-		stmt, err = db.connection.Prepare("SELECT * FROM accounts WHERE NOT disabled AND module = ? ORDER BY module, uid")
+		stmt, err = db.prepare("SELECT * FROM accounts WHERE NOT disabled AND module = ? ORDER BY module, uid")
 		raven.CheckError(err)
 
 		rows, err = stmt.Query(module.ModuleKey())
 		raven.CheckError(err)
 	} else {
-		rows, err = db.connection.Query("SELECT * FROM accounts WHERE NOT disabled ORDER BY module, uid")
+		rows, err = db.query("SELECT * FROM accounts WHERE NOT disabled ORDER BY module, uid")
 	}
 
 	raven.CheckError(err)
@@ -72,6 +74,8 @@ func (db *DbIO) GetAllAccounts(module models.ModuleInterface) (accounts []*model
 		err := rows.Scan(&account.ID, &account.Username, &account.Password, &account.Module, &account.Disabled)
 		raven.CheckError(err)
 
+		account.Password = db.decryptSecret(account.Password)
+
 		accounts = append(accounts, &account)
 	}
 
@@ -81,7 +85,7 @@ func (db *DbIO) GetAllAccounts(module models.ModuleInterface) (accounts []*model
 // GetFirstOrCreateAccount checks if an account exists already, else creates it
 // returns the already persisted or the newly created account
 func (db *DbIO) GetFirstOrCreateAccount(user string, password string, module models.ModuleInterface) *models.Account {
-	stmt, err := db.connection.Prepare("SELECT * FROM accounts WHERE user = ? AND module = ?")
+	stmt, err := db.prepare(`SELECT * FROM accounts WHERE "user" = ? AND module = ?`)
 	raven.CheckError(err)
 
 	rows, err := stmt.Query(user, module.ModuleKey())
@@ -95,6 +99,8 @@ func (db *DbIO) GetFirstOrCreateAccount(user string, password string, module mod
 		err = rows.Scan(&account.ID, &account.Username, &account.Password, &account.Module, &account.Disabled)
 		raven.CheckError(err)
 
+		account.Password = db.decryptSecret(account.Password)
+
 		return &account
 	}
 
@@ -106,41 +112,44 @@ func (db *DbIO) GetFirstOrCreateAccount(user string, password string, module mod
 
 // CreateAccount inserts the passed user and password of the specific module into the database
 func (db *DbIO) CreateAccount(user string, password string, module models.ModuleInterface) {
-	stmt, err := db.connection.Prepare("INSERT INTO accounts (user, password, module) VALUES (?, ?, ?)")
+	stmt, err := db.prepare(`INSERT INTO accounts ("user", password, module) VALUES (?, ?, ?)`)
 	raven.CheckError(err)
 
 	defer raven.CheckClosure(stmt)
 
-	_, err = stmt.Exec(user, password, module.ModuleKey())
+	_, err = stmt.Exec(user, db.encryptSecret(password), module.ModuleKey())
 	raven.CheckError(err)
 }
 
 // UpdateAccount updates the password of the passed user/module entry
 func (db *DbIO) UpdateAccount(user string, password string, module models.ModuleInterface) {
-	stmt, err := db.connection.Prepare("UPDATE accounts SET password = ? WHERE user = ? AND module = ?")
+	stmt, err := db.prepare(`UPDATE accounts SET password = ? WHERE "user" = ? AND module = ?`)
 	raven.CheckError(err)
 
 	defer raven.CheckClosure(stmt)
 
-	_, err = stmt.Exec(password, user, module.ModuleKey())
+	_, err = stmt.Exec(db.encryptSecret(password), user, module.ModuleKey())
 	raven.CheckError(err)
 }
 
 // UpdateAccountDisabledStatus disables the account of the passed user/module
 func (db *DbIO) UpdateAccountDisabledStatus(user string, disabled bool, module models.ModuleInterface) {
-	var disabledInt int8
+	stmt, err := db.prepare(`UPDATE accounts SET disabled = ? WHERE "user" = ? AND module = ?`)
+	raven.CheckError(err)
 
-	if disabled {
-		disabledInt = 1
-	} else {
-		disabledInt = 0
-	}
+	defer raven.CheckClosure(stmt)
+
+	_, err = stmt.Exec(disabled, user, module.ModuleKey())
+	raven.CheckError(err)
+}
 
-	stmt, err := db.connection.Prepare("UPDATE accounts SET disabled = ? WHERE user = ? AND module = ?")
+// DeleteAccount deletes the account of the passed user/module
+func (db *DbIO) DeleteAccount(user string, module models.ModuleInterface) {
+	stmt, err := db.prepare(`DELETE FROM accounts WHERE "user" = ? AND module = ?`)
 	raven.CheckError(err)
 
 	defer raven.CheckClosure(stmt)
 
-	_, err = stmt.Exec(disabledInt, user, module.ModuleKey())
+	_, err = stmt.Exec(user, module.ModuleKey())
 	raven.CheckError(err)
 }
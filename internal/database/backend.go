@@ -0,0 +1,203 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/raven"
+)
+
+// Backend contains the parts of the database access differing between the supported database systems.
+// The queries of the DbIO are written with ? placeholders and rebound by the backend before executing them
+type Backend interface {
+	// Open opens the connection to the passed data source name
+	Open(dsn string) (*sql.DB, error)
+	// CreateSchema creates all tables in the latest schema version
+	CreateSchema(connection executor) error
+	// CreateSchemaMigrationsTable creates the tables containing the applied migrations and the pinned schema version
+	// if they don't exist yet
+	CreateSchemaMigrationsTable(connection executor) error
+	// TableNames returns the names of all tables in the database excluding internal tables of the database system
+	TableNames(connection executor) ([]string, error)
+	// Rebind converts the ? placeholders of the passed query into the placeholders of the backend
+	Rebind(query string) string
+	// Now returns the SQL expression of the current unix timestamp
+	Now() string
+	// Like returns the operator of case-insensitive LIKE comparisons
+	Like() string
+	// NotesSearch returns the condition matching the tracked items whose notes or generated notes contain
+	// all passed phrases using the full-text index of the backend and the argument of its placeholder
+	NotesSearch(phrases [][]string) (condition string, arg string)
+}
+
+var (
+	backends   = make(map[string]Backend)
+	backendsMu sync.RWMutex
+)
+
+// RegisterBackend registers the passed backend under the passed name used in the database.driver setting
+func RegisterBackend(name string, backend Backend) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+
+	backends[name] = backend
+}
+
+// GetBackend returns the backend registered under the passed name
+func GetBackend(name string) (Backend, error) {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+
+	backend, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf(
+			"unknown database driver \"%s\", supported drivers: %s", name, strings.Join(backendNames(), ", "),
+		)
+	}
+
+	return backend, nil
+}
+
+// Backends returns the sorted names of all registered backends
+func Backends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+
+	return backendNames()
+}
+
+// backendNames returns the sorted names of all registered backends, the caller has to hold the lock
+func backendNames() []string {
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// rebindNumbered converts the ? placeholders of the passed query into numbered placeholders ($1, $2, ...),
+// question marks in string literals and quoted identifiers are kept
+func rebindNumbered(query string) string {
+	var (
+		builder strings.Builder
+		quote   rune
+		index   int
+	)
+
+	builder.Grow(len(query) + 8)
+
+	for _, char := range query {
+		switch {
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			}
+		case char == '\'' || char == '"':
+			quote = char
+		case char == '?':
+			index++
+			builder.WriteString("$" + strconv.Itoa(index))
+
+			continue
+		}
+
+		builder.WriteRune(char)
+	}
+
+	return builder.String()
+}
+
+// prepare creates a prepared statement of the passed query rebound for the backend
+func (db *DbIO) prepare(query string) (*sql.Stmt, error) {
+	return db.connection.Prepare(db.backend.Rebind(query))
+}
+
+// query executes the passed query rebound for the backend and returns the resulting rows
+func (db *DbIO) query(query string, args ...any) (*sql.Rows, error) {
+	return db.connection.Query(db.backend.Rebind(query), args...)
+}
+
+// queryRow executes the passed query rebound for the backend and returns the first resulting row
+func (db *DbIO) queryRow(query string, args ...any) *sql.Row {
+	return db.connection.QueryRow(db.backend.Rebind(query), args...)
+}
+
+// exec executes the passed statement rebound for the backend
+func (db *DbIO) exec(query string, args ...any) (sql.Result, error) {
+	return db.connection.Exec(db.backend.Rebind(query), args...)
+}
+
+// unixTime scans the time columns stored as unix timestamps into times. SQLite already converts columns
+// declared as DATETIME into times, other backends return the plain unix timestamps
+type unixTime struct {
+	target *time.Time
+}
+
+// Scan implements the sql.Scanner interface
+func (t unixTime) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*t.target = time.Time{}
+	case time.Time:
+		*t.target = v
+	case int64:
+		*t.target = time.Unix(v, 0).UTC()
+	case []byte, string:
+		timestamp, err := strconv.ParseInt(fmt.Sprintf("%s", v), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to parse unix timestamp %q: %w", v, err)
+		}
+
+		*t.target = time.Unix(timestamp, 0).UTC()
+	default:
+		return fmt.Errorf("unsupported type %T for unix timestamps", value)
+	}
+
+	return nil
+}
+
+// nullUnixTime scans the nullable time columns stored as unix timestamps into null times
+type nullUnixTime struct {
+	target *sql.NullTime
+}
+
+// Scan implements the sql.Scanner interface
+func (t nullUnixTime) Scan(value any) error {
+	if value == nil {
+		*t.target = sql.NullTime{}
+		return nil
+	}
+
+	t.target.Valid = true
+
+	return unixTime{target: &t.target.Time}.Scan(value)
+}
+
+// queryNames returns the values of the first column of all rows of the passed query
+func queryNames(connection executor, query string, args ...any) (names []string, err error) {
+	rows, err := connection.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer raven.CheckClosure(rows)
+
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, err
+		}
+
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
@@ -0,0 +1,291 @@
+package database
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/raven"
+)
+
+// changeLogTables maps the tables recorded in the change log to the column identifying the changed rows.
+// The tags are recorded by their item, so all tags of a changed item are backed up together
+var changeLogTables = map[string]string{
+	"accounts":      "uid",
+	"tracked_items": "uid",
+	"item_tags":     "item_id",
+	"oauth_clients": "uid",
+	"cookies":       "uid",
+}
+
+// changeLogChunkSize is the maximum amount of keys per statement of the change dumps
+const changeLogChunkSize = 500
+
+// createChangeLogTable creates the change log and the triggers recording every change of the backed up tables
+func createChangeLogTable(connection executor) (err error) {
+	sqlStatement := `
+		CREATE TABLE IF NOT EXISTS change_log
+		(
+			uid        INTEGER PRIMARY KEY AUTOINCREMENT,
+			table_name VARCHAR(255) DEFAULT '' NOT NULL,
+			row_key    INTEGER      DEFAULT 0 NOT NULL,
+			changed_at DATETIME     DEFAULT (strftime('%s','now')) NOT NULL
+		);
+	`
+	if _, err = connection.Exec(sqlStatement); err != nil {
+		return err
+	}
+
+	for _, table := range changeLogTableNames() {
+		for _, trigger := range changeLogTriggers(table) {
+			if _, err = connection.Exec(fmt.Sprintf(
+				"CREATE TRIGGER IF NOT EXISTS %[1]s AFTER %[2]s ON %[3]s "+
+					"BEGIN INSERT INTO change_log (table_name, row_key) VALUES ('%[3]s', %[4]s.%[5]s); END",
+				trigger.name, trigger.event, table, trigger.row, changeLogTables[table],
+			)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// changeLogTrigger is a trigger recording the changes of one event of a table
+type changeLogTrigger struct {
+	name  string
+	event string
+	// row is the row containing the key after the event, NEW for inserts and updates and OLD for deletes
+	row string
+}
+
+// changeLogTriggers returns the triggers recording the inserts, updates and deletes of the passed table
+func changeLogTriggers(table string) []changeLogTrigger {
+	return []changeLogTrigger{
+		{name: table + "_change_log_insert", event: "INSERT", row: "NEW"},
+		{name: table + "_change_log_update", event: "UPDATE", row: "NEW"},
+		{name: table + "_change_log_delete", event: "DELETE", row: "OLD"},
+	}
+}
+
+// changeLogTableNames returns the sorted names of the tables recorded in the change log
+func changeLogTableNames() []string {
+	tableNames := make([]string, 0, len(changeLogTables))
+	for table := range changeLogTables {
+		tableNames = append(tableNames, table)
+	}
+
+	sort.Strings(tableNames)
+
+	return tableNames
+}
+
+// migrateChangeLog adds the change log used for incremental backups.
+// Backups are only supported for SQLite, so other backends don't record their changes
+func (db *DbIO) migrateChangeLog(tx *sql.Tx) error {
+	if db.driver != SQLiteDriver {
+		return nil
+	}
+
+	return createChangeLogTable(tx)
+}
+
+// revertChangeLog removes the change log and the triggers recording the changes
+func (db *DbIO) revertChangeLog(tx *sql.Tx) error {
+	if db.driver != SQLiteDriver {
+		return nil
+	}
+
+	for _, table := range changeLogTableNames() {
+		for _, trigger := range changeLogTriggers(table) {
+			if _, err := tx.Exec("DROP TRIGGER IF EXISTS " + trigger.name); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := tx.Exec("DROP TABLE IF EXISTS change_log")
+
+	return err
+}
+
+// ChangeLogPosition returns the uid of the latest change log entry, 0 if no changes were recorded yet.
+// The position is read from the autoincrement sequence, so it is kept when pruning the change log
+func (db *DbIO) ChangeLogPosition() (position int64, err error) {
+	if db.driver != SQLiteDriver {
+		return 0, fmt.Errorf("the change log is not supported by the %s database driver", db.driver)
+	}
+
+	err = db.queryRow(
+		"SELECT COALESCE((SELECT seq FROM sqlite_sequence WHERE name = 'change_log'), 0)",
+	).Scan(&position)
+
+	return position, err
+}
+
+// DumpChanges dumps the rows of the passed tables changed after the passed change log position to the passed writer
+// and returns the change log position the dump is based on. Tracked items modified since the passed time are
+// included as well, so changes of items are covered even if the change log got pruned.
+// Changed rows get deleted and inserted again, so deleted rows are also removed when executing the dump
+func (db *DbIO) DumpChanges(
+	writer io.Writer, position int64, since time.Time, tableNames ...string,
+) (latestPosition int64, err error) {
+	if latestPosition, err = db.ChangeLogPosition(); err != nil {
+		return 0, err
+	}
+
+	changedKeys, err := db.getChangedKeys(position, latestPosition, since)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err = writer.Write([]byte("BEGIN TRANSACTION;\n")); err != nil {
+		return 0, err
+	}
+
+	for _, table := range tableNames {
+		keyColumn, ok := changeLogTables[table]
+		if !ok {
+			return 0, fmt.Errorf("changes of the table %s are not recorded", table)
+		}
+
+		keys := changedKeys[table]
+		for start := 0; start < len(keys); start += changeLogChunkSize {
+			condition := fmt.Sprintf(
+				`WHERE "%s" IN (%s)`, keyColumn, joinKeys(keys[start:min(start+changeLogChunkSize, len(keys))]),
+			)
+
+			if _, err = writer.Write([]byte(`DELETE FROM "` + table + `" ` + condition + ";\n")); err != nil {
+				return 0, err
+			}
+
+			var inserts []string
+			if inserts, err = db.getTableRows(table, condition); err != nil {
+				return 0, err
+			}
+
+			for _, insert := range inserts {
+				if _, err = writer.Write([]byte(insert + "\n")); err != nil {
+					return 0, err
+				}
+			}
+		}
+	}
+
+	_, err = writer.Write([]byte("COMMIT;\n"))
+
+	return latestPosition, err
+}
+
+// getChangedKeys returns the sorted keys of the changed rows between the passed change log positions by table
+func (db *DbIO) getChangedKeys(position int64, latestPosition int64, since time.Time) (map[string][]int64, error) {
+	rows, err := db.query(
+		`SELECT DISTINCT table_name, row_key FROM change_log WHERE uid > ? AND uid <= ?
+		 UNION
+		 SELECT 'tracked_items', uid FROM tracked_items WHERE last_modified >= ?`,
+		position, latestPosition, since.Unix(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer raven.CheckClosure(rows)
+
+	changedKeys := make(map[string][]int64)
+
+	for rows.Next() {
+		var (
+			table string
+			key   int64
+		)
+
+		if err = rows.Scan(&table, &key); err != nil {
+			return nil, err
+		}
+
+		changedKeys[table] = append(changedKeys[table], key)
+	}
+
+	for _, keys := range changedKeys {
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	}
+
+	return changedKeys, rows.Err()
+}
+
+// joinKeys returns the passed keys as comma separated list for IN conditions
+func joinKeys(keys []int64) string {
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = strconv.FormatInt(key, 10)
+	}
+
+	return strings.Join(values, ",")
+}
+
+// PruneChangeLog removes the change log entries up to the passed position, which are no longer required for backups
+func (db *DbIO) PruneChangeLog(position int64) error {
+	if db.driver != SQLiteDriver {
+		return fmt.Errorf("the change log is not supported by the %s database driver", db.driver)
+	}
+
+	_, err := db.exec("DELETE FROM change_log WHERE uid <= ?", position)
+
+	return err
+}
+
+// TableChecksums returns the SHA-256 checksums of the content of the passed tables independent of the row order,
+// used to verify that a restored backup matches the backed up database
+func (db *DbIO) TableChecksums(tableNames ...string) (map[string]string, error) {
+	if db.driver != SQLiteDriver {
+		return nil, fmt.Errorf("table checksums are not supported by the %s database driver", db.driver)
+	}
+
+	checksums := make(map[string]string, len(tableNames))
+
+	for _, table := range tableNames {
+		inserts, err := db.getTableRows(table, "")
+		if err != nil {
+			return nil, err
+		}
+
+		sort.Strings(inserts)
+
+		hash := sha256.New()
+		for _, insert := range inserts {
+			_, _ = hash.Write([]byte(insert + "\n"))
+		}
+
+		checksums[table] = hex.EncodeToString(hash.Sum(nil))
+	}
+
+	return checksums, nil
+}
+
+// Checkpoint writes the content of the write-ahead log into the database file, so copies of the file are complete
+func (db *DbIO) Checkpoint() error {
+	if db.driver != SQLiteDriver {
+		return fmt.Errorf("checkpoints are not supported by the %s database driver", db.driver)
+	}
+
+	_, err := db.exec("PRAGMA wal_checkpoint(TRUNCATE)")
+
+	return err
+}
+
+// ExecuteScript executes the passed SQL script like the dumps of the DumpTables and DumpChanges functions
+func (db *DbIO) ExecuteScript(script string) error {
+	if db.driver != SQLiteDriver {
+		return fmt.Errorf("executing scripts is not supported by the %s database driver", db.driver)
+	}
+
+	_, err := db.connection.Exec(script)
+
+	return err
+}
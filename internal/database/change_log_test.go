@@ -0,0 +1,79 @@
+package database
+
+import (
+	"bytes"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/modules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpChanges(t *testing.T) {
+	tables := []string{"accounts", "tracked_items", "item_tags", "oauth_clients", "cookies"}
+	module := modules.GetModuleFactory().GetAllModules()[0]
+
+	source, err := NewSQLiteConnection(filepath.Join(t.TempDir(), "source.db"))
+	require.NoError(t, err)
+
+	defer source.CloseConnection()
+
+	target, err := NewSQLiteConnection(filepath.Join(t.TempDir(), "target.db"))
+	require.NoError(t, err)
+
+	defer target.CloseConnection()
+
+	source.GetFirstOrCreateAccount("user", "password", module)
+	unchanged := source.GetFirstOrCreateTrackedItem("https://example.com/unchanged", "", module)
+	updated := source.GetFirstOrCreateTrackedItem("https://example.com/updated", "", module)
+	deleted := source.GetFirstOrCreateTrackedItem("https://example.com/deleted", "", module)
+	source.AddTrackedItemTag(deleted, "nightly")
+
+	// restore the full dump into the target database
+	full := new(bytes.Buffer)
+	require.NoError(t, source.DumpTables(full, tables...))
+	require.NoError(t, target.ExecuteScript(full.String()))
+
+	position, err := source.ChangeLogPosition()
+	require.NoError(t, err)
+	assert.NotZero(t, position)
+
+	// only consider the change log for the incremental dump
+	since := time.Now().Add(time.Hour)
+
+	source.UpdateTrackedItem(updated, "42")
+	source.AddTrackedItemTag(updated, "weekly")
+	source.DeleteTrackedItem(deleted)
+	source.RemoveTrackedItemTag(deleted, "nightly")
+	source.CreateCookie("session", "value", sql.NullTime{}, module)
+
+	changes := new(bytes.Buffer)
+	latestPosition, err := source.DumpChanges(changes, position, since, tables...)
+	require.NoError(t, err)
+	assert.Greater(t, latestPosition, position)
+	assert.NotContains(t, changes.String(), unchanged.URI)
+
+	require.NoError(t, target.ExecuteScript(changes.String()))
+
+	sourceChecksums, err := source.TableChecksums(tables...)
+	require.NoError(t, err)
+
+	targetChecksums, err := target.TableChecksums(tables...)
+	require.NoError(t, err)
+	assert.Equal(t, sourceChecksums, targetChecksums)
+
+	// without changes the dump is empty
+	changes.Reset()
+	_, err = source.DumpChanges(changes, latestPosition, since, tables...)
+	require.NoError(t, err)
+	assert.Equal(t, "BEGIN TRANSACTION;\nCOMMIT;\n", changes.String())
+
+	// pruning the change log keeps the position
+	require.NoError(t, source.PruneChangeLog(latestPosition))
+	position, err = source.ChangeLogPosition()
+	require.NoError(t, err)
+	assert.Equal(t, latestPosition, position)
+}
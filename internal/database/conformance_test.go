@@ -0,0 +1,329 @@
+package database
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/models"
+	"github.com/DaRealFreak/watcher-go/internal/modules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// envTestPostgresDSN contains the data source name of an empty PostgreSQL database for the conformance suite.
+// All tables of the database are dropped before running the suite against it
+const envTestPostgresDSN = "WATCHER_TEST_POSTGRES_DSN"
+
+// numberedSQLiteDriver is the stubbed backend running SQLite with the numbered placeholders of PostgreSQL,
+// so the rebinding of every query is covered without a PostgreSQL server
+const numberedSQLiteDriver = "sqlite3-numbered"
+
+// numberedSQLiteBackend is a SQLite backend rebinding the queries to numbered placeholders
+type numberedSQLiteBackend struct {
+	sqliteBackend
+}
+
+// Rebind converts the ? placeholders into numbered placeholders, which SQLite supports as well
+func (b numberedSQLiteBackend) Rebind(query string) string {
+	return rebindNumbered(query)
+}
+
+func init() {
+	RegisterBackend(numberedSQLiteDriver, numberedSQLiteBackend{})
+}
+
+// openConformanceBackend opens an empty database of the passed backend for the conformance suite
+func openConformanceBackend(t *testing.T, driver string) *DbIO {
+	var dsn string
+
+	switch driver {
+	case SQLiteDriver, numberedSQLiteDriver:
+		dsn = filepath.Join(t.TempDir(), "conformance.db")
+	case PostgresDriver:
+		if dsn = os.Getenv(envTestPostgresDSN); dsn == "" {
+			t.Skipf("%s is not set", envTestPostgresDSN)
+		}
+
+		dropTables(t, driver, dsn)
+	default:
+		t.Skipf("no conformance setup for the %s backend", driver)
+	}
+
+	db, err := newConnection(driver, dsn, true)
+	require.NoError(t, err)
+
+	t.Cleanup(db.CloseConnection)
+
+	return db
+}
+
+// dropTables drops all tables of the passed database, so the conformance suite starts with an empty database
+func dropTables(t *testing.T, driver string, dsn string) {
+	backend, err := GetBackend(driver)
+	require.NoError(t, err)
+
+	connection, err := backend.Open(dsn)
+	require.NoError(t, err)
+
+	defer func() { _ = connection.Close() }()
+
+	tableNames, err := backend.TableNames(connection)
+	require.NoError(t, err)
+
+	for _, tableName := range tableNames {
+		_, err = connection.Exec(`DROP TABLE "` + tableName + `" CASCADE`)
+		require.NoError(t, err)
+	}
+}
+
+// TestBackendConformance runs the same cases against every registered backend
+func TestBackendConformance(t *testing.T) {
+	cases := map[string]func(t *testing.T, db *DbIO, module models.ModuleInterface){
+		"schema":        testConformanceSchema,
+		"accounts":      testConformanceAccounts,
+		"tracked items": testConformanceTrackedItems,
+		"oauth clients": testConformanceOAuthClients,
+		"cookies":       testConformanceCookies,
+		"runs":          testConformanceRuns,
+		"tags":          testConformanceTags,
+		"hashes":        testConformanceHashes,
+	}
+
+	module := modules.GetModuleFactory().GetAllModules()[0]
+
+	for _, driver := range Backends() {
+		t.Run(driver, func(t *testing.T) {
+			for name, testCase := range cases {
+				t.Run(name, func(t *testing.T) {
+					testCase(t, openConformanceBackend(t, driver), module)
+				})
+			}
+		})
+	}
+}
+
+func testConformanceSchema(t *testing.T, db *DbIO, _ models.ModuleInterface) {
+	version, err := db.SchemaVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, LatestSchemaVersion(), version)
+
+	tableNames, err := db.getTableNames()
+	assert.NoError(t, err)
+	assert.Subset(t, tableNames, []string{
+		"accounts", "tracked_items", "oauth_clients", "cookies", "runs", "item_runs",
+		"item_tags", "file_hashes", "image_hashes", "schema_migrations",
+	})
+
+	// migrating an up-to-date database is a no-op
+	assert.NoError(t, db.Migrate(LatestSchemaVersion()))
+}
+
+func testConformanceAccounts(t *testing.T, db *DbIO, module models.ModuleInterface) {
+	account := db.GetFirstOrCreateAccount("user", "password", module)
+	require.NotNil(t, account)
+	assert.Equal(t, "user", account.Username)
+	assert.Equal(t, "password", account.Password)
+	assert.Equal(t, account.ID, db.GetFirstOrCreateAccount("user", "other", module).ID)
+
+	db.UpdateAccount("user", "changed", module)
+	assert.Equal(t, "changed", db.GetAccount(module).Password)
+
+	db.CreateAccount("second", "password", module)
+	assert.Len(t, db.GetAllAccounts(module), 2)
+	assert.Len(t, db.GetAllAccounts(nil), 2)
+
+	db.UpdateAccountDisabledStatus("user", true, module)
+	assert.Equal(t, "second", db.GetAccount(module).Username)
+
+	db.DeleteAccount("second", module)
+	assert.Nil(t, db.GetAccount(module))
+}
+
+func testConformanceTrackedItems(t *testing.T, db *DbIO, module models.ModuleInterface) {
+	item := db.GetFirstOrCreateTrackedItem("https://example.com/Gallery/1", "", module)
+	require.NotNil(t, item)
+	assert.Equal(t, item.ID, db.GetFirstOrCreateTrackedItem("https://example.com/Gallery/1", "", module).ID)
+	assert.True(t, item.LastModified.Valid)
+
+	db.UpdateTrackedItem(item, "42")
+	db.UpdateTrackedItemNotes(item, "Artist with 100% Cats")
+	db.ChangeTrackedItemFavoriteStatus(item, true)
+	db.ChangeTrackedItemPriority(item, 5)
+	db.ChangeTrackedItemSchedule(item, "1h")
+
+	persisted := db.GetTrackedItem(item.ID)
+	require.NotNil(t, persisted)
+	assert.Equal(t, "42", persisted.CurrentItem)
+	assert.True(t, persisted.Favorite)
+	assert.Equal(t, 5, persisted.Priority)
+	assert.Equal(t, "1h", persisted.Schedule)
+
+	// searches match case-insensitive word prefixes and phrases of the full-text index
+	assert.Len(t, db.SearchTrackedItems([]string{"100% cats"}), 1)
+	assert.Len(t, db.SearchTrackedItems([]string{"ART", "cat"}), 1)
+	assert.Empty(t, db.SearchTrackedItems([]string{"cats 100"}))
+	assert.Empty(t, db.SearchTrackedItems([]string{"1000"}))
+	assert.Len(t, db.GetTrackedItemsByDomain("example.com/gallery", false), 1)
+
+	db.UpdateTrackedItemFailures(item, 2, 1)
+	persisted = db.GetTrackedItem(item.ID)
+	assert.Equal(t, 2, persisted.Failures)
+	assert.True(t, persisted.LastFailure.Valid)
+	assert.WithinDuration(t, time.Now(), persisted.LastFailure.Time, time.Minute)
+
+	db.ChangeTrackedItemQuarantineStatus(item, true)
+	assert.Empty(t, db.GetTrackedItems(module, false))
+	assert.Len(t, db.GetTrackedItems(module, true), 1)
+
+	db.ChangeTrackedItemQuarantineStatus(item, false)
+	db.ChangeTrackedItemCompleteStatus(item, true)
+	assert.Empty(t, db.GetTrackedItems(nil, false))
+
+	items := db.GetAllOrCreateTrackedItemIgnoreSubFolder("https://example.com/Gallery/1", module)
+	assert.Len(t, items, 1)
+
+	db.DeleteTrackedItem(item)
+	assert.Nil(t, db.GetTrackedItem(item.ID))
+}
+
+func testConformanceOAuthClients(t *testing.T, db *DbIO, module models.ModuleInterface) {
+	client := db.GetFirstOrCreateOAuthClient("client", "secret", "token", "refresh", module)
+	require.NotNil(t, client)
+	assert.Equal(t, "secret", client.ClientSecret)
+	assert.Equal(t, client.ID, db.GetFirstOrCreateOAuthClient("client", "secret", "token", "refresh", module).ID)
+
+	db.UpdateOAuthClient("client", "secret", "new-token", "new-refresh", module)
+	assert.Equal(t, "new-token", db.GetOAuthClient(module).AccessToken)
+
+	db.UpdateOAuthClientDisabledStatus("client", "new-token", true, module)
+	assert.Nil(t, db.GetOAuthClient(module))
+
+	db.DeleteOAuthClient("client", module)
+	assert.Empty(t, db.GetAllOAuthClients(nil))
+}
+
+func testConformanceCookies(t *testing.T, db *DbIO, module models.ModuleInterface) {
+	expiration := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	db.CreateCookie("session", "value", sql.NullTime{Time: expiration, Valid: true}, module)
+	db.CreateCookie("expired", "value", sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true}, module)
+	db.CreateCookie("session-only", "value", sql.NullTime{}, module)
+
+	cookie := db.GetCookie("session", module)
+	require.NotNil(t, cookie)
+	assert.Equal(t, "value", cookie.Value)
+	assert.True(t, expiration.Equal(cookie.Expiration.Time))
+	assert.Nil(t, db.GetCookie("expired", module))
+	assert.Len(t, db.GetAllCookies(module), 2)
+
+	db.UpdateCookie("session", "changed", "", module)
+	assert.Equal(t, "changed", db.GetCookie("session", module).Value)
+
+	db.UpdateCookieDisabledStatus("session", true, module)
+	assert.Nil(t, db.GetCookie("session", module))
+
+	db.DeleteCookie("session-only", module)
+	assert.Empty(t, db.GetAllCookies(nil))
+}
+
+func testConformanceRuns(t *testing.T, db *DbIO, module models.ModuleInterface) {
+	item := db.GetFirstOrCreateTrackedItem("https://example.com/runs", "", module)
+
+	run := db.CreateRun()
+	assert.NotZero(t, run.ID)
+
+	startedAt := time.Now().Add(-time.Minute).Truncate(time.Second)
+	for _, runError := range []string{"", "failed"} {
+		itemRun := &models.ItemRun{
+			RunID: run.ID, ItemID: item.ID, Module: module.ModuleKey(), URI: item.URI,
+			StartedAt: startedAt, FinishedAt: startedAt.Add(time.Second),
+			FilesDownloaded: 2, BytesDownloaded: 1 << 40, Error: runError,
+		}
+		db.CreateItemRun(itemRun)
+		assert.NotZero(t, itemRun.ID)
+	}
+
+	db.FinishRun(run)
+
+	runs := db.GetRuns(10)
+	require.Len(t, runs, 1)
+	assert.Equal(t, 2, runs[0].Items)
+	assert.Equal(t, 1, runs[0].FailedItems)
+	assert.Equal(t, 4, runs[0].FilesDownloaded)
+	assert.Equal(t, int64(2<<40), runs[0].BytesDownloaded)
+	assert.True(t, runs[0].FinishedAt.Valid)
+
+	itemRuns := db.GetItemRuns(item.ID, module, true, 10)
+	require.Len(t, itemRuns, 1)
+	assert.True(t, startedAt.Equal(itemRuns[0].StartedAt))
+
+	summaries := db.GetItemRunSummaries(nil)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, 2, summaries[0].Runs)
+	assert.Equal(t, 1, summaries[0].ConsecutiveFailures)
+
+	assert.True(t, startedAt.Equal(db.GetLastItemRunTimes()[item.ID]))
+}
+
+func testConformanceTags(t *testing.T, db *DbIO, module models.ModuleInterface) {
+	item := db.GetFirstOrCreateTrackedItem("https://example.com/tags", "", module)
+
+	db.AddTrackedItemTag(item, "Artists")
+	db.AddTrackedItemTag(item, "artists")
+	db.AddTrackedItemTag(item, "favorites")
+
+	assert.Equal(t, []string{"artists", "favorites"}, db.GetTrackedItemTags(item))
+	assert.Equal(t, map[int][]string{item.ID: {"artists", "favorites"}}, db.GetTrackedItemTagMap())
+	assert.Len(t, db.GetTags(), 2)
+
+	db.RemoveTrackedItemTag(item, "favorites")
+	assert.Equal(t, []string{"artists"}, db.GetTrackedItemTags(item))
+}
+
+func testConformanceHashes(t *testing.T, db *DbIO, module models.ModuleInterface) {
+	db.AddFileHash("a.jpg", "hash", 10, module.ModuleKey())
+	db.AddFileHash("b.jpg", "hash", 10, module.ModuleKey())
+	db.AddFileHash("b.jpg", "other", 10, module.ModuleKey())
+
+	assert.Equal(t, []string{"a.jpg"}, db.GetFilePathsByHash("hash", 10))
+	assert.Len(t, db.GetFileHashes(), 2)
+
+	db.DeleteFileHash("a.jpg")
+	assert.Empty(t, db.GetFilePathsByHash("hash", 10))
+
+	// hashes with the highest bit set are stored as negative signed integers
+	imageHash := &models.ImageHash{
+		Path: "a.jpg", PHash: 1<<63 + 1, DHash: 42, Size: 10,
+		ModifiedAt: time.Now().Truncate(time.Second), Module: module.ModuleKey(),
+	}
+	db.AddImageHash(imageHash)
+
+	imageHashes := db.GetImageHashes()
+	require.Len(t, imageHashes, 1)
+	assert.Equal(t, imageHash.PHash, imageHashes[0].PHash)
+	assert.True(t, imageHash.ModifiedAt.Equal(imageHashes[0].ModifiedAt))
+
+	db.DeleteImageHash("a.jpg")
+	assert.Empty(t, db.GetImageHashes())
+}
+
+func TestRebindNumbered(t *testing.T) {
+	assert.Equal(
+		t,
+		`SELECT * FROM t WHERE a = $1 AND b LIKE '?%' AND "c?" = $2`,
+		rebindNumbered(`SELECT * FROM t WHERE a = ? AND b LIKE '?%' AND "c?" = ?`),
+	)
+}
+
+func TestPostgresDriverRegistered(t *testing.T) {
+	// the connection is only established on the first query, so no server is required
+	connection, err := postgresBackend{}.Open("postgres://watcher@localhost:5432/watcher")
+	require.NoError(t, err)
+	require.NoError(t, connection.Close())
+
+	_, err = postgresBackend{}.Open("")
+	assert.Error(t, err)
+}
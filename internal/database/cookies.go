@@ -9,7 +9,7 @@ import (
 	"github.com/DaRealFreak/watcher-go/internal/raven"
 )
 
-func (db *DbIO) createCookiesTable(connection *sql.DB) (err error) {
+func createCookiesTable(connection executor) (err error) {
 	sqlStatement := `
 		CREATE TABLE cookies
 		(
@@ -35,10 +35,10 @@ func (db *DbIO) GetAllCookies(module models.ModuleInterface) (cookies []*models.
 	)
 
 	if module != nil {
-		stmt, err = db.connection.Prepare(`
+		stmt, err = db.prepare(`
 			SELECT * FROM cookies
 			WHERE NOT disabled
-			  AND (strftime('%s','now') < expiration OR expiration = 0)
+			  AND (` + db.backend.Now() + ` < expiration OR expiration = 0)
 			  AND module = ?
 			ORDER BY uid
 		`)
@@ -47,11 +47,11 @@ func (db *DbIO) GetAllCookies(module models.ModuleInterface) (cookies []*models.
 		rows, err = stmt.Query(module.ModuleKey())
 		raven.CheckError(err)
 	} else {
-		rows, err = db.connection.Query(`
+		rows, err = db.query(`
 			SELECT * 
 			FROM cookies 
 			WHERE NOT disabled
-			  AND (strftime('%s','now') < expiration OR expiration = 0)
+			  AND (` + db.backend.Now() + ` < expiration OR expiration = 0)
 			ORDER BY module, uid
 		`)
 	}
@@ -62,9 +62,11 @@ func (db *DbIO) GetAllCookies(module models.ModuleInterface) (cookies []*models.
 		var cookie models.Cookie
 
 		raven.CheckError(rows.Scan(
-			&cookie.ID, &cookie.Name, &cookie.Value, &cookie.Expiration, &cookie.Module, &cookie.Disabled,
+			&cookie.ID, &cookie.Name, &cookie.Value, nullUnixTime{&cookie.Expiration}, &cookie.Module, &cookie.Disabled,
 		))
 
+		cookie.Value = db.decryptSecret(cookie.Value)
+
 		cookies = append(cookies, &cookie)
 	}
 
@@ -73,10 +75,10 @@ func (db *DbIO) GetAllCookies(module models.ModuleInterface) (cookies []*models.
 
 // GetCookie retrieves a specific cookie associated to the passed module which is not expired or disabled
 func (db *DbIO) GetCookie(name string, module models.ModuleInterface) *models.Cookie {
-	stmt, err := db.connection.Prepare(`
+	stmt, err := db.prepare(`
 		SELECT * FROM cookies
 		WHERE NOT disabled
-		  AND (strftime('%s','now') < expiration OR expiration = 0)
+		  AND (` + db.backend.Now() + ` < expiration OR expiration = 0)
 		  AND name = ?
 		  AND module = ?
 		ORDER BY uid
@@ -92,9 +94,11 @@ func (db *DbIO) GetCookie(name string, module models.ModuleInterface) *models.Co
 		var cookie models.Cookie
 
 		raven.CheckError(rows.Scan(
-			&cookie.ID, &cookie.Name, &cookie.Value, &cookie.Expiration, &cookie.Module, &cookie.Disabled,
+			&cookie.ID, &cookie.Name, &cookie.Value, nullUnixTime{&cookie.Expiration}, &cookie.Module, &cookie.Disabled,
 		))
 
+		cookie.Value = db.decryptSecret(cookie.Value)
+
 		return &cookie
 	}
 
@@ -108,7 +112,7 @@ func (db *DbIO) GetFirstOrCreateCookie(
 ) *models.Cookie {
 	expiration := db.getNullTimeFromString(expirationString)
 
-	stmt, err := db.connection.Prepare("SELECT * FROM cookies WHERE name = ? AND module = ?")
+	stmt, err := db.prepare("SELECT * FROM cookies WHERE name = ? AND module = ?")
 	raven.CheckError(err)
 
 	rows, err := stmt.Query(name, module.ModuleKey())
@@ -120,9 +124,11 @@ func (db *DbIO) GetFirstOrCreateCookie(
 		var cookie models.Cookie
 
 		raven.CheckError(rows.Scan(
-			&cookie.ID, &cookie.Name, &cookie.Value, &cookie.Expiration, &cookie.Module, &cookie.Disabled,
+			&cookie.ID, &cookie.Name, &cookie.Value, nullUnixTime{&cookie.Expiration}, &cookie.Module, &cookie.Disabled,
 		))
 
+		cookie.Value = db.decryptSecret(cookie.Value)
+
 		return &cookie
 	}
 
@@ -134,12 +140,12 @@ func (db *DbIO) GetFirstOrCreateCookie(
 
 // CreateCookie inserts the passed name, value and expiration of the specific module into the cookies table
 func (db *DbIO) CreateCookie(name string, value string, expiration sql.NullTime, module models.ModuleInterface) {
-	stmt, err := db.connection.Prepare("INSERT INTO cookies (name, value, expiration, module) VALUES (?, ?, ?, ?)")
+	stmt, err := db.prepare("INSERT INTO cookies (name, value, expiration, module) VALUES (?, ?, ?, ?)")
 	raven.CheckError(err)
 
 	defer raven.CheckClosure(stmt)
 
-	_, err = stmt.Exec(name, value, db.getUnixTimestampFromNullTime(expiration), module.ModuleKey())
+	_, err = stmt.Exec(name, db.encryptSecret(value), db.getUnixTimestampFromNullTime(expiration), module.ModuleKey())
 	raven.CheckError(err)
 }
 
@@ -147,35 +153,43 @@ func (db *DbIO) CreateCookie(name string, value string, expiration sql.NullTime,
 func (db *DbIO) UpdateCookie(name string, value string, expirationString string, module models.ModuleInterface) {
 	expiration := db.getNullTimeFromString(expirationString)
 
-	stmt, err := db.connection.Prepare("UPDATE cookies SET value = ?, expiration = ? WHERE name = ? AND module = ?")
+	stmt, err := db.prepare("UPDATE cookies SET value = ?, expiration = ? WHERE name = ? AND module = ?")
 	raven.CheckError(err)
 
 	defer raven.CheckClosure(stmt)
 
-	_, err = stmt.Exec(value, db.getUnixTimestampFromNullTime(expiration), name, module.ModuleKey())
+	_, err = stmt.Exec(db.encryptSecret(value), db.getUnixTimestampFromNullTime(expiration), name, module.ModuleKey())
 	raven.CheckError(err)
 }
 
 // UpdateCookieDisabledStatus disables or enables the cookie of the passed user/module
 func (db *DbIO) UpdateCookieDisabledStatus(name string, disabled bool, module models.ModuleInterface) {
-	var disabledInt int8
+	stmt, err := db.prepare("UPDATE cookies SET disabled = ? WHERE name = ? AND module = ?")
+	raven.CheckError(err)
 
-	if disabled {
-		disabledInt = 1
-	} else {
-		disabledInt = 0
-	}
+	defer raven.CheckClosure(stmt)
+
+	_, err = stmt.Exec(disabled, name, module.ModuleKey())
+	raven.CheckError(err)
+}
 
-	stmt, err := db.connection.Prepare("UPDATE cookies SET disabled = ? WHERE name = ? AND module = ?")
+// DeleteCookie deletes the cookie of the passed name/module
+func (db *DbIO) DeleteCookie(name string, module models.ModuleInterface) {
+	stmt, err := db.prepare("DELETE FROM cookies WHERE name = ? AND module = ?")
 	raven.CheckError(err)
 
 	defer raven.CheckClosure(stmt)
 
-	_, err = stmt.Exec(disabledInt, name, module.ModuleKey())
+	_, err = stmt.Exec(name, module.ModuleKey())
 	raven.CheckError(err)
 }
 
 func (db *DbIO) getNullTimeFromString(timeString string) sql.NullTime {
+	return CookieExpiration(timeString)
+}
+
+// CookieExpiration parses the passed expiration of a cookie, returns an invalid NullTime for unknown formats
+func CookieExpiration(timeString string) sql.NullTime {
 	supportedLayouts := []string{
 		time.ANSIC,
 		time.UnixDate,
@@ -1,47 +1,99 @@
-// Package database is the implementation of the DatabaseInterface using SQLite3
+// Package database is the implementation of the DatabaseInterface using SQLite3 or PostgreSQL
 package database
 
 import (
 	"database/sql"
 	"os"
+	"slices"
 
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/DaRealFreak/watcher-go/internal/raven"
+	"github.com/DaRealFreak/watcher-go/internal/secrets"
 	"github.com/spf13/viper"
-
-	// import for side effects
-	_ "github.com/mattn/go-sqlite3"
 )
 
 // DbIO implements the DatabaseInterface and contains the connection to the database
 type DbIO struct {
 	models.DatabaseInterface
 	connection *sql.DB
+	// driver is the name of the backend, backend contains the differences between the database systems
+	driver  string
+	dsn     string
+	backend Backend
+	// secrets encrypts the credentials of the accounts, OAuth clients and cookies, nil stores them in plaintext
+	secrets *secrets.Cipher
 }
 
-// NewConnection initializes the database DatabaseConnection to our sqlite file.
-// Creates the database if the looked up file doesn't exist yet
+// NewConnection initializes the database connection of the backend configured in the database.driver setting
+// (SQLite by default) with the data source name of the database.dsn setting.
+// Creates the tables if the database doesn't contain them yet
 func NewConnection() *DbIO {
-	dbIO := DbIO{}
+	return openConnection(true)
+}
+
+// NewConnectionWithoutAutoMigrate initializes the database connection like NewConnection
+// but never applies pending migrations, used by the commands managing the migrations themselves
+func NewConnectionWithoutAutoMigrate() *DbIO {
+	return openConnection(false)
+}
 
-	if _, err := os.Stat(viper.GetString("Database.Path")); os.IsNotExist(err) {
-		dbIO.createDatabase()
+// openConnection opens the database configured in the database.driver and database.dsn settings
+func openConnection(autoMigrate bool) *DbIO {
+	driver := viper.GetString("database.driver")
+	if driver == "" {
+		driver = SQLiteDriver
 	}
 
-	db, err := sql.Open("sqlite3", viper.GetString("Database.Path")+"?_journal=WAL")
+	dbIO, err := newConnection(driver, viper.GetString("database.dsn"), autoMigrate)
 	raven.CheckError(err)
 
-	dbIO.connection = db
+	return dbIO
+}
+
+// newConnection opens the database of the passed backend and data source name and creates the schema.
+// Pending migrations of existing databases are only applied if autoMigrate is set
+func newConnection(driver string, dsn string, autoMigrate bool) (*DbIO, error) {
+	backend, err := GetBackend(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	connection, err := backend.Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	cipher, err := secrets.Default()
+	if err != nil {
+		raven.CheckClosure(connection)
+		return nil, err
+	}
+
+	dbIO := &DbIO{connection: connection, driver: driver, dsn: dsn, backend: backend, secrets: cipher}
+
+	tableNames, err := backend.TableNames(connection)
+	if err != nil {
+		raven.CheckClosure(connection)
+		return nil, err
+	}
+
+	// databases without the tracked items table are new databases
+	created := !slices.Contains(tableNames, "tracked_items")
+	if created {
+		if err = backend.CreateSchema(connection); err != nil {
+			raven.CheckClosure(connection)
+			return nil, err
+		}
+	}
 
-	dbIO.migrate()
+	dbIO.initMigrations(created, autoMigrate)
 
-	return &dbIO
+	return dbIO, nil
 }
 
-// migrate runs all idempotent schema migrations to bring older databases up to the
-// current expected shape. Called on every connection open.
-func (db *DbIO) migrate() {
-	db.migrateTrackedItemsTable()
+// Driver returns the name of the backend of the database connection
+func (db *DbIO) Driver() string {
+	return db.driver
 }
 
 // CloseConnection safely closes the database connection
@@ -58,16 +110,3 @@ func RemoveDatabase() {
 		}
 	}
 }
-
-// createDatabase creates the sqlite file and creates the required tables
-func (db *DbIO) createDatabase() {
-	connection, err := sql.Open("sqlite3", viper.GetString("Database.Path")+"?_journal=WAL")
-	raven.CheckError(err)
-
-	defer raven.CheckClosure(connection)
-
-	raven.CheckError(db.createAccountsTable(connection))
-	raven.CheckError(db.createTrackedItemsTable(connection))
-	raven.CheckError(db.createOAuthClientsTable(connection))
-	raven.CheckError(db.createCookiesTable(connection))
-}
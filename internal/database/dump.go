@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 
 	"github.com/DaRealFreak/watcher-go/internal/raven"
@@ -18,6 +19,10 @@ type tableSchema struct {
 
 // DumpTables dumps the database tableSchema and the inserts to the passed writer
 func (db *DbIO) DumpTables(writer io.Writer, tableNames ...string) (err error) {
+	if db.driver != SQLiteDriver {
+		return fmt.Errorf("dumping tables is not supported by the %s database driver", db.driver)
+	}
+
 	if _, err = writer.Write([]byte("BEGIN TRANSACTION;\n")); err != nil {
 		return err
 	}
@@ -28,6 +33,15 @@ func (db *DbIO) DumpTables(writer io.Writer, tableNames ...string) (err error) {
 	}
 
 	for _, currentTableSchema := range tableSchemas {
+		// indexes and triggers are dropped together with their table and only have to be recreated
+		if currentTableSchema.Type != "table" {
+			if _, err = writer.Write([]byte(currentTableSchema.SQL + ";\n")); err != nil {
+				return err
+			}
+
+			continue
+		}
+
 		if _, err = writer.Write([]byte("DROP TABLE IF EXISTS " + currentTableSchema.Name + ";\n")); err != nil {
 			return err
 		}
@@ -37,7 +51,7 @@ func (db *DbIO) DumpTables(writer io.Writer, tableNames ...string) (err error) {
 		}
 
 		var inserts []string
-		inserts, err = db.getTableRows(currentTableSchema.Name)
+		inserts, err = db.getTableRows(currentTableSchema.Name, "")
 		if err != nil {
 			return err
 		}
@@ -49,13 +63,27 @@ func (db *DbIO) DumpTables(writer io.Writer, tableNames ...string) (err error) {
 		}
 	}
 
+	// the full-text index isn't dumped, so it gets rebuilt from the restored tracked items
+	if slices.Contains(tableNames, "tracked_items") {
+		var hasNotesIndex bool
+		if hasNotesIndex, err = db.hasNotesIndex(); err != nil {
+			return err
+		}
+
+		if hasNotesIndex {
+			if _, err = writer.Write([]byte(notesIndexRebuild + ";\n")); err != nil {
+				return err
+			}
+		}
+	}
+
 	_, err = writer.Write([]byte("COMMIT;\n"))
 
 	return err
 }
 
-// getTableRows returns the insert queries for the passed table
-func (db *DbIO) getTableRows(tableName string) (inserts []string, err error) {
+// getTableRows returns the insert queries for the passed table, optionally limited by the passed WHERE condition
+func (db *DbIO) getTableRows(tableName string, condition string) (inserts []string, err error) {
 	// first get the column names
 	columnNames, err := db.getPragmaTableInfo(tableName)
 	if err != nil {
@@ -71,11 +99,12 @@ func (db *DbIO) getTableRows(tableName string) (inserts []string, err error) {
 	// create insert queries with the pragma table info, so we can't use static queries here
 	// #nosec
 	q := fmt.Sprintf(`
-		SELECT 'INSERT INTO "%s" VALUES(%s);' FROM "%s";
+		SELECT 'INSERT INTO "%s" VALUES(%s);' FROM "%s" %s;
 	`,
 		tableName,
 		strings.Join(columnSelects, ","),
 		tableName,
+		condition,
 	)
 
 	stmt, err := db.connection.Prepare(q)
@@ -162,7 +191,8 @@ func (db *DbIO) getPragmaTableInfo(tableName string) (columnNames []string, err
 	return columnNames, err
 }
 
-// getSchemas returns the available schemas, optional parameter names to specify f.e. tables
+// getSchemas returns the available schemas, optional parameter names to specify the tables.
+// The tables are returned first, so their indexes and triggers are created after inserting the rows
 func (db *DbIO) getSchemas(names ...string) (schemas []*tableSchema, err error) {
 	tableNames := make([]interface{}, len(names))
 	for i, v := range names {
@@ -173,10 +203,10 @@ func (db *DbIO) getSchemas(names ...string) (schemas []*tableSchema, err error)
 			  FROM "sqlite_master"
 			  WHERE "sql" NOT NULL
 			  %s
-			  ORDER BY "name"`
+			  ORDER BY "type" = 'table' DESC, "name"`
 	if len(names) > 0 {
 		query = fmt.Sprintf(query,
-			"AND name IN ("+
+			"AND tbl_name IN ("+
 				strings.TrimSuffix(
 					strings.Repeat("?,", len(names)),
 					",",
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"github.com/DaRealFreak/watcher-go/internal/models"
+	"github.com/DaRealFreak/watcher-go/internal/raven"
+)
+
+// createFileHashesTable creates the content index of the downloaded files
+func createFileHashesTable(connection executor) (err error) {
+	sqlStatement := `
+		CREATE TABLE IF NOT EXISTS file_hashes
+		(
+			uid        INTEGER PRIMARY KEY AUTOINCREMENT,
+			path       TEXT         DEFAULT '' NOT NULL UNIQUE,
+			sha256     CHAR(64)     DEFAULT '' NOT NULL,
+			size       INTEGER      DEFAULT 0 NOT NULL,
+			module     VARCHAR(255) DEFAULT '' NOT NULL,
+			created_at DATETIME     DEFAULT (strftime('%s','now')) NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS file_hashes_sha256 ON file_hashes (sha256, size);
+	`
+	_, err = connection.Exec(sqlStatement)
+
+	return err
+}
+
+// AddFileHash adds the passed file to the content index or updates the hash if the path is already indexed
+func (db *DbIO) AddFileHash(path string, sha256 string, size int64, module string) {
+	stmt, err := db.prepare(`
+		INSERT INTO file_hashes (path, sha256, size, module) VALUES (?, ?, ?, ?)
+		ON CONFLICT (path) DO UPDATE SET sha256 = excluded.sha256, size = excluded.size, module = excluded.module
+	`)
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	_, err = stmt.Exec(path, sha256, size, module)
+	raven.CheckError(err)
+}
+
+// GetFilePathsByHash returns the paths of all indexed files with the passed hash and size
+func (db *DbIO) GetFilePathsByHash(sha256 string, size int64) (paths []string) {
+	stmt, err := db.prepare("SELECT path FROM file_hashes WHERE sha256 = ? AND size = ? ORDER BY uid")
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	rows, err := stmt.Query(sha256, size)
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(rows)
+
+	for rows.Next() {
+		var path string
+		raven.CheckError(rows.Scan(&path))
+		paths = append(paths, path)
+	}
+
+	raven.CheckError(rows.Err())
+
+	return paths
+}
+
+// GetFileHashes returns all indexed files
+func (db *DbIO) GetFileHashes() (fileHashes []*models.FileHash) {
+	rows, err := db.query("SELECT uid, path, sha256, size, module FROM file_hashes ORDER BY uid")
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(rows)
+
+	for rows.Next() {
+		fileHash := new(models.FileHash)
+		raven.CheckError(rows.Scan(&fileHash.ID, &fileHash.Path, &fileHash.SHA256, &fileHash.Size, &fileHash.Module))
+		fileHashes = append(fileHashes, fileHash)
+	}
+
+	raven.CheckError(rows.Err())
+
+	return fileHashes
+}
+
+// DeleteFileHash removes the passed path from the content index
+func (db *DbIO) DeleteFileHash(path string) {
+	stmt, err := db.prepare("DELETE FROM file_hashes WHERE path = ?")
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	_, err = stmt.Exec(path)
+	raven.CheckError(err)
+}
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/models"
+	"github.com/DaRealFreak/watcher-go/internal/raven"
+)
+
+// createImageHashesTable creates the perceptual hash index of the downloaded pictures
+func createImageHashesTable(connection executor) (err error) {
+	sqlStatement := `
+		CREATE TABLE IF NOT EXISTS image_hashes
+		(
+			uid         INTEGER PRIMARY KEY AUTOINCREMENT,
+			path        TEXT         DEFAULT '' NOT NULL UNIQUE,
+			phash       INTEGER      DEFAULT 0 NOT NULL,
+			dhash       INTEGER      DEFAULT 0 NOT NULL,
+			size        INTEGER      DEFAULT 0 NOT NULL,
+			modified_at INTEGER      DEFAULT 0 NOT NULL,
+			module      VARCHAR(255) DEFAULT '' NOT NULL
+		);
+	`
+	_, err = connection.Exec(sqlStatement)
+
+	return err
+}
+
+// AddImageHash adds the passed picture to the perceptual hash index or updates the hashes if the path is already indexed
+func (db *DbIO) AddImageHash(imageHash *models.ImageHash) {
+	stmt, err := db.prepare(`
+		INSERT INTO image_hashes (path, phash, dhash, size, modified_at, module) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (path) DO UPDATE SET
+			phash = excluded.phash, dhash = excluded.dhash, size = excluded.size,
+			modified_at = excluded.modified_at, module = excluded.module
+	`)
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	// SQLite only supports signed 64-bit integers, so the hashes are stored with their bits reinterpreted
+	_, err = stmt.Exec(
+		imageHash.Path,
+		int64(imageHash.PHash),
+		int64(imageHash.DHash),
+		imageHash.Size,
+		imageHash.ModifiedAt.Unix(),
+		imageHash.Module,
+	)
+	raven.CheckError(err)
+}
+
+// GetImageHashes returns all indexed pictures
+func (db *DbIO) GetImageHashes() (imageHashes []*models.ImageHash) {
+	rows, err := db.query(
+		"SELECT uid, path, phash, dhash, size, modified_at, module FROM image_hashes ORDER BY uid",
+	)
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(rows)
+
+	for rows.Next() {
+		var (
+			pHash      int64
+			dHash      int64
+			modifiedAt int64
+		)
+
+		imageHash := new(models.ImageHash)
+		raven.CheckError(rows.Scan(
+			&imageHash.ID, &imageHash.Path, &pHash, &dHash, &imageHash.Size, &modifiedAt, &imageHash.Module,
+		))
+
+		imageHash.PHash = uint64(pHash)
+		imageHash.DHash = uint64(dHash)
+		imageHash.ModifiedAt = time.Unix(modifiedAt, 0)
+		imageHashes = append(imageHashes, imageHash)
+	}
+
+	raven.CheckError(rows.Err())
+
+	return imageHashes
+}
+
+// DeleteImageHash removes the passed path from the perceptual hash index
+func (db *DbIO) DeleteImageHash(path string) {
+	stmt, err := db.prepare("DELETE FROM image_hashes WHERE path = ?")
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	_, err = stmt.Exec(path)
+	raven.CheckError(err)
+}
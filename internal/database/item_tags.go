@@ -0,0 +1,109 @@
+package database
+
+import (
+	"github.com/DaRealFreak/watcher-go/internal/models"
+	"github.com/DaRealFreak/watcher-go/internal/raven"
+)
+
+// createItemTagsTable creates the many-to-many table between tracked items and tags
+func createItemTagsTable(connection executor) (err error) {
+	sqlStatement := `
+		CREATE TABLE IF NOT EXISTS item_tags
+		(
+			item_id INTEGER      DEFAULT 0 NOT NULL,
+			tag     VARCHAR(255) DEFAULT '' NOT NULL,
+			PRIMARY KEY (item_id, tag)
+		);
+		CREATE INDEX IF NOT EXISTS item_tags_tag ON item_tags (tag);
+	`
+	_, err = connection.Exec(sqlStatement)
+
+	return err
+}
+
+// AddTrackedItemTag assigns the passed tag to the passed tracked item, assigning a tag twice has no effect
+func (db *DbIO) AddTrackedItemTag(trackedItem *models.TrackedItem, tag string) {
+	stmt, err := db.prepare("INSERT INTO item_tags (item_id, tag) VALUES (?, ?) ON CONFLICT DO NOTHING")
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	_, err = stmt.Exec(trackedItem.ID, models.NormalizeTag(tag))
+	raven.CheckError(err)
+}
+
+// RemoveTrackedItemTag removes the passed tag from the passed tracked item
+func (db *DbIO) RemoveTrackedItemTag(trackedItem *models.TrackedItem, tag string) {
+	stmt, err := db.prepare("DELETE FROM item_tags WHERE item_id = ? AND tag = ?")
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	_, err = stmt.Exec(trackedItem.ID, models.NormalizeTag(tag))
+	raven.CheckError(err)
+}
+
+// GetTrackedItemTags returns the alphabetically sorted tags of the passed tracked item
+func (db *DbIO) GetTrackedItemTags(trackedItem *models.TrackedItem) (tags []string) {
+	stmt, err := db.prepare("SELECT tag FROM item_tags WHERE item_id = ? ORDER BY tag")
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	rows, err := stmt.Query(trackedItem.ID)
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(rows)
+
+	for rows.Next() {
+		var tag string
+		raven.CheckError(rows.Scan(&tag))
+		tags = append(tags, tag)
+	}
+
+	raven.CheckError(rows.Err())
+
+	return tags
+}
+
+// GetTrackedItemTagMap returns the alphabetically sorted tags of all tagged items, keyed by the tracked item ID
+func (db *DbIO) GetTrackedItemTagMap() map[int][]string {
+	rows, err := db.query("SELECT item_id, tag FROM item_tags ORDER BY item_id, tag")
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(rows)
+
+	tagMap := make(map[int][]string)
+
+	for rows.Next() {
+		var (
+			itemID int
+			tag    string
+		)
+
+		raven.CheckError(rows.Scan(&itemID, &tag))
+		tagMap[itemID] = append(tagMap[itemID], tag)
+	}
+
+	raven.CheckError(rows.Err())
+
+	return tagMap
+}
+
+// GetTags returns all assigned tags with the amount of tracked items they are assigned to
+func (db *DbIO) GetTags() (tags []*models.Tag) {
+	rows, err := db.query("SELECT tag, COUNT(*) FROM item_tags GROUP BY tag ORDER BY tag")
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(rows)
+
+	for rows.Next() {
+		tag := new(models.Tag)
+		raven.CheckError(rows.Scan(&tag.Name, &tag.Items))
+		tags = append(tags, tag)
+	}
+
+	raven.CheckError(rows.Err())
+
+	return tags
+}
@@ -0,0 +1,39 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/DaRealFreak/watcher-go/internal/models"
+	"github.com/DaRealFreak/watcher-go/internal/modules"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemTags(t *testing.T) {
+	module := modules.GetModuleFactory().GetAllModules()[0]
+	item := dbIO.GetFirstOrCreateTrackedItem("https://chan.sankakucomplex.com/?tags=item_tags", "", module)
+	otherItem := dbIO.GetFirstOrCreateTrackedItem("https://chan.sankakucomplex.com/?tags=item_tags_2", "", module)
+
+	dbIO.AddTrackedItemTag(item, "Nightly")
+	dbIO.AddTrackedItemTag(item, " nightly ")
+	dbIO.AddTrackedItemTag(item, "collector-a")
+	dbIO.AddTrackedItemTag(otherItem, "nightly")
+
+	assert.Equal(t, []string{"collector-a", "nightly"}, dbIO.GetTrackedItemTags(item))
+	assert.Equal(t, []string{"collector-a", "nightly"}, dbIO.GetTrackedItemTagMap()[item.ID])
+
+	tags := make(map[string]int)
+	for _, tag := range dbIO.GetTags() {
+		tags[tag.Name] = tag.Items
+	}
+
+	assert.Equal(t, 2, tags["nightly"])
+	assert.Equal(t, 1, tags["collector-a"])
+
+	dbIO.RemoveTrackedItemTag(item, "NIGHTLY")
+	assert.Equal(t, []string{"collector-a"}, dbIO.GetTrackedItemTags(item))
+
+	// tags of deleted items are removed as well
+	dbIO.DeleteTrackedItem(otherItem)
+	assert.Empty(t, dbIO.GetTrackedItemTags(otherItem))
+	assert.Equal(t, "nightly", models.NormalizeTag(" NightLy"))
+}
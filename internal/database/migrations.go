@@ -0,0 +1,378 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/raven"
+	"github.com/spf13/viper"
+)
+
+// executor is implemented by the database connection and by transactions
+type executor interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// Migration is a numbered change of the database schema. Up applies the change, Down reverts it.
+// Both are executed in a transaction together with the update of the schema_migrations table.
+// Migrations without Down can't be reverted
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(db *DbIO, tx *sql.Tx) error
+	Down    func(db *DbIO, tx *sql.Tx) error
+}
+
+// MigrationStatus contains a known migration and whether it is applied to the database
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// migrations are all schema migrations ordered by their version.
+// The create statements of the tables always represent the latest version, so new databases are marked as
+// fully migrated on creation. New migrations therefore have to update the create statements as well
+var migrations = []Migration{
+	{Version: 1, Name: "baseline of the schema before versioned migrations", Up: (*DbIO).migrateBaseline},
+	{
+		Version: 2,
+		Name:    "change log for incremental backups",
+		Up:      (*DbIO).migrateChangeLog,
+		Down:    (*DbIO).revertChangeLog,
+	},
+	{
+		Version: 3,
+		Name:    "full-text index of the notes",
+		Up:      (*DbIO).migrateNotesIndex,
+		Down:    (*DbIO).revertNotesIndex,
+	},
+}
+
+// LatestSchemaVersion returns the version of the latest known migration
+func LatestSchemaVersion() int {
+	return latestVersion(migrations)
+}
+
+// latestVersion returns the version of the last of the passed migrations
+func latestVersion(migrations []Migration) int {
+	if len(migrations) == 0 {
+		return 0
+	}
+
+	return migrations[len(migrations)-1].Version
+}
+
+// schemaVersionPinTable is the create statement of the table containing the schema version
+// the database got explicitly downgraded to, which prevents the automatic migrations from applying it again
+const schemaVersionPinTable = `
+	CREATE TABLE IF NOT EXISTS schema_version_pin
+	(
+		version INTEGER PRIMARY KEY
+	);
+`
+
+// createSchemaMigrationsTable creates the table containing the applied migrations and the pinned schema version
+func createSchemaMigrationsTable(connection executor) (err error) {
+	sqlStatement := `
+		CREATE TABLE IF NOT EXISTS schema_migrations
+		(
+			version    INTEGER PRIMARY KEY,
+			name       VARCHAR(255) DEFAULT '' NOT NULL,
+			applied_at DATETIME     DEFAULT (strftime('%s','now')) NOT NULL
+		);
+	`
+	if _, err = connection.Exec(sqlStatement); err != nil {
+		return err
+	}
+
+	_, err = connection.Exec(schemaVersionPinTable)
+
+	return err
+}
+
+// migrateBaseline brings databases created before the versioned migrations existed to the shape of the first version.
+// Only SQLite databases can predate the versioned migrations. The steps are idempotent since older databases
+// may already contain parts of the schema
+func (db *DbIO) migrateBaseline(tx *sql.Tx) error {
+	if err := db.migrateTrackedItemsTable(tx); err != nil {
+		return err
+	}
+
+	for _, createTable := range []func(connection executor) error{
+		createRunsTable,
+		createItemRunsTable,
+		createItemTagsTable,
+		createFileHashesTable,
+		createImageHashesTable,
+	} {
+		if err := createTable(tx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// initMigrations creates the schema_migrations table and applies the pending migrations if autoMigrate is set
+// and the database.auto_migrate setting isn't disabled. Newly created databases are marked as fully migrated.
+// Databases which got explicitly downgraded are not migrated until they get migrated up explicitly again
+func (db *DbIO) initMigrations(created bool, autoMigrate bool) {
+	raven.CheckError(db.backend.CreateSchemaMigrationsTable(db.connection))
+
+	if created {
+		raven.CheckError(db.markMigrated(migrations))
+		return
+	}
+
+	if !autoMigrate {
+		return
+	}
+
+	pinnedVersion, pinned, err := db.PinnedSchemaVersion()
+	raven.CheckError(err)
+
+	if pinned {
+		slog.Warn(fmt.Sprintf(
+			"database schema got downgraded to version %d, run \"watcher db migrate\" to migrate to version %d",
+			pinnedVersion, LatestSchemaVersion(),
+		))
+
+		return
+	}
+
+	if !viper.IsSet("database.auto_migrate") || viper.GetBool("database.auto_migrate") {
+		raven.CheckError(db.Migrate(LatestSchemaVersion()))
+		return
+	}
+
+	version, err := db.SchemaVersion()
+	raven.CheckError(err)
+
+	if version < LatestSchemaVersion() {
+		slog.Warn(fmt.Sprintf(
+			"database schema version %d is outdated, run \"watcher db migrate\" to migrate to version %d",
+			version, LatestSchemaVersion(),
+		))
+	}
+}
+
+// markMigrated marks all passed migrations as applied without running them
+func (db *DbIO) markMigrated(migrations []Migration) error {
+	for _, migration := range migrations {
+		if _, err := db.exec(
+			"INSERT INTO schema_migrations (version, name) VALUES (?, ?) ON CONFLICT DO NOTHING",
+			migration.Version, migration.Name,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SchemaVersion returns the version of the latest applied migration, 0 if no migration is applied
+func (db *DbIO) SchemaVersion() (version int, err error) {
+	err = db.queryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+
+	return version, err
+}
+
+// PinnedSchemaVersion returns the schema version the database got explicitly downgraded to.
+// pinned is false if the database wasn't downgraded or got migrated to the latest version again
+func (db *DbIO) PinnedSchemaVersion() (version int, pinned bool, err error) {
+	err = db.queryRow("SELECT version FROM schema_version_pin").Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+
+	return version, err == nil, err
+}
+
+// pinSchemaVersion pins the passed target version if it is older than the latest version, else removes the pin
+func (db *DbIO) pinSchemaVersion(target int, latest int) error {
+	if _, err := db.exec("DELETE FROM schema_version_pin"); err != nil {
+		return err
+	}
+
+	if target >= latest {
+		return nil
+	}
+
+	_, err := db.exec("INSERT INTO schema_version_pin (version) VALUES (?)", target)
+
+	return err
+}
+
+// MigrationStatus returns all known migrations and whether they are applied to the database
+func (db *DbIO) MigrationStatus() ([]MigrationStatus, error) {
+	return db.migrationStatus(migrations)
+}
+
+// migrationStatus returns the status of the passed migrations
+func (db *DbIO) migrationStatus(migrations []Migration) (statuses []MigrationStatus, err error) {
+	rows, err := db.query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	defer raven.CheckClosure(rows)
+
+	appliedAt := make(map[int]time.Time)
+
+	for rows.Next() {
+		var (
+			version int
+			applied time.Time
+		)
+
+		if err = rows.Scan(&version, unixTime{&applied}); err != nil {
+			return nil, err
+		}
+
+		appliedAt[version] = applied
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, migration := range migrations {
+		applied, ok := appliedAt[migration.Version]
+		statuses = append(statuses, MigrationStatus{Migration: migration, Applied: ok, AppliedAt: applied})
+	}
+
+	return statuses, nil
+}
+
+// Migrate applies or reverts the migrations until the database reaches the passed schema version.
+// For SQLite databases a backup of all tables is dumped next to the database file before changing the schema
+func (db *DbIO) Migrate(target int) error {
+	return db.migrate(migrations, target)
+}
+
+// migrate applies or reverts the passed migrations until the database reaches the passed schema version
+func (db *DbIO) migrate(migrations []Migration, target int) error {
+	if target < 0 || target > latestVersion(migrations) {
+		return fmt.Errorf("unknown schema version %d, latest version is %d", target, latestVersion(migrations))
+	}
+
+	statuses, err := db.migrationStatus(migrations)
+	if err != nil {
+		return err
+	}
+
+	var (
+		apply  []Migration
+		revert []Migration
+	)
+
+	for _, status := range statuses {
+		switch {
+		case !status.Applied && status.Version <= target:
+			apply = append(apply, status.Migration)
+		case status.Applied && status.Version > target:
+			if status.Down == nil {
+				return fmt.Errorf("migration %d (%s) can't be reverted", status.Version, status.Name)
+			}
+
+			// revert the migrations in reverse order
+			revert = append([]Migration{status.Migration}, revert...)
+		}
+	}
+
+	if len(apply) == 0 && len(revert) == 0 {
+		return db.pinSchemaVersion(target, latestVersion(migrations))
+	}
+
+	version, err := db.SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	// dumping the tables is only supported for SQLite, other database systems have their own backup tools
+	if db.driver == SQLiteDriver {
+		backupPath, backupErr := db.backupBeforeMigration(version)
+		if backupErr != nil {
+			return fmt.Errorf("unable to back up the database before migrating: %w", backupErr)
+		}
+
+		slog.Info(fmt.Sprintf("backed up the database to \"%s\" before migrating", backupPath))
+	}
+
+	for _, migration := range revert {
+		if err = db.runMigration(migration, migration.Down, "DELETE FROM schema_migrations WHERE version = ?"); err != nil {
+			return fmt.Errorf("unable to revert migration %d (%s): %w", migration.Version, migration.Name, err)
+		}
+
+		slog.Info(fmt.Sprintf("reverted migration %d (%s)", migration.Version, migration.Name))
+	}
+
+	for _, migration := range apply {
+		if err = db.runMigration(
+			migration, migration.Up, "INSERT INTO schema_migrations (version, name) VALUES (?, ?)", migration.Name,
+		); err != nil {
+			return fmt.Errorf("unable to apply migration %d (%s): %w", migration.Version, migration.Name, err)
+		}
+
+		slog.Info(fmt.Sprintf("applied migration %d (%s)", migration.Version, migration.Name))
+	}
+
+	// explicit downgrades are kept until the database gets migrated to the latest version again
+	return db.pinSchemaVersion(target, latestVersion(migrations))
+}
+
+// runMigration runs the passed migration step and the update of the schema_migrations table in a transaction
+func (db *DbIO) runMigration(
+	migration Migration, step func(db *DbIO, tx *sql.Tx) error, statement string, args ...any,
+) error {
+	tx, err := db.connection.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err = step(db, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.Exec(db.backend.Rebind(statement), append([]any{migration.Version}, args...)...); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// backupBeforeMigration dumps all tables into an SQL file next to the database file and returns the path of it
+func (db *DbIO) backupBeforeMigration(version int) (string, error) {
+	tableNames, err := db.getTableNames()
+	if err != nil {
+		return "", err
+	}
+
+	backupPath := fmt.Sprintf("%s.v%d-%s.sql", sqlitePath(db.dsn), version, time.Now().Format("20060102-150405"))
+
+	// the dump contains the credentials, so only the user is allowed to read it
+	file, err := os.OpenFile(backupPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", err
+	}
+
+	if err = db.DumpTables(file, tableNames...); err != nil {
+		raven.CheckClosure(file)
+		return "", err
+	}
+
+	return backupPath, file.Close()
+}
+
+// getTableNames returns the names of all tables in the database
+func (db *DbIO) getTableNames() ([]string, error) {
+	return db.backend.TableNames(db.connection)
+}
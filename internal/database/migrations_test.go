@@ -0,0 +1,261 @@
+package database
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// removeMigrationBackups removes the SQL dumps created before the migrations of the passed database file
+func removeMigrationBackups(t *testing.T, databasePath string) {
+	backups, err := filepath.Glob(databasePath + ".v*.sql")
+	assert.NoError(t, err)
+
+	for _, backup := range backups {
+		assert.NoError(t, os.Remove(backup))
+	}
+}
+
+func TestMigrateNewDatabase(t *testing.T) {
+	version, err := dbIO.SchemaVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, LatestSchemaVersion(), version)
+
+	statuses, err := dbIO.MigrationStatus()
+	assert.NoError(t, err)
+	assert.Len(t, statuses, len(migrations))
+
+	for _, status := range statuses {
+		assert.True(t, status.Applied)
+	}
+}
+
+func TestMigrateToVersion(t *testing.T) {
+	defer removeMigrationBackups(t, viper.GetString("Database.Path"))
+
+	testMigrations := append(append([]Migration{}, migrations...), Migration{
+		Version: LatestSchemaVersion() + 1,
+		Name:    "test table",
+		Up: func(db *DbIO, tx *sql.Tx) error {
+			_, err := tx.Exec("CREATE TABLE migration_test (uid INTEGER PRIMARY KEY)")
+			return err
+		},
+		Down: func(db *DbIO, tx *sql.Tx) error {
+			_, err := tx.Exec("DROP TABLE migration_test")
+			return err
+		},
+	})
+
+	// apply the new migration
+	assert.NoError(t, dbIO.migrate(testMigrations, latestVersion(testMigrations)))
+
+	version, err := dbIO.SchemaVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, latestVersion(testMigrations), version)
+
+	tableNames, err := dbIO.getTableNames()
+	assert.NoError(t, err)
+	assert.Contains(t, tableNames, "migration_test")
+
+	backups, err := filepath.Glob(viper.GetString("Database.Path") + ".v*.sql")
+	assert.NoError(t, err)
+	assert.Len(t, backups, 1)
+
+	// migrating to the current version is a no-op
+	assert.NoError(t, dbIO.migrate(testMigrations, latestVersion(testMigrations)))
+
+	// revert the migration again
+	assert.NoError(t, dbIO.migrate(testMigrations, LatestSchemaVersion()))
+
+	version, err = dbIO.SchemaVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, LatestSchemaVersion(), version)
+
+	tableNames, err = dbIO.getTableNames()
+	assert.NoError(t, err)
+	assert.NotContains(t, tableNames, "migration_test")
+
+	// the downgrade is pinned until the database gets migrated to the latest version again
+	pinnedVersion, pinned, err := dbIO.PinnedSchemaVersion()
+	assert.NoError(t, err)
+	assert.True(t, pinned)
+	assert.Equal(t, LatestSchemaVersion(), pinnedVersion)
+	assert.NoError(t, dbIO.Migrate(LatestSchemaVersion()))
+
+	// the baseline can't be reverted and unknown versions are rejected
+	assert.Error(t, dbIO.migrate(testMigrations, 0))
+	assert.Error(t, dbIO.migrate(testMigrations, latestVersion(testMigrations)+1))
+}
+
+func TestMigrateFailedMigrationIsRolledBack(t *testing.T) {
+	defer removeMigrationBackups(t, viper.GetString("Database.Path"))
+
+	testMigrations := append(append([]Migration{}, migrations...), Migration{
+		Version: LatestSchemaVersion() + 1,
+		Name:    "failing migration",
+		Up: func(db *DbIO, tx *sql.Tx) error {
+			if _, err := tx.Exec("CREATE TABLE migration_rollback_test (uid INTEGER PRIMARY KEY)"); err != nil {
+				return err
+			}
+
+			_, err := tx.Exec("INVALID SQL")
+			return err
+		},
+	})
+
+	assert.Error(t, dbIO.migrate(testMigrations, latestVersion(testMigrations)))
+
+	version, err := dbIO.SchemaVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, LatestSchemaVersion(), version)
+
+	tableNames, err := dbIO.getTableNames()
+	assert.NoError(t, err)
+	assert.NotContains(t, tableNames, "migration_rollback_test")
+}
+
+func TestMigrateLegacyDatabase(t *testing.T) {
+	f, err := os.CreateTemp("", "*.db")
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	defer func() { _ = os.Remove(f.Name()) }()
+	defer removeMigrationBackups(t, f.Name())
+
+	connection, err := sql.Open("sqlite3", f.Name())
+	assert.NoError(t, err)
+
+	legacyDbIO := &DbIO{connection: connection, driver: SQLiteDriver, dsn: f.Name(), backend: sqliteBackend{}}
+	defer legacyDbIO.CloseConnection()
+
+	// tracked items table of a database created before the priority and failure columns existed
+	_, err = connection.Exec(`
+		CREATE TABLE tracked_items
+		(
+			uid          INTEGER PRIMARY KEY AUTOINCREMENT,
+			uri          VARCHAR(255) DEFAULT '',
+			subfolder    VARCHAR(255) DEFAULT '',
+			current_item VARCHAR(255) DEFAULT '',
+			module       VARCHAR(255) DEFAULT '' NOT NULL,
+			last_modified DATETIME    DEFAULT (strftime('%s','now')),
+			favorite     BOOLEAN      DEFAULT FALSE NOT NULL,
+			complete     BOOLEAN      DEFAULT FALSE NOT NULL,
+			notes        TEXT         DEFAULT '' NOT NULL
+		);
+	`)
+	assert.NoError(t, err)
+
+	// the accounts, OAuth clients and cookies tables exist since the first release
+	for _, createTable := range []func(connection executor) error{
+		createAccountsTable, createOAuthClientsTable, createCookiesTable, createSchemaMigrationsTable,
+	} {
+		assert.NoError(t, createTable(connection))
+	}
+
+	assert.NoError(t, legacyDbIO.Migrate(LatestSchemaVersion()))
+
+	for _, column := range []string{"generated_notes", "schedule", "quarantined", "priority"} {
+		exists, existsErr := columnExists(connection, "tracked_items", column)
+		assert.NoError(t, existsErr)
+		assert.True(t, exists, column)
+	}
+
+	tableNames, err := legacyDbIO.getTableNames()
+	assert.NoError(t, err)
+	assert.Subset(t, tableNames, []string{"runs", "item_runs", "item_tags", "file_hashes", "image_hashes", "change_log"})
+}
+
+func TestMigrateDowngradeIsKept(t *testing.T) {
+	databasePath := filepath.Join(t.TempDir(), "watcher.db")
+	defer removeMigrationBackups(t, databasePath)
+
+	db, err := NewSQLiteConnection(databasePath)
+	assert.NoError(t, err)
+
+	// explicitly downgrade the database to the previous version
+	assert.NoError(t, db.Migrate(LatestSchemaVersion()-1))
+
+	pinnedVersion, pinned, err := db.PinnedSchemaVersion()
+	assert.NoError(t, err)
+	assert.True(t, pinned)
+	assert.Equal(t, LatestSchemaVersion()-1, pinnedVersion)
+	db.CloseConnection()
+
+	// reopening the database doesn't apply the reverted migration again
+	db, err = NewSQLiteConnection(databasePath)
+	assert.NoError(t, err)
+
+	version, err := db.SchemaVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, LatestSchemaVersion()-1, version)
+
+	// migrating up explicitly removes the pin again
+	assert.NoError(t, db.Migrate(LatestSchemaVersion()))
+
+	_, pinned, err = db.PinnedSchemaVersion()
+	assert.NoError(t, err)
+	assert.False(t, pinned)
+	db.CloseConnection()
+}
+
+func TestNewConnectionWithoutAutoMigrate(t *testing.T) {
+	databasePath := filepath.Join(t.TempDir(), "watcher.db")
+	defer removeMigrationBackups(t, databasePath)
+
+	db, err := NewSQLiteConnection(databasePath)
+	assert.NoError(t, err)
+	// revert the latest migration and remove the pin to simulate a database of an older release
+	assert.NoError(t, db.Migrate(LatestSchemaVersion()-1))
+	_, err = db.exec("DELETE FROM schema_version_pin")
+	assert.NoError(t, err)
+	db.CloseConnection()
+
+	db, err = newConnection(SQLiteDriver, databasePath, false)
+	assert.NoError(t, err)
+
+	statuses, err := db.MigrationStatus()
+	assert.NoError(t, err)
+	assert.False(t, statuses[len(statuses)-1].Applied)
+	db.CloseConnection()
+
+	// the automatic migration applies the pending migration
+	db, err = NewSQLiteConnection(databasePath)
+	assert.NoError(t, err)
+
+	version, err := db.SchemaVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, LatestSchemaVersion(), version)
+	db.CloseConnection()
+}
+
+func TestMigrateNotesIndex(t *testing.T) {
+	databasePath := filepath.Join(t.TempDir(), "watcher.db")
+	defer removeMigrationBackups(t, databasePath)
+
+	db, err := NewSQLiteConnection(databasePath)
+	assert.NoError(t, err)
+
+	defer db.CloseConnection()
+
+	// notes written before the full-text index existed are indexed by the migration
+	assert.NoError(t, db.Migrate(2))
+	_, err = db.exec("INSERT INTO tracked_items (uri, module, notes) VALUES ('https://example.com', 'test', 'Indexed')")
+	assert.NoError(t, err)
+
+	exists, err := db.hasNotesIndex()
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	assert.NoError(t, db.Migrate(LatestSchemaVersion()))
+	assert.Len(t, db.SearchTrackedItems([]string{"indexed"}), 1)
+
+	// the tables of the full-text index are not listed, so they aren't part of the backups
+	tableNames, err := db.getTableNames()
+	assert.NoError(t, err)
+	assert.NotContains(t, tableNames, notesIndexTable)
+	assert.NotContains(t, tableNames, notesIndexTable+"_segments")
+}
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+	"unicode"
+)
+
+// notesIndexTable is the SQLite full-text index of the notes and generated notes of the tracked items.
+// FTS4 is used since the SQLite driver only bundles FTS5 with the sqlite_fts5 build tag.
+// The index uses the tracked items as external content, so it only stores the index itself
+const notesIndexTable = "tracked_items_notes"
+
+// notesIndexTrigger is a trigger keeping the full-text index in sync with the notes of the tracked items
+type notesIndexTrigger struct {
+	name      string
+	statement string
+}
+
+// notesIndexTriggers are the triggers keeping the full-text index in sync, the index entry of a changed item
+// is removed before changing the row and inserted again afterwards
+var notesIndexTriggers = []notesIndexTrigger{
+	{
+		name: "tracked_items_notes_insert",
+		statement: "AFTER INSERT ON tracked_items BEGIN " +
+			"INSERT INTO tracked_items_notes (docid, notes, generated_notes) " +
+			"VALUES (NEW.uid, NEW.notes, NEW.generated_notes); END",
+	},
+	{
+		name: "tracked_items_notes_before_update",
+		statement: "BEFORE UPDATE OF notes, generated_notes ON tracked_items BEGIN " +
+			"DELETE FROM tracked_items_notes WHERE docid = OLD.uid; END",
+	},
+	{
+		name: "tracked_items_notes_after_update",
+		statement: "AFTER UPDATE OF notes, generated_notes ON tracked_items BEGIN " +
+			"INSERT INTO tracked_items_notes (docid, notes, generated_notes) " +
+			"VALUES (NEW.uid, NEW.notes, NEW.generated_notes); END",
+	},
+	{
+		name: "tracked_items_notes_delete",
+		statement: "BEFORE DELETE ON tracked_items BEGIN " +
+			"DELETE FROM tracked_items_notes WHERE docid = OLD.uid; END",
+	},
+}
+
+// notesIndexRebuild is the statement rebuilding the full-text index from the current notes of the tracked items
+const notesIndexRebuild = "INSERT INTO tracked_items_notes (tracked_items_notes) VALUES ('rebuild')"
+
+// postgresNotesVector is the text search vector of the notes of the tracked items in PostgreSQL.
+// The simple configuration only lowercases the words, since the notes aren't written in a specific language
+const postgresNotesVector = "to_tsvector('simple', notes || ' ' || generated_notes)"
+
+// postgresNotesIndex is the create statement of the index of the text search vector of the notes
+const postgresNotesIndex = "CREATE INDEX IF NOT EXISTS tracked_items_notes_search ON tracked_items USING GIN (" +
+	postgresNotesVector + ")"
+
+// createNotesIndex creates the full-text index of the notes and the triggers keeping it in sync
+func createNotesIndex(connection executor) (err error) {
+	if _, err = connection.Exec(
+		"CREATE VIRTUAL TABLE IF NOT EXISTS " + notesIndexTable +
+			" USING fts4(content=\"tracked_items\", notes, generated_notes, tokenize=unicode61)",
+	); err != nil {
+		return err
+	}
+
+	for _, trigger := range notesIndexTriggers {
+		if _, err = connection.Exec("CREATE TRIGGER IF NOT EXISTS " + trigger.name + " " + trigger.statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrateNotesIndex adds the full-text index of the notes and indexes the notes of the existing tracked items
+func (db *DbIO) migrateNotesIndex(tx *sql.Tx) error {
+	if db.driver != SQLiteDriver {
+		_, err := tx.Exec(postgresNotesIndex)
+		return err
+	}
+
+	if err := createNotesIndex(tx); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(notesIndexRebuild)
+
+	return err
+}
+
+// revertNotesIndex removes the full-text index of the notes and the triggers keeping it in sync
+func (db *DbIO) revertNotesIndex(tx *sql.Tx) error {
+	if db.driver != SQLiteDriver {
+		_, err := tx.Exec("DROP INDEX IF EXISTS tracked_items_notes_search")
+		return err
+	}
+
+	for _, trigger := range notesIndexTriggers {
+		if _, err := tx.Exec("DROP TRIGGER IF EXISTS " + trigger.name); err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.Exec("DROP TABLE IF EXISTS " + notesIndexTable)
+
+	return err
+}
+
+// hasNotesIndex checks if the SQLite database contains the full-text index of the notes
+func (db *DbIO) hasNotesIndex() (exists bool, err error) {
+	err = db.queryRow(
+		"SELECT COUNT(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = ?", notesIndexTable,
+	).Scan(&exists)
+
+	return exists, err
+}
+
+// searchPhrases splits the passed search terms into phrases of words. Every character except for letters
+// and digits separates the words like in the full-text indexes, terms without any words are skipped
+func searchPhrases(terms []string) (phrases [][]string) {
+	for _, term := range terms {
+		words := strings.FieldsFunc(strings.ToLower(term), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+
+		if len(words) > 0 {
+			phrases = append(phrases, words)
+		}
+	}
+
+	return phrases
+}
@@ -10,7 +10,7 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 )
 
-func (db *DbIO) createOAuthClientsTable(connection *sql.DB) (err error) {
+func createOAuthClientsTable(connection executor) (err error) {
 	sqlStatement := `
 		CREATE TABLE oauth_clients
 		(
@@ -30,7 +30,7 @@ func (db *DbIO) createOAuthClientsTable(connection *sql.DB) (err error) {
 
 // GetOAuthClient retrieves the first not disabled OAuth client of the passed module
 func (db *DbIO) GetOAuthClient(module models.ModuleInterface) *models.OAuthClient {
-	stmt, err := db.connection.Prepare(
+	stmt, err := db.prepare(
 		"SELECT * FROM oauth_clients WHERE NOT disabled AND module = ? ORDER BY uid",
 	)
 	raven.CheckError(err)
@@ -41,14 +41,7 @@ func (db *DbIO) GetOAuthClient(module models.ModuleInterface) *models.OAuthClien
 	defer raven.CheckClosure(rows)
 
 	if rows.Next() {
-		oAuthClient := models.OAuthClient{}
-		raven.CheckError(rows.Scan(
-			&oAuthClient.ID, &oAuthClient.ClientID, &oAuthClient.ClientSecret,
-			&oAuthClient.AccessToken, &oAuthClient.RefreshToken,
-			&oAuthClient.Module, &oAuthClient.Disabled,
-		))
-
-		return &oAuthClient
+		return db.scanOAuthClient(rows)
 	}
 
 	return nil
@@ -63,7 +56,7 @@ func (db *DbIO) GetAllOAuthClients(module models.ModuleInterface) (oAuthClients
 	)
 
 	if module != nil {
-		stmt, err = db.connection.Prepare(
+		stmt, err = db.prepare(
 			"SELECT * FROM oauth_clients WHERE NOT disabled AND module = ? ORDER BY module, uid",
 		)
 		raven.CheckError(err)
@@ -71,20 +64,13 @@ func (db *DbIO) GetAllOAuthClients(module models.ModuleInterface) (oAuthClients
 		rows, err = stmt.Query(module.ModuleKey())
 		raven.CheckError(err)
 	} else {
-		rows, err = db.connection.Query("SELECT * FROM oauth_clients WHERE NOT disabled ORDER BY module, uid")
+		rows, err = db.query("SELECT * FROM oauth_clients WHERE NOT disabled ORDER BY module, uid")
 	}
 
 	raven.CheckError(err)
 
 	for rows.Next() {
-		oAuthClient := models.OAuthClient{}
-		raven.CheckError(rows.Scan(
-			&oAuthClient.ID, &oAuthClient.ClientID, &oAuthClient.ClientSecret,
-			&oAuthClient.AccessToken, &oAuthClient.RefreshToken,
-			&oAuthClient.Module, &oAuthClient.Disabled,
-		))
-
-		oAuthClients = append(oAuthClients, &oAuthClient)
+		oAuthClients = append(oAuthClients, db.scanOAuthClient(rows))
 	}
 
 	return oAuthClients
@@ -95,26 +81,20 @@ func (db *DbIO) GetAllOAuthClients(module models.ModuleInterface) (oAuthClients
 func (db *DbIO) GetFirstOrCreateOAuthClient(
 	clientID string, clientSecret string, accessToken string, refreshToken string, module models.ModuleInterface,
 ) *models.OAuthClient {
-	stmt, err := db.connection.Prepare(
-		"SELECT * FROM oauth_clients WHERE client_id = ? AND access_token = ? AND module = ?",
-	)
+	// the access tokens are possibly encrypted with random nonces, so they are compared after decrypting them
+	stmt, err := db.prepare("SELECT * FROM oauth_clients WHERE client_id = ? AND module = ? ORDER BY uid")
 	raven.CheckError(err)
 
-	rows, err := stmt.Query(clientID, accessToken, module.ModuleKey())
+	rows, err := stmt.Query(clientID, module.ModuleKey())
 	raven.CheckError(err)
 
 	defer raven.CheckClosure(rows)
 
-	if rows.Next() {
-		oAuthClient := models.OAuthClient{}
+	for rows.Next() {
 		// item already persisted
-		raven.CheckError(rows.Scan(
-			&oAuthClient.ID, &oAuthClient.ClientID, &oAuthClient.ClientSecret,
-			&oAuthClient.AccessToken, &oAuthClient.RefreshToken,
-			&oAuthClient.Module, &oAuthClient.Disabled,
-		))
-
-		return &oAuthClient
+		if oAuthClient := db.scanOAuthClient(rows); oAuthClient.AccessToken == accessToken {
+			return oAuthClient
+		}
 	}
 
 	// create the item and call the same function again
@@ -127,7 +107,7 @@ func (db *DbIO) GetFirstOrCreateOAuthClient(
 func (db *DbIO) CreateOAuthClient(
 	clientID string, clientSecret string, accessToken string, refreshToken string, module models.ModuleInterface,
 ) {
-	stmt, err := db.connection.Prepare(
+	stmt, err := db.prepare(
 		"INSERT INTO oauth_clients (client_id, client_secret, access_token, refresh_token, module) " +
 			"VALUES (?, ?, ?, ?, ?)",
 	)
@@ -135,7 +115,10 @@ func (db *DbIO) CreateOAuthClient(
 
 	defer raven.CheckClosure(stmt)
 
-	_, err = stmt.Exec(clientID, clientSecret, accessToken, refreshToken, module.ModuleKey())
+	_, err = stmt.Exec(
+		clientID, db.encryptSecret(clientSecret), db.encryptSecret(accessToken), db.encryptSecret(refreshToken),
+		module.ModuleKey(),
+	)
 	raven.CheckError(err)
 }
 
@@ -144,7 +127,7 @@ func (db *DbIO) CreateOAuthClient(
 func (db *DbIO) UpdateOAuthClient(
 	clientID string, clientSecret string, accessToken string, refreshToken string, module models.ModuleInterface,
 ) {
-	stmt, err := db.connection.Prepare(
+	stmt, err := db.prepare(
 		"UPDATE oauth_clients " +
 			"SET client_id = ?, client_secret = ?, access_token = ?, refresh_token = ? " +
 			"WHERE client_id = ? AND module = ?",
@@ -153,7 +136,10 @@ func (db *DbIO) UpdateOAuthClient(
 
 	defer raven.CheckClosure(stmt)
 
-	_, err = stmt.Exec(clientID, clientSecret, accessToken, refreshToken, clientID, module.ModuleKey())
+	_, err = stmt.Exec(
+		clientID, db.encryptSecret(clientSecret), db.encryptSecret(accessToken), db.encryptSecret(refreshToken),
+		clientID, module.ModuleKey(),
+	)
 	raven.CheckError(err)
 }
 
@@ -161,21 +147,68 @@ func (db *DbIO) UpdateOAuthClient(
 func (db *DbIO) UpdateOAuthClientDisabledStatus(
 	clientID string, accessToken string, disabled bool, module models.ModuleInterface,
 ) {
-	var disabledInt int8
+	// the access tokens are possibly encrypted with random nonces, so the matching clients are updated by their ID
+	var uids []int
 
-	if disabled {
-		disabledInt = 1
-	} else {
-		disabledInt = 0
+	for _, oAuthClient := range db.getOAuthClientsByClientID(clientID, module) {
+		if oAuthClient.AccessToken == accessToken {
+			uids = append(uids, oAuthClient.ID)
+		}
 	}
 
-	stmt, err := db.connection.Prepare(
-		"UPDATE oauth_clients SET disabled = ? WHERE client_id = ? AND access_token = ? AND module = ?",
-	)
+	stmt, err := db.prepare("UPDATE oauth_clients SET disabled = ? WHERE uid = ?")
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	for _, uid := range uids {
+		_, err = stmt.Exec(disabled, uid)
+		raven.CheckError(err)
+	}
+}
+
+// getOAuthClientsByClientID retrieves all OAuth clients with the passed client ID of the passed module
+func (db *DbIO) getOAuthClientsByClientID(clientID string, module models.ModuleInterface) (oAuthClients []*models.OAuthClient) {
+	stmt, err := db.prepare("SELECT * FROM oauth_clients WHERE client_id = ? AND module = ? ORDER BY uid")
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	rows, err := stmt.Query(clientID, module.ModuleKey())
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(rows)
+
+	for rows.Next() {
+		oAuthClients = append(oAuthClients, db.scanOAuthClient(rows))
+	}
+
+	return oAuthClients
+}
+
+// scanOAuthClient scans the current row into an OAuth client and decrypts its secrets
+func (db *DbIO) scanOAuthClient(rows *sql.Rows) *models.OAuthClient {
+	oAuthClient := models.OAuthClient{}
+	raven.CheckError(rows.Scan(
+		&oAuthClient.ID, &oAuthClient.ClientID, &oAuthClient.ClientSecret,
+		&oAuthClient.AccessToken, &oAuthClient.RefreshToken,
+		&oAuthClient.Module, &oAuthClient.Disabled,
+	))
+
+	oAuthClient.ClientSecret = db.decryptSecret(oAuthClient.ClientSecret)
+	oAuthClient.AccessToken = db.decryptSecret(oAuthClient.AccessToken)
+	oAuthClient.RefreshToken = db.decryptSecret(oAuthClient.RefreshToken)
+
+	return &oAuthClient
+}
+
+// DeleteOAuthClient deletes the OAuth client of the passed client ID/module
+func (db *DbIO) DeleteOAuthClient(clientID string, module models.ModuleInterface) {
+	stmt, err := db.prepare("DELETE FROM oauth_clients WHERE client_id = ? AND module = ?")
 	raven.CheckError(err)
 
 	defer raven.CheckClosure(stmt)
 
-	_, err = stmt.Exec(disabledInt, clientID, accessToken, module.ModuleKey())
+	_, err = stmt.Exec(clientID, module.ModuleKey())
 	raven.CheckError(err)
 }
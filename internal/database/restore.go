@@ -1,13 +1,16 @@
 package database
 
 import (
+	"fmt"
 	"os/exec"
-
-	"github.com/spf13/viper"
 )
 
 // RestoreTableFromFile executes the passed SQL file, used primarily for restoration
 // #nosec
 func (db *DbIO) RestoreTableFromFile(fileName string) error {
-	return exec.Command("sqlite3", viper.GetString("Database.Path"), ".read '"+fileName+"'").Run()
+	if db.driver != SQLiteDriver {
+		return fmt.Errorf("restoring tables is not supported by the %s database driver", db.driver)
+	}
+
+	return exec.Command("sqlite3", sqlitePath(db.dsn), ".read '"+fileName+"'").Run()
 }
@@ -0,0 +1,277 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/models"
+	"github.com/DaRealFreak/watcher-go/internal/raven"
+)
+
+// createRunsTable creates the table for the run history
+func createRunsTable(connection executor) (err error) {
+	sqlStatement := `
+		CREATE TABLE IF NOT EXISTS runs
+		(
+			uid         INTEGER PRIMARY KEY AUTOINCREMENT,
+			started_at  DATETIME DEFAULT (strftime('%s','now')) NOT NULL,
+			finished_at DATETIME DEFAULT NULL
+		);
+	`
+	_, err = connection.Exec(sqlStatement)
+
+	return err
+}
+
+// createItemRunsTable creates the table for the results of every parsed tracked item during a run
+func createItemRunsTable(connection executor) (err error) {
+	sqlStatement := `
+		CREATE TABLE IF NOT EXISTS item_runs
+		(
+			uid              INTEGER PRIMARY KEY AUTOINCREMENT,
+			run_id           INTEGER      DEFAULT 0 NOT NULL,
+			item_id          INTEGER      DEFAULT 0 NOT NULL,
+			module           VARCHAR(255) DEFAULT '' NOT NULL,
+			uri              VARCHAR(255) DEFAULT '' NOT NULL,
+			started_at       DATETIME     DEFAULT (strftime('%s','now')) NOT NULL,
+			finished_at      DATETIME     DEFAULT (strftime('%s','now')) NOT NULL,
+			files_downloaded INTEGER      DEFAULT 0 NOT NULL,
+			bytes_downloaded INTEGER      DEFAULT 0 NOT NULL,
+			error            TEXT         DEFAULT '' NOT NULL,
+			error_class      VARCHAR(255) DEFAULT '' NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS item_runs_item_id ON item_runs (item_id);
+		CREATE INDEX IF NOT EXISTS item_runs_run_id ON item_runs (run_id);
+	`
+	_, err = connection.Exec(sqlStatement)
+
+	return err
+}
+
+// CreateRun inserts a new run starting now and returns it
+func (db *DbIO) CreateRun() *models.Run {
+	run := &models.Run{StartedAt: time.Now()}
+
+	raven.CheckError(
+		db.queryRow("INSERT INTO runs (started_at) VALUES (?) RETURNING uid", run.StartedAt.Unix()).Scan(&run.ID),
+	)
+
+	return run
+}
+
+// FinishRun sets the finish time of the passed run to now
+func (db *DbIO) FinishRun(run *models.Run) {
+	run.FinishedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	stmt, err := db.prepare("UPDATE runs SET finished_at = ? WHERE uid = ?")
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	_, err = stmt.Exec(run.FinishedAt.Time.Unix(), run.ID)
+	raven.CheckError(err)
+}
+
+// CreateItemRun inserts the passed result of a parsed tracked item
+func (db *DbIO) CreateItemRun(itemRun *models.ItemRun) {
+	stmt, err := db.prepare(`
+		INSERT INTO item_runs (
+			run_id, item_id, module, uri, started_at, finished_at,
+			files_downloaded, bytes_downloaded, error, error_class
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING uid
+	`)
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	raven.CheckError(stmt.QueryRow(
+		itemRun.RunID, itemRun.ItemID, itemRun.Module, itemRun.URI,
+		itemRun.StartedAt.Unix(), itemRun.FinishedAt.Unix(),
+		itemRun.FilesDownloaded, itemRun.BytesDownloaded, itemRun.Error, itemRun.ErrorClass,
+	).Scan(&itemRun.ID))
+}
+
+// GetRuns returns the latest runs including the aggregated results of their item runs
+func (db *DbIO) GetRuns(limit int) (runs []*models.Run) {
+	stmt, err := db.prepare(`
+		SELECT r.uid, r.started_at, r.finished_at,
+		       COUNT(ir.uid),
+		       COALESCE(SUM(CASE WHEN ir.error != '' THEN 1 ELSE 0 END), 0),
+		       COALESCE(SUM(ir.files_downloaded), 0),
+		       COALESCE(SUM(ir.bytes_downloaded), 0)
+		FROM runs r
+		LEFT JOIN item_runs ir ON ir.run_id = r.uid
+		GROUP BY r.uid
+		ORDER BY r.uid DESC
+		LIMIT ?
+	`)
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	rows, err := stmt.Query(limit)
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(rows)
+
+	for rows.Next() {
+		run := models.Run{}
+		raven.CheckError(rows.Scan(
+			&run.ID, unixTime{&run.StartedAt}, nullUnixTime{&run.FinishedAt},
+			&run.Items, &run.FailedItems, &run.FilesDownloaded, &run.BytesDownloaded,
+		))
+		runs = append(runs, &run)
+	}
+
+	return runs
+}
+
+// GetItemRuns returns the latest item runs, optionally limited to the passed item ID (if not 0),
+// the passed module (if not nil) and failed item runs
+func (db *DbIO) GetItemRuns(
+	itemID int, module models.ModuleInterface, onlyFailed bool, limit int,
+) (itemRuns []*models.ItemRun) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	if itemID != 0 {
+		conditions = append(conditions, "item_id = ?")
+		args = append(args, itemID)
+	}
+
+	if module != nil {
+		conditions = append(conditions, "module = ?")
+		args = append(args, module.ModuleKey())
+	}
+
+	if onlyFailed {
+		conditions = append(conditions, "error != ''")
+	}
+
+	query := `SELECT uid, run_id, item_id, module, uri, started_at, finished_at,
+		files_downloaded, bytes_downloaded, error, error_class FROM item_runs`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += " ORDER BY uid DESC LIMIT ?"
+	args = append(args, limit)
+
+	stmt, err := db.prepare(query)
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	rows, err := stmt.Query(args...)
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(rows)
+
+	for rows.Next() {
+		itemRun := models.ItemRun{}
+		raven.CheckError(rows.Scan(
+			&itemRun.ID, &itemRun.RunID, &itemRun.ItemID, &itemRun.Module, &itemRun.URI,
+			unixTime{&itemRun.StartedAt}, unixTime{&itemRun.FinishedAt},
+			&itemRun.FilesDownloaded, &itemRun.BytesDownloaded, &itemRun.Error, &itemRun.ErrorClass,
+		))
+		itemRuns = append(itemRuns, &itemRun)
+	}
+
+	return itemRuns
+}
+
+// GetItemRunSummaries returns the aggregated run history of all still tracked items,
+// optionally limited to the passed module (if not nil)
+func (db *DbIO) GetItemRunSummaries(module models.ModuleInterface) (summaries []*models.ItemRunSummary) {
+	query := `
+		SELECT ir.item_id, t.module, t.uri, ir.started_at, ir.error, ir.error_class
+		FROM item_runs ir
+		INNER JOIN tracked_items t ON t.uid = ir.item_id
+	`
+
+	var args []interface{}
+	if module != nil {
+		query += " WHERE t.module = ?"
+		args = append(args, module.ModuleKey())
+	}
+
+	// newest item runs first, so the first row of every item is its last run
+	query += " ORDER BY t.module, ir.item_id, ir.uid DESC"
+
+	stmt, err := db.prepare(query)
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	rows, err := stmt.Query(args...)
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(rows)
+
+	var summary *models.ItemRunSummary
+
+	for rows.Next() {
+		var (
+			itemID     int
+			itemModule string
+			uri        string
+			startedAt  time.Time
+			runError   string
+			errorClass string
+		)
+
+		raven.CheckError(rows.Scan(&itemID, &itemModule, &uri, unixTime{&startedAt}, &runError, &errorClass))
+
+		if summary == nil || summary.ItemID != itemID {
+			summary = &models.ItemRunSummary{
+				ItemID:         itemID,
+				Module:         itemModule,
+				URI:            uri,
+				LastRun:        startedAt,
+				LastError:      runError,
+				LastErrorClass: errorClass,
+			}
+			summaries = append(summaries, summary)
+		}
+
+		summary.Runs++
+
+		if runError != "" {
+			summary.Failed++
+
+			if !summary.LastSuccess.Valid {
+				summary.ConsecutiveFailures++
+			}
+		} else if !summary.LastSuccess.Valid {
+			summary.LastSuccess = sql.NullTime{Time: startedAt, Valid: true}
+		}
+	}
+
+	return summaries
+}
+
+// GetLastItemRunTimes returns the start time of the last run of every tracked item with a run history
+func (db *DbIO) GetLastItemRunTimes() map[int]time.Time {
+	rows, err := db.query("SELECT item_id, MAX(started_at) FROM item_runs GROUP BY item_id")
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(rows)
+
+	lastRuns := make(map[int]time.Time)
+
+	for rows.Next() {
+		var (
+			itemID    int
+			startedAt int64
+		)
+
+		raven.CheckError(rows.Scan(&itemID, &startedAt))
+		lastRuns[itemID] = time.Unix(startedAt, 0)
+	}
+
+	return lastRuns
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/models"
+	"github.com/DaRealFreak/watcher-go/internal/modules"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemRunHistory(t *testing.T) {
+	module := modules.GetModuleFactory().GetAllModules()[0]
+	item := dbIO.GetFirstOrCreateTrackedItem("https://chan.sankakucomplex.com/?tags=run_history", "", module)
+
+	results := []struct {
+		err        string
+		errorClass string
+	}{
+		{"", ""},
+		{"unexpected status code: 404", "http_404"},
+		{"unexpected status code: 404", "http_404"},
+	}
+
+	for _, result := range results {
+		run := dbIO.CreateRun()
+		dbIO.CreateItemRun(&models.ItemRun{
+			RunID:           run.ID,
+			ItemID:          item.ID,
+			Module:          item.Module,
+			URI:             item.URI,
+			StartedAt:       time.Now(),
+			FinishedAt:      time.Now(),
+			FilesDownloaded: 2,
+			BytesDownloaded: 1024,
+			Error:           result.err,
+			ErrorClass:      result.errorClass,
+		})
+		dbIO.FinishRun(run)
+	}
+
+	runs := dbIO.GetRuns(3)
+	assert.New(t).Len(runs, 3)
+	assert.New(t).Equal(1, runs[0].Items)
+	assert.New(t).Equal(1, runs[0].FailedItems)
+	assert.New(t).Equal(int64(1024), runs[0].BytesDownloaded)
+	assert.New(t).True(runs[0].FinishedAt.Valid)
+
+	itemRuns := dbIO.GetItemRuns(item.ID, module, true, 10)
+	assert.New(t).Len(itemRuns, 2)
+	assert.New(t).Equal("http_404", itemRuns[0].ErrorClass)
+
+	var summary *models.ItemRunSummary
+	for _, itemSummary := range dbIO.GetItemRunSummaries(module) {
+		if itemSummary.ItemID == item.ID {
+			summary = itemSummary
+		}
+	}
+
+	assert.New(t).NotNil(summary)
+	assert.New(t).Equal(3, summary.Runs)
+	assert.New(t).Equal(2, summary.Failed)
+	assert.New(t).Equal(2, summary.ConsecutiveFailures)
+	assert.New(t).True(summary.LastSuccess.Valid)
+	assert.New(t).Equal("http_404", summary.LastErrorClass)
+
+	assert.New(t).Contains(dbIO.GetLastItemRunTimes(), item.ID)
+}
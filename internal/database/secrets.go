@@ -0,0 +1,137 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/DaRealFreak/watcher-go/internal/raven"
+	"github.com/DaRealFreak/watcher-go/internal/secrets"
+)
+
+// secretColumns are the columns of the tables containing credentials, encrypted if a secrets key is configured
+var secretColumns = map[string][]string{
+	"accounts":      {"password"},
+	"oauth_clients": {"client_secret", "access_token", "refresh_token"},
+	"cookies":       {"value"},
+}
+
+// encryptSecret encrypts the passed secret if a secrets key is configured
+func (db *DbIO) encryptSecret(value string) string {
+	encrypted, err := db.secrets.Encrypt(value)
+	raven.CheckError(err)
+
+	return encrypted
+}
+
+// decryptSecret decrypts the passed secret if it is encrypted
+func (db *DbIO) decryptSecret(value string) string {
+	decrypted, err := db.secrets.Decrypt(value)
+	raven.CheckError(err)
+
+	return decrypted
+}
+
+// CountPlaintextSecrets returns the amount of not encrypted non-empty secrets in the database
+func (db *DbIO) CountPlaintextSecrets() (count int, err error) {
+	for table, columns := range secretColumns {
+		for _, column := range columns {
+			var columnCount int
+
+			// table and column names are static, so we can't use prepared statements here
+			// #nosec
+			err = db.queryRow(fmt.Sprintf(
+				"SELECT COUNT(*) FROM %s WHERE %s != '' AND %s NOT LIKE '%s%%'",
+				table, column, column, secrets.Prefix,
+			)).Scan(&columnCount)
+			if err != nil {
+				return count, err
+			}
+
+			count += columnCount
+		}
+	}
+
+	return count, nil
+}
+
+// EncryptSecrets encrypts all not encrypted secrets in the database with the configured secrets key
+// and returns the amount of encrypted secrets
+func (db *DbIO) EncryptSecrets() (count int, err error) {
+	if !db.secrets.Enabled() {
+		return 0, fmt.Errorf("no secrets key configured")
+	}
+
+	tx, err := db.connection.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	for table, columns := range secretColumns {
+		for _, column := range columns {
+			var encrypted int
+
+			encrypted, err = db.encryptColumn(tx, table, column)
+			if err != nil {
+				_ = tx.Rollback()
+				return 0, err
+			}
+
+			count += encrypted
+		}
+	}
+
+	return count, tx.Commit()
+}
+
+// encryptColumn encrypts all not encrypted values of the passed table column
+func (db *DbIO) encryptColumn(tx *sql.Tx, table string, column string) (count int, err error) {
+	// table and column names are static, so we can't use prepared statements here
+	// #nosec
+	rows, err := tx.Query(fmt.Sprintf("SELECT uid, %s FROM %s", column, table))
+	if err != nil {
+		return 0, err
+	}
+
+	plaintexts := make(map[int]string)
+
+	for rows.Next() {
+		var (
+			uid   int
+			value string
+		)
+
+		if err = rows.Scan(&uid, &value); err != nil {
+			raven.CheckClosure(rows)
+			return 0, err
+		}
+
+		if value != "" && !secrets.IsEncrypted(value) {
+			plaintexts[uid] = value
+		}
+	}
+
+	raven.CheckClosure(rows)
+
+	// #nosec
+	stmt, err := tx.Prepare(db.backend.Rebind(fmt.Sprintf("UPDATE %s SET %s = ? WHERE uid = ?", table, column)))
+	if err != nil {
+		return 0, err
+	}
+
+	defer raven.CheckClosure(stmt)
+
+	for uid, plaintext := range plaintexts {
+		encrypted, encryptErr := db.secrets.Encrypt(plaintext)
+		if encryptErr != nil {
+			return count, encryptErr
+		}
+
+		if _, err = stmt.Exec(encrypted, uid); err != nil {
+			return count, err
+		}
+
+		count++
+	}
+
+	return count, nil
+}
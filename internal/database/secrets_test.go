@@ -0,0 +1,51 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/DaRealFreak/watcher-go/internal/modules"
+	"github.com/DaRealFreak/watcher-go/internal/secrets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDbIO_EncryptedSecrets(t *testing.T) {
+	key, err := secrets.GenerateKey()
+	assert.NoError(t, err)
+
+	cipher, err := secrets.NewKeyCipher(key)
+	assert.NoError(t, err)
+
+	previous := dbIO.secrets
+	dbIO.secrets = cipher
+
+	defer func() {
+		dbIO.secrets = previous
+	}()
+
+	module := modules.GetModuleFactory().GetAllModules()[0]
+	account := dbIO.GetFirstOrCreateAccount("encrypted_user", "hunter2", module)
+	assert.Equal(t, "hunter2", account.Password)
+
+	// the stored column only contains the ciphertext
+	var stored string
+	assert.NoError(t, dbIO.queryRow(`SELECT password FROM accounts WHERE "user" = ?`, "encrypted_user").Scan(&stored))
+	assert.True(t, secrets.IsEncrypted(stored))
+	assert.NotContains(t, stored, "hunter2")
+
+	// plaintext credentials stored without a key get encrypted by the secrets encrypt command
+	dbIO.secrets = nil
+	dbIO.GetFirstOrCreateAccount("plaintext_user", "hunter3", module)
+	dbIO.secrets = cipher
+
+	count, err := dbIO.CountPlaintextSecrets()
+	assert.NoError(t, err)
+	assert.NotZero(t, count)
+
+	encrypted, err := dbIO.EncryptSecrets()
+	assert.NoError(t, err)
+	assert.Equal(t, count, encrypted)
+
+	assert.NoError(t, dbIO.queryRow(`SELECT password FROM accounts WHERE "user" = ?`, "plaintext_user").Scan(&stored))
+	assert.True(t, secrets.IsEncrypted(stored))
+	assert.Equal(t, "hunter3", dbIO.GetFirstOrCreateAccount("plaintext_user", "hunter3", module).Password)
+}
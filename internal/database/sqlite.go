@@ -0,0 +1,119 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	// import for side effects
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteDriver is the name of the SQLite backend, used by default
+const SQLiteDriver = "sqlite3"
+
+// sqliteBackend stores the database in a local SQLite file
+type sqliteBackend struct{}
+
+func init() {
+	RegisterBackend(SQLiteDriver, sqliteBackend{})
+}
+
+// Open opens the passed SQLite file, defaults to the Database.Path setting if no file is passed
+func (b sqliteBackend) Open(dsn string) (*sql.DB, error) {
+	if dsn == "" {
+		dsn = viper.GetString("Database.Path")
+	}
+
+	if !strings.Contains(dsn, "?") {
+		dsn += "?_journal=WAL"
+	}
+
+	return sql.Open("sqlite3", dsn)
+}
+
+// NewSQLiteConnection opens the SQLite database of the passed file independent of the database settings,
+// used f.e. to verify backups in a temporary database
+func NewSQLiteConnection(path string) (*DbIO, error) {
+	return newConnection(SQLiteDriver, path, true)
+}
+
+// sqlitePath returns the file path of the passed data source name, defaults to the Database.Path setting
+func sqlitePath(dsn string) string {
+	if dsn == "" {
+		return viper.GetString("Database.Path")
+	}
+
+	path, _, _ := strings.Cut(strings.TrimPrefix(dsn, "file:"), "?")
+
+	return path
+}
+
+// CreateSchema creates all tables in the latest schema version
+func (b sqliteBackend) CreateSchema(connection executor) error {
+	for _, createTable := range []func(connection executor) error{
+		createAccountsTable,
+		createTrackedItemsTable,
+		createOAuthClientsTable,
+		createCookiesTable,
+		createRunsTable,
+		createItemRunsTable,
+		createItemTagsTable,
+		createFileHashesTable,
+		createImageHashesTable,
+		createChangeLogTable,
+		createNotesIndex,
+		createSchemaMigrationsTable,
+	} {
+		if err := createTable(connection); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateSchemaMigrationsTable creates the tables containing the applied migrations and the pinned schema version
+// if they don't exist yet
+func (b sqliteBackend) CreateSchemaMigrationsTable(connection executor) error {
+	return createSchemaMigrationsTable(connection)
+}
+
+// TableNames returns the names of all tables in the database except for the internal tables of SQLite
+// and the tables of the full-text index, which is rebuilt from the tracked items
+func (b sqliteBackend) TableNames(connection executor) (tableNames []string, err error) {
+	return queryNames(
+		connection,
+		`SELECT name FROM sqlite_master
+		 WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name NOT LIKE '`+notesIndexTable+`%'
+		 ORDER BY name`,
+	)
+}
+
+// Rebind returns the passed query unchanged since SQLite supports ? placeholders
+func (b sqliteBackend) Rebind(query string) string {
+	return query
+}
+
+// Now returns the SQL expression of the current unix timestamp
+func (b sqliteBackend) Now() string {
+	return "strftime('%s','now')"
+}
+
+// Like returns the operator of case-insensitive LIKE comparisons, LIKE of SQLite is case-insensitive already
+func (b sqliteBackend) Like() string {
+	return "LIKE"
+}
+
+// NotesSearch returns the condition matching the tracked items whose notes contain all passed phrases
+// using the FTS4 index of the notes, every word of the phrases is matched as prefix
+func (b sqliteBackend) NotesSearch(phrases [][]string) (condition string, arg string) {
+	queries := make([]string, len(phrases))
+	for i, words := range phrases {
+		queries[i] = `"` + strings.Join(words, "* ") + `*"`
+	}
+
+	return "uid IN (SELECT docid FROM " + notesIndexTable + " WHERE " + notesIndexTable + " MATCH ?)",
+		strings.Join(queries, " ")
+}
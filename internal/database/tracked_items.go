@@ -3,6 +3,7 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/DaRealFreak/watcher-go/internal/raven"
@@ -11,7 +12,7 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 )
 
-func (db *DbIO) createTrackedItemsTable(connection *sql.DB) (err error) {
+func createTrackedItemsTable(connection executor) (err error) {
 	sqlStatement := `
 		CREATE TABLE tracked_items
 		(
@@ -24,7 +25,13 @@ func (db *DbIO) createTrackedItemsTable(connection *sql.DB) (err error) {
 			favorite        BOOLEAN      DEFAULT FALSE NOT NULL,
 			complete        BOOLEAN      DEFAULT FALSE NOT NULL,
 			notes           TEXT         DEFAULT '' NOT NULL,
-			generated_notes TEXT         DEFAULT '' NOT NULL
+			generated_notes TEXT         DEFAULT '' NOT NULL,
+			schedule        VARCHAR(255) DEFAULT '' NOT NULL,
+			failures        INTEGER      DEFAULT 0 NOT NULL,
+			strong_failures INTEGER      DEFAULT 0 NOT NULL,
+			last_failure    DATETIME     DEFAULT NULL,
+			quarantined     BOOLEAN      DEFAULT FALSE NOT NULL,
+			priority        INTEGER      DEFAULT 0 NOT NULL
 		);
 	`
 	_, err = connection.Exec(sqlStatement)
@@ -32,21 +39,50 @@ func (db *DbIO) createTrackedItemsTable(connection *sql.DB) (err error) {
 	return err
 }
 
-// migrateTrackedItemsTable ensures schema additions present in newer versions of the
-// application also exist on older databases. Each migration step is idempotent.
-func (db *DbIO) migrateTrackedItemsTable() {
-	if !db.columnExists("tracked_items", "generated_notes") {
-		_, err := db.connection.Exec(
-			`ALTER TABLE tracked_items ADD COLUMN generated_notes TEXT DEFAULT '' NOT NULL`,
-		)
-		raven.CheckError(err)
+// migrateTrackedItemsTable adds the columns of newer versions of the application to tracked items tables
+// of databases created before the versioned migrations existed. Each step is idempotent.
+func (db *DbIO) migrateTrackedItemsTable(tx *sql.Tx) error {
+	columns := []struct {
+		name       string
+		statements []string
+	}{
+		{"generated_notes", []string{`ALTER TABLE tracked_items ADD COLUMN generated_notes TEXT DEFAULT '' NOT NULL`}},
+		{"schedule", []string{`ALTER TABLE tracked_items ADD COLUMN schedule VARCHAR(255) DEFAULT '' NOT NULL`}},
+		{"quarantined", []string{
+			`ALTER TABLE tracked_items ADD COLUMN failures INTEGER DEFAULT 0 NOT NULL`,
+			`ALTER TABLE tracked_items ADD COLUMN strong_failures INTEGER DEFAULT 0 NOT NULL`,
+			`ALTER TABLE tracked_items ADD COLUMN last_failure DATETIME DEFAULT NULL`,
+			`ALTER TABLE tracked_items ADD COLUMN quarantined BOOLEAN DEFAULT FALSE NOT NULL`,
+		}},
+		{"priority", []string{`ALTER TABLE tracked_items ADD COLUMN priority INTEGER DEFAULT 0 NOT NULL`}},
 	}
+
+	for _, column := range columns {
+		exists, err := columnExists(tx, "tracked_items", column.name)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			continue
+		}
+
+		for _, statement := range column.statements {
+			if _, err = tx.Exec(statement); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
 }
 
 // columnExists returns true if the passed column already exists on the passed table.
-func (db *DbIO) columnExists(tableName string, columnName string) bool {
-	rows, err := db.connection.Query(`PRAGMA table_info("` + tableName + `")`)
-	raven.CheckError(err)
+func columnExists(connection executor, tableName string, columnName string) (exists bool, err error) {
+	rows, err := connection.Query(`PRAGMA table_info("` + tableName + `")`)
+	if err != nil {
+		return false, err
+	}
 
 	defer raven.CheckClosure(rows)
 
@@ -60,18 +96,34 @@ func (db *DbIO) columnExists(tableName string, columnName string) bool {
 			primaryKey   int
 		)
 
-		raven.CheckError(rows.Scan(&cid, &name, &columnType, &notNull, &defaultValue, &primaryKey))
+		if err = rows.Scan(&cid, &name, &columnType, &notNull, &defaultValue, &primaryKey); err != nil {
+			return false, err
+		}
 
 		if name == columnName {
-			return true
+			exists = true
 		}
 	}
 
-	return false
+	return exists, rows.Err()
+}
+
+// trackedItemColumns are the selected columns of the tracked_items table in the order scanTrackedItem expects them
+const trackedItemColumns = "uid, uri, subfolder, current_item, module, last_modified, favorite, complete, " +
+	"notes, generated_notes, schedule, failures, strong_failures, last_failure, quarantined, priority"
+
+// scanTrackedItem scans the current row selected with trackedItemColumns into the passed tracked item
+func scanTrackedItem(rows *sql.Rows, item *models.TrackedItem) error {
+	return rows.Scan(
+		&item.ID, &item.URI, &item.SubFolder, &item.CurrentItem, &item.Module, nullUnixTime{&item.LastModified},
+		&item.Favorite, &item.Complete, &item.Notes, &item.GeneratedNotes, &item.Schedule,
+		&item.Failures, &item.StrongFailures, nullUnixTime{&item.LastFailure}, &item.Quarantined, &item.Priority,
+	)
 }
 
 // GetTrackedItems retrieves all tracked items from the sqlite database
-// if module is set limit the results use the passed module as restraint
+// if module is set limit the results use the passed module as restraint.
+// Quarantined items are excluded like completed items
 func (db *DbIO) GetTrackedItems(module models.ModuleInterface, includeCompleted bool) (items []*models.TrackedItem) {
 	var (
 		rows *sql.Rows
@@ -80,17 +132,17 @@ func (db *DbIO) GetTrackedItems(module models.ModuleInterface, includeCompleted
 
 	if module == nil {
 		if includeCompleted {
-			rows, err = db.connection.Query("SELECT uid, uri, subfolder, current_item, module, last_modified, favorite, complete, generated_notes FROM tracked_items ORDER BY module, favorite DESC, uid")
+			rows, err = db.query("SELECT " + trackedItemColumns + " FROM tracked_items ORDER BY module, favorite DESC, uid")
 		} else {
-			rows, err = db.connection.Query("SELECT uid, uri, subfolder, current_item, module, last_modified, favorite, complete, generated_notes FROM tracked_items WHERE NOT complete ORDER BY module, favorite DESC, uid")
+			rows, err = db.query("SELECT " + trackedItemColumns + " FROM tracked_items WHERE NOT complete AND NOT quarantined ORDER BY module, favorite DESC, uid")
 		}
 	} else {
 		var stmt *sql.Stmt
 
 		if includeCompleted {
-			stmt, err = db.connection.Prepare("SELECT uid, uri, subfolder, current_item, module, last_modified, favorite, complete, generated_notes FROM tracked_items WHERE module = ? ORDER BY favorite DESC, uid")
+			stmt, err = db.prepare("SELECT " + trackedItemColumns + " FROM tracked_items WHERE module = ? ORDER BY favorite DESC, uid")
 		} else {
-			stmt, err = db.connection.Prepare("SELECT uid, uri, subfolder, current_item, module, last_modified, favorite, complete, generated_notes FROM tracked_items WHERE NOT complete AND module = ? ORDER BY favorite DESC, uid")
+			stmt, err = db.prepare("SELECT " + trackedItemColumns + " FROM tracked_items WHERE NOT complete AND NOT quarantined AND module = ? ORDER BY favorite DESC, uid")
 		}
 		defer raven.CheckClosure(stmt)
 		raven.CheckError(err)
@@ -105,7 +157,7 @@ func (db *DbIO) GetTrackedItems(module models.ModuleInterface, includeCompleted
 	for rows.Next() {
 		item := models.TrackedItem{}
 
-		err = rows.Scan(&item.ID, &item.URI, &item.SubFolder, &item.CurrentItem, &item.Module, &item.LastModified, &item.Favorite, &item.Complete, &item.GeneratedNotes)
+		err = scanTrackedItem(rows, &item)
 		raven.CheckError(err)
 
 		items = append(items, &item)
@@ -114,6 +166,28 @@ func (db *DbIO) GetTrackedItems(module models.ModuleInterface, includeCompleted
 	return items
 }
 
+// GetTrackedItem retrieves the tracked item with the passed ID, returns nil if no item exists with the ID
+func (db *DbIO) GetTrackedItem(id int) *models.TrackedItem {
+	stmt, err := db.prepare("SELECT " + trackedItemColumns + " FROM tracked_items WHERE uid = ?")
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	rows, err := stmt.Query(id)
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(rows)
+
+	if rows.Next() {
+		item := models.TrackedItem{}
+		raven.CheckError(scanTrackedItem(rows, &item))
+
+		return &item
+	}
+
+	return nil
+}
+
 // GetTrackedItemsByDomain retrieves all tracked items from the sqlite database based on the domain
 func (db *DbIO) GetTrackedItemsByDomain(domain string, includeCompleted bool) (items []*models.TrackedItem) {
 	var (
@@ -124,9 +198,9 @@ func (db *DbIO) GetTrackedItemsByDomain(domain string, includeCompleted bool) (i
 	var stmt *sql.Stmt
 
 	if includeCompleted {
-		stmt, err = db.connection.Prepare("SELECT uid, uri, subfolder, current_item, module, last_modified, favorite, complete, generated_notes FROM tracked_items WHERE uri LIKE ? ORDER BY favorite DESC, uid")
+		stmt, err = db.prepare("SELECT " + trackedItemColumns + " FROM tracked_items WHERE uri " + db.backend.Like() + " ? ORDER BY favorite DESC, uid")
 	} else {
-		stmt, err = db.connection.Prepare("SELECT uid, uri, subfolder, current_item, module, last_modified, favorite, complete, generated_notes FROM tracked_items WHERE NOT complete AND uri LIKE ? ORDER BY favorite DESC, uid")
+		stmt, err = db.prepare("SELECT " + trackedItemColumns + " FROM tracked_items WHERE NOT complete AND NOT quarantined AND uri " + db.backend.Like() + " ? ORDER BY favorite DESC, uid")
 	}
 	defer raven.CheckClosure(stmt)
 	raven.CheckError(err)
@@ -139,7 +213,7 @@ func (db *DbIO) GetTrackedItemsByDomain(domain string, includeCompleted bool) (i
 	for rows.Next() {
 		item := models.TrackedItem{}
 
-		err = rows.Scan(&item.ID, &item.URI, &item.SubFolder, &item.CurrentItem, &item.Module, &item.LastModified, &item.Favorite, &item.Complete, &item.GeneratedNotes)
+		err = scanTrackedItem(rows, &item)
 		raven.CheckError(err)
 
 		items = append(items, &item)
@@ -151,7 +225,7 @@ func (db *DbIO) GetTrackedItemsByDomain(domain string, includeCompleted bool) (i
 // GetFirstOrCreateTrackedItem checks if an item exists already, else creates it
 // returns the already persisted or the newly created item
 func (db *DbIO) GetFirstOrCreateTrackedItem(uri string, subFolder string, module models.ModuleInterface) *models.TrackedItem {
-	stmt, err := db.connection.Prepare("SELECT uid, uri, subfolder, current_item, module, last_modified, favorite, complete, generated_notes FROM tracked_items WHERE uri = ? and subfolder = ? and module = ?")
+	stmt, err := db.prepare("SELECT " + trackedItemColumns + " FROM tracked_items WHERE uri = ? and subfolder = ? and module = ?")
 	defer raven.CheckClosure(stmt)
 	raven.CheckError(err)
 
@@ -164,7 +238,7 @@ func (db *DbIO) GetFirstOrCreateTrackedItem(uri string, subFolder string, module
 
 	if rows.Next() {
 		// item already persisted
-		err = rows.Scan(&item.ID, &item.URI, &item.SubFolder, &item.CurrentItem, &item.Module, &item.LastModified, &item.Favorite, &item.Complete, &item.GeneratedNotes)
+		err = scanTrackedItem(rows, &item)
 		raven.CheckError(err)
 	} else {
 		// create the item and call the same function again
@@ -175,10 +249,10 @@ func (db *DbIO) GetFirstOrCreateTrackedItem(uri string, subFolder string, module
 	return &item
 }
 
-// GetAllOrCreateTrackedItemIgnoreSubFolder checks if an item exists already, else creates it, ignores sub folders
-// returns the already persisted or the newly created item
-func (db *DbIO) GetAllOrCreateTrackedItemIgnoreSubFolder(uri string, module models.ModuleInterface) (items []*models.TrackedItem) {
-	stmt, err := db.connection.Prepare("SELECT uid, uri, subfolder, current_item, module, last_modified, favorite, complete, generated_notes FROM tracked_items WHERE uri = ? and module = ?")
+// GetTrackedItemsIgnoreSubFolder returns all tracked items of the passed uri and module regardless of their
+// sub folder without creating any items
+func (db *DbIO) GetTrackedItemsIgnoreSubFolder(uri string, module models.ModuleInterface) (items []*models.TrackedItem) {
+	stmt, err := db.prepare("SELECT " + trackedItemColumns + " FROM tracked_items WHERE uri = ? and module = ?")
 	defer raven.CheckClosure(stmt)
 	raven.CheckError(err)
 
@@ -190,12 +264,19 @@ func (db *DbIO) GetAllOrCreateTrackedItemIgnoreSubFolder(uri string, module mode
 	for rows.Next() {
 		item := models.TrackedItem{}
 
-		err = rows.Scan(&item.ID, &item.URI, &item.SubFolder, &item.CurrentItem, &item.Module, &item.LastModified, &item.Favorite, &item.Complete, &item.GeneratedNotes)
+		err = scanTrackedItem(rows, &item)
 		raven.CheckError(err)
 
 		items = append(items, &item)
 	}
 
+	return items
+}
+
+// GetAllOrCreateTrackedItemIgnoreSubFolder checks if an item exists already, else creates it, ignores sub folders
+// returns the already persisted or the newly created item
+func (db *DbIO) GetAllOrCreateTrackedItemIgnoreSubFolder(uri string, module models.ModuleInterface) (items []*models.TrackedItem) {
+	items = db.GetTrackedItemsIgnoreSubFolder(uri, module)
 	if len(items) == 0 {
 		items = append(items, db.GetFirstOrCreateTrackedItem(uri, "", module))
 	}
@@ -205,7 +286,7 @@ func (db *DbIO) GetAllOrCreateTrackedItemIgnoreSubFolder(uri string, module mode
 
 // CreateTrackedItem inserts the passed uri and the module into the tracked_items table
 func (db *DbIO) CreateTrackedItem(uri string, subFolder string, module models.ModuleInterface) {
-	stmt, err := db.connection.Prepare("INSERT INTO tracked_items (uri, subfolder, module) VALUES (?, ?, ?)")
+	stmt, err := db.prepare("INSERT INTO tracked_items (uri, subfolder, module) VALUES (?, ?, ?)")
 	raven.CheckError(err)
 
 	defer raven.CheckClosure(stmt)
@@ -217,22 +298,69 @@ func (db *DbIO) CreateTrackedItem(uri string, subFolder string, module models.Mo
 // UpdateTrackedItem updates the current item column of the tracked item in the database
 // also sets the complete status to false to check it on the next check cycle
 func (db *DbIO) UpdateTrackedItem(trackedItem *models.TrackedItem, currentItem string) {
-	stmt, err := db.connection.Prepare("UPDATE tracked_items SET current_item = ?, last_modified = strftime('%s', 'now'), complete = ? WHERE uid = ?")
+	stmt, err := db.prepare("UPDATE tracked_items SET current_item = ?, last_modified = " + db.backend.Now() + ", complete = ? WHERE uid = ?")
 	raven.CheckError(err)
 
 	defer raven.CheckClosure(stmt)
 
-	_, err = stmt.Exec(currentItem, 0, trackedItem.ID)
+	_, err = stmt.Exec(currentItem, false, trackedItem.ID)
 	raven.CheckError(err)
 
 	// update current item
 	trackedItem.CurrentItem = currentItem
 }
 
+// UpdateTrackedItemNotes updates the user defined notes of the passed tracked item in the database
+func (db *DbIO) UpdateTrackedItemNotes(trackedItem *models.TrackedItem, notes string) {
+	stmt, err := db.prepare("UPDATE tracked_items SET notes = ? WHERE uid = ?")
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	_, err = stmt.Exec(notes, trackedItem.ID)
+	raven.CheckError(err)
+
+	trackedItem.Notes = notes
+}
+
+// SearchTrackedItems returns all tracked items whose notes or generated notes contain every passed term
+// using the full-text index of the notes. Terms consisting of multiple words are matched as phrases
+// and every word is matched case-insensitive as prefix of the words in the notes
+func (db *DbIO) SearchTrackedItems(terms []string) (items []*models.TrackedItem) {
+	phrases := searchPhrases(terms)
+	if len(phrases) == 0 {
+		return items
+	}
+
+	condition, arg := db.backend.NotesSearch(phrases)
+
+	stmt, err := db.prepare(
+		"SELECT " + trackedItemColumns + " FROM tracked_items WHERE " + condition + " ORDER BY module, favorite DESC, uid",
+	)
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	rows, err := stmt.Query(arg)
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(rows)
+
+	for rows.Next() {
+		item := new(models.TrackedItem)
+		raven.CheckError(scanTrackedItem(rows, item))
+		items = append(items, item)
+	}
+
+	raven.CheckError(rows.Err())
+
+	return items
+}
+
 // UpdateTrackedItemGeneratedNotes updates the generated_notes column of the passed tracked item in the
 // database, intended for module-generated metadata (e.g. cached profile data so it survives upstream deletion).
 func (db *DbIO) UpdateTrackedItemGeneratedNotes(trackedItem *models.TrackedItem, generatedNotes string) {
-	stmt, err := db.connection.Prepare("UPDATE tracked_items SET generated_notes = ? WHERE uid = ?")
+	stmt, err := db.prepare("UPDATE tracked_items SET generated_notes = ? WHERE uid = ?")
 	raven.CheckError(err)
 
 	defer raven.CheckClosure(stmt)
@@ -245,26 +373,19 @@ func (db *DbIO) UpdateTrackedItemGeneratedNotes(trackedItem *models.TrackedItem,
 
 // ChangeTrackedItemCompleteStatus changes the complete status of the passed tracked item in the database
 func (db *DbIO) ChangeTrackedItemCompleteStatus(trackedItem *models.TrackedItem, complete bool) {
-	var completeInt int8
-	if complete {
-		completeInt = 1
-	} else {
-		completeInt = 0
-	}
-
-	stmt, err := db.connection.Prepare("UPDATE tracked_items SET last_modified = strftime('%s', 'now'), complete = ? WHERE uid = ?")
+	stmt, err := db.prepare("UPDATE tracked_items SET last_modified = " + db.backend.Now() + ", complete = ? WHERE uid = ?")
 	raven.CheckError(err)
 
 	defer raven.CheckClosure(stmt)
 
-	_, err = stmt.Exec(completeInt, trackedItem.ID)
+	_, err = stmt.Exec(complete, trackedItem.ID)
 	raven.CheckError(err)
 
 	trackedItem.Complete = complete
 }
 
 func (db *DbIO) ChangeTrackedItemUri(trackedItem *models.TrackedItem, uri string) {
-	stmt, err := db.connection.Prepare("UPDATE tracked_items SET uri = ? WHERE uid = ?")
+	stmt, err := db.prepare("UPDATE tracked_items SET uri = ? WHERE uid = ?")
 	raven.CheckError(err)
 
 	defer raven.CheckClosure(stmt)
@@ -276,7 +397,7 @@ func (db *DbIO) ChangeTrackedItemUri(trackedItem *models.TrackedItem, uri string
 }
 
 func (db *DbIO) ChangeTrackedItemSubFolder(trackedItem *models.TrackedItem, subFolder string) {
-	stmt, err := db.connection.Prepare("UPDATE tracked_items SET subfolder = ? WHERE uid = ?")
+	stmt, err := db.prepare("UPDATE tracked_items SET subfolder = ? WHERE uid = ?")
 	raven.CheckError(err)
 
 	defer raven.CheckClosure(stmt)
@@ -287,32 +408,99 @@ func (db *DbIO) ChangeTrackedItemSubFolder(trackedItem *models.TrackedItem, subF
 	trackedItem.SubFolder = subFolder
 }
 
-// ChangeTrackedItemFavoriteStatus changes the favorite status of the passed tracked item in the database
-func (db *DbIO) ChangeTrackedItemFavoriteStatus(trackedItem *models.TrackedItem, favorite bool) {
-	var favoriteInt int8
-	if favorite {
-		favoriteInt = 1
-	} else {
-		favoriteInt = 0
+// ChangeTrackedItemSchedule changes the update interval of the passed tracked item used by the daemon,
+// an empty schedule falls back to the module or global schedule
+func (db *DbIO) ChangeTrackedItemSchedule(trackedItem *models.TrackedItem, schedule string) {
+	stmt, err := db.prepare("UPDATE tracked_items SET schedule = ? WHERE uid = ?")
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	_, err = stmt.Exec(schedule, trackedItem.ID)
+	raven.CheckError(err)
+
+	trackedItem.Schedule = schedule
+}
+
+// ChangeTrackedItemPriority changes the priority of the passed tracked item in the database
+func (db *DbIO) ChangeTrackedItemPriority(trackedItem *models.TrackedItem, priority int) {
+	stmt, err := db.prepare("UPDATE tracked_items SET priority = ? WHERE uid = ?")
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	_, err = stmt.Exec(priority, trackedItem.ID)
+	raven.CheckError(err)
+
+	trackedItem.Priority = priority
+}
+
+// UpdateTrackedItemFailures sets the consecutive failure counters of the passed tracked item,
+// the time of the last failure is set to now or reset if no failures are passed
+func (db *DbIO) UpdateTrackedItemFailures(trackedItem *models.TrackedItem, failures int, strongFailures int) {
+	var (
+		lastFailure          sql.NullTime
+		lastFailureTimestamp interface{}
+	)
+
+	if failures > 0 {
+		lastFailure = sql.NullTime{Time: time.Now(), Valid: true}
+		lastFailureTimestamp = lastFailure.Time.Unix()
 	}
 
-	stmt, err := db.connection.Prepare("UPDATE tracked_items SET last_modified = strftime('%s', 'now'), favorite = ? WHERE uid = ?")
+	stmt, err := db.prepare(
+		"UPDATE tracked_items SET failures = ?, strong_failures = ?, last_failure = ? WHERE uid = ?",
+	)
 	raven.CheckError(err)
 
 	defer raven.CheckClosure(stmt)
 
-	_, err = stmt.Exec(favoriteInt, trackedItem.ID)
+	_, err = stmt.Exec(failures, strongFailures, lastFailureTimestamp, trackedItem.ID)
 	raven.CheckError(err)
 
-	trackedItem.Favorite = favorite
+	trackedItem.Failures = failures
+	trackedItem.StrongFailures = strongFailures
+	trackedItem.LastFailure = lastFailure
 }
 
-func (db *DbIO) DeleteTrackedItem(trackedItem *models.TrackedItem) {
-	stmt, err := db.connection.Prepare("DELETE FROM tracked_items WHERE uid = ?")
+// ChangeTrackedItemQuarantineStatus changes the quarantine status of the passed tracked item in the database,
+// lifting the quarantine also resets the failure counters
+func (db *DbIO) ChangeTrackedItemQuarantineStatus(trackedItem *models.TrackedItem, quarantined bool) {
+	if !quarantined {
+		db.UpdateTrackedItemFailures(trackedItem, 0, 0)
+	}
+
+	stmt, err := db.prepare("UPDATE tracked_items SET quarantined = ? WHERE uid = ?")
 	raven.CheckError(err)
 
 	defer raven.CheckClosure(stmt)
 
-	_, err = stmt.Exec(trackedItem.ID)
+	_, err = stmt.Exec(quarantined, trackedItem.ID)
 	raven.CheckError(err)
+
+	trackedItem.Quarantined = quarantined
+}
+
+// ChangeTrackedItemFavoriteStatus changes the favorite status of the passed tracked item in the database
+func (db *DbIO) ChangeTrackedItemFavoriteStatus(trackedItem *models.TrackedItem, favorite bool) {
+	stmt, err := db.prepare("UPDATE tracked_items SET last_modified = " + db.backend.Now() + ", favorite = ? WHERE uid = ?")
+	raven.CheckError(err)
+
+	defer raven.CheckClosure(stmt)
+
+	_, err = stmt.Exec(favorite, trackedItem.ID)
+	raven.CheckError(err)
+
+	trackedItem.Favorite = favorite
+}
+
+func (db *DbIO) DeleteTrackedItem(trackedItem *models.TrackedItem) {
+	for _, query := range []string{"DELETE FROM tracked_items WHERE uid = ?", "DELETE FROM item_tags WHERE item_id = ?"} {
+		stmt, err := db.prepare(query)
+		raven.CheckError(err)
+
+		_, err = stmt.Exec(trackedItem.ID)
+		raven.CheckError(err)
+		raven.CheckClosure(stmt)
+	}
 }
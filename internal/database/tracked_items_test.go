@@ -0,0 +1,74 @@
+package database
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/DaRealFreak/watcher-go/internal/modules"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackedItemNotes(t *testing.T) {
+	module := modules.GetModuleFactory().GetAllModules()[0]
+	item := dbIO.GetFirstOrCreateTrackedItem("https://chan.sankakucomplex.com/?tags=notes", "", module)
+	otherItem := dbIO.GetFirstOrCreateTrackedItem("https://chan.sankakucomplex.com/?tags=notes_2", "", module)
+
+	dbIO.UpdateTrackedItemNotes(item, "Commissioned artist,\nask collector B about 100% pack")
+	dbIO.UpdateTrackedItemGeneratedNotes(otherItem, "display name: Collector_B")
+
+	assert.Equal(t, "Commissioned artist,\nask collector B about 100% pack", dbIO.GetTrackedItem(item.ID).Notes)
+
+	searchIDs := func(terms ...string) (ids []int) {
+		for _, result := range dbIO.SearchTrackedItems(terms) {
+			ids = append(ids, result.ID)
+		}
+
+		return ids
+	}
+
+	// terms are matched case-insensitive as word prefixes in both notes columns
+	assert.Equal(t, []int{item.ID, otherItem.ID}, searchIDs("COLLECTOR"))
+	assert.Equal(t, []int{item.ID, otherItem.ID}, searchIDs("collect"))
+	assert.Equal(t, []int{item.ID}, searchIDs("collector", "artist"))
+	assert.Empty(t, searchIDs("ollector"))
+	// terms with multiple words are matched as phrase, special characters only separate the words
+	assert.Equal(t, []int{item.ID, otherItem.ID}, searchIDs("collector_b"))
+	assert.Equal(t, []int{item.ID}, searchIDs("100% pack"))
+	assert.Empty(t, searchIDs("collector%artist"))
+	assert.Empty(t, searchIDs(" "))
+	assert.Empty(t, searchIDs("%"))
+
+	// the full-text index follows changes and deletions of the notes
+	dbIO.UpdateTrackedItemNotes(item, "Commissioned artist")
+	assert.Equal(t, []int{otherItem.ID}, searchIDs("collector"))
+	assert.Equal(t, []int{item.ID}, searchIDs("commissioned"))
+	dbIO.DeleteTrackedItem(otherItem)
+	assert.Empty(t, searchIDs("collector"))
+
+	// notes are part of the table dump used for backups
+	buffer := new(bytes.Buffer)
+	assert.NoError(t, dbIO.DumpTables(buffer, "tracked_items"))
+	assert.Contains(t, buffer.String(), "Commissioned artist")
+	// the full-text index isn't part of the dump and gets rebuilt instead
+	assert.NotContains(t, buffer.String(), "CREATE VIRTUAL TABLE")
+	assert.Contains(t, buffer.String(), notesIndexRebuild)
+}
+
+func TestGetTrackedItemsIgnoreSubFolder(t *testing.T) {
+	module := modules.GetModuleFactory().GetAllModules()[0]
+	uri := "https://chan.sankakucomplex.com/?tags=lookup"
+
+	// the lookup never creates items
+	assert.Empty(t, dbIO.GetTrackedItemsIgnoreSubFolder(uri, module))
+	assert.Empty(t, dbIO.GetTrackedItemsIgnoreSubFolder(uri, module))
+
+	item := dbIO.GetFirstOrCreateTrackedItem(uri, "", module)
+	subFolderItem := dbIO.GetFirstOrCreateTrackedItem(uri, "sub folder", module)
+
+	var ids []int
+	for _, result := range dbIO.GetTrackedItemsIgnoreSubFolder(uri, module) {
+		ids = append(ids, result.ID)
+	}
+
+	assert.Equal(t, []int{item.ID, subFolderItem.ID}, ids)
+}
@@ -0,0 +1,40 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// runCommand runs the command of the passed step with the expanded arguments and passes the event as JSON on stdin.
+// The placeholders are only expanded in the arguments, the command itself is never built from the event values
+func runCommand(ctx context.Context, step Step, event *Event, payload []byte) error {
+	args := make([]string, len(step.Args))
+	for i, arg := range step.Args {
+		args[i] = expandArgument(arg, event)
+	}
+
+	// #nosec
+	cmd := exec.CommandContext(ctx, step.Command, args...)
+	cmd.Stdin = bytes.NewReader(payload)
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if out := strings.TrimSpace(output.String()); out != "" {
+			return fmt.Errorf("%w: %s", err, out)
+		}
+
+		return err
+	}
+
+	return nil
+}
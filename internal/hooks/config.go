@@ -0,0 +1,165 @@
+package hooks
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// step types of the hook pipeline
+const (
+	// StepTypeCommand runs an external command with templated arguments, the event is passed as JSON on stdin
+	StepTypeCommand = "command"
+	// StepTypeConverter runs a built-in converter on the downloaded file
+	StepTypeConverter = "converter"
+	// StepTypeWebhook posts the event as JSON to the configured URL
+	StepTypeWebhook = "webhook"
+)
+
+// failure policies of the hook steps
+const (
+	// PolicyIgnore only logs failed steps on debug level
+	PolicyIgnore = "ignore"
+	// PolicyWarn logs failed steps as warning and continues, default policy
+	PolicyWarn = "warn"
+	// PolicyAbort aborts the current tracked item if the step failed
+	PolicyAbort = "abort"
+)
+
+// defaultTimeout is the timeout of a single step if no timeout is configured
+const defaultTimeout = 60 * time.Second
+
+// Config holds the global "hooks" settings block.
+type Config struct {
+	// PostDownload steps run after each successful file download
+	PostDownload []Step `mapstructure:"post_download"`
+	// PostItem steps run after each successfully parsed tracked item
+	PostItem []Step `mapstructure:"post_item"`
+}
+
+// Step is a single step of the hook pipeline
+type Step struct {
+	Name string `mapstructure:"name"`
+	Type string `mapstructure:"type"`
+	// Command and Args are used by command steps, the arguments can contain placeholders like {path}
+	Command string   `mapstructure:"command"`
+	Args    []string `mapstructure:"args"`
+	// Converter and Options are used by converter steps
+	Converter string            `mapstructure:"converter"`
+	Options   map[string]string `mapstructure:"options"`
+	// URL and Headers are used by webhook steps
+	URL     string            `mapstructure:"url"`
+	Headers map[string]string `mapstructure:"headers"`
+	// Modules restricts the step to the passed module keys, runs for all modules if empty
+	Modules []string      `mapstructure:"modules"`
+	Timeout time.Duration `mapstructure:"timeout"`
+	Policy  string        `mapstructure:"policy"`
+}
+
+// LoadConfig reads the global "hooks" config from viper. Invalid steps are logged and skipped
+func LoadConfig() Config {
+	var cfg Config
+	if err := viper.UnmarshalKey("hooks", &cfg); err != nil {
+		slog.Warn(fmt.Sprintf("failed to parse hooks config: %v", err))
+	}
+
+	cfg.PostDownload = validSteps("post_download", cfg.PostDownload)
+	cfg.PostItem = validSteps("post_item", cfg.PostItem)
+
+	return cfg
+}
+
+// validSteps returns the passed steps without the invalid steps
+func validSteps(hook string, steps []Step) (valid []Step) {
+	for index, step := range steps {
+		if err := step.Validate(); err != nil {
+			slog.Warn(fmt.Sprintf("skipping invalid %s hook step %d: %v", hook, index+1, err))
+			continue
+		}
+
+		valid = append(valid, step)
+	}
+
+	return valid
+}
+
+// Validate checks if the step is configured correctly
+func (s Step) Validate() error {
+	switch strings.ToLower(s.Type) {
+	case StepTypeCommand:
+		if s.Command == "" {
+			return fmt.Errorf("command step requires a command")
+		}
+	case StepTypeConverter:
+		if _, ok := converters[strings.ToLower(s.Converter)]; !ok {
+			return fmt.Errorf("unknown converter \"%s\", available converters: %s", s.Converter, converterNames())
+		}
+	case StepTypeWebhook:
+		if s.URL == "" {
+			return fmt.Errorf("webhook step requires an url")
+		}
+	default:
+		return fmt.Errorf(
+			"unknown step type \"%s\", expected %s, %s or %s", s.Type, StepTypeCommand, StepTypeConverter, StepTypeWebhook,
+		)
+	}
+
+	switch strings.ToLower(s.Policy) {
+	case "", PolicyIgnore, PolicyWarn, PolicyAbort:
+		return nil
+	default:
+		return fmt.Errorf("unknown failure policy \"%s\", expected %s, %s or %s", s.Policy, PolicyIgnore, PolicyWarn, PolicyAbort)
+	}
+}
+
+// String returns the name of the step or a short description if no name is configured
+func (s Step) String() string {
+	if s.Name != "" {
+		return s.Name
+	}
+
+	switch strings.ToLower(s.Type) {
+	case StepTypeCommand:
+		return fmt.Sprintf("command %s", s.Command)
+	case StepTypeConverter:
+		return fmt.Sprintf("converter %s", s.Converter)
+	default:
+		return fmt.Sprintf("webhook %s", s.URL)
+	}
+}
+
+// appliesTo checks if the step runs for the passed module
+func (s Step) appliesTo(moduleKey string) bool {
+	if len(s.Modules) == 0 {
+		return true
+	}
+
+	for _, module := range s.Modules {
+		if strings.EqualFold(module, moduleKey) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// timeout returns the configured timeout of the step or the default timeout
+func (s Step) timeout() time.Duration {
+	if s.Timeout > 0 {
+		return s.Timeout
+	}
+
+	return defaultTimeout
+}
+
+// policy returns the configured failure policy of the step, warn if not configured
+func (s Step) policy() string {
+	if s.Policy == "" {
+		return PolicyWarn
+	}
+
+	return strings.ToLower(s.Policy)
+}
@@ -0,0 +1,171 @@
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+
+	// imports for registering formats to image decoder
+	_ "image/gif"
+
+	// more imports for registering formats to image decoder
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/webp"
+)
+
+// defaultThumbnailSize is the maximum width and height of the thumbnails if no size is configured
+const defaultThumbnailSize = 256
+
+// converter converts the passed file with the passed options
+type converter func(path string, options map[string]string) error
+
+// converters contains all built-in converters usable in converter steps
+var converters = map[string]converter{
+	// thumbnail creates a JPEG thumbnail next to downloaded pictures as [name].thumb.jpg.
+	// Options: size (maximum width and height, default 256), suffix (default ".thumb")
+	"thumbnail": convertThumbnail,
+	// convert saves downloaded pictures additionally in the configured format next to the original file.
+	// Options: format (png or jpeg, default png), quality (JPEG quality, default 90)
+	"convert": convertFormat,
+}
+
+// converterNames returns the sorted names of the built-in converters
+func converterNames() string {
+	names := make([]string, 0, len(converters))
+	for name := range converters {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
+
+// convertThumbnail creates a JPEG thumbnail of the passed picture, other files are ignored
+func convertThumbnail(path string, options map[string]string) error {
+	img, ok, err := decodeImage(path)
+	if !ok {
+		return err
+	}
+
+	size := defaultThumbnailSize
+	if options["size"] != "" {
+		if size, err = strconv.Atoi(options["size"]); err != nil || size <= 0 {
+			return fmt.Errorf("invalid thumbnail size \"%s\"", options["size"])
+		}
+	}
+
+	suffix := ".thumb"
+	if value, exists := options["suffix"]; exists && value != "" {
+		suffix = value
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// only scale down, keeping the aspect ratio
+	if width > size || height > size {
+		if width >= height {
+			width, height = size, max(1, height*size/width)
+		} else {
+			width, height = max(1, width*size/height), size
+		}
+	}
+
+	thumbnail := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(thumbnail, thumbnail.Bounds(), img, bounds, draw.Src, nil)
+
+	return writeImage(strings.TrimSuffix(path, filepath.Ext(path))+suffix+".jpg", thumbnail, "jpeg", 85)
+}
+
+// convertFormat saves the passed picture in the configured format next to the original file, other files are ignored
+func convertFormat(path string, options map[string]string) error {
+	format := strings.ToLower(options["format"])
+	switch format {
+	case "":
+		format = "png"
+	case "jpg":
+		format = "jpeg"
+	case "png", "jpeg":
+	default:
+		return fmt.Errorf("unsupported format \"%s\", expected png or jpeg", options["format"])
+	}
+
+	quality := 90
+	if options["quality"] != "" {
+		var err error
+		if quality, err = strconv.Atoi(options["quality"]); err != nil || quality < 1 || quality > 100 {
+			return fmt.Errorf("invalid quality \"%s\", expected a value between 1 and 100", options["quality"])
+		}
+	}
+
+	extension := "." + format
+	if format == "jpeg" {
+		extension = ".jpg"
+	}
+
+	// nothing to convert if the file already has the requested format
+	if strings.EqualFold(filepath.Ext(path), extension) || (format == "jpeg" && strings.EqualFold(filepath.Ext(path), ".jpeg")) {
+		return nil
+	}
+
+	img, ok, err := decodeImage(path)
+	if !ok {
+		return err
+	}
+
+	return writeImage(strings.TrimSuffix(path, filepath.Ext(path))+extension, img, format, quality)
+}
+
+// decodeImage decodes the picture of the passed path.
+// Returns false without an error if the file is no supported picture, so converters can ignore other files
+func decodeImage(path string) (image.Image, bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			return nil, false, nil
+		}
+
+		return nil, false, err
+	}
+
+	return img, true, nil
+}
+
+// writeImage encodes the passed picture in the passed format to the passed path
+func writeImage(path string, img image.Image, format string, quality int) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	if format == "jpeg" {
+		return jpeg.Encode(f, img, &jpeg.Options{Quality: quality})
+	}
+
+	return png.Encode(f, img)
+}
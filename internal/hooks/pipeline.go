@@ -0,0 +1,206 @@
+// Package hooks runs the configured hook pipeline after each successful file download
+// and after each successfully parsed tracked item
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// event types passed to the hook steps
+const (
+	EventDownload = "download"
+	EventItem     = "item"
+)
+
+// Event is passed as JSON to the hook steps
+type Event struct {
+	Event  string    `json:"event"`
+	Module string    `json:"module"`
+	Time   time.Time `json:"time"`
+	File   *File     `json:"file,omitempty"`
+	Item   *Item     `json:"item,omitempty"`
+}
+
+// File describes the downloaded file of download events
+type File struct {
+	Path   string `json:"path"`
+	URL    string `json:"url,omitempty"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// Item describes the parsed tracked item of item events
+type Item struct {
+	ID          int    `json:"id"`
+	URI         string `json:"uri"`
+	SubFolder   string `json:"sub_folder,omitempty"`
+	CurrentItem string `json:"current_item,omitempty"`
+}
+
+// AbortError is returned if a step with the abort failure policy failed
+type AbortError struct {
+	Step string
+	Err  error
+}
+
+func (e AbortError) Error() string {
+	return fmt.Sprintf("hook step \"%s\" failed: %s", e.Step, e.Err.Error())
+}
+
+func (e AbortError) Unwrap() error {
+	return e.Err
+}
+
+// IsAbortError checks if the passed error got returned by a step with the abort failure policy.
+// Modules must not retry downloads with fallback urls on these errors since the file itself got downloaded
+func IsAbortError(err error) bool {
+	var abortError AbortError
+	return errors.As(err, &abortError)
+}
+
+// Pipeline runs the configured steps of the hook points. It is safe for concurrent use
+type Pipeline struct {
+	cfg Config
+}
+
+// NewPipeline returns a pipeline for the given config.
+func NewPipeline(cfg Config) *Pipeline {
+	return &Pipeline{cfg: cfg}
+}
+
+var (
+	defaultPipeline *Pipeline
+	defaultOnce     sync.Once
+)
+
+// Default returns the process-wide pipeline built from the global config.
+func Default() *Pipeline {
+	defaultOnce.Do(func() {
+		defaultPipeline = NewPipeline(LoadConfig())
+	})
+
+	return defaultPipeline
+}
+
+// HasItemSteps checks if any post item steps are configured
+func (p *Pipeline) HasItemSteps() bool {
+	return p != nil && len(p.cfg.PostItem) > 0
+}
+
+// RunDownload runs the post download steps for the passed downloaded file.
+// Returns an AbortError if a step with the abort failure policy failed
+func (p *Pipeline) RunDownload(moduleKey string, path string, uri string, size int64, sha256 string) error {
+	if p == nil || len(p.cfg.PostDownload) == 0 {
+		return nil
+	}
+
+	return p.run(p.cfg.PostDownload, &Event{
+		Event:  EventDownload,
+		Module: moduleKey,
+		Time:   time.Now(),
+		File:   &File{Path: path, URL: uri, Size: size, SHA256: sha256},
+	})
+}
+
+// RunItem runs the post item steps for the passed tracked item.
+// Returns an AbortError if a step with the abort failure policy failed
+func (p *Pipeline) RunItem(moduleKey string, item Item) error {
+	if !p.HasItemSteps() {
+		return nil
+	}
+
+	return p.run(p.cfg.PostItem, &Event{
+		Event:  EventItem,
+		Module: moduleKey,
+		Time:   time.Now(),
+		Item:   &item,
+	})
+}
+
+// run runs the passed steps for the passed event and applies the failure policies
+func (p *Pipeline) run(steps []Step, event *Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	for _, step := range steps {
+		if !step.appliesTo(event.Module) {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), step.timeout())
+		stepErr := runStep(ctx, step, event, payload)
+		cancel()
+
+		if stepErr == nil {
+			continue
+		}
+
+		if errors.Is(stepErr, context.DeadlineExceeded) {
+			stepErr = fmt.Errorf("timed out after %s", step.timeout())
+		}
+
+		switch step.policy() {
+		case PolicyIgnore:
+			slog.Debug(fmt.Sprintf("%s hook step \"%s\" failed: %s", event.Event, step, stepErr.Error()), "module", event.Module)
+		case PolicyAbort:
+			return AbortError{Step: step.String(), Err: stepErr}
+		default:
+			slog.Warn(fmt.Sprintf("%s hook step \"%s\" failed: %s", event.Event, step, stepErr.Error()), "module", event.Module)
+		}
+	}
+
+	return nil
+}
+
+// runStep runs the passed step depending on its type
+func runStep(ctx context.Context, step Step, event *Event, payload []byte) error {
+	switch strings.ToLower(step.Type) {
+	case StepTypeCommand:
+		return runCommand(ctx, step, event, payload)
+	case StepTypeConverter:
+		if event.File == nil {
+			return fmt.Errorf("converters can only be used for download hooks")
+		}
+
+		return converters[strings.ToLower(step.Converter)](event.File.Path, step.Options)
+	case StepTypeWebhook:
+		return runWebhook(ctx, step, payload)
+	default:
+		return fmt.Errorf("unknown step type \"%s\"", step.Type)
+	}
+}
+
+// expandArgument replaces the placeholders of the passed command argument with the values of the event
+func expandArgument(argument string, event *Event) string {
+	values := []string{"{event}", event.Event, "{module}", event.Module}
+
+	if event.File != nil {
+		values = append(values,
+			"{path}", event.File.Path,
+			"{url}", event.File.URL,
+			"{size}", strconv.FormatInt(event.File.Size, 10),
+			"{sha256}", event.File.SHA256,
+		)
+	}
+
+	if event.Item != nil {
+		values = append(values,
+			"{item_id}", strconv.Itoa(event.Item.ID),
+			"{item_uri}", event.Item.URI,
+			"{sub_folder}", event.Item.SubFolder,
+			"{current_item}", event.Item.CurrentItem,
+		)
+	}
+
+	return strings.NewReplacer(values...).Replace(argument)
+}
@@ -0,0 +1,182 @@
+package hooks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfig(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	viper.Set("hooks.post_download", []map[string]any{
+		{"type": "command", "command": "echo", "args": []string{"{path}"}, "policy": "abort", "timeout": "5s"},
+		{"type": "converter", "converter": "unknown"},
+		{"type": "webhook"},
+	})
+	viper.Set("hooks.post_item", []map[string]any{
+		{"type": "webhook", "url": "http://localhost", "modules": []string{"pixiv.net"}},
+	})
+
+	cfg := LoadConfig()
+	assert.Len(t, cfg.PostDownload, 1)
+	assert.Equal(t, "echo", cfg.PostDownload[0].Command)
+	assert.Equal(t, PolicyAbort, cfg.PostDownload[0].policy())
+	assert.Equal(t, "5s", cfg.PostDownload[0].timeout().String())
+	assert.Len(t, cfg.PostItem, 1)
+	assert.Equal(t, PolicyWarn, cfg.PostItem[0].policy())
+}
+
+func TestPipeline_Webhook(t *testing.T) {
+	var events []Event
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, _ := io.ReadAll(r.Body)
+
+		var event Event
+		assert.NoError(t, json.Unmarshal(content, &event))
+		assert.Equal(t, "secret", r.Header.Get("Authorization"))
+		events = append(events, event)
+	}))
+	defer server.Close()
+
+	pipeline := NewPipeline(Config{
+		PostItem: []Step{
+			{Type: StepTypeWebhook, URL: server.URL, Headers: map[string]string{"Authorization": "secret"}},
+			{Type: StepTypeWebhook, URL: server.URL, Modules: []string{"pixiv.net"}},
+		},
+	})
+
+	assert.NoError(t, pipeline.RunItem("twitter.com", Item{ID: 1, URI: "https://twitter.com/user", CurrentItem: "123"}))
+	assert.Len(t, events, 1)
+	assert.Equal(t, EventItem, events[0].Event)
+	assert.Equal(t, "twitter.com", events[0].Module)
+	assert.Equal(t, "123", events[0].Item.CurrentItem)
+	assert.Nil(t, events[0].File)
+}
+
+func TestPipeline_FailurePolicies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	for policy, expectAbort := range map[string]bool{PolicyIgnore: false, PolicyWarn: false, PolicyAbort: true} {
+		pipeline := NewPipeline(Config{
+			PostDownload: []Step{{Type: StepTypeWebhook, URL: server.URL, Policy: policy}},
+		})
+
+		err := pipeline.RunDownload("twitter.com", "file.png", "https://example.com/file.png", 10, "hash")
+
+		var abortErr AbortError
+		assert.Equal(t, expectAbort, errors.As(err, &abortErr), policy)
+		assert.Equal(t, expectAbort, IsAbortError(fmt.Errorf("wrapped: %w", err)), policy)
+	}
+
+	// no configured steps
+	assert.NoError(t, NewPipeline(Config{}).RunDownload("twitter.com", "file.png", "", 0, ""))
+}
+
+func TestPipeline_Command(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test requires a POSIX shell")
+	}
+
+	directory := t.TempDir()
+	output := filepath.Join(directory, "output.json")
+
+	pipeline := NewPipeline(Config{
+		PostDownload: []Step{{
+			Type:    StepTypeCommand,
+			Command: "sh",
+			Args:    []string{"-c", "cat > \"$0\"; echo \"$1\" >> \"$0.args\"", output, "{module}:{path}:{size}"},
+			Policy:  PolicyAbort,
+		}},
+	})
+
+	assert.NoError(t, pipeline.RunDownload("twitter.com", "/tmp/file.png", "https://example.com/file.png", 42, "hash"))
+
+	content, err := os.ReadFile(output)
+	assert.NoError(t, err)
+
+	var event Event
+	assert.NoError(t, json.Unmarshal(content, &event))
+	assert.Equal(t, EventDownload, event.Event)
+	assert.Equal(t, "/tmp/file.png", event.File.Path)
+	assert.Equal(t, int64(42), event.File.Size)
+
+	args, err := os.ReadFile(output + ".args")
+	assert.NoError(t, err)
+	assert.Equal(t, "twitter.com:/tmp/file.png:42\n", string(args))
+
+	// the placeholders are never expanded in the command itself
+	unexpanded := NewPipeline(Config{
+		PostDownload: []Step{{Type: StepTypeCommand, Command: "{path}", Policy: PolicyAbort}},
+	})
+	err = unexpanded.RunDownload("twitter.com", "sh", "", 0, "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "{path}")
+
+	failing := NewPipeline(Config{
+		PostDownload: []Step{{Type: StepTypeCommand, Command: "sh", Args: []string{"-c", "exit 1"}, Policy: PolicyAbort}},
+	})
+	assert.Error(t, failing.RunDownload("twitter.com", "/tmp/file.png", "", 0, ""))
+}
+
+func TestPipeline_Converter(t *testing.T) {
+	directory := t.TempDir()
+	picture := filepath.Join(directory, "picture.png")
+
+	img := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for x := 0; x < 400; x++ {
+		img.Set(x, x/2, color.RGBA{R: 255, A: 255})
+	}
+
+	f, err := os.Create(picture)
+	assert.NoError(t, err)
+	assert.NoError(t, png.Encode(f, img))
+	assert.NoError(t, f.Close())
+
+	text := filepath.Join(directory, "text.txt")
+	assert.NoError(t, os.WriteFile(text, []byte("no picture"), 0644))
+
+	pipeline := NewPipeline(Config{
+		PostDownload: []Step{
+			{Type: StepTypeConverter, Converter: "thumbnail", Options: map[string]string{"size": "100"}, Policy: PolicyAbort},
+			{Type: StepTypeConverter, Converter: "convert", Options: map[string]string{"format": "jpeg"}, Policy: PolicyAbort},
+		},
+	})
+
+	assert.NoError(t, pipeline.RunDownload("twitter.com", picture, "", 0, ""))
+	// files which are no pictures are ignored by the converters
+	assert.NoError(t, pipeline.RunDownload("twitter.com", text, "", 0, ""))
+
+	thumbnail, err := os.Open(filepath.Join(directory, "picture.thumb.jpg"))
+	assert.NoError(t, err)
+
+	defer func() {
+		_ = thumbnail.Close()
+	}()
+
+	config, _, err := image.DecodeConfig(thumbnail)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, config.Width)
+	assert.Equal(t, 50, config.Height)
+
+	_, err = os.Stat(filepath.Join(directory, "picture.jpg"))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,327 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"golang.org/x/time/rate"
+)
+
+// bandwidthChunkSize is the maximum amount of bytes read from a limited body before waiting for the token bucket.
+// It is also the minimum burst of the buckets, so a single read never exceeds the burst
+const bandwidthChunkSize = 32 * 1024
+
+// BandwidthConfig is the byte rate limit of the download.bandwidth block, globally or per module.
+// Limits are byte rates like "10MB", "512KiB" or "1048576", empty or "0" means unlimited
+type BandwidthConfig struct {
+	Limit string `mapstructure:"limit"`
+	// Windows override the limit during the time of the day between From and To (f.e. "08:00" to "23:00").
+	// Windows may cross midnight, the first matching window wins
+	Windows []BandwidthWindow `mapstructure:"windows"`
+}
+
+// BandwidthWindow is a time of the day window with a different byte rate limit
+type BandwidthWindow struct {
+	From  string `mapstructure:"from"`
+	To    string `mapstructure:"to"`
+	Limit string `mapstructure:"limit"`
+}
+
+// LoadBandwidthConfig reads the bandwidth config block of the passed viper key
+func LoadBandwidthConfig(key string) (cfg BandwidthConfig, err error) {
+	err = viper.UnmarshalKey(key, &cfg)
+
+	return cfg, err
+}
+
+// ParseByteRate parses byte rates like "10MB", "1.5M", "512KiB/s" or "1048576" into bytes per second.
+// K, M and G are decimal units, Ki, Mi and Gi binary units
+func ParseByteRate(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "/s"), "ps")
+	if value == "" {
+		return 0, nil
+	}
+
+	number := strings.TrimRightFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	unit := strings.ToLower(strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(value[len(number):], "B"), "b")))
+
+	multipliers := map[string]float64{
+		"": 1, "k": 1e3, "m": 1e6, "g": 1e9, "ki": 1 << 10, "mi": 1 << 20, "gi": 1 << 30,
+	}
+
+	multiplier, ok := multipliers[unit]
+	if !ok || number == "" {
+		return 0, fmt.Errorf("invalid byte rate \"%s\"", value)
+	}
+
+	parsed, err := strconv.ParseFloat(number, 64)
+	if err != nil || parsed < 0 {
+		return 0, fmt.Errorf("invalid byte rate \"%s\"", value)
+	}
+
+	return int64(parsed * multiplier), nil
+}
+
+// bandwidthWindow is a parsed time of the day window, from and to are minutes since midnight
+type bandwidthWindow struct {
+	from  int
+	to    int
+	limit int64
+}
+
+// contains checks if the passed minute of the day is inside the window, windows with from > to cross midnight
+func (w bandwidthWindow) contains(minute int) bool {
+	if w.from <= w.to {
+		return minute >= w.from && minute < w.to
+	}
+
+	return minute >= w.from || minute < w.to
+}
+
+// bandwidthSchedule is the parsed bandwidth config
+type bandwidthSchedule struct {
+	limit   int64
+	windows []bandwidthWindow
+}
+
+// parseBandwidthConfig validates and parses the passed bandwidth config
+func parseBandwidthConfig(cfg BandwidthConfig) (schedule bandwidthSchedule, err error) {
+	if schedule.limit, err = ParseByteRate(cfg.Limit); err != nil {
+		return bandwidthSchedule{}, err
+	}
+
+	for _, window := range cfg.Windows {
+		parsed := bandwidthWindow{}
+		if parsed.from, err = parseTimeOfDay(window.From); err != nil {
+			return bandwidthSchedule{}, err
+		}
+
+		if parsed.to, err = parseTimeOfDay(window.To); err != nil {
+			return bandwidthSchedule{}, err
+		}
+
+		if parsed.limit, err = ParseByteRate(window.Limit); err != nil {
+			return bandwidthSchedule{}, err
+		}
+
+		schedule.windows = append(schedule.windows, parsed)
+	}
+
+	return schedule, nil
+}
+
+// parseTimeOfDay parses a "15:04" time of the day into minutes since midnight
+func parseTimeOfDay(value string) (int, error) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of the day \"%s\", expected HH:MM", value)
+	}
+
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// limitAt returns the byte rate limit at the passed time, 0 means unlimited
+func (s bandwidthSchedule) limitAt(t time.Time) int64 {
+	minute := t.Hour()*60 + t.Minute()
+	for _, window := range s.windows {
+		if window.contains(minute) {
+			return window.limit
+		}
+	}
+
+	return s.limit
+}
+
+// unlimited checks if the schedule never limits the bandwidth
+func (s bandwidthSchedule) unlimited() bool {
+	if s.limit > 0 {
+		return false
+	}
+
+	for _, window := range s.windows {
+		if window.limit > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// bandwidthBucket is a token bucket following the limit of its schedule
+type bandwidthBucket struct {
+	schedule bandwidthSchedule
+	mu       sync.Mutex
+	limiter  *rate.Limiter
+	current  int64
+}
+
+// newBandwidthBucket returns a bucket for the passed schedule or nil if the schedule never limits the bandwidth
+func newBandwidthBucket(schedule bandwidthSchedule) *bandwidthBucket {
+	if schedule.unlimited() {
+		return nil
+	}
+
+	return &bandwidthBucket{schedule: schedule}
+}
+
+// wait blocks until n bytes are allowed by the current limit of the schedule
+func (b *bandwidthBucket) wait(ctx context.Context, n int) error {
+	if b == nil {
+		return nil
+	}
+
+	limiter := b.currentLimiter(time.Now())
+	if limiter == nil {
+		return nil
+	}
+
+	return limiter.WaitN(ctx, n)
+}
+
+// currentLimiter updates the limiter to the limit of the schedule at the passed time,
+// returns nil if the bandwidth is currently unlimited
+func (b *bandwidthBucket) currentLimiter(t time.Time) *rate.Limiter {
+	limit := b.schedule.limitAt(t)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if limit <= 0 {
+		b.current = 0
+		return nil
+	}
+
+	if b.limiter == nil || b.current != limit {
+		burst := int(max(limit, bandwidthChunkSize))
+		if b.limiter == nil {
+			b.limiter = rate.NewLimiter(rate.Limit(limit), burst)
+		} else {
+			b.limiter.SetLimit(rate.Limit(limit))
+			b.limiter.SetBurst(burst)
+		}
+
+		b.current = limit
+	}
+
+	return b.limiter
+}
+
+// BandwidthLimiter limits the byte rate of response bodies globally and per module
+type BandwidthLimiter struct {
+	global  *bandwidthBucket
+	mu      sync.RWMutex
+	modules map[string]*bandwidthBucket
+}
+
+// GlobalBandwidth is the process-wide bandwidth limiter. Initialized once at startup via
+// InitGlobalBandwidth. Nil before initialization; nil-safe to call.
+var GlobalBandwidth *BandwidthLimiter
+
+// InitGlobalBandwidth (re-)initializes the package-global bandwidth limiter with the passed global limit.
+// Invalid configurations return an error and leave the global bandwidth unlimited
+func InitGlobalBandwidth(cfg BandwidthConfig) error {
+	limiter, err := NewBandwidthLimiter(cfg)
+	GlobalBandwidth = limiter
+
+	return err
+}
+
+// NewBandwidthLimiter returns a bandwidth limiter with the passed global limit
+func NewBandwidthLimiter(cfg BandwidthConfig) (*BandwidthLimiter, error) {
+	limiter := &BandwidthLimiter{modules: make(map[string]*bandwidthBucket)}
+
+	schedule, err := parseBandwidthConfig(cfg)
+	if err != nil {
+		return limiter, err
+	}
+
+	limiter.global = newBandwidthBucket(schedule)
+
+	return limiter, nil
+}
+
+// SetModuleConfig sets the bandwidth limit of the passed module, applied additionally to the global limit.
+// Invalid configurations return an error and leave the module unlimited
+func (l *BandwidthLimiter) SetModuleConfig(moduleKey string, cfg BandwidthConfig) error {
+	if l == nil {
+		return nil
+	}
+
+	schedule, err := parseBandwidthConfig(cfg)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err != nil {
+		delete(l.modules, moduleKey)
+		return err
+	}
+
+	l.modules[moduleKey] = newBandwidthBucket(schedule)
+
+	return nil
+}
+
+// Wait blocks until n bytes of the passed module are allowed by the global and the module limit
+func (l *BandwidthLimiter) Wait(ctx context.Context, moduleKey string, n int) error {
+	if l == nil {
+		return nil
+	}
+
+	if err := l.global.wait(ctx, n); err != nil {
+		return err
+	}
+
+	return l.module(moduleKey).wait(ctx, n)
+}
+
+// WrapBody returns a body limited to the bandwidth of the passed module.
+// If neither a global nor a module limit is configured the body is returned unchanged
+func (l *BandwidthLimiter) WrapBody(ctx context.Context, moduleKey string, body io.ReadCloser) io.ReadCloser {
+	if l == nil || body == nil || (l.global == nil && l.module(moduleKey) == nil) {
+		return body
+	}
+
+	return &bandwidthLimitedBody{ReadCloser: body, ctx: ctx, limiter: l, moduleKey: moduleKey}
+}
+
+// module returns the bucket of the passed module, nil if the module is unlimited
+func (l *BandwidthLimiter) module(moduleKey string) *bandwidthBucket {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.modules[moduleKey]
+}
+
+// bandwidthLimitedBody waits for the bandwidth limiter after every read
+type bandwidthLimitedBody struct {
+	io.ReadCloser
+	ctx       context.Context
+	limiter   *BandwidthLimiter
+	moduleKey string
+}
+
+// Read reads at most one chunk and waits until the read bytes are allowed by the bandwidth limiter
+func (b *bandwidthLimitedBody) Read(p []byte) (int, error) {
+	if len(p) > bandwidthChunkSize {
+		p = p[:bandwidthChunkSize]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		if waitErr := b.limiter.Wait(b.ctx, b.moduleKey, n); waitErr != nil && err == nil {
+			err = waitErr
+		}
+	}
+
+	return n, err
+}
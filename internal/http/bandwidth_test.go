@@ -0,0 +1,93 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseByteRate(t *testing.T) {
+	for input, expected := range map[string]int64{
+		"":          0,
+		"0":         0,
+		"1048576":   1048576,
+		"10MB":      10_000_000,
+		"1.5M":      1_500_000,
+		"512KiB/s":  512 * 1024,
+		"2 MiB":     2 * 1024 * 1024,
+		"100kbps":   100_000,
+		"1GiB":      1 << 30,
+		" 250 KB  ": 250_000,
+	} {
+		rate, err := ParseByteRate(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, rate, input)
+	}
+
+	for _, input := range []string{"fast", "10XB", "-5MB", "MB"} {
+		_, err := ParseByteRate(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestBandwidthSchedule_LimitAt(t *testing.T) {
+	schedule, err := parseBandwidthConfig(BandwidthConfig{
+		Limit: "10MB",
+		Windows: []BandwidthWindow{
+			{From: "08:00", To: "18:00", Limit: "1MB"},
+			{From: "22:00", To: "02:00", Limit: "0"},
+		},
+	})
+	assert.NoError(t, err)
+
+	at := func(hour int, minute int) time.Time {
+		return time.Date(2024, 1, 1, hour, minute, 0, 0, time.Local)
+	}
+
+	assert.Equal(t, int64(10_000_000), schedule.limitAt(at(7, 59)))
+	assert.Equal(t, int64(1_000_000), schedule.limitAt(at(8, 0)))
+	assert.Equal(t, int64(10_000_000), schedule.limitAt(at(18, 0)))
+	assert.Equal(t, int64(0), schedule.limitAt(at(23, 30)))
+	assert.Equal(t, int64(0), schedule.limitAt(at(1, 59)))
+	assert.Equal(t, int64(10_000_000), schedule.limitAt(at(2, 0)))
+
+	_, err = parseBandwidthConfig(BandwidthConfig{Windows: []BandwidthWindow{{From: "8am", To: "18:00"}}})
+	assert.Error(t, err)
+}
+
+func TestBandwidthLimiter_WrapBody(t *testing.T) {
+	var nilLimiter *BandwidthLimiter
+	body := io.NopCloser(bytes.NewReader(nil))
+	assert.Equal(t, body, nilLimiter.WrapBody(context.Background(), "test", body))
+
+	limiter, err := NewBandwidthLimiter(BandwidthConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, body, limiter.WrapBody(context.Background(), "test", body))
+
+	// the module limit applies to the module only
+	assert.NoError(t, limiter.SetModuleConfig("test", BandwidthConfig{Limit: "32KiB"}))
+	assert.Error(t, limiter.SetModuleConfig("invalid", BandwidthConfig{Limit: "fast"}))
+	assert.Equal(t, body, limiter.WrapBody(context.Background(), "other", body))
+	assert.Equal(t, body, limiter.WrapBody(context.Background(), "invalid", body))
+
+	content := bytes.Repeat([]byte{1}, 64*1024)
+	start := time.Now()
+
+	read, err := io.ReadAll(limiter.WrapBody(context.Background(), "test", io.NopCloser(bytes.NewReader(content))))
+	assert.NoError(t, err)
+	assert.Equal(t, content, read)
+
+	// the first 32KiB are covered by the burst, the remaining 32KiB take a second
+	assert.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+
+	// canceled contexts abort the read
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = io.ReadAll(limiter.WrapBody(ctx, "test", io.NopCloser(bytes.NewReader(content))))
+	assert.Error(t, err)
+}
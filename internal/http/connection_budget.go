@@ -10,6 +10,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/DaRealFreak/watcher-go/internal/metrics"
 	"golang.org/x/net/publicsuffix"
 )
 
@@ -257,7 +258,9 @@ func (l *ConnectionLimiter) Acquire(ctx context.Context, poolKey, host string) (
 		}
 		cooldownEnd.Store(deadline.UnixNano())
 		waitState.Store(waitStateCooldown)
+		metrics.BudgetWaiting.Add(1, poolKey, "cooldown")
 		l.mu.Unlock()
+		waitStart := time.Now()
 		timer := time.NewTimer(wait)
 		var ctxErr error
 		select {
@@ -266,8 +269,10 @@ func (l *ConnectionLimiter) Acquire(ctx context.Context, poolKey, host string) (
 			ctxErr = ctx.Err()
 		}
 		timer.Stop()
+		metrics.BudgetCooldownWaitSeconds.Add(time.Since(waitStart).Seconds(), poolKey)
 		l.mu.Lock()
 		waitState.Store(waitStateUnknown)
+		metrics.BudgetWaiting.Add(-1, poolKey, "cooldown")
 		return true, ctxErr
 	}
 
@@ -316,8 +321,10 @@ func (l *ConnectionLimiter) Acquire(ctx context.Context, poolKey, host string) (
 				))
 			}
 			waitState.Store(waitStateAtCap)
+			metrics.BudgetWaiting.Add(1, poolKey, "at_cap")
 			l.cond.Wait()
 			waitState.Store(waitStateUnknown)
+			metrics.BudgetWaiting.Add(-1, poolKey, "at_cap")
 			continue
 		}
 
@@ -9,6 +9,8 @@ import (
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/metrics"
 )
 
 func policy(max int) map[string]DomainPolicy {
@@ -475,3 +477,42 @@ func TestConnectionBudget_EvictsOnlyIdle(t *testing.T) {
 	}
 	slotA.Release()
 }
+
+func TestConnectionBudget_Metrics(t *testing.T) {
+	b := NewConnectionBudget(policy(1))
+	first := &ProxySettings{Enable: true, Host: "us1.proxy.nordvpn.com", Username: "metrics"}
+	second := &ProxySettings{Enable: true, Host: "us2.proxy.nordvpn.com", Username: "metrics"}
+
+	s1, err := b.Acquire(context.Background(), "", first)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := b.Stats()
+	if len(stats) != 1 || stats[0].Pool != "metrics@nordvpn.com" || stats[0].Active != 1 || stats[0].Capacity != 1 {
+		t.Fatalf("unexpected pool stats %+v", stats)
+	}
+
+	acquired := make(chan *Slot)
+	go func() {
+		s, _ := b.Acquire(context.Background(), "", second)
+		acquired <- s
+	}()
+
+	// the second host waits at the cap until the first host gets idle
+	deadline := time.Now().Add(2 * time.Second)
+	for metrics.BudgetWaiting.Get("metrics@nordvpn.com", "at_cap") != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("at cap wait state was not published")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	s1.Release()
+	s2 := <-acquired
+	defer s2.Release()
+
+	if waiting := metrics.BudgetWaiting.Get("metrics@nordvpn.com", "at_cap"); waiting != 0 {
+		t.Fatalf("expected no waiting requests after the acquire, got %v", waiting)
+	}
+}
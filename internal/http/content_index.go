@@ -0,0 +1,214 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DuplicatePolicy defines how downloaded files are handled if a byte-identical file is already indexed
+type DuplicatePolicy string
+
+const (
+	// DuplicatePolicyNone keeps every downloaded file, the content index is only populated
+	DuplicatePolicyNone DuplicatePolicy = ""
+	// DuplicatePolicySkip doesn't save the downloaded file if a byte-identical file already exists
+	DuplicatePolicySkip DuplicatePolicy = "skip"
+	// DuplicatePolicyHardlink creates a hard link to the existing byte-identical file instead
+	DuplicatePolicyHardlink DuplicatePolicy = "hardlink"
+	// DuplicatePolicySymlink creates a symbolic link to the existing byte-identical file instead
+	DuplicatePolicySymlink DuplicatePolicy = "symlink"
+)
+
+// ContentIndexStore persists the hashes of the downloaded files, implemented by the database
+type ContentIndexStore interface {
+	AddFileHash(path string, sha256 string, size int64, module string)
+	GetFilePathsByHash(sha256 string, size int64) (paths []string)
+	DeleteFileHash(path string)
+}
+
+// ContentIndex is the content-addressed index of all downloaded files across all modules
+type ContentIndex struct {
+	store  ContentIndexStore
+	policy DuplicatePolicy
+	root   string
+}
+
+// GlobalContentIndex is the process-wide content index. Initialized once at startup via
+// InitGlobalContentIndex. Nil before initialization; nil-safe to call.
+var GlobalContentIndex *ContentIndex
+
+// InitGlobalContentIndex (re-)initializes the package-global content index.
+// Only byte-identical files below the passed root directory are considered as duplicates
+func InitGlobalContentIndex(store ContentIndexStore, policy DuplicatePolicy, root string) {
+	if root != "" {
+		root = absPath(root)
+	}
+
+	GlobalContentIndex = &ContentIndex{
+		store:  store,
+		policy: policy,
+		root:   root,
+	}
+}
+
+// ParseDuplicatePolicy parses the passed duplicate policy, returns an error for unknown policies
+func ParseDuplicatePolicy(policy string) (DuplicatePolicy, error) {
+	switch DuplicatePolicy(strings.ToLower(policy)) {
+	case DuplicatePolicyNone, "none":
+		return DuplicatePolicyNone, nil
+	case DuplicatePolicySkip:
+		return DuplicatePolicySkip, nil
+	case DuplicatePolicyHardlink:
+		return DuplicatePolicyHardlink, nil
+	case DuplicatePolicySymlink:
+		return DuplicatePolicySymlink, nil
+	default:
+		return DuplicatePolicyNone, fmt.Errorf(
+			"unsupported duplicate policy \"%s\", expected none, %s, %s or %s",
+			policy, DuplicatePolicySkip, DuplicatePolicyHardlink, DuplicatePolicySymlink,
+		)
+	}
+}
+
+// HashFile returns the hex encoded SHA-256 hash and the size of the passed file
+func HashFile(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	hash := sha256.New()
+
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// Add adds the passed file to the content index, the path is stored as absolute path
+// to not depend on the working directory of the run
+func (i *ContentIndex) Add(moduleKey string, path string, sha256 string, size int64) {
+	if i == nil {
+		return
+	}
+
+	i.store.AddFileHash(absPath(path), sha256, size, moduleKey)
+}
+
+// FindDuplicate returns the path of an indexed byte-identical file below the root directory
+// other than the passed path. Indexed files which got removed or changed in the meantime are removed from the index
+func (i *ContentIndex) FindDuplicate(path string, sha256 string, size int64) string {
+	if i == nil || i.policy == DuplicatePolicyNone {
+		return ""
+	}
+
+	path = absPath(path)
+
+	for _, candidate := range i.store.GetFilePathsByHash(sha256, size) {
+		if absPath(candidate) == path || !i.isBelowRoot(candidate) {
+			continue
+		}
+
+		stat, err := os.Lstat(candidate)
+		if err != nil || !stat.Mode().IsRegular() || stat.Size() != size {
+			i.store.DeleteFileHash(candidate)
+			continue
+		}
+
+		return candidate
+	}
+
+	return ""
+}
+
+// ApplyPolicy replaces the downloaded file at the passed part path with the configured handling of the duplicate.
+// Returns false if the downloaded file has to be kept, f.e. if the link couldn't be created
+func (i *ContentIndex) ApplyPolicy(moduleKey string, duplicate string, partPath string, path string) bool {
+	if i == nil {
+		return false
+	}
+
+	switch i.policy {
+	case DuplicatePolicySkip:
+		slog.Info(
+			fmt.Sprintf("skipping download of \"%s\", byte-identical file \"%s\" already exists", path, duplicate),
+			"module", moduleKey,
+		)
+	case DuplicatePolicyHardlink, DuplicatePolicySymlink:
+		// the link can't be created if a file already exists at the path
+		_ = os.Remove(path)
+
+		var err error
+		if i.policy == DuplicatePolicyHardlink {
+			err = os.Link(duplicate, path)
+		} else {
+			target, absErr := filepath.Abs(duplicate)
+			if absErr != nil {
+				target = duplicate
+			}
+
+			err = os.Symlink(target, path)
+		}
+
+		if err != nil {
+			slog.Warn(
+				fmt.Sprintf("unable to link \"%s\" to byte-identical file \"%s\", keeping the download: %s", path, duplicate, err.Error()),
+				"module", moduleKey,
+			)
+
+			return false
+		}
+
+		slog.Info(
+			fmt.Sprintf("linked \"%s\" to byte-identical file \"%s\" (%s)", path, duplicate, i.policy),
+			"module", moduleKey,
+		)
+	default:
+		return false
+	}
+
+	_ = os.Remove(partPath)
+
+	return true
+}
+
+// Policy returns the configured duplicate policy
+func (i *ContentIndex) Policy() DuplicatePolicy {
+	if i == nil {
+		return DuplicatePolicyNone
+	}
+
+	return i.policy
+}
+
+// isBelowRoot checks if the passed path is located below the root directory of the content index
+func (i *ContentIndex) isBelowRoot(path string) bool {
+	if i.root == "" {
+		return true
+	}
+
+	relativePath, err := filepath.Rel(i.root, absPath(path))
+
+	return err == nil && relativePath != ".." && !strings.HasPrefix(relativePath, ".."+string(filepath.Separator))
+}
+
+// absPath returns the absolute path of the passed path or the cleaned path if it can't be resolved
+func absPath(path string) string {
+	if absolutePath, err := filepath.Abs(path); err == nil {
+		return absolutePath
+	}
+
+	return filepath.Clean(path)
+}
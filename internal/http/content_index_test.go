@@ -0,0 +1,172 @@
+package http
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// memoryContentIndexStore is an in-memory ContentIndexStore for the unit tests
+type memoryContentIndexStore struct {
+	hashes map[string]string
+}
+
+func (s *memoryContentIndexStore) AddFileHash(path string, sha256 string, _ int64, _ string) {
+	s.hashes[path] = sha256
+}
+
+func (s *memoryContentIndexStore) GetFilePathsByHash(sha256 string, _ int64) (paths []string) {
+	for path, hash := range s.hashes {
+		if hash == sha256 {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
+
+func (s *memoryContentIndexStore) DeleteFileHash(path string) {
+	delete(s.hashes, path)
+}
+
+// downloadTestFile writes the passed content through a partial download to the passed path
+func downloadTestFile(t *testing.T, path string, content string) *PartialDownload {
+	t.Helper()
+
+	download, err := StartPartialDownload("test", path, http.StatusOK, make(http.Header))
+	if err != nil {
+		t.Fatalf("unable to start download: %v", err)
+	}
+
+	if _, err = download.Write([]byte(content)); err != nil {
+		t.Fatalf("unable to write download: %v", err)
+	}
+
+	if err = download.Finish(); err != nil {
+		t.Fatalf("unable to finish download: %v", err)
+	}
+
+	return download
+}
+
+func TestContentIndex(t *testing.T) {
+	defer func() {
+		GlobalContentIndex = nil
+	}()
+
+	for _, policy := range []DuplicatePolicy{DuplicatePolicyNone, DuplicatePolicySkip, DuplicatePolicyHardlink, DuplicatePolicySymlink} {
+		directory := t.TempDir()
+		store := &memoryContentIndexStore{hashes: make(map[string]string)}
+		InitGlobalContentIndex(store, policy, directory)
+
+		original := filepath.Join(directory, "pixiv", "artwork.png")
+		duplicate := filepath.Join(directory, "twitter", "artwork.png")
+
+		if err := os.MkdirAll(filepath.Dir(original), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(duplicate), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		downloadTestFile(t, original, "artwork")
+		if downloadTestFile(t, duplicate, "artwork").Skipped() != (policy == DuplicatePolicySkip) {
+			t.Fatalf("expected the duplicate only to be reported as skipped for the skip policy, policy \"%s\"", policy)
+		}
+
+		if _, err := os.Stat(GetPartFilePath(duplicate)); !os.IsNotExist(err) {
+			t.Fatalf("expected no partial file to be left for policy \"%s\"", policy)
+		}
+
+		stat, err := os.Lstat(duplicate)
+
+		switch policy {
+		case DuplicatePolicyNone:
+			if err != nil || !stat.Mode().IsRegular() || len(store.hashes) != 2 {
+				t.Fatalf("expected the duplicate to be saved and indexed without a policy")
+			}
+		case DuplicatePolicySkip:
+			if !os.IsNotExist(err) || len(store.hashes) != 1 {
+				t.Fatalf("expected the duplicate to be skipped")
+			}
+		case DuplicatePolicyHardlink:
+			originalStat, _ := os.Stat(original)
+			if err != nil || !os.SameFile(stat, originalStat) || len(store.hashes) != 2 {
+				t.Fatalf("expected the duplicate to be a hard link to the original file")
+			}
+		case DuplicatePolicySymlink:
+			if err != nil || stat.Mode()&os.ModeSymlink == 0 || len(store.hashes) != 1 {
+				t.Fatalf("expected the duplicate to be a symbolic link to the original file")
+			}
+		}
+	}
+}
+
+func TestContentIndex_RelativePaths(t *testing.T) {
+	directory := t.TempDir()
+	t.Chdir(directory)
+
+	store := &memoryContentIndexStore{hashes: make(map[string]string)}
+	index := &ContentIndex{store: store, policy: DuplicatePolicySkip}
+
+	if err := os.WriteFile("artwork.png", []byte("artwork"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	index.Add("test", "artwork.png", "hash", 7)
+
+	absolutePath := filepath.Join(directory, "artwork.png")
+	if _, ok := store.hashes[absolutePath]; !ok {
+		t.Fatalf("expected the file to be indexed with its absolute path, got %v", store.hashes)
+	}
+
+	// the same file is not its own duplicate, independent of the passed path being relative or absolute
+	if duplicate := index.FindDuplicate("artwork.png", "hash", 7); duplicate != "" {
+		t.Fatalf("expected no duplicate for the indexed file itself, got %s", duplicate)
+	}
+
+	if duplicate := index.FindDuplicate("copy.png", "hash", 7); duplicate != absolutePath {
+		t.Fatalf("expected the absolute path of the indexed file as duplicate, got %s", duplicate)
+	}
+}
+
+func TestContentIndex_StaleEntries(t *testing.T) {
+	directory := t.TempDir()
+	store := &memoryContentIndexStore{hashes: map[string]string{
+		filepath.Join(directory, "removed.png"): "hash",
+		"/outside/of/root.png":                  "hash",
+	}}
+	index := &ContentIndex{store: store, policy: DuplicatePolicySkip, root: directory}
+
+	if duplicate := index.FindDuplicate(filepath.Join(directory, "new.png"), "hash", 4); duplicate != "" {
+		t.Fatalf("expected no duplicate, got %s", duplicate)
+	}
+
+	if _, ok := store.hashes[filepath.Join(directory, "removed.png")]; ok {
+		t.Fatal("expected the removed file to be removed from the index")
+	}
+
+	if _, ok := store.hashes["/outside/of/root.png"]; !ok {
+		t.Fatal("expected files outside of the root directory to be ignored but kept in the index")
+	}
+}
+
+func TestParseDuplicatePolicy(t *testing.T) {
+	for policy, expected := range map[string]DuplicatePolicy{
+		"":         DuplicatePolicyNone,
+		"none":     DuplicatePolicyNone,
+		"Hardlink": DuplicatePolicyHardlink,
+		"symlink":  DuplicatePolicySymlink,
+		"skip":     DuplicatePolicySkip,
+	} {
+		if parsed, err := ParseDuplicatePolicy(policy); err != nil || parsed != expected {
+			t.Fatalf("expected policy %q to be parsed as %q, got %q (%v)", policy, expected, parsed, err)
+		}
+	}
+
+	if _, err := ParseDuplicatePolicy("copy"); err == nil {
+		t.Fatal("expected an error for unknown policies")
+	}
+}
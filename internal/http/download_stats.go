@@ -0,0 +1,120 @@
+package http
+
+import (
+	"sync"
+
+	"github.com/DaRealFreak/watcher-go/internal/metrics"
+)
+
+// DownloadCounter contains the amount of downloaded files and bytes
+type DownloadCounter struct {
+	Files int
+	Bytes int64
+}
+
+// DownloadStats counts the successfully downloaded files and bytes per module and per session.
+// The run history claims the downloads of the module after parsing an item to attribute them to the item,
+// items parsed at the same time by multiple workers claim the downloads of the session of their worker.
+type DownloadStats struct {
+	mu       sync.Mutex
+	counters map[string]DownloadCounter
+	claimed  map[string]DownloadCounter
+	sessions map[any]DownloadCounter
+}
+
+// GlobalDownloadStats is the process-wide download counter. Initialized once at startup via
+// InitGlobalDownloadStats. Nil before initialization; nil-safe to call.
+var GlobalDownloadStats *DownloadStats
+
+// InitGlobalDownloadStats (re-)initializes the package-global download counter.
+func InitGlobalDownloadStats() {
+	GlobalDownloadStats = NewDownloadStats()
+}
+
+// NewDownloadStats returns an empty download counter
+func NewDownloadStats() *DownloadStats {
+	return &DownloadStats{
+		counters: make(map[string]DownloadCounter),
+		claimed:  make(map[string]DownloadCounter),
+		sessions: make(map[any]DownloadCounter),
+	}
+}
+
+// RecordDownload adds a downloaded file with the passed size to the counter of the passed module
+// and to the unclaimed downloads of the passed session
+func (s *DownloadStats) RecordDownload(moduleKey string, session any, bytes int64) {
+	if s == nil {
+		return
+	}
+
+	metrics.FilesDownloaded.Inc(moduleKey)
+	metrics.BytesDownloaded.Add(float64(bytes), moduleKey)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counter := s.counters[moduleKey]
+	counter.Files++
+	counter.Bytes += bytes
+	s.counters[moduleKey] = counter
+
+	if session != nil {
+		sessionCounter := s.sessions[session]
+		sessionCounter.Files++
+		sessionCounter.Bytes += bytes
+		s.sessions[session] = sessionCounter
+	}
+}
+
+// Get returns the current counter of the passed module
+func (s *DownloadStats) Get(moduleKey string) DownloadCounter {
+	if s == nil {
+		return DownloadCounter{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.counters[moduleKey]
+}
+
+// Claim returns the downloads of the passed module since the last claim.
+// Only usable if a single item of the module is parsed at a time, use ClaimSession for multiple workers
+func (s *DownloadStats) Claim(moduleKey string) DownloadCounter {
+	if s == nil {
+		return DownloadCounter{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counter, claimed := s.counters[moduleKey], s.claimed[moduleKey]
+	s.claimed[moduleKey] = counter
+
+	return DownloadCounter{
+		Files: counter.Files - claimed.Files,
+		Bytes: counter.Bytes - claimed.Bytes,
+	}
+}
+
+// ClaimSession returns the downloads of the passed session of the passed module since the last claim.
+// Every worker parses its items with its own session, so the downloads of the session belong to the parsed item
+func (s *DownloadStats) ClaimSession(moduleKey string, session any) DownloadCounter {
+	if s == nil {
+		return DownloadCounter{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counter := s.sessions[session]
+	delete(s.sessions, session)
+
+	// the downloads are claimed, so they are not returned again by a following claim of the module
+	claimed := s.claimed[moduleKey]
+	claimed.Files += counter.Files
+	claimed.Bytes += counter.Bytes
+	s.claimed[moduleKey] = claimed
+
+	return counter
+}
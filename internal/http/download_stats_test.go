@@ -0,0 +1,64 @@
+package http
+
+import "testing"
+
+// TestDownloadStats_Claim checks that every download is only claimed once.
+func TestDownloadStats_Claim(t *testing.T) {
+	stats := NewDownloadStats()
+
+	stats.RecordDownload("module", nil, 100)
+	stats.RecordDownload("module", nil, 50)
+	stats.RecordDownload("other", nil, 10)
+
+	if claimed := stats.Claim("module"); claimed.Files != 2 || claimed.Bytes != 150 {
+		t.Fatalf("expected 2 files/150 bytes to be claimed, got %+v", claimed)
+	}
+
+	if claimed := stats.Claim("module"); claimed.Files != 0 || claimed.Bytes != 0 {
+		t.Fatalf("expected nothing to be claimed twice, got %+v", claimed)
+	}
+
+	stats.RecordDownload("module", nil, 25)
+
+	if claimed := stats.Claim("module"); claimed.Files != 1 || claimed.Bytes != 25 {
+		t.Fatalf("expected 1 file/25 bytes to be claimed, got %+v", claimed)
+	}
+
+	if total := stats.Get("module"); total.Files != 3 || total.Bytes != 175 {
+		t.Fatalf("expected the total to be unaffected by claims, got %+v", total)
+	}
+
+	var nilStats *DownloadStats
+	nilStats.RecordDownload("module", nil, 1)
+
+	if claimed := nilStats.Claim("module"); claimed.Files != 0 {
+		t.Fatalf("expected nil stats to be a no-op, got %+v", claimed)
+	}
+}
+
+// TestDownloadStats_ClaimSession checks that items parsed at the same time by multiple workers
+// are only attributed the downloads of the session of their worker.
+func TestDownloadStats_ClaimSession(t *testing.T) {
+	stats := NewDownloadStats()
+	first, second := new(int), new(int)
+
+	stats.RecordDownload("module", first, 100)
+	stats.RecordDownload("module", second, 50)
+	stats.RecordDownload("module", first, 25)
+
+	if claimed := stats.ClaimSession("module", second); claimed.Files != 1 || claimed.Bytes != 50 {
+		t.Fatalf("expected 1 file/50 bytes to be claimed by the second session, got %+v", claimed)
+	}
+
+	if claimed := stats.ClaimSession("module", first); claimed.Files != 2 || claimed.Bytes != 125 {
+		t.Fatalf("expected 2 files/125 bytes to be claimed by the first session, got %+v", claimed)
+	}
+
+	if claimed := stats.ClaimSession("module", first); claimed.Files != 0 {
+		t.Fatalf("expected nothing to be claimed twice, got %+v", claimed)
+	}
+
+	if claimed := stats.Claim("module"); claimed.Files != 0 {
+		t.Fatalf("expected the session claims to be claimed for the module, got %+v", claimed)
+	}
+}
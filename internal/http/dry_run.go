@@ -0,0 +1,94 @@
+package http
+
+import "sync"
+
+// PlannedDownload is a download which would have been executed if the run wasn't a dry run
+type PlannedDownload struct {
+	Module  string `json:"module"`
+	ItemID  int    `json:"item_id"`
+	ItemURI string `json:"item_uri"`
+	Path    string `json:"path"`
+	URI     string `json:"uri"`
+	// session is the session which would have downloaded the file, nil for files written by the modules
+	session any
+}
+
+// DryRun records the downloads of the modules instead of executing them.
+// The sessions check it before downloading, so the modules don't have to handle dry runs themselves.
+type DryRun struct {
+	mu        sync.Mutex
+	enabled   bool
+	downloads []*PlannedDownload
+}
+
+// GlobalDryRun is the process-wide dry run recorder. Initialized once at startup via
+// InitGlobalDryRun. Nil before initialization; nil-safe to call.
+var GlobalDryRun *DryRun
+
+// InitGlobalDryRun (re-)initializes the package-global dry run recorder.
+func InitGlobalDryRun(enabled bool) {
+	GlobalDryRun = &DryRun{enabled: enabled}
+}
+
+// Enabled returns true if downloads should only be recorded instead of executed
+func (d *DryRun) Enabled() bool {
+	return d != nil && d.enabled
+}
+
+// RecordDownload records the download of the passed URI to the passed file path
+func (d *DryRun) RecordDownload(moduleKey string, filepath string, uri string) {
+	d.RecordSessionDownload(moduleKey, nil, filepath, uri)
+}
+
+// RecordSessionDownload records the download of the passed URI to the passed file path by the passed session
+func (d *DryRun) RecordSessionDownload(moduleKey string, session any, filepath string, uri string) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.downloads = append(d.downloads, &PlannedDownload{
+		Module:  moduleKey,
+		Path:    filepath,
+		URI:     uri,
+		session: session,
+	})
+}
+
+// AssignItem assigns the recorded downloads of the passed module without an item to the passed item.
+// With a single worker per module all downloads recorded since the last call belong to the item. Items parsed
+// by one of multiple workers pass the session of their worker to only get the downloads of the session assigned
+func (d *DryRun) AssignItem(moduleKey string, session any, itemID int, itemURI string) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, download := range d.downloads {
+		if download.Module == moduleKey && download.ItemID == 0 && (session == nil || download.session == session) {
+			download.ItemID = itemID
+			download.ItemURI = itemURI
+		}
+	}
+}
+
+// Downloads returns all recorded downloads in the order they got recorded
+func (d *DryRun) Downloads() []PlannedDownload {
+	if d == nil {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	downloads := make([]PlannedDownload, 0, len(d.downloads))
+	for _, download := range d.downloads {
+		downloads = append(downloads, *download)
+	}
+
+	return downloads
+}
@@ -0,0 +1,36 @@
+package http
+
+import "testing"
+
+// TestDryRun_AssignItem checks that items parsed by multiple workers only get the downloads
+// of the session of their worker assigned.
+func TestDryRun_AssignItem(t *testing.T) {
+	dryRun := &DryRun{enabled: true}
+	first, second := new(int), new(int)
+
+	dryRun.RecordSessionDownload("module", first, "a", "https://example.com/a")
+	dryRun.RecordSessionDownload("module", second, "b", "https://example.com/b")
+	dryRun.RecordSessionDownload("module", first, "c", "https://example.com/c")
+
+	dryRun.AssignItem("module", second, 2, "https://example.com/2")
+	dryRun.AssignItem("module", first, 1, "https://example.com/1")
+
+	for _, download := range dryRun.Downloads() {
+		expected := 1
+		if download.Path == "b" {
+			expected = 2
+		}
+
+		if download.ItemID != expected {
+			t.Fatalf("expected download %s to be assigned to item %d, got %d", download.Path, expected, download.ItemID)
+		}
+	}
+
+	// files written by the modules are assigned to the next item of the module
+	dryRun.RecordDownload("module", "d", "https://example.com/d")
+	dryRun.AssignItem("module", nil, 3, "https://example.com/3")
+
+	if downloads := dryRun.Downloads(); downloads[3].ItemID != 3 {
+		t.Fatalf("expected download d to be assigned to item 3, got %d", downloads[3].ItemID)
+	}
+}
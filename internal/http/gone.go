@@ -0,0 +1,24 @@
+package http
+
+import (
+	"errors"
+)
+
+// GoneError is implemented by errors which can indicate that the requested content got deleted
+// (f.e. 404 status codes or DMCA takedowns). Items failing repeatedly with these errors get quarantined earlier
+type GoneError interface {
+	error
+	Gone() bool
+}
+
+// IsGoneError returns true if the passed error or one of its wrapped errors indicates deleted content
+func IsGoneError(err error) bool {
+	var goneError GoneError
+
+	return errors.As(err, &goneError) && goneError.Gone()
+}
+
+// IsGoneStatusCode returns true for status codes indicating that the requested resource doesn't exist anymore
+func IsGoneStatusCode(statusCode int) bool {
+	return statusCode == 404 || statusCode == 410
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/metrics"
+)
+
+// PoolStats is the occupancy of a connection budget pool
+type PoolStats struct {
+	Pool     string
+	Active   int
+	Idle     int
+	Capacity int
+}
+
+// LeaseStats is the reservation of a module lease
+type LeaseStats struct {
+	Module  string
+	Account string
+	Slots   int
+}
+
+func init() {
+	metrics.Default().RegisterCollector(collectMetrics)
+}
+
+// collectMetrics updates the occupancy gauges of the global connection budget and lease coordinator
+func collectMetrics() {
+	metrics.BudgetPoolHosts.Reset()
+	metrics.BudgetPoolCapacity.Reset()
+
+	for _, pool := range Global.Stats() {
+		metrics.BudgetPoolHosts.Set(float64(pool.Active), pool.Pool, "active")
+		metrics.BudgetPoolHosts.Set(float64(pool.Idle), pool.Pool, "idle")
+		metrics.BudgetPoolCapacity.Set(float64(pool.Capacity), pool.Pool)
+	}
+
+	metrics.LeaseSlots.Reset()
+
+	for _, lease := range GlobalLeases.Stats() {
+		metrics.LeaseSlots.Set(float64(lease.Slots), lease.Module, lease.Account)
+	}
+}
+
+// Stats returns the current occupancy of all pools of the budget
+func (b *ConnectionBudget) Stats() (stats []PoolStats) {
+	if b == nil {
+		return nil
+	}
+
+	b.pools.Range(func(key, value any) bool {
+		limiter := value.(*ConnectionLimiter)
+		active, idle := limiter.occupancy()
+		stats = append(stats, PoolStats{Pool: key.(string), Active: active, Idle: idle, Capacity: limiter.cap})
+
+		return true
+	})
+
+	return stats
+}
+
+// Stats returns the reservations of all active leases
+func (c *LeaseCoordinator) Stats() (stats []LeaseStats) {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for accountKey, pool := range c.pools {
+		for moduleKey, lease := range pool.leases {
+			stats = append(stats, LeaseStats{Module: moduleKey, Account: accountKey, Slots: lease.slots})
+		}
+	}
+
+	return stats
+}
+
+// occupancy returns the amount of hosts with in-flight requests and the amount of idle hosts of the pool
+func (l *ConnectionLimiter) occupancy() (active int, idle int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, hs := range l.pool {
+		if hs.refcount > 0 {
+			active++
+		} else {
+			idle++
+		}
+	}
+
+	return active, idle
+}
+
+// RecordRequest counts a finished HTTP request of the passed module by its status code,
+// requests failing without response are counted with the status "error"
+func RecordRequest(moduleKey string, statusCode int, err error) {
+	status := "error"
+	if err == nil && statusCode > 0 {
+		status = strconv.Itoa(statusCode)
+	}
+
+	metrics.HTTPRequests.Inc(moduleKey, status)
+}
+
+// RecordRateLimitWait adds the passed time spent waiting for the rate limiter of the passed module
+func RecordRateLimitWait(moduleKey string, wait time.Duration) {
+	metrics.RateLimitWaitSeconds.Add(wait.Seconds(), moduleKey)
+}
@@ -0,0 +1,280 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PartialFileExt is appended to the file path while the file is being downloaded,
+// the file is only renamed to the final path after the download got completed and validated
+const PartialFileExt = ".part"
+
+// resumeStateExt is appended to the file path for the resume state of a partial download
+const resumeStateExt = ".part.json"
+
+// HeaderGetter is implemented by the response headers of the standard library and the TLS client
+type HeaderGetter interface {
+	Get(key string) string
+}
+
+// ResumeState contains the validators of a partially downloaded file, saved next to the .part file
+// if the server supports range requests. The validators are passed as If-Range header on resuming,
+// so the server sends the complete file again if it changed in the meantime
+type ResumeState struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// PartialDownload writes the response body into the .part file of the final path
+// and renames it to the final path after the download got completed.
+// The content is hashed while writing to add the file to the content index
+type PartialDownload struct {
+	moduleKey string
+	filepath  string
+	file      *os.File
+	resumable bool
+	hash      hash.Hash
+	size      int64
+	sum       string
+	skipped   bool
+}
+
+// GetPartFilePath returns the path of the .part file used while downloading the passed file path
+func GetPartFilePath(filepath string) string {
+	return filepath + PartialFileExt
+}
+
+// GetResumeRange returns the headers required to resume the download of the passed file path.
+// Returns false if no resumable partial download exists
+func GetResumeRange(filepath string) (rangeHeader string, ifRangeHeader string, ok bool) {
+	state, ok := readResumeState(filepath)
+	if !ok {
+		return "", "", false
+	}
+
+	stat, err := os.Stat(GetPartFilePath(filepath))
+	if err != nil || stat.Size() == 0 {
+		RemovePartialDownload(filepath)
+		return "", "", false
+	}
+
+	ifRangeHeader = state.ETag
+	if ifRangeHeader == "" {
+		ifRangeHeader = state.LastModified
+	}
+
+	return fmt.Sprintf("bytes=%d-", stat.Size()), ifRangeHeader, true
+}
+
+// HasResumableDownload checks if a resumable partial download exists for the passed file path
+func HasResumableDownload(filepath string) bool {
+	_, _, ok := GetResumeRange(filepath)
+
+	return ok
+}
+
+// RemovePartialDownload removes the .part file and the resume state of the passed file path
+func RemovePartialDownload(filepath string) {
+	_ = os.Remove(GetPartFilePath(filepath))
+	_ = os.Remove(filepath + resumeStateExt)
+}
+
+// StartPartialDownload opens the .part file of the passed file path for the response with the passed status code.
+// Partial content responses are appended to the existing .part file if the content range matches its size,
+// every other response replaces the .part file.
+// If the server supports range requests the resume state is saved, so an interrupted download can be resumed
+func StartPartialDownload(moduleKey string, filepath string, statusCode int, header HeaderGetter) (*PartialDownload, error) {
+	partFilePath := GetPartFilePath(filepath)
+	download := &PartialDownload{
+		moduleKey: moduleKey,
+		filepath:  filepath,
+		resumable: strings.EqualFold(header.Get("Accept-Ranges"), "bytes") || statusCode == 206,
+		hash:      sha256.New(),
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+
+	if statusCode == 206 {
+		stat, err := os.Stat(partFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("received partial content without a partial download: %w", err)
+		}
+
+		if start, ok := parseContentRangeStart(header.Get("Content-Range")); !ok || start != stat.Size() {
+			RemovePartialDownload(filepath)
+			return nil, fmt.Errorf(
+				"content range \"%s\" doesn't match the partial download size of %d bytes",
+				header.Get("Content-Range"), stat.Size(),
+			)
+		}
+
+		flags = os.O_WRONLY | os.O_APPEND
+	}
+
+	file, err := os.OpenFile(partFilePath, flags, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	download.file = file
+
+	// the hash of resumed downloads has to include the previously downloaded content
+	if statusCode == 206 {
+		if download.size, err = hashExistingContent(partFilePath, download.hash); err != nil {
+			download.Discard()
+			return nil, err
+		}
+	}
+
+	if download.resumable && statusCode != 206 {
+		state := ResumeState{ETag: header.Get("ETag"), LastModified: header.Get("Last-Modified")}
+		if err = writeResumeState(filepath, state); err != nil {
+			download.resumable = false
+		}
+	}
+
+	return download, nil
+}
+
+// Write writes the passed bytes into the .part file
+func (d *PartialDownload) Write(p []byte) (int, error) {
+	n, err := d.file.Write(p)
+	d.hash.Write(p[:n])
+	d.size += int64(n)
+
+	return n, err
+}
+
+// Finish syncs the .part file to the disk and renames it to the final path.
+// If a byte-identical file is already indexed the configured duplicate policy is applied instead
+func (d *PartialDownload) Finish() error {
+	if err := d.file.Sync(); err != nil {
+		d.Discard()
+		return err
+	}
+
+	if err := d.file.Close(); err != nil {
+		d.Discard()
+		return err
+	}
+
+	_ = os.Remove(d.filepath + resumeStateExt)
+
+	d.sum = hex.EncodeToString(d.hash.Sum(nil))
+	if duplicate := GlobalContentIndex.FindDuplicate(d.filepath, d.sum, d.size); duplicate != "" {
+		if GlobalContentIndex.ApplyPolicy(d.moduleKey, duplicate, GetPartFilePath(d.filepath), d.filepath) {
+			// symbolic links and skipped files are not indexed, only the original file
+			if GlobalContentIndex.Policy() == DuplicatePolicyHardlink {
+				GlobalContentIndex.Add(d.moduleKey, d.filepath, d.sum, d.size)
+			}
+
+			d.skipped = GlobalContentIndex.Policy() == DuplicatePolicySkip
+
+			return nil
+		}
+	}
+
+	if err := os.Rename(GetPartFilePath(d.filepath), d.filepath); err != nil {
+		d.Discard()
+		return err
+	}
+
+	GlobalContentIndex.Add(d.moduleKey, d.filepath, d.sum, d.size)
+
+	return nil
+}
+
+// Size returns the size of the complete file including the previously downloaded content of resumed downloads
+func (d *PartialDownload) Size() int64 {
+	return d.size
+}
+
+// SHA256 returns the hex encoded SHA-256 hash of the complete file, only set after the download got finished
+func (d *PartialDownload) SHA256() string {
+	return d.sum
+}
+
+// Skipped returns true if the file got not saved since a byte-identical file already exists (skip duplicate policy)
+func (d *PartialDownload) Skipped() bool {
+	return d.skipped
+}
+
+// Abort closes the .part file after the transfer got interrupted by the passed error.
+// The .part file is kept if the download can be resumed, otherwise it gets removed. Returns the passed error
+func (d *PartialDownload) Abort(err error) error {
+	_ = d.file.Close()
+
+	if !d.resumable {
+		RemovePartialDownload(d.filepath)
+	}
+
+	return err
+}
+
+// Discard closes and removes the .part file, used if the downloaded content is invalid
+func (d *PartialDownload) Discard() {
+	_ = d.file.Close()
+	RemovePartialDownload(d.filepath)
+}
+
+// hashExistingContent writes the content of the passed file into the passed hash and returns the size of the file
+func hashExistingContent(path string, contentHash hash.Hash) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return io.Copy(contentHash, file)
+}
+
+// readResumeState reads the resume state of the passed file path
+func readResumeState(filepath string) (state ResumeState, ok bool) {
+	content, err := os.ReadFile(filepath + resumeStateExt)
+	if err != nil {
+		return state, false
+	}
+
+	if err = json.Unmarshal(content, &state); err != nil {
+		return state, false
+	}
+
+	return state, true
+}
+
+// writeResumeState writes the resume state of the passed file path
+func writeResumeState(filepath string, state ResumeState) error {
+	content, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath+resumeStateExt, content, 0644)
+}
+
+// parseContentRangeStart parses the first byte position of a Content-Range header like "bytes 100-199/200"
+func parseContentRangeStart(contentRange string) (int64, bool) {
+	contentRange, ok := strings.CutPrefix(contentRange, "bytes ")
+	if !ok {
+		return 0, false
+	}
+
+	start, _, ok := strings.Cut(contentRange, "-")
+	if !ok {
+		return 0, false
+	}
+
+	position, err := strconv.ParseInt(strings.TrimSpace(start), 10, 64)
+
+	return position, err == nil
+}
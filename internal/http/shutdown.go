@@ -0,0 +1,164 @@
+package http
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrShutdownRequested is returned by the session download functions once a graceful
+// shutdown got requested and the download queue item in progress got completed.
+var ErrShutdownRequested = errors.New("graceful shutdown requested, not starting new downloads")
+
+// ShutdownGate coordinates graceful shutdowns across all modules and workers. Every parsed item is registered
+// with the session downloading its files. After Request is called each session keeps downloading until
+// the module reports the current download queue item of its item as completed (which every module does
+// by updating the current item of the tracked item). From that point on every new download of the session
+// is refused with ErrShutdownRequested, while the sessions of the other items in progress continue
+// until their own download queue items are completed.
+type ShutdownGate struct {
+	requested atomic.Bool
+	mu        sync.Mutex
+	// items are the sessions of the items in progress by their item ID
+	items map[int]any
+	// sessions are the items in progress by their session
+	sessions map[any]*shutdownItem
+}
+
+// shutdownItem is the state of an item in progress
+type shutdownItem struct {
+	id     int
+	closed bool
+}
+
+// GlobalShutdown is the process-wide shutdown gate. Initialized once at startup via
+// InitGlobalShutdown. Nil before initialization; nil-safe to call.
+var GlobalShutdown *ShutdownGate
+
+// InitGlobalShutdown (re-)initializes the package-global shutdown gate.
+func InitGlobalShutdown() {
+	GlobalShutdown = &ShutdownGate{}
+}
+
+// Request marks the gate for a graceful shutdown.
+func (g *ShutdownGate) Request() {
+	if g == nil {
+		return
+	}
+
+	g.requested.Store(true)
+}
+
+// Requested returns true if a graceful shutdown got requested.
+func (g *ShutdownGate) Requested() bool {
+	return g != nil && g.requested.Load()
+}
+
+// StartItem registers the passed item as in progress by the passed session.
+// Items started after the shutdown request are not allowed to start any download.
+func (g *ShutdownGate) StartItem(session any, itemID int) {
+	if g == nil {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.items == nil {
+		g.items = make(map[int]any)
+		g.sessions = make(map[any]*shutdownItem)
+	}
+
+	// sessions parse a single item at a time, so a previous item of the session is not in progress anymore
+	if previous, ok := g.sessions[session]; ok {
+		delete(g.items, previous.id)
+	}
+
+	g.items[itemID] = session
+	g.sessions[session] = &shutdownItem{id: itemID, closed: g.requested.Load()}
+}
+
+// FinishItem removes the passed item from the items in progress after it got parsed.
+func (g *ShutdownGate) FinishItem(itemID int) {
+	if g == nil {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if session, ok := g.items[itemID]; ok {
+		delete(g.items, itemID)
+
+		if item, ok := g.sessions[session]; ok && item.id == itemID {
+			delete(g.sessions, session)
+		}
+	}
+}
+
+// CompleteItem marks the end of a download queue item of the passed item. If a shutdown got requested
+// no further downloads are allowed for the session of the item.
+func (g *ShutdownGate) CompleteItem(itemID int) {
+	if g == nil || !g.requested.Load() {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if session, ok := g.items[itemID]; ok {
+		g.sessions[session].closed = true
+	}
+}
+
+// ItemClosed returns true if the download queue item of the passed item in progress during the shutdown request
+// got completed. Items which aren't in progress are closed once every item in progress got closed.
+func (g *ShutdownGate) ItemClosed(itemID int) bool {
+	if !g.Requested() {
+		return false
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if session, ok := g.items[itemID]; ok {
+		return g.sessions[session].closed
+	}
+
+	return !g.hasOpenItems()
+}
+
+// CheckDownload returns ErrShutdownRequested if the passed session shouldn't start new downloads anymore.
+// Sessions without an item in progress (f.e. the sessions of other modules used for external links of an item)
+// are allowed to download until every item in progress got closed.
+func (g *ShutdownGate) CheckDownload(session any) error {
+	if !g.Requested() {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	closed := !g.hasOpenItems()
+	if item, ok := g.sessions[session]; ok {
+		closed = item.closed
+	}
+
+	if closed {
+		return ErrShutdownRequested
+	}
+
+	return nil
+}
+
+// hasOpenItems returns true if any item in progress didn't complete its download queue item yet
+// since the shutdown request. The caller has to hold the lock.
+func (g *ShutdownGate) hasOpenItems() bool {
+	for _, item := range g.sessions {
+		if !item.closed {
+			return true
+		}
+	}
+
+	return false
+}
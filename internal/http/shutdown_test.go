@@ -0,0 +1,81 @@
+package http
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestShutdownGate_FinishesCurrentItem(t *testing.T) {
+	g := &ShutdownGate{}
+	session := new(int)
+	g.StartItem(session, 1)
+
+	// progress updates without a shutdown request never close the gate
+	g.CompleteItem(1)
+	if err := g.CheckDownload(session); err != nil {
+		t.Fatalf("download refused without shutdown request: %v", err)
+	}
+
+	// the item in progress during the request may still download its files
+	g.Request()
+	if err := g.CheckDownload(session); err != nil {
+		t.Fatalf("download of the current item refused: %v", err)
+	}
+
+	// completing the item closes the gate for every following download of its session
+	g.CompleteItem(1)
+	if err := g.CheckDownload(session); !errors.Is(err, ErrShutdownRequested) {
+		t.Fatalf("expected ErrShutdownRequested after completed item, got %v", err)
+	}
+
+	if !g.ItemClosed(1) {
+		t.Fatal("expected the completed item to be closed")
+	}
+}
+
+func TestShutdownGate_WaitsForEveryItem(t *testing.T) {
+	g := &ShutdownGate{}
+	firstSession, secondSession, externalSession := new(int), new(int), new(int)
+	g.StartItem(firstSession, 1)
+	g.StartItem(secondSession, 2)
+	g.Request()
+
+	// completing the first item doesn't stop the worker of the second item
+	g.CompleteItem(1)
+	if err := g.CheckDownload(firstSession); !errors.Is(err, ErrShutdownRequested) {
+		t.Fatalf("expected ErrShutdownRequested for the completed item, got %v", err)
+	}
+
+	if err := g.CheckDownload(secondSession); err != nil || g.ItemClosed(2) {
+		t.Fatalf("download of the second item refused: %v", err)
+	}
+
+	// sessions without an item may download as long as any item is still in progress
+	if err := g.CheckDownload(externalSession); err != nil {
+		t.Fatalf("download of session without item refused: %v", err)
+	}
+
+	g.CompleteItem(2)
+	if err := g.CheckDownload(externalSession); !errors.Is(err, ErrShutdownRequested) {
+		t.Fatalf("expected ErrShutdownRequested after all items completed, got %v", err)
+	}
+
+	// items started after the shutdown request don't start any download
+	g.FinishItem(1)
+	g.StartItem(firstSession, 3)
+	if err := g.CheckDownload(firstSession); !errors.Is(err, ErrShutdownRequested) {
+		t.Fatalf("expected ErrShutdownRequested for item started after the request, got %v", err)
+	}
+}
+
+func TestShutdownGate_NilSafe(t *testing.T) {
+	var g *ShutdownGate
+
+	g.Request()
+	g.StartItem(nil, 1)
+	g.CompleteItem(1)
+	g.FinishItem(1)
+	if g.Requested() || g.ItemClosed(1) || g.CheckDownload(nil) != nil {
+		t.Fatal("nil gate should never request or refuse anything")
+	}
+}
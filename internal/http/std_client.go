@@ -31,6 +31,7 @@ type StdClientSessionInterface interface {
 	GetCookies(u *url.URL) []*http.Cookie
 	SetCookies(u *url.URL, cookies []*http.Cookie)
 	SetRateLimiter(rateLimiter *rate.Limiter)
+	GetRateLimiter() *rate.Limiter
 }
 
 type StdClientErrorHandler interface {
@@ -14,7 +14,8 @@ type sessionInfoProvider func() (moduleKey string, ps *watcherHttp.ProxySettings
 // budgetingTransport wraps an inner RoundTripper to gate every request on
 // the global ConnectionBudget. If the budget is nil or the session has no
 // active proxy, RoundTrip is a no-op pass-through. On a successful response
-// the body is wrapped so Close releases the slot.
+// the body is wrapped so Close releases the slot. Response bodies are also
+// limited to the global and module bandwidth, which covers the download path.
 type budgetingTransport struct {
 	inner http.RoundTripper
 	info  sessionInfoProvider
@@ -29,8 +30,8 @@ func newBudgetingTransport(inner http.RoundTripper, fn sessionInfoProvider) http
 
 func (t *budgetingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	var slot *watcherHttp.Slot
+	moduleKey, ps := t.info()
 	if watcherHttp.Global != nil {
-		moduleKey, ps := t.info()
 		s, err := watcherHttp.Global.Acquire(req.Context(), moduleKey, ps)
 		if err != nil {
 			return nil, err
@@ -38,6 +39,12 @@ func (t *budgetingTransport) RoundTrip(req *http.Request) (*http.Response, error
 		slot = s
 	}
 	resp, err := t.inner.RoundTrip(req)
+	if resp != nil {
+		watcherHttp.RecordRequest(moduleKey, resp.StatusCode, err)
+		resp.Body = watcherHttp.GlobalBandwidth.WrapBody(req.Context(), moduleKey, resp.Body)
+	} else {
+		watcherHttp.RecordRequest(moduleKey, 0, err)
+	}
 	if slot == nil {
 		return resp, err
 	}
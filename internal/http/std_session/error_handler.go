@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"strconv"
 	"time"
+
+	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
 )
 
 type StatusError struct {
@@ -15,6 +17,11 @@ func (e StatusError) Error() string {
 	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
 }
 
+// Gone returns true if the status code indicates that the requested resource doesn't exist anymore
+func (e StatusError) Gone() bool {
+	return watcherHttp.IsGoneStatusCode(e.StatusCode)
+}
+
 type WrittenSizeError struct {
 	Message string
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -70,5 +72,205 @@ func TestGet_FatalErrorClosesBody(t *testing.T) {
 	}
 }
 
+// TestDownloadFile_DryRun checks that dry runs only record the download
+// without requesting the file or creating anything on the file system.
+func TestDownloadFile_DryRun(t *testing.T) {
+	watcherHttp.InitGlobalDryRun(true)
+	defer watcherHttp.InitGlobalDryRun(false)
+
+	body := &trackingBody{Reader: strings.NewReader("content")}
+	s := NewStdClientSession("test")
+	s.Client = &http.Client{Transport: stubRoundTripper{resp: &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       body,
+		Header:     make(http.Header),
+	}}}
+
+	filePath := filepath.Join(t.TempDir(), "sub", "file.jpg")
+	if err := s.DownloadFile(filePath, "http://example.invalid/file.jpg"); err != nil {
+		t.Fatalf("expected no error during dry run, got %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Dir(filePath)); !os.IsNotExist(err) {
+		t.Fatal("dry run created the download directory")
+	}
+
+	downloads := watcherHttp.GlobalDryRun.Downloads()
+	if len(downloads) != 1 || downloads[0].Path != filePath || downloads[0].URI != "http://example.invalid/file.jpg" {
+		t.Fatalf("expected the download to be recorded, got %+v", downloads)
+	}
+}
+
+// interruptedReader returns the passed content and the passed error afterwards to simulate an interrupted transfer
+type interruptedReader struct {
+	content io.Reader
+	err     error
+}
+
+func (r *interruptedReader) Read(p []byte) (int, error) {
+	n, err := r.content.Read(p)
+	if err == io.EOF {
+		return n, r.err
+	}
+
+	return n, err
+}
+
+// resumingRoundTripper interrupts the first transfer in the middle of the content if interrupt is set
+// and serves range requests matching the ETag with the remaining content
+type resumingRoundTripper struct {
+	content       string
+	interrupt     bool
+	acceptRanges  bool
+	rangeRequests []string
+}
+
+func (r *resumingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	header := make(http.Header)
+
+	if rangeHeader := req.Header.Get("Range"); rangeHeader != "" && req.Header.Get("If-Range") == `"etag"` {
+		r.rangeRequests = append(r.rangeRequests, rangeHeader)
+
+		var start int
+		_, _ = fmt.Sscanf(rangeHeader, "bytes=%d-", &start)
+
+		header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(r.content)-1, len(r.content)))
+		header.Set("Content-Length", fmt.Sprintf("%d", len(r.content)-start))
+
+		return &http.Response{
+			StatusCode: http.StatusPartialContent,
+			Body:       io.NopCloser(strings.NewReader(r.content[start:])),
+			Header:     header,
+		}, nil
+	}
+
+	header.Set("Content-Length", fmt.Sprintf("%d", len(r.content)))
+	header.Set("ETag", `"etag"`)
+
+	if r.acceptRanges {
+		header.Set("Accept-Ranges", "bytes")
+	}
+
+	var body io.Reader = strings.NewReader(r.content)
+	if r.interrupt {
+		r.interrupt = false
+		body = &interruptedReader{
+			content: strings.NewReader(r.content[:len(r.content)/2]),
+			err:     io.ErrUnexpectedEOF,
+		}
+	}
+
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(body), Header: header}, nil
+}
+
+// TestDownloadFile_Resume checks that interrupted downloads are resumed with range requests
+// and only moved to the final path after completion
+func TestDownloadFile_Resume(t *testing.T) {
+	transport := &resumingRoundTripper{content: "0123456789abcdef", interrupt: true, acceptRanges: true}
+	s := NewStdClientSession("test")
+	s.Client = &http.Client{Transport: transport}
+
+	filePath := filepath.Join(t.TempDir(), "file.mp4")
+	if err := s.DownloadFile(filePath, "http://example.invalid/file.mp4"); err != nil {
+		t.Fatalf("expected the download to be resumed, got %v", err)
+	}
+
+	if len(transport.rangeRequests) != 1 || transport.rangeRequests[0] != "bytes=8-" {
+		t.Fatalf("expected a single range request for the remaining bytes, got %v", transport.rangeRequests)
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil || string(content) != transport.content {
+		t.Fatalf("expected the complete content in the final file, got %q (%v)", content, err)
+	}
+
+	for _, leftover := range []string{watcherHttp.GetPartFilePath(filePath), filePath + ".part.json"} {
+		if _, err = os.Stat(leftover); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be removed after the download", leftover)
+		}
+	}
+}
+
+// TestDownloadFile_WithoutRetries checks that the file is downloaded once even without download retries
+// and that interrupted downloads are reported as failed instead of being resumed
+func TestDownloadFile_WithoutRetries(t *testing.T) {
+	transport := &resumingRoundTripper{content: "0123456789abcdef", acceptRanges: true}
+	s := NewStdClientSession("test")
+	s.Client = &http.Client{Transport: transport}
+	s.MaxDownloadRetries = 0
+
+	filePath := filepath.Join(t.TempDir(), "file.mp4")
+	if err := s.DownloadFile(filePath, "http://example.invalid/file.mp4"); err != nil {
+		t.Fatalf("expected the file to be downloaded, got %v", err)
+	}
+
+	if content, err := os.ReadFile(filePath); err != nil || string(content) != transport.content {
+		t.Fatalf("expected the complete content in the final file, got %q (%v)", content, err)
+	}
+
+	transport.interrupt = true
+	filePath = filepath.Join(t.TempDir(), "interrupted.mp4")
+	if err := s.DownloadFile(filePath, "http://example.invalid/file.mp4"); err == nil {
+		t.Fatal("expected the interrupted download to fail without retries")
+	}
+
+	if len(transport.rangeRequests) != 0 {
+		t.Fatalf("expected no range request without retries, got %v", transport.rangeRequests)
+	}
+}
+
+// recordingErrorHandler records the status codes of all checked responses
+type recordingErrorHandler struct {
+	statusCodes *[]int
+}
+
+func (h recordingErrorHandler) CheckResponse(response *http.Response) (error, bool) {
+	*h.statusCodes = append(*h.statusCodes, response.StatusCode)
+	return nil, false
+}
+
+func (recordingErrorHandler) CheckDownloadedFileForErrors(int64, http.Header) error { return nil }
+
+func (recordingErrorHandler) IsFatalError(error) bool { return false }
+
+// TestDownloadFile_ResumeUsesErrorHandlers checks that the range requests of resumed downloads
+// are checked by the error handlers like every other request of the session
+func TestDownloadFile_ResumeUsesErrorHandlers(t *testing.T) {
+	transport := &resumingRoundTripper{content: "0123456789abcdef", interrupt: true, acceptRanges: true}
+	s := NewStdClientSession("test")
+	s.Client = &http.Client{Transport: transport}
+
+	var statusCodes []int
+	filePath := filepath.Join(t.TempDir(), "file.mp4")
+	if err := s.DownloadFile(
+		filePath, "http://example.invalid/file.mp4", recordingErrorHandler{statusCodes: &statusCodes},
+	); err != nil {
+		t.Fatalf("expected the download to be resumed, got %v", err)
+	}
+
+	if len(statusCodes) != 2 || statusCodes[1] != http.StatusPartialContent {
+		t.Fatalf("expected the range request to be checked by the error handler, got %v", statusCodes)
+	}
+}
+
+// TestDownloadFile_Atomic checks that failed downloads from servers without range support
+// leave neither the final file nor a partial file behind
+func TestDownloadFile_Atomic(t *testing.T) {
+	transport := &resumingRoundTripper{content: "0123456789abcdef", interrupt: true}
+	s := NewStdClientSession("test")
+	s.Client = &http.Client{Transport: transport}
+
+	filePath := filepath.Join(t.TempDir(), "file.mp4")
+	if err := s.DownloadFile(filePath, "http://example.invalid/file.mp4"); err == nil {
+		t.Fatal("expected the interrupted download to fail")
+	}
+
+	for _, leftover := range []string{filePath, watcherHttp.GetPartFilePath(filePath)} {
+		if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to not exist after the failed download", leftover)
+		}
+	}
+}
+
 // compile-time check that the handler satisfies the interface used by Get.
 var _ watcherHttp.StdClientErrorHandler = fatalErrorHandler{}
@@ -30,6 +30,7 @@ type TlsClientSessionInterface interface {
 	GetCookies(u *url.URL) []*http.Cookie
 	SetCookies(u *url.URL, cookies []*http.Cookie)
 	SetRateLimiter(rateLimiter *rate.Limiter)
+	GetRateLimiter() *rate.Limiter
 }
 
 type TlsClientErrorHandler interface {
@@ -19,7 +19,8 @@ type sessionInfoProvider func() (moduleKey string, ps *watcherHttp.ProxySettings
 // unchanged. Do/Get/Head/Post are intercepted to acquire a budget slot,
 // then the response body is wrapped so Close releases it. If the budget is
 // nil or the session has no active proxy, the wrapped methods are no-ops
-// over the inner client.
+// over the inner client. Response bodies are also limited to the global and
+// module bandwidth, which covers the download path.
 type budgetingTlsClient struct {
 	tls_client.HttpClient
 	info sessionInfoProvider
@@ -37,7 +38,14 @@ func (c *budgetingTlsClient) acquire(ctx context.Context) (*watcherHttp.Slot, er
 	return watcherHttp.Global.Acquire(ctx, moduleKey, ps)
 }
 
-func (c *budgetingTlsClient) bindToBody(resp *http.Response, err error, slot *watcherHttp.Slot) (*http.Response, error) {
+func (c *budgetingTlsClient) bindToBody(ctx context.Context, resp *http.Response, err error, slot *watcherHttp.Slot) (*http.Response, error) {
+	moduleKey, _ := c.info()
+	if resp != nil {
+		watcherHttp.RecordRequest(moduleKey, resp.StatusCode, err)
+		resp.Body = watcherHttp.GlobalBandwidth.WrapBody(ctx, moduleKey, resp.Body)
+	} else {
+		watcherHttp.RecordRequest(moduleKey, 0, err)
+	}
 	if slot == nil {
 		return resp, err
 	}
@@ -55,7 +63,7 @@ func (c *budgetingTlsClient) Do(req *http.Request) (*http.Response, error) {
 		return nil, err
 	}
 	resp, doErr := c.HttpClient.Do(req)
-	return c.bindToBody(resp, doErr, slot)
+	return c.bindToBody(req.Context(), resp, doErr, slot)
 }
 
 func (c *budgetingTlsClient) Get(url string) (*http.Response, error) {
@@ -64,7 +72,7 @@ func (c *budgetingTlsClient) Get(url string) (*http.Response, error) {
 		return nil, err
 	}
 	resp, getErr := c.HttpClient.Get(url)
-	return c.bindToBody(resp, getErr, slot)
+	return c.bindToBody(context.Background(), resp, getErr, slot)
 }
 
 func (c *budgetingTlsClient) Head(url string) (*http.Response, error) {
@@ -73,7 +81,7 @@ func (c *budgetingTlsClient) Head(url string) (*http.Response, error) {
 		return nil, err
 	}
 	resp, headErr := c.HttpClient.Head(url)
-	return c.bindToBody(resp, headErr, slot)
+	return c.bindToBody(context.Background(), resp, headErr, slot)
 }
 
 func (c *budgetingTlsClient) Post(url, ct string, body io.Reader) (*http.Response, error) {
@@ -82,5 +90,5 @@ func (c *budgetingTlsClient) Post(url, ct string, body io.Reader) (*http.Respons
 		return nil, err
 	}
 	resp, postErr := c.HttpClient.Post(url, ct, body)
-	return c.bindToBody(resp, postErr, slot)
+	return c.bindToBody(context.Background(), resp, postErr, slot)
 }
@@ -2,6 +2,7 @@ package tls_session
 
 import (
 	"fmt"
+	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
 	http "github.com/bogdanfinn/fhttp"
 	"strconv"
 	"time"
@@ -19,6 +20,11 @@ func (e StatusError) Error() string {
 	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
 }
 
+// Gone returns true if the status code indicates that the requested resource doesn't exist anymore
+func (e StatusError) Gone() bool {
+	return watcherHttp.IsGoneStatusCode(e.StatusCode)
+}
+
 type WrittenSizeError struct {
 	Message string
 }
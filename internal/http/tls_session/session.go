@@ -3,6 +3,7 @@ package tls_session
 import (
 	"context"
 	"fmt"
+	"github.com/DaRealFreak/watcher-go/internal/hooks"
 	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
 	"github.com/DaRealFreak/watcher-go/internal/raven"
 	http "github.com/bogdanfinn/fhttp"
@@ -12,7 +13,6 @@ import (
 	"io"
 	"log/slog"
 	"net/url"
-	"os"
 	"strings"
 	"time"
 )
@@ -283,12 +283,33 @@ func (s *TlsClientSession) DownloadFile(filepath string, uri string, errorHandle
 		"module", s.ModuleKey,
 	)
 
-	err = s.tryDownloadFile(filepath, uri, errorHandlers...)
-	if err != nil {
-		// try to clean up the failed file if it exists
-		if _, statErr := os.Stat(filepath); statErr == nil {
-			_ = os.Remove(filepath)
+	// don't start new downloads after a graceful shutdown got requested
+	if err = watcherHttp.GlobalShutdown.CheckDownload(s); err != nil {
+		return err
+	}
+
+	// only record the download during dry runs
+	if watcherHttp.GlobalDryRun.Enabled() {
+		watcherHttp.GlobalDryRun.RecordSessionDownload(s.ModuleKey, s, filepath, uri)
+		return nil
+	}
+
+	// the file is always downloaded at least once, interrupted transfers are resumed up to MaxDownloadRetries times
+	// with range requests if the server supports them
+	for try := 0; ; try++ {
+		err = s.tryDownloadFile(filepath, uri, errorHandlers...)
+		if err == nil || try >= s.MaxDownloadRetries || !watcherHttp.HasResumableDownload(filepath) ||
+			watcherHttp.GlobalShutdown.CheckDownload(s) != nil {
+			break
 		}
+
+		slog.Warn(
+			fmt.Sprintf(
+				"download of \"%s\" got interrupted (%s), resuming download (retry: %d/%d)",
+				uri, err.Error(), try+1, s.MaxDownloadRetries,
+			),
+			"module", s.ModuleKey,
+		)
 	}
 
 	return err
@@ -298,6 +319,21 @@ func (s *TlsClientSession) DownloadFile(filepath string, uri string, errorHandle
 func (s *TlsClientSession) DownloadFileFromResponse(resp *http.Response, filepath string, errorHandlers ...watcherHttp.TlsClientErrorHandler) (err error) {
 	defer raven.CheckClosure(resp.Body)
 
+	if err = watcherHttp.GlobalShutdown.CheckDownload(s); err != nil {
+		return err
+	}
+
+	if watcherHttp.GlobalDryRun.Enabled() {
+		var uri string
+		if resp.Request != nil && resp.Request.URL != nil {
+			uri = resp.Request.URL.String()
+		}
+
+		watcherHttp.GlobalDryRun.RecordSessionDownload(s.ModuleKey, s, filepath, uri)
+
+		return nil
+	}
+
 	// ensure the directory
 	s.EnsureDownloadDirectory(filepath)
 
@@ -307,42 +343,63 @@ func (s *TlsClientSession) DownloadFileFromResponse(resp *http.Response, filepat
 		}
 	}
 
-	// create the file
-	out, createErr := os.Create(filepath)
+	// write into a .part file first, so interrupted downloads never leave truncated files at the final path
+	out, createErr := watcherHttp.StartPartialDownload(s.ModuleKey, filepath, resp.StatusCode, resp.Header)
 	if createErr != nil {
 		return createErr
 	}
 
-	defer raven.CheckClosure(out)
-
 	// write the body to file
 	written, copyErr := io.Copy(out, resp.Body)
 	if copyErr != nil {
-		return copyErr
+		return out.Abort(copyErr)
 	}
 
-	// update parent folders access and modified times
-	s.UpdateTreeFolderChangeTimes(filepath)
-
 	// additional validation to compare sent headers with the written file
 	for _, errorHandler := range s.ErrorHandlers {
 		if err = errorHandler.CheckDownloadedFileForErrors(written, resp.Header); err != nil {
+			out.Discard()
 			return err
 		}
 	}
 
 	for _, errorHandler := range errorHandlers {
 		if err = errorHandler.CheckDownloadedFileForErrors(written, resp.Header); err != nil {
+			out.Discard()
 			return err
 		}
 	}
 
-	return nil
+	if err = out.Finish(); err != nil {
+		return err
+	}
+
+	// update parent folders access and modified times
+	s.UpdateTreeFolderChangeTimes(filepath)
+
+	watcherHttp.GlobalDownloadStats.RecordDownload(s.ModuleKey, s, written)
+
+	var uri string
+	if resp.Request != nil && resp.Request.URL != nil {
+		uri = resp.Request.URL.String()
+	}
+
+	// skipped duplicates got not saved, so there is no file to run the post download hooks on
+	if out.Skipped() {
+		return nil
+	}
+
+	// the post download hooks can abort the current item depending on their failure policy
+	return hooks.Default().RunDownload(s.ModuleKey, filepath, uri, out.Size(), out.SHA256())
 }
 
 // tryDownloadFile will try download an url to a local file.
 // It's efficient because it will write as it downloads and not load the whole file into memory.
 func (s *TlsClientSession) tryDownloadFile(filepath string, uri string, errorHandlers ...watcherHttp.TlsClientErrorHandler) error {
+	if resp := s.tryResumeDownload(filepath, uri, errorHandlers...); resp != nil {
+		return s.DownloadFileFromResponse(resp, filepath)
+	}
+
 	// retrieve the data
 	resp, err := s.Get(uri, errorHandlers...)
 	if err != nil {
@@ -352,6 +409,48 @@ func (s *TlsClientSession) tryDownloadFile(filepath string, uri string, errorHan
 	return s.DownloadFileFromResponse(resp, filepath)
 }
 
+// tryResumeDownload requests the remaining bytes of a previously interrupted download of the passed file path.
+// The request is sent like every other request of the session with the rate limit, retries and error handlers.
+// Returns nil if no resumable download exists or the server rejected the range request,
+// in which case the partial download is removed and the file has to be downloaded completely again
+func (s *TlsClientSession) tryResumeDownload(
+	filepath string, uri string, errorHandlers ...watcherHttp.TlsClientErrorHandler,
+) *http.Response {
+	rangeHeader, ifRangeHeader, ok := watcherHttp.GetResumeRange(filepath)
+	if !ok {
+		return nil
+	}
+
+	req, err := http.NewRequest("GET", uri, nil)
+	if err != nil {
+		watcherHttp.RemovePartialDownload(filepath)
+		return nil
+	}
+
+	req.Header.Set("Range", rangeHeader)
+	if ifRangeHeader != "" {
+		req.Header.Set("If-Range", ifRangeHeader)
+	}
+
+	slog.Debug(
+		fmt.Sprintf("resuming download of uri \"%s\" (range: %s)", uri, rangeHeader),
+		"module", s.ModuleKey,
+	)
+
+	resp, err := s.Do(req, errorHandlers...)
+	if err != nil || resp == nil || (resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent) {
+		if resp != nil && resp.Body != nil {
+			_ = resp.Body.Close()
+		}
+
+		watcherHttp.RemovePartialDownload(filepath)
+
+		return nil
+	}
+
+	return resp
+}
+
 // GetClient returns the used *http.Client, required f.e. to manually set cookies
 func (s *TlsClientSession) GetClient() tls_client.HttpClient {
 	return s.Client
@@ -379,12 +478,19 @@ func (s *TlsClientSession) SetRateLimiter(rateLimiter *rate.Limiter) {
 	s.RateLimiter = rateLimiter
 }
 
+// GetRateLimiter returns the rate limiter of the session, nil if the requests of the session are not limited
+func (s *TlsClientSession) GetRateLimiter() *rate.Limiter {
+	return s.RateLimiter
+}
+
 // ApplyRateLimit waits for the leaky bucket to fill again
 func (s *TlsClientSession) ApplyRateLimit() {
 	// if no rate limiter is defined, we don't have to wait
 	if s.RateLimiter != nil {
 		// wait for the request to stay within the rate limit
+		start := time.Now()
 		err := s.RateLimiter.Wait(s.ctx)
+		watcherHttp.RecordRateLimitWait(s.ModuleKey, time.Since(start))
 		raven.CheckError(err)
 	}
 }
@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// keys of the structured attributes shared across the application
+const (
+	KeyModule = "module"
+	KeyItemID = "item_id"
+	KeyURI    = "uri"
+	KeyRunID  = "run_id"
+)
+
+// currentRunID is the ID of the active run, added to every record by the run handler
+var currentRunID atomic.Int64
+
+// SetRunID sets the ID of the active run, 0 marks that no run is active
+func SetRunID(runID int) {
+	currentRunID.Store(int64(runID))
+}
+
+// RunID returns the ID of the active run or 0 if no run is active
+func RunID() int {
+	return int(currentRunID.Load())
+}
+
+// Module returns a logger adding the module attribute to all records
+func Module(moduleKey string) *slog.Logger {
+	return slog.Default().With(KeyModule, moduleKey)
+}
+
+// Item returns a logger adding the module, item ID and URI attributes of a tracked item to all records
+func Item(moduleKey string, itemID int, uri string) *slog.Logger {
+	return slog.Default().With(ItemAttrs(moduleKey, itemID, uri)...)
+}
+
+// URI returns a logger adding the module and URI attributes to all records, used if no item ID is available
+func URI(moduleKey string, uri string) *slog.Logger {
+	return slog.Default().With(KeyModule, moduleKey, KeyURI, uri)
+}
+
+// ItemAttrs returns the attributes of a tracked item as arguments for the slog functions
+func ItemAttrs(moduleKey string, itemID int, uri string) []any {
+	return []any{KeyModule, moduleKey, KeyItemID, itemID, KeyURI, uri}
+}
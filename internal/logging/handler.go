@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// runHandler adds the ID of the current run to all records logged during a run
+type runHandler struct {
+	slog.Handler
+}
+
+// Handle adds the run ID attribute if a run is active and passes the record to the wrapped handler
+func (h *runHandler) Handle(ctx context.Context, record slog.Record) error {
+	if runID := RunID(); runID > 0 {
+		record.AddAttrs(slog.Int(KeyRunID, runID))
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs returns a run handler wrapping the handler with the passed attributes
+func (h *runHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &runHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup returns a run handler wrapping the handler with the passed group
+func (h *runHandler) WithGroup(name string) slog.Handler {
+	return &runHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// multiHandler passes the records to multiple handlers, f.e. the console and the log file
+type multiHandler struct {
+	handlers []slog.Handler
+}
+
+// Enabled returns true if any of the handlers handles records of the passed level
+func (h *multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Handle passes the record to all handlers which handle the level of the record
+func (h *multiHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, record.Level) {
+			errs = append(errs, handler.Handle(ctx, record.Clone()))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// WithAttrs returns a multi handler with the passed attributes added to all handlers
+func (h *multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithAttrs(attrs)
+	}
+
+	return &multiHandler{handlers: handlers}
+}
+
+// WithGroup returns a multi handler with the passed group added to all handlers
+func (h *multiHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithGroup(name)
+	}
+
+	return &multiHandler{handlers: handlers}
+}
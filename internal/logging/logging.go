@@ -0,0 +1,104 @@
+// Package logging configures the log output formats, the optional log file
+// and the structured attributes shared by all log records of the application
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	formatter "github.com/DaRealFreak/colored-nested-formatter/v2"
+)
+
+// log output formats
+const (
+	// FormatPretty is the colored and nested human-readable output
+	FormatPretty = "pretty"
+	// FormatText is the logfmt output of the slog text handler
+	FormatText = "text"
+	// FormatJSON outputs one JSON object per record
+	FormatJSON = "json"
+)
+
+// Options configures the handlers of the default logger
+type Options struct {
+	// Format of the console output (pretty, text or json)
+	Format string
+	Level  slog.Level
+	// Pretty contains the options of the pretty console output
+	Pretty *formatter.Handler
+	// File additionally writes all records to the passed path, pretty output is written as JSON to files
+	File string
+	// FileMaxSize is the size in bytes after which the log file gets rotated, 0 disables size based rotation
+	FileMaxSize int64
+	// FileMaxBackups is the amount of rotated log files to keep
+	FileMaxBackups int
+	// FilePerRun rotates the log file on every start of the application
+	FilePerRun bool
+}
+
+// ParseFormat validates the passed log format, empty formats default to the pretty output
+func ParseFormat(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", FormatPretty:
+		return FormatPretty, nil
+	case FormatText:
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return FormatPretty, fmt.Errorf(
+			"unknown log format \"%s\", expected %s, %s or %s", format, FormatJSON, FormatText, FormatPretty,
+		)
+	}
+}
+
+// NewHandler returns a handler writing records in the passed format to the passed writer
+func NewHandler(w io.Writer, format string, level slog.Level, pretty *formatter.Handler) slog.Handler {
+	switch format {
+	case FormatJSON:
+		return slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})
+	case FormatText:
+		return slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})
+	default:
+		if pretty == nil {
+			pretty = &formatter.Handler{}
+		}
+
+		pretty.Level = level
+
+		return formatter.NewHandler(w, pretty)
+	}
+}
+
+// Setup creates the handlers for the passed options and sets them as default logger.
+// The returned closer closes the log file and is never nil
+func Setup(console io.Writer, opts Options) (io.Closer, error) {
+	format, err := ParseFormat(opts.Format)
+	handler := NewHandler(console, format, opts.Level, opts.Pretty)
+
+	var closer io.Closer = io.NopCloser(nil)
+
+	if opts.File != "" {
+		file, fileErr := OpenRotatingFile(opts.File, opts.FileMaxSize, opts.FileMaxBackups, opts.FilePerRun)
+		if fileErr != nil {
+			err = fileErr
+		} else {
+			fileFormat := format
+			if fileFormat == FormatPretty {
+				fileFormat = FormatJSON
+			}
+
+			handler = &multiHandler{handlers: []slog.Handler{
+				handler,
+				NewHandler(file, fileFormat, opts.Level, opts.Pretty),
+			}}
+			closer = file
+		}
+	}
+
+	slog.SetDefault(slog.New(&runHandler{Handler: handler}))
+
+	return closer, err
+}
@@ -0,0 +1,137 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetup_JSONAttributes(t *testing.T) {
+	defaultLogger := slog.Default()
+	defer slog.SetDefault(defaultLogger)
+
+	var output bytes.Buffer
+
+	closer, err := Setup(&output, Options{Format: FormatJSON, Level: slog.LevelInfo})
+	assert.NoError(t, err)
+
+	defer func() {
+		_ = closer.Close()
+	}()
+
+	SetRunID(42)
+	Item("test", 7, "https://example.org/item").Info("parsing item")
+	SetRunID(0)
+	Module("test").Debug("ignored due to the level")
+	Module("test").Info("outside of run")
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Len(t, lines, 2)
+
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, "parsing item", record["msg"])
+	assert.Equal(t, "test", record[KeyModule])
+	assert.Equal(t, float64(7), record[KeyItemID])
+	assert.Equal(t, "https://example.org/item", record[KeyURI])
+	assert.Equal(t, float64(42), record[KeyRunID])
+
+	record = nil
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.NotContains(t, record, KeyRunID)
+}
+
+func TestSetup_LogFile(t *testing.T) {
+	defaultLogger := slog.Default()
+	defer slog.SetDefault(defaultLogger)
+
+	var output bytes.Buffer
+
+	logFile := filepath.Join(t.TempDir(), "logs", "watcher.log")
+	closer, err := Setup(&output, Options{Format: FormatText, Level: slog.LevelInfo, File: logFile})
+	assert.NoError(t, err)
+
+	slog.Info("written to both", "module", "test")
+	assert.NoError(t, closer.Close())
+
+	content, err := os.ReadFile(logFile)
+	assert.NoError(t, err)
+	assert.Equal(t, output.String(), string(content))
+	assert.Contains(t, string(content), "module=test")
+}
+
+func TestParseFormat(t *testing.T) {
+	for input, expected := range map[string]string{"": FormatPretty, "JSON": FormatJSON, "text": FormatText} {
+		format, err := ParseFormat(input)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, format)
+	}
+
+	_, err := ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watcher.log")
+
+	file, err := OpenRotatingFile(path, 10, 2, false)
+	assert.NoError(t, err)
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err = file.Write([]byte(line))
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, file.Close())
+
+	assertContent := func(path string, expected string) {
+		content, readErr := os.ReadFile(path)
+		assert.NoError(t, readErr)
+		assert.Equal(t, expected, string(content))
+	}
+
+	assertContent(path, "fourth\n")
+	assertContent(path+".1", "third\n")
+	assertContent(path+".2", "second\n")
+	assert.NoFileExists(t, path+".3")
+
+	// rotating on open starts a new log file for every run
+	file, err = OpenRotatingFile(path, 0, 2, true)
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	assertContent(path, "")
+	assertContent(path+".1", "fourth\n")
+	assertContent(path+".2", "third\n")
+}
+
+func TestRotatingFile_FailedRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watcher.log")
+
+	// a non-empty directory at the backup path lets the rotation fail
+	assert.NoError(t, os.MkdirAll(filepath.Join(path+".1", "blocked"), os.ModePerm))
+
+	file, err := OpenRotatingFile(path, 10, 1, false)
+	assert.NoError(t, err)
+
+	_, err = file.Write([]byte("first\n"))
+	assert.NoError(t, err)
+
+	n, err := file.Write([]byte("second\n"))
+	assert.Error(t, err)
+	assert.Equal(t, len("second\n"), n)
+
+	// the log file got reopened and is still usable
+	assert.NotNil(t, file.file)
+	assert.NoError(t, file.Close())
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "first\nsecond\n", string(content))
+}
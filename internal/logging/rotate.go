@@ -0,0 +1,145 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// RotatingFile is a log file which gets rotated after reaching its maximum size.
+// Rotated files are renamed to <path>.1 up to <path>.<maxBackups>, the oldest file gets removed
+type RotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// OpenRotatingFile opens or creates the log file at the passed path.
+// If rotateOnOpen is set, an existing non-empty log file gets rotated first, resulting in one log file per run
+func OpenRotatingFile(path string, maxSize int64, maxBackups int, rotateOnOpen bool) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	f := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	if rotateOnOpen && f.size > 0 {
+		if err := f.rotate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+// Write writes the passed content to the log file, rotating it before if it would exceed the maximum size
+func (f *RotatingFile) Write(p []byte) (n int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	var rotateErr error
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		// a failed rotation keeps logging into the reopened log file if possible
+		if rotateErr = f.rotate(); f.file == nil {
+			return 0, rotateErr
+		}
+	}
+
+	n, err = f.file.Write(p)
+	f.size += int64(n)
+
+	if err == nil {
+		err = rotateErr
+	}
+
+	return n, err
+}
+
+// Close closes the log file
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+
+	return err
+}
+
+// open opens the log file in append mode and retrieves its current size
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = stat.Size()
+
+	return nil
+}
+
+// rotate shifts the backups of the log file by one, moves the current log file to the first backup
+// and opens a new empty log file. If the log file can't be moved it gets reopened to continue logging into it
+func (f *RotatingFile) rotate() error {
+	err := f.file.Close()
+	f.file = nil
+
+	if err != nil {
+		return err
+	}
+
+	if err = f.moveBackups(); err != nil {
+		if openErr := f.open(); openErr != nil {
+			return fmt.Errorf("%w (reopening log file failed: %s)", err, openErr.Error())
+		}
+
+		return err
+	}
+
+	return f.open()
+}
+
+// moveBackups shifts the backups of the log file by one and moves the current log file to the first backup
+// or removes it if no backups are kept
+func (f *RotatingFile) moveBackups() error {
+	if f.maxBackups <= 0 {
+		return os.Remove(f.path)
+	}
+
+	_ = os.Remove(f.backupPath(f.maxBackups))
+
+	for i := f.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(f.backupPath(i), f.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return os.Rename(f.path, f.backupPath(1))
+}
+
+// backupPath returns the path of the passed backup index
+func (f *RotatingFile) backupPath(index int) string {
+	return fmt.Sprintf("%s.%d", f.path, index)
+}
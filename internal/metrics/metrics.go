@@ -0,0 +1,52 @@
+package metrics
+
+// metrics of the application, registered in the default registry
+var (
+	// ItemsParsed counts the parsed tracked items by module and result (success or failed)
+	ItemsParsed = Default().NewCounterVec(
+		"watcher_items_parsed_total", "Amount of parsed tracked items.", "module", "result",
+	)
+	// ItemErrors counts the failed tracked items by module and error class
+	ItemErrors = Default().NewCounterVec(
+		"watcher_item_errors_total", "Amount of failed tracked items by error class.", "module", "class",
+	)
+	// FilesDownloaded counts the downloaded files by module
+	FilesDownloaded = Default().NewCounterVec(
+		"watcher_files_downloaded_total", "Amount of downloaded files.", "module",
+	)
+	// BytesDownloaded counts the downloaded bytes by module
+	BytesDownloaded = Default().NewCounterVec(
+		"watcher_bytes_downloaded_total", "Amount of downloaded bytes.", "module",
+	)
+	// HTTPRequests counts the HTTP requests by module and status code, failed requests have the status "error"
+	HTTPRequests = Default().NewCounterVec(
+		"watcher_http_requests_total", "Amount of HTTP requests by status code.", "module", "status",
+	)
+	// RateLimitWaitSeconds is the time spent waiting for the rate limiter of the module sessions
+	RateLimitWaitSeconds = Default().NewCounterVec(
+		"watcher_rate_limit_wait_seconds_total", "Time spent waiting for the rate limiter.", "module",
+	)
+	// BudgetPoolHosts is the amount of hosts in the connection budget pools by state (active or idle)
+	BudgetPoolHosts = Default().NewGaugeVec(
+		"watcher_connection_budget_hosts", "Amount of hosts held in the connection budget pool.", "pool", "state",
+	)
+	// BudgetPoolCapacity is the maximum amount of hosts of the connection budget pools
+	BudgetPoolCapacity = Default().NewGaugeVec(
+		"watcher_connection_budget_capacity", "Maximum amount of hosts of the connection budget pool.", "pool",
+	)
+	// BudgetWaiting is the amount of requests waiting for a slot of a connection budget pool by wait state
+	BudgetWaiting = Default().NewGaugeVec(
+		"watcher_connection_budget_waiting",
+		"Amount of requests waiting for a connection budget slot (at_cap or cooldown).", "pool", "state",
+	)
+	// BudgetCooldownWaitSeconds is the time spent waiting for the eviction cooldown of the connection budget pools
+	BudgetCooldownWaitSeconds = Default().NewCounterVec(
+		"watcher_connection_budget_cooldown_wait_seconds_total",
+		"Time spent waiting for the eviction cooldown of the connection budget pool.", "pool",
+	)
+	// LeaseSlots is the amount of slots held by module leases of the connection budget
+	LeaseSlots = Default().NewGaugeVec(
+		"watcher_connection_lease_slots", "Amount of connection budget slots reserved by module leases.",
+		"module", "account",
+	)
+)
@@ -0,0 +1,267 @@
+// Package metrics collects run and HTTP statistics of the application and exposes them
+// in the Prometheus text exposition format, either over HTTP or as textfile for the node exporter
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// metric types of the exposition format
+const (
+	typeCounter = "counter"
+	typeGauge   = "gauge"
+)
+
+// Registry contains all metric families and the collectors updating gauges right before they get exposed
+type Registry struct {
+	mu         sync.Mutex
+	families   map[string]*family
+	collectors []func()
+}
+
+// family is a metric with all its label value combinations
+type family struct {
+	name       string
+	help       string
+	typ        string
+	labelNames []string
+	samples    map[string]*sample
+}
+
+// sample is the value of a single label value combination
+type sample struct {
+	labelValues []string
+	value       float64
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+var defaultRegistry = NewRegistry()
+
+// Default returns the process-wide registry containing the metrics of the application
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// CounterVec is a counter partitioned by its labels
+type CounterVec struct {
+	registry *Registry
+	name     string
+}
+
+// GaugeVec is a gauge partitioned by its labels
+type GaugeVec struct {
+	registry *Registry
+	name     string
+}
+
+// NewCounterVec registers a counter with the passed label names
+func (r *Registry) NewCounterVec(name string, help string, labelNames ...string) *CounterVec {
+	r.register(name, help, typeCounter, labelNames)
+
+	return &CounterVec{registry: r, name: name}
+}
+
+// NewGaugeVec registers a gauge with the passed label names
+func (r *Registry) NewGaugeVec(name string, help string, labelNames ...string) *GaugeVec {
+	r.register(name, help, typeGauge, labelNames)
+
+	return &GaugeVec{registry: r, name: name}
+}
+
+// RegisterCollector adds a function which gets called before the metrics get exposed,
+// used to update gauges of states which aren't tracked continuously
+func (r *Registry) RegisterCollector(collector func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.collectors = append(r.collectors, collector)
+}
+
+// Inc increases the counter of the passed label values by 1
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increases the counter of the passed label values, negative values are ignored
+func (c *CounterVec) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		return
+	}
+
+	c.registry.update(c.name, labelValues, func(current float64) float64 { return current + value })
+}
+
+// Get returns the current value of the counter with the passed label values
+func (c *CounterVec) Get(labelValues ...string) float64 {
+	return c.registry.get(c.name, labelValues)
+}
+
+// Set sets the gauge of the passed label values
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.registry.update(g.name, labelValues, func(float64) float64 { return value })
+}
+
+// Add adds the passed value to the gauge of the passed label values, negative values decrease the gauge
+func (g *GaugeVec) Add(value float64, labelValues ...string) {
+	g.registry.update(g.name, labelValues, func(current float64) float64 { return current + value })
+}
+
+// Get returns the current value of the gauge with the passed label values
+func (g *GaugeVec) Get(labelValues ...string) float64 {
+	return g.registry.get(g.name, labelValues)
+}
+
+// Reset removes all label value combinations of the gauge, used by collectors before setting the current state
+func (g *GaugeVec) Reset() {
+	g.registry.mu.Lock()
+	defer g.registry.mu.Unlock()
+
+	g.registry.families[g.name].samples = make(map[string]*sample)
+}
+
+// register adds the passed metric family, registering the same name twice panics like a duplicate flag
+func (r *Registry) register(name string, help string, typ string, labelNames []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.families[name]; exists {
+		panic(fmt.Sprintf("metric %s is already registered", name))
+	}
+
+	r.families[name] = &family{
+		name:       name,
+		help:       help,
+		typ:        typ,
+		labelNames: labelNames,
+		samples:    make(map[string]*sample),
+	}
+}
+
+// update applies the passed function to the sample of the passed label values
+func (r *Registry) update(name string, labelValues []string, fn func(current float64) float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f := r.families[name]
+	if len(labelValues) != len(f.labelNames) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", name, len(f.labelNames), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+
+	s, ok := f.samples[key]
+	if !ok {
+		s = &sample{labelValues: append([]string{}, labelValues...)}
+		f.samples[key] = s
+	}
+
+	s.value = fn(s.value)
+}
+
+// get returns the value of the sample of the passed label values
+func (r *Registry) get(name string, labelValues []string) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok := r.families[name].samples[strings.Join(labelValues, "\xff")]; ok {
+		return s.value
+	}
+
+	return 0
+}
+
+// WriteTo runs the collectors and writes all metrics in the Prometheus text exposition format to the passed writer
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	collectors := append([]func(){}, r.collectors...)
+	r.mu.Unlock()
+
+	for _, collector := range collectors {
+		collector()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	counter := &countingWriter{w: w}
+	buf := bufio.NewWriter(counter)
+
+	for _, name := range names {
+		f := r.families[name]
+		_, _ = fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", f.name, escapeHelp(f.help), f.name, f.typ)
+
+		keys := make([]string, 0, len(f.samples))
+		for key := range f.samples {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			s := f.samples[key]
+			_, _ = fmt.Fprintf(
+				buf, "%s%s %s\n",
+				f.name, formatLabels(f.labelNames, s.labelValues), strconv.FormatFloat(s.value, 'g', -1, 64),
+			)
+		}
+	}
+
+	err := buf.Flush()
+
+	return counter.n, err
+}
+
+// formatLabels returns the label pairs of a sample in the exposition format
+func formatLabels(labelNames []string, labelValues []string) string {
+	if len(labelNames) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, len(labelNames))
+	for i, labelName := range labelNames {
+		pairs[i] = fmt.Sprintf("%s=\"%s\"", labelName, escapeLabelValue(labelValues[i]))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// escapeHelp escapes backslashes and line breaks of help texts
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+// escapeLabelValue escapes backslashes, quotes and line breaks of label values
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// countingWriter counts the written bytes for the io.WriterTo implementation
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write writes to the wrapped writer and counts the written bytes
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+
+	return n, err
+}
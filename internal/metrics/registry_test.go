@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_WriteTo(t *testing.T) {
+	registry := NewRegistry()
+	requests := registry.NewCounterVec("test_requests_total", "Amount of requests.", "module", "status")
+	waiting := registry.NewGaugeVec("test_waiting", "Waiting requests.", "pool")
+	collected := registry.NewGaugeVec("test_collected", "Collected state.")
+
+	requests.Inc("test", "200")
+	requests.Add(2, "test", "200")
+	requests.Add(-1, "test", "200")
+	requests.Inc("test", "error")
+	waiting.Add(1, `user@"proxy".com`)
+	waiting.Add(1, `user@"proxy".com`)
+	waiting.Add(-1, `user@"proxy".com`)
+	registry.RegisterCollector(func() {
+		collected.Reset()
+		collected.Set(42)
+	})
+
+	var output bytes.Buffer
+
+	n, err := registry.WriteTo(&output)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(output.Len()), n)
+	assert.Equal(t, `# HELP test_collected Collected state.
+# TYPE test_collected gauge
+test_collected 42
+# HELP test_requests_total Amount of requests.
+# TYPE test_requests_total counter
+test_requests_total{module="test",status="200"} 3
+test_requests_total{module="test",status="error"} 1
+# HELP test_waiting Waiting requests.
+# TYPE test_waiting gauge
+test_waiting{pool="user@\"proxy\".com"} 1
+`, output.String())
+
+	assert.Equal(t, float64(3), requests.Get("test", "200"))
+	assert.Panics(t, func() { requests.Inc("missing label") })
+	assert.Panics(t, func() { registry.NewGaugeVec("test_waiting", "duplicate") })
+}
+
+func TestHandlerAndTextfile(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounterVec("test_files_total", "Amount of files.", "module").Inc("test")
+
+	server := httptest.NewServer(Handler(registry))
+	defer server.Close()
+
+	res, err := http.Get(server.URL)
+	assert.NoError(t, err)
+
+	body, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, res.Body.Close())
+	assert.Contains(t, res.Header.Get("Content-Type"), "text/plain")
+	assert.Contains(t, string(body), `test_files_total{module="test"} 1`)
+
+	path := filepath.Join(t.TempDir(), "textfile", "watcher.prom")
+	assert.NoError(t, WriteTextfile(registry, path))
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, string(body), string(content))
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
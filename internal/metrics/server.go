@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Handler returns the HTTP handler exposing the metrics of the passed registry
+func Handler(registry *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = registry.WriteTo(w)
+	})
+}
+
+// GetAddress returns the configured listening address of the metrics endpoint, empty if disabled
+func GetAddress() string {
+	return viper.GetString("metrics.address")
+}
+
+// GetTextfilePath returns the configured path of the textfile collector file, empty if disabled
+func GetTextfilePath() string {
+	return viper.GetString("metrics.textfile")
+}
+
+// ListenAndServe serves the /metrics endpoint of the default registry on the passed address
+// until the passed context is done
+func ListenAndServe(ctx context.Context, address string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler(Default()))
+
+	server := &http.Server{Addr: address, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info(fmt.Sprintf("serving metrics on http://%s/metrics", address))
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// WriteTextfile writes the metrics of the passed registry to the passed path for the node exporter textfile collector.
+// The file is written to a temporary file first and renamed afterward, so the collector never reads partial files
+func WriteTextfile(registry *Registry, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err = registry.WriteTo(tmp); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	// the node exporter requires the file to be readable
+	if err = os.Chmod(tmp.Name(), 0o644); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -1,22 +1,32 @@
 package models
 
-import "database/sql"
+import (
+	"database/sql"
+	"time"
+)
 
 // DatabaseInterface of used functions from the application to eventually change the underlying library
 type DatabaseInterface interface {
 	// tracked item storage functionality
 
+	GetTrackedItem(id int) *TrackedItem
 	GetTrackedItems(module ModuleInterface, includeCompleted bool) (items []*TrackedItem)
 	GetTrackedItemsByDomain(domain string, includeCompleted bool) (items []*TrackedItem)
 	GetFirstOrCreateTrackedItem(uri string, subFolder string, module ModuleInterface) *TrackedItem
 	GetAllOrCreateTrackedItemIgnoreSubFolder(uri string, module ModuleInterface) (items []*TrackedItem)
+	SearchTrackedItems(terms []string) (items []*TrackedItem)
 	UpdateTrackedItem(trackedItem *TrackedItem, currentItem string)
+	UpdateTrackedItemNotes(trackedItem *TrackedItem, notes string)
 	UpdateTrackedItemGeneratedNotes(trackedItem *TrackedItem, generatedNotes string)
 	ChangeTrackedItemUri(trackedItem *TrackedItem, uri string)
 	CreateTrackedItem(uri string, subFolder string, module ModuleInterface)
 	ChangeTrackedItemCompleteStatus(trackedItem *TrackedItem, complete bool)
 	ChangeTrackedItemSubFolder(trackedItem *TrackedItem, subFolder string)
 	ChangeTrackedItemFavoriteStatus(trackedItem *TrackedItem, favorite bool)
+	ChangeTrackedItemSchedule(trackedItem *TrackedItem, schedule string)
+	ChangeTrackedItemPriority(trackedItem *TrackedItem, priority int)
+	UpdateTrackedItemFailures(trackedItem *TrackedItem, failures int, strongFailures int)
+	ChangeTrackedItemQuarantineStatus(trackedItem *TrackedItem, quarantined bool)
 	DeleteTrackedItem(trackedItem *TrackedItem)
 
 	// account storage functionality
@@ -24,6 +34,10 @@ type DatabaseInterface interface {
 	CreateAccount(user string, password string, module ModuleInterface)
 	GetFirstOrCreateAccount(user string, password string, module ModuleInterface) *Account
 	GetAccount(module ModuleInterface) *Account
+	GetAllAccounts(module ModuleInterface) (accounts []*Account)
+	UpdateAccount(user string, password string, module ModuleInterface)
+	UpdateAccountDisabledStatus(user string, disabled bool, module ModuleInterface)
+	DeleteAccount(user string, module ModuleInterface)
 
 	// OAuth2 client storage functionality
 
@@ -32,6 +46,12 @@ type DatabaseInterface interface {
 		id string, secret string, accessToken string, refreshToken string, module ModuleInterface,
 	) *OAuthClient
 	GetOAuthClient(module ModuleInterface) *OAuthClient
+	GetAllOAuthClients(module ModuleInterface) (oAuthClients []*OAuthClient)
+	UpdateOAuthClient(
+		id string, secret string, accessToken string, refreshToken string, module ModuleInterface,
+	)
+	UpdateOAuthClientDisabledStatus(id string, accessToken string, disabled bool, module ModuleInterface)
+	DeleteOAuthClient(id string, module ModuleInterface)
 
 	// cookie storage functionality
 
@@ -41,4 +61,36 @@ type DatabaseInterface interface {
 	CreateCookie(name string, value string, expiration sql.NullTime, module ModuleInterface)
 	UpdateCookie(name string, value string, expirationString string, module ModuleInterface)
 	UpdateCookieDisabledStatus(name string, disabled bool, module ModuleInterface)
+	DeleteCookie(name string, module ModuleInterface)
+
+	// run history functionality
+
+	CreateRun() *Run
+	FinishRun(run *Run)
+	CreateItemRun(itemRun *ItemRun)
+	GetRuns(limit int) (runs []*Run)
+	GetItemRuns(itemID int, module ModuleInterface, onlyFailed bool, limit int) (itemRuns []*ItemRun)
+	GetItemRunSummaries(module ModuleInterface) (summaries []*ItemRunSummary)
+	GetLastItemRunTimes() map[int]time.Time
+
+	// tag functionality
+
+	AddTrackedItemTag(trackedItem *TrackedItem, tag string)
+	RemoveTrackedItemTag(trackedItem *TrackedItem, tag string)
+	GetTrackedItemTags(trackedItem *TrackedItem) []string
+	GetTrackedItemTagMap() map[int][]string
+	GetTags() []*Tag
+
+	// content index functionality
+
+	AddFileHash(path string, sha256 string, size int64, module string)
+	GetFilePathsByHash(sha256 string, size int64) (paths []string)
+	GetFileHashes() (fileHashes []*FileHash)
+	DeleteFileHash(path string)
+
+	// perceptual hash index functionality
+
+	AddImageHash(imageHash *ImageHash)
+	GetImageHashes() (imageHashes []*ImageHash)
+	DeleteImageHash(path string)
 }
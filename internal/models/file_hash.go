@@ -0,0 +1,10 @@
+package models
+
+// FileHash contains the SHA-256 hash and size of a downloaded file, used to detect byte-identical files
+type FileHash struct {
+	ID     int
+	Path   string
+	SHA256 string
+	Size   int64
+	Module string
+}
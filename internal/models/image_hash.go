@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// ImageHash contains the perceptual hashes of a downloaded picture, used to detect similar pictures
+type ImageHash struct {
+	ID         int
+	Path       string
+	PHash      uint64
+	DHash      uint64
+	Size       int64
+	ModifiedAt time.Time
+	Module     string
+}
@@ -0,0 +1,182 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	internalHttp "github.com/DaRealFreak/watcher-go/internal/http"
+	"github.com/DaRealFreak/watcher-go/pkg/fp"
+	"github.com/spf13/viper"
+)
+
+// SidecarMode defines if and how the metadata of downloaded files is saved as .json sidecar file
+type SidecarMode string
+
+const (
+	// SidecarModeNone doesn't save any metadata
+	SidecarModeNone SidecarMode = ""
+	// SidecarModeFile saves the metadata next to each downloaded file as [file name].json
+	SidecarModeFile SidecarMode = "file"
+	// SidecarModePost saves the metadata once per post as [post ID].json next to the downloaded files
+	SidecarModePost SidecarMode = "post"
+)
+
+// SidecarExt is the file extension of the sidecar metadata files
+const SidecarExt = ".json"
+
+// Metadata is the cross-module schema of the metadata of a downloaded post, saved as .json sidecar file
+type Metadata struct {
+	Module       string         `json:"module"`
+	SourceURL    string         `json:"source_url,omitempty"`
+	PostID       string         `json:"post_id,omitempty"`
+	Author       string         `json:"author,omitempty"`
+	AuthorID     string         `json:"author_id,omitempty"`
+	Title        string         `json:"title,omitempty"`
+	Description  string         `json:"description,omitempty"`
+	Tags         []string       `json:"tags,omitempty"`
+	CreatedAt    *time.Time     `json:"created_at,omitempty"`
+	UpdatedAt    *time.Time     `json:"updated_at,omitempty"`
+	FileURL      string         `json:"file_url,omitempty"`
+	FileName     string         `json:"file_name,omitempty"`
+	DownloadedAt time.Time      `json:"downloaded_at"`
+	Extra        map[string]any `json:"extra,omitempty"`
+}
+
+// ParseSidecarMode parses the passed sidecar mode, returns an error for unknown modes
+func ParseSidecarMode(mode string) (SidecarMode, error) {
+	switch SidecarMode(strings.ToLower(mode)) {
+	case SidecarModeNone, "none":
+		return SidecarModeNone, nil
+	case SidecarModeFile:
+		return SidecarModeFile, nil
+	case SidecarModePost:
+		return SidecarModePost, nil
+	default:
+		return SidecarModeNone, fmt.Errorf(
+			"unsupported sidecar mode \"%s\", expected none, %s or %s", mode, SidecarModeFile, SidecarModePost,
+		)
+	}
+}
+
+// GetSidecarPath returns the path of the sidecar file for the passed downloaded file path and post ID
+func GetSidecarPath(mode SidecarMode, filePath string, postID string) string {
+	switch mode {
+	case SidecarModeFile:
+		return filePath + SidecarExt
+	case SidecarModePost:
+		if postID == "" {
+			return filePath + SidecarExt
+		}
+
+		return filepath.Join(
+			filepath.Dir(filePath),
+			fp.TruncateMaxLength(fp.SanitizePath(postID, false))+SidecarExt,
+		)
+	default:
+		return ""
+	}
+}
+
+// WriteSidecar writes the passed metadata into the sidecar file of the passed path.
+// The file is written into a temporary file first and renamed afterwards, so no incomplete sidecar files remain
+func WriteSidecar(sidecarPath string, metadata *Metadata) error {
+	content, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(sidecarPath), os.ModePerm); err != nil {
+		return err
+	}
+
+	tmpPath := sidecarPath + ".tmp"
+	if err = os.WriteFile(tmpPath, content, 0644); err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmpPath, sidecarPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	return nil
+}
+
+// invalidSidecarModes contains the already reported invalid sidecar modes, so every invalid mode is only reported once
+var invalidSidecarModes sync.Map
+
+// GetSidecarMode returns the configured sidecar mode of the module, the module setting overrides the global setting.
+// An invalid module setting falls back to the global setting, an invalid global setting disables the sidecar files
+func (t *Module) GetSidecarMode() SidecarMode {
+	if mode := viper.GetString(fmt.Sprintf("Modules.%s.download.sidecar", t.GetViperModuleKey())); mode != "" {
+		sidecarMode, err := ParseSidecarMode(mode)
+		if err == nil {
+			return sidecarMode
+		}
+
+		t.reportInvalidSidecarMode(mode, err, "using the global sidecar mode")
+	}
+
+	mode := viper.GetString("download.sidecar")
+
+	sidecarMode, err := ParseSidecarMode(mode)
+	if err != nil {
+		t.reportInvalidSidecarMode(mode, err, "no metadata is saved")
+		return SidecarModeNone
+	}
+
+	return sidecarMode
+}
+
+// reportInvalidSidecarMode logs a warning for the passed invalid sidecar mode with the used fallback
+func (t *Module) reportInvalidSidecarMode(mode string, err error, fallback string) {
+	if _, reported := invalidSidecarModes.LoadOrStore(t.Key+"\x00"+mode, true); !reported {
+		slog.Warn(fmt.Sprintf("%s, %s", err.Error(), fallback), "module", t.Key)
+	}
+}
+
+// SaveMetadata saves the passed metadata of the file downloaded from the passed URL to the passed path
+// as sidecar file if enabled.
+// In post mode the sidecar file is only written once per post, nothing is written during dry runs.
+// Errors are only logged since missing metadata shouldn't interrupt the download process
+func (t *Module) SaveMetadata(filePath string, fileURL string, metadata *Metadata) {
+	mode := t.GetSidecarMode()
+	if mode == SidecarModeNone || metadata == nil || internalHttp.GlobalDryRun.Enabled() {
+		return
+	}
+
+	// downloads skipped due to a byte-identical file (skip duplicate policy) don't get a sidecar
+	if _, err := os.Lstat(filePath); err != nil {
+		return
+	}
+
+	sidecarPath := GetSidecarPath(mode, filePath, metadata.PostID)
+	if mode == SidecarModePost {
+		if _, err := os.Stat(sidecarPath); err == nil {
+			return
+		}
+	}
+
+	// copy the metadata to not modify the shared metadata of multiple files of the same post
+	sidecar := *metadata
+	sidecar.Module = t.Key
+	sidecar.DownloadedAt = time.Now()
+
+	if mode == SidecarModeFile {
+		sidecar.FileURL = fileURL
+		sidecar.FileName = filepath.Base(filePath)
+	} else {
+		sidecar.FileURL = ""
+		sidecar.FileName = ""
+	}
+
+	if err := WriteSidecar(sidecarPath, &sidecar); err != nil {
+		slog.Warn(fmt.Sprintf("unable to write metadata to \"%s\": %s", sidecarPath, err.Error()), "module", t.Key)
+	}
+}
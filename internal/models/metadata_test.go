@@ -0,0 +1,106 @@
+package models
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSidecarMode(t *testing.T) {
+	for mode, expected := range map[string]SidecarMode{
+		"":     SidecarModeNone,
+		"none": SidecarModeNone,
+		"File": SidecarModeFile,
+		"post": SidecarModePost,
+	} {
+		parsed, err := ParseSidecarMode(mode)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, parsed)
+	}
+
+	_, err := ParseSidecarMode("xml")
+	assert.Error(t, err)
+}
+
+func TestGetSidecarMode(t *testing.T) {
+	module := &Module{Key: "example.com"}
+
+	defer viper.Set("download.sidecar", nil)
+	defer viper.Set("Modules.example_com.download.sidecar", nil)
+
+	viper.Set("download.sidecar", "file")
+	viper.Set("Modules.example_com.download.sidecar", "post")
+	assert.Equal(t, SidecarModePost, module.GetSidecarMode())
+
+	// invalid module settings fall back to the global setting
+	viper.Set("Modules.example_com.download.sidecar", "xml")
+	assert.Equal(t, SidecarModeFile, module.GetSidecarMode())
+
+	// invalid global settings disable the sidecar files
+	viper.Set("download.sidecar", "yaml")
+	assert.Equal(t, SidecarModeNone, module.GetSidecarMode())
+}
+
+func TestSaveMetadata(t *testing.T) {
+	directory := t.TempDir()
+	module := &Module{Key: "example.com"}
+	metadata := &Metadata{PostID: "123", Title: "title", Tags: []string{"tag"}}
+
+	defer viper.Set("download.sidecar", nil)
+	defer viper.Set("Modules.example_com.download.sidecar", nil)
+
+	// sidecar files are opt-in
+	module.SaveMetadata(filepath.Join(directory, "disabled.png"), "https://example.com/disabled.png", metadata)
+	entries, _ := os.ReadDir(directory)
+	assert.Empty(t, entries)
+
+	for _, fileName := range []string{"1.png", "2.png", "3.png"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(directory, fileName), []byte(fileName), 0o644))
+	}
+
+	viper.Set("download.sidecar", "file")
+	module.SaveMetadata(filepath.Join(directory, "1.png"), "https://example.com/1.png", metadata)
+
+	// files which didn't get saved (f.e. skipped duplicates) don't get a sidecar
+	module.SaveMetadata(filepath.Join(directory, "skipped.png"), "https://example.com/skipped.png", metadata)
+
+	_, err := os.Stat(filepath.Join(directory, "skipped.png.json"))
+	assert.True(t, os.IsNotExist(err))
+
+	content, err := os.ReadFile(filepath.Join(directory, "1.png.json"))
+	assert.NoError(t, err)
+
+	var sidecar Metadata
+	assert.NoError(t, json.Unmarshal(content, &sidecar))
+	assert.Equal(t, "example.com", sidecar.Module)
+	assert.Equal(t, "123", sidecar.PostID)
+	assert.Equal(t, "https://example.com/1.png", sidecar.FileURL)
+	assert.Equal(t, "1.png", sidecar.FileName)
+	assert.Equal(t, []string{"tag"}, sidecar.Tags)
+	assert.False(t, sidecar.DownloadedAt.IsZero())
+
+	// the shared metadata of the post is not modified
+	assert.Empty(t, metadata.Module)
+	assert.Empty(t, metadata.FileURL)
+
+	// the module setting overrides the global setting
+	viper.Set("Modules.example_com.download.sidecar", "post")
+	module.SaveMetadata(filepath.Join(directory, "2.png"), "https://example.com/2.png", metadata)
+	module.SaveMetadata(filepath.Join(directory, "3.png"), "https://example.com/3.png", metadata)
+
+	_, err = os.Stat(filepath.Join(directory, "2.png.json"))
+	assert.True(t, os.IsNotExist(err))
+
+	content, err = os.ReadFile(filepath.Join(directory, "123.json"))
+	assert.NoError(t, err)
+
+	var postSidecar Metadata
+	assert.NoError(t, json.Unmarshal(content, &postSidecar))
+	assert.Equal(t, "123", postSidecar.PostID)
+	assert.Empty(t, postSidecar.FileURL)
+	assert.Empty(t, postSidecar.FileName)
+}
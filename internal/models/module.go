@@ -2,12 +2,10 @@
 package models
 
 import (
-	"context"
 	"fmt"
 	"log/slog"
 	"net/url"
 	"os"
-	"path"
 	"regexp"
 	"strings"
 
@@ -15,7 +13,7 @@ import (
 
 	"github.com/DaRealFreak/watcher-go/internal/configuration"
 	internalHttp "github.com/DaRealFreak/watcher-go/internal/http"
-	"github.com/DaRealFreak/watcher-go/pkg/fp"
+	"github.com/DaRealFreak/watcher-go/internal/logging"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -46,6 +44,10 @@ type DownloadQueueItem struct {
 	FileName        string
 	FileURI         string
 	FallbackFileURI string
+	// Metadata is saved as sidecar file next to the downloaded file if enabled, optional
+	Metadata *Metadata
+	// TemplateValues contains the values of the module specific download template fields, optional
+	TemplateValues map[string]string
 }
 
 // Module is an implementation to the ModuleInterface to provide basic functions/variables
@@ -62,6 +64,22 @@ type Module struct {
 	ProxyLoopIndex int
 	Cfg            *configuration.AppConfiguration
 	SettingsSchema interface{}
+	// TemplateFields are the module specific fields available in the download template.
+	// Modules building their own download paths only support the template if they use GetTemplateDownloadPath
+	TemplateFields []string
+	// NewWorker returns a new bare instance of the module. Modules opt in to parsing multiple items at the same time
+	// by setting it, every worker parses its items with its own instance and session, so the per-item state of the
+	// module is never shared between workers. The rate limiters are shared with the module (see ShareRateLimiters)
+	// and the downloads of the module have to use the session of the module
+	NewWorker func() *Module
+	// logger is the logger of the currently parsed tracked item, set by the watcher while the item gets parsed
+	logger *slog.Logger
+}
+
+// APIRateLimitSharer is implemented by modules using rate limiters besides the one of their session,
+// f.e. for API requests, to let their workers wait for the rate limiters of the module as well
+type APIRateLimitSharer interface {
+	ShareAPIRateLimiters(worker *Module)
 }
 
 type ModuleNotImplementedError struct {
@@ -86,6 +104,34 @@ func (t *Module) ModuleKey() string {
 	return t.Key
 }
 
+// ShareRateLimiters lets the passed loaded worker instance of the module use the rate limiters of the module,
+// so all workers together don't exceed the configured request rate of the module
+func (t *Module) ShareRateLimiters(worker *Module) {
+	if t.Session != nil && worker.Session != nil {
+		worker.Session.SetRateLimiter(t.Session.GetRateLimiter())
+	}
+
+	if sharer, ok := t.ModuleInterface.(APIRateLimitSharer); ok {
+		sharer.ShareAPIRateLimiters(worker)
+	}
+}
+
+// Log returns the logger of the module. While a tracked item is parsed the records contain the module,
+// item ID and URI of the item, otherwise only the module
+func (t *Module) Log() *slog.Logger {
+	if t.logger != nil {
+		return t.logger
+	}
+
+	return logging.Module(t.Key)
+}
+
+// SetItemLogger sets the logger of the currently parsed tracked item, nil resets it to the module logger.
+// Every worker parses with its own module instance, so the logger is never shared between items
+func (t *Module) SetItemLogger(logger *slog.Logger) {
+	t.logger = logger
+}
+
 // RegisterURISchema registers the URI schemas of the module to the passed map
 func (t *Module) RegisterURISchema(uriSchemas map[string][]*regexp.Regexp) {
 	uriSchemas[t.Key] = t.URISchemas
@@ -134,39 +180,32 @@ func (t *Module) SetCookies() {
 
 // ProcessDownloadQueue processes the default download queue, can be used if the module doesn't require special actions
 func (t *Module) ProcessDownloadQueue(downloadQueue []DownloadQueueItem, trackedItem *TrackedItem, notifications ...*Notification) error {
-	slog.Info(
-		fmt.Sprintf("found %d new items for uri: \"%s\"", len(downloadQueue), trackedItem.URI),
-		"module", t.Key,
-	)
+	t.ReportNewItems(trackedItem.URI, len(downloadQueue), notifications...)
 
-	for _, notification := range notifications {
-		slog.Log(context.Background(), notification.Level, notification.Message, "module", t.Key)
-	}
+	logger := logging.Item(t.Key, trackedItem.ID, trackedItem.URI)
+
+	// the index of the files in their post for the download template
+	postIndexes := make(map[string]int)
 
 	for index, data := range downloadQueue {
-		slog.Info(
+		logger.Info(
 			fmt.Sprintf(
 				"downloading updates for uri: \"%s\" (%0.2f%%)",
 				trackedItem.URI,
 				float64(index+1)/float64(len(downloadQueue))*100,
 			),
-			"module", t.Key,
+			"post_id", data.ItemID,
 		)
 
-		err := t.Session.DownloadFile(
-			path.Join(
-				t.GetDownloadDirectory(),
-				t.Key,
-				fp.TruncateMaxLength(fp.SanitizePath(trackedItem.SubFolder, false)),
-				fp.TruncateMaxLength(fp.SanitizePath(data.DownloadTag, false)),
-				fp.TruncateMaxLength(fp.SanitizePath(data.FileName, false)),
-			),
-			data.FileURI,
-		)
-		if err != nil {
+		postIndexes[data.ItemID]++
+		filePath := t.GetDownloadPath(trackedItem, &data, postIndexes[data.ItemID])
+
+		if err := t.Session.DownloadFile(filePath, data.FileURI); err != nil {
 			return err
 		}
 
+		t.SaveMetadata(filePath, data.FileURI, data.Metadata)
+
 		t.DbIO.UpdateTrackedItem(trackedItem, data.ItemID)
 	}
 
@@ -188,12 +227,26 @@ func (t *Module) GetViperModuleKey() string {
 	return strings.ReplaceAll(t.Key, ".", "_")
 }
 
+// initBandwidthLimit registers the download.bandwidth setting of the module in the global bandwidth limiter
+func (t *Module) initBandwidthLimit() {
+	cfg, err := internalHttp.LoadBandwidthConfig(fmt.Sprintf("Modules.%s.download.bandwidth", t.GetViperModuleKey()))
+	if err == nil {
+		err = internalHttp.GlobalBandwidth.SetModuleConfig(t.Key, cfg)
+	}
+
+	if err != nil {
+		slog.Warn(fmt.Sprintf("ignoring invalid bandwidth limit of module: %s", err.Error()), "module", t.Key)
+	}
+}
+
 func (t *Module) Load() error {
 	if !t.Initialized {
 		t.InitializeModule()
 
 		// set whatever cookies we have
 		t.ModuleInterface.SetCookies()
+		// limit the bandwidth of the module sessions if configured
+		t.initBandwidthLimit()
 		t.Initialized = true
 	}
 
@@ -230,9 +283,11 @@ func (t *Module) Load() error {
 				"module requires a login, but the login failed",
 				"module", t.Key,
 			)
+			t.notifyLoginFailure("module requires a login, but the login failed")
 			os.Exit(1)
 		} else {
 			slog.Error("login not successful", "module", t.Key)
+			t.notifyLoginFailure("login not successful")
 			os.Exit(1)
 		}
 	}
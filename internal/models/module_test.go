@@ -0,0 +1,60 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/http/tls_session"
+	"github.com/DaRealFreak/watcher-go/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestModule_Log(t *testing.T) {
+	defaultLogger := slog.Default()
+	defer slog.SetDefault(defaultLogger)
+
+	var output bytes.Buffer
+	slog.SetDefault(slog.New(logging.NewHandler(&output, logging.FormatJSON, slog.LevelInfo, nil)))
+
+	module := &Module{Key: "test"}
+	module.SetItemLogger(logging.Item(module.Key, 7, "https://example.org/item"))
+	module.Log().Info("parsing item")
+
+	module.SetItemLogger(nil)
+	module.Log().Info("outside of item")
+
+	var record map[string]any
+	decoder := json.NewDecoder(&output)
+
+	assert.NoError(t, decoder.Decode(&record))
+	assert.Equal(t, "test", record[logging.KeyModule])
+	assert.Equal(t, float64(7), record[logging.KeyItemID])
+	assert.Equal(t, "https://example.org/item", record[logging.KeyURI])
+
+	record = nil
+	assert.NoError(t, decoder.Decode(&record))
+	assert.Equal(t, "test", record[logging.KeyModule])
+	assert.NotContains(t, record, logging.KeyItemID)
+}
+
+func TestModule_ShareRateLimiters(t *testing.T) {
+	newLimitedModule := func() *Module {
+		session := tls_session.NewTlsClientSession("test")
+		session.RateLimiter = rate.NewLimiter(rate.Every(time.Second), 1)
+
+		return &Module{Key: "test", Session: session}
+	}
+
+	module := newLimitedModule()
+	worker := newLimitedModule()
+
+	module.ShareRateLimiters(worker)
+
+	// the worker keeps its own session but waits for the rate limiter of the module
+	assert.NotSame(t, module.Session, worker.Session)
+	assert.Same(t, module.Session.GetRateLimiter(), worker.Session.GetRateLimiter())
+}
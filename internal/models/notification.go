@@ -1,9 +1,65 @@
 package models
 
-import "log/slog"
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	internalHttp "github.com/DaRealFreak/watcher-go/internal/http"
+	"github.com/DaRealFreak/watcher-go/internal/logging"
+	"github.com/DaRealFreak/watcher-go/internal/notify"
+)
 
 // Notification contains information for the download queue to notify the user about
 type Notification struct {
 	Message string     `json:"message"`
 	Level   slog.Level `json:"level"`
 }
+
+// ReportNewItems logs the amount of new items found for the passed URI and the passed notifications
+// and forwards them to the configured notification sinks unless it's a dry run
+func (t *Module) ReportNewItems(uri string, count int, notifications ...*Notification) {
+	logger := logging.URI(t.Key, uri)
+	logger.Info(fmt.Sprintf("found %d new items for uri: \"%s\"", count, uri), "count", count)
+
+	// nothing gets downloaded during dry runs, so there is nothing to notify about
+	dryRun := internalHttp.GlobalDryRun.Enabled()
+
+	if count > 0 && !dryRun {
+		notify.Default().Notify(notify.Message{
+			Title:  fmt.Sprintf("%d new items for %s", count, uri),
+			Text:   fmt.Sprintf("found %d new items for uri: \"%s\"", count, uri),
+			Level:  slog.LevelInfo,
+			Module: t.Key,
+			URI:    uri,
+		})
+	}
+
+	for _, notification := range notifications {
+		logger.Log(context.Background(), notification.Level, notification.Message)
+
+		if dryRun {
+			continue
+		}
+
+		notify.Default().Notify(notify.Message{
+			Title:  fmt.Sprintf("notification for %s", uri),
+			Text:   notification.Message,
+			Level:  notification.Level,
+			Module: t.Key,
+			URI:    uri,
+		})
+	}
+}
+
+// notifyLoginFailure notifies the configured sinks about a failed login and flushes pending digests
+// since the application exits afterward
+func (t *Module) notifyLoginFailure(message string) {
+	notify.Default().Notify(notify.Message{
+		Title:  fmt.Sprintf("login failed for module %s", t.Key),
+		Text:   message,
+		Level:  slog.LevelError,
+		Module: t.Key,
+	})
+	notify.Default().Flush()
+}
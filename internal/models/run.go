@@ -0,0 +1,54 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Run contains the data of a single run over one or multiple tracked items
+type Run struct {
+	ID         int
+	StartedAt  time.Time
+	FinishedAt sql.NullTime
+	// aggregated from the item runs of this run
+	Items           int
+	FailedItems     int
+	FilesDownloaded int
+	BytesDownloaded int64
+}
+
+// ItemRun contains the result of parsing a single tracked item during a run
+type ItemRun struct {
+	ID              int
+	RunID           int
+	ItemID          int
+	Module          string
+	URI             string
+	StartedAt       time.Time
+	FinishedAt      time.Time
+	FilesDownloaded int
+	BytesDownloaded int64
+	Error           string
+	ErrorClass      string
+}
+
+// Failed returns true if the parsing of the tracked item returned an error
+func (r *ItemRun) Failed() bool {
+	return r.Error != ""
+}
+
+// ItemRunSummary contains the aggregated run history of a single tracked item
+type ItemRunSummary struct {
+	ItemID  int
+	Module  string
+	URI     string
+	Runs    int
+	Failed  int
+	LastRun time.Time
+	// LastSuccess is not valid if the item never got parsed without an error
+	LastSuccess sql.NullTime
+	// ConsecutiveFailures is the amount of failed runs since the last successful run
+	ConsecutiveFailures int
+	LastError           string
+	LastErrorClass      string
+}
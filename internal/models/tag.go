@@ -0,0 +1,14 @@
+package models
+
+import "strings"
+
+// Tag contains the name of a tag and the amount of tracked items it is assigned to
+type Tag struct {
+	Name  string
+	Items int
+}
+
+// NormalizeTag returns the passed tag trimmed and in lower case, so tags are matched case-insensitive
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
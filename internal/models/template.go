@@ -0,0 +1,161 @@
+package models
+
+import (
+	"fmt"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/DaRealFreak/watcher-go/pkg/fp"
+	"github.com/spf13/viper"
+)
+
+// DefaultTemplateFields are the fields available in the download templates of all modules.
+// The author, post and date fields are only set if the module populates the metadata of the download queue items
+var DefaultTemplateFields = []string{
+	"module",
+	"sub_folder",
+	"download_tag",
+	"file_name",
+	"name",
+	"ext",
+	"item_id",
+	"index",
+	"post_id",
+	"author",
+	"author_id",
+	"title",
+	"date",
+}
+
+// uniqueFileNameFields are the fields of which at least one has to be used in the file name of download templates,
+// otherwise the files of a post would overwrite each other
+var uniqueFileNameFields = []string{"index", "name", "file_name"}
+
+// downloadTemplates caches the parsed download templates, so invalid templates are only reported once.
+// Invalid templates are cached as nil
+var downloadTemplates sync.Map
+
+// GetTemplateFields returns all fields available in the download template of the module
+func (t *Module) GetTemplateFields() []string {
+	return append(append([]string{}, DefaultTemplateFields...), t.TemplateFields...)
+}
+
+// GetDownloadTemplate returns the parsed download template configured in Modules.<key>.download.template,
+// returns nil if no template is configured or the configured template is invalid
+func (t *Module) GetDownloadTemplate() *fp.PathTemplate {
+	template := viper.GetString(fmt.Sprintf("Modules.%s.download.template", t.GetViperModuleKey()))
+	if template == "" {
+		return nil
+	}
+
+	cacheKey := t.Key + "\x00" + template
+	if cached, ok := downloadTemplates.Load(cacheKey); ok {
+		return cached.(*fp.PathTemplate)
+	}
+
+	parsed, err := ParseDownloadTemplate(template, t.GetTemplateFields())
+	if err != nil {
+		slog.Error(
+			fmt.Sprintf("invalid download template, using the default download path: %s", err.Error()),
+			"module", t.Key,
+		)
+	}
+
+	downloadTemplates.Store(cacheKey, parsed)
+
+	return parsed
+}
+
+// ParseDownloadTemplate parses the passed download template with the passed allowed fields.
+// The file name of the template has to contain the index, name or file_name field to create unique paths
+func ParseDownloadTemplate(template string, allowedFields []string) (*fp.PathTemplate, error) {
+	parsed, err := fp.ParseTemplate(template, allowedFields)
+	if err != nil {
+		return nil, err
+	}
+
+	if !parsed.FileNameContains(uniqueFileNameFields...) {
+		return nil, fmt.Errorf(
+			"file name of template \"%s\" has to contain at least one of the fields %s",
+			template, strings.Join(uniqueFileNameFields, ", "),
+		)
+	}
+
+	return parsed, nil
+}
+
+// GetDownloadPath returns the path of the passed download queue item of the passed tracked item.
+// The index is the position of the file in its post starting at 1.
+// If no download template is configured the default path [module]/[sub folder]/[download tag]/[file name] is used
+func (t *Module) GetDownloadPath(trackedItem *TrackedItem, data *DownloadQueueItem, index int) string {
+	if filePath, ok := t.GetTemplateDownloadPath(trackedItem, data, index); ok {
+		return filePath
+	}
+
+	return path.Join(
+		t.GetDownloadDirectory(),
+		t.Key,
+		fp.TruncateMaxLength(fp.SanitizePath(trackedItem.SubFolder, false)),
+		fp.TruncateMaxLength(fp.SanitizePath(data.DownloadTag, false)),
+		fp.TruncateMaxLength(fp.SanitizePath(data.FileName, false)),
+	)
+}
+
+// GetTemplateDownloadPath returns the path of the passed download queue item based on the download template.
+// Returns false if no valid download template is configured, so modules can fall back to their own default path
+func (t *Module) GetTemplateDownloadPath(trackedItem *TrackedItem, data *DownloadQueueItem, index int) (string, bool) {
+	template := t.GetDownloadTemplate()
+	if template == nil {
+		return "", false
+	}
+
+	return path.Join(t.GetDownloadDirectory(), template.Execute(t.getTemplateValues(trackedItem, data, index))), true
+}
+
+// getTemplateValues returns the values of all template fields for the passed download queue item
+func (t *Module) getTemplateValues(trackedItem *TrackedItem, data *DownloadQueueItem, index int) map[string]string {
+	fileName := data.FileName
+	if fileName == "" {
+		fileName = fp.GetFileName(data.FileURI)
+	}
+
+	ext := filepath.Ext(fileName)
+	if ext == "" {
+		ext = fp.GetFileExtension(data.FileURI)
+	}
+
+	values := map[string]string{
+		"module":       t.Key,
+		"sub_folder":   trackedItem.SubFolder,
+		"download_tag": data.DownloadTag,
+		"file_name":    fileName,
+		"name":         strings.TrimSuffix(fileName, filepath.Ext(fileName)),
+		"ext":          strings.TrimPrefix(ext, "."),
+		"item_id":      data.ItemID,
+		"index":        strconv.Itoa(index),
+	}
+
+	if metadata := data.Metadata; metadata != nil {
+		values["post_id"] = metadata.PostID
+		values["author"] = metadata.Author
+		values["author_id"] = metadata.AuthorID
+		values["title"] = metadata.Title
+
+		if metadata.CreatedAt != nil {
+			values["date"] = metadata.CreatedAt.Format("2006-01-02")
+		}
+	}
+
+	// module specific fields can't override the default fields
+	for field, value := range data.TemplateValues {
+		if _, exists := values[field]; !exists {
+			values[field] = value
+		}
+	}
+
+	return values
+}
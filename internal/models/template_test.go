@@ -0,0 +1,58 @@
+package models
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDownloadPath(t *testing.T) {
+	module := &Module{Key: "example.com", TemplateFields: []string{"media_type"}}
+	trackedItem := &TrackedItem{SubFolder: "sub/folder"}
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	data := &DownloadQueueItem{
+		ItemID:         "123",
+		DownloadTag:    "tag",
+		FileName:       "file.png",
+		FileURI:        "https://example.com/file.png",
+		Metadata:       &Metadata{PostID: "123", AuthorID: "42", CreatedAt: &createdAt},
+		TemplateValues: map[string]string{"media_type": "photo", "module": "override"},
+	}
+
+	viper.Set("download.directory", "downloads")
+	defer viper.Set("download.directory", nil)
+	defer viper.Set("Modules.example_com.download.template", nil)
+
+	assert.Equal(t, path.Join("downloads", "example.com", "sub_folder", "tag", "file.png"), module.GetDownloadPath(trackedItem, data, 1))
+
+	viper.Set("Modules.example_com.download.template", "{module}/{author|author_id}/{date}_{post_id}_{index}_{media_type}.{ext}")
+	assert.Equal(t, path.Join("downloads", "example.com", "42", "2024-05-01_123_2_photo.png"), module.GetDownloadPath(trackedItem, data, 2))
+
+	// invalid templates fall back to the default path
+	viper.Set("Modules.example_com.download.template", "{module}/{unknown}")
+	assert.Equal(t, path.Join("downloads", "example.com", "sub_folder", "tag", "file.png"), module.GetDownloadPath(trackedItem, data, 1))
+
+	_, ok := module.GetTemplateDownloadPath(trackedItem, data, 1)
+	assert.False(t, ok)
+
+	// templates without unique file names would overwrite the files of the same post
+	viper.Set("Modules.example_com.download.template", "{module}/{index}/{post_id}.{ext}")
+	assert.Equal(t, path.Join("downloads", "example.com", "sub_folder", "tag", "file.png"), module.GetDownloadPath(trackedItem, data, 1))
+}
+
+func TestParseDownloadTemplate(t *testing.T) {
+	for template, valid := range map[string]bool{
+		"{module}/{post_id}.{ext}":             false,
+		"{index}/{post_id}.{ext}":              false,
+		"{module}/{post_id}_{index}.{ext}":     true,
+		"{module}/{name}.{ext}":                true,
+		"{module}/{author|file_name}":          true,
+		"{module}/{post_id}/{unknown}_{index}": false,
+	} {
+		_, err := ParseDownloadTemplate(template, DefaultTemplateFields)
+		assert.Equal(t, valid, err == nil, template)
+	}
+}
@@ -12,5 +12,15 @@ type TrackedItem struct {
 	LastModified   sql.NullTime
 	Favorite       bool
 	Complete       bool
+	Notes          string
 	GeneratedNotes string
+	Schedule       string
+	// Priority of the item, items with a higher priority are updated first if ordered by priority
+	Priority int
+	// Failures is the amount of consecutive failed runs, StrongFailures the amount of those
+	// which failed with errors indicating that the source got deleted (f.e. 404 status codes)
+	Failures       int
+	StrongFailures int
+	LastFailure    sql.NullTime
+	Quarantined    bool
 }
@@ -3,7 +3,6 @@ package bsky
 
 import (
 	"fmt"
-	"log/slog"
 	"regexp"
 	"strings"
 
@@ -113,9 +112,8 @@ func (m *bsky) Login(account *models.Account) bool {
 
 	// fresh login with credentials
 	if err := m.createAuthSession(account.Username, account.Password); err != nil {
-		slog.Error(
+		m.Log().Error(
 			fmt.Sprintf("failed to login to bsky: %s", err.Error()),
-			"module", m.Key,
 		)
 		return false
 	}
@@ -3,7 +3,6 @@ package bsky
 import (
 	"encoding/json"
 	"fmt"
-	"log/slog"
 	"net/url"
 	"path"
 	"strings"
@@ -32,9 +31,8 @@ func (m *bsky) parseProfile(item *models.TrackedItem) error {
 		return err
 	}
 
-	slog.Info(
+	m.Log().Info(
 		fmt.Sprintf("parsing profile \"%s\" (%s)", profile.DisplayName, profile.Handle),
-		"module", m.Key,
 	)
 
 	// resolve PDS for video blob downloads
@@ -100,19 +98,15 @@ func (m *bsky) parseProfile(item *models.TrackedItem) error {
 func (m *bsky) processMediaPosts(mediaPosts []mediaPost, trackedItem *models.TrackedItem) error {
 	total := len(mediaPosts)
 
-	slog.Info(
-		fmt.Sprintf("found %d new items for uri: \"%s\"", total, trackedItem.URI),
-		"module", m.Key,
-	)
+	m.ReportNewItems(trackedItem.URI, total)
 
 	for i, mp := range mediaPosts {
-		slog.Info(
+		m.Log().Info(
 			fmt.Sprintf(
 				"downloading updates for uri: \"%s\" (%0.2f%%)",
 				trackedItem.URI,
 				float64(i+1)/float64(total)*100,
 			),
-			"module", m.Key,
 		)
 
 		for _, item := range mp.items {
@@ -126,9 +120,8 @@ func (m *bsky) processMediaPosts(mediaPosts []mediaPost, trackedItem *models.Tra
 
 			if err := m.Session.DownloadFile(filePath, item.fileURI); err != nil {
 				if item.isVideo {
-					slog.Warn(
+					m.Log().Warn(
 						fmt.Sprintf("failed to download video %s, skipping: %s", item.fileName, err.Error()),
-						"module", m.Key,
 					)
 					continue
 				}
@@ -1,28 +1,21 @@
 package chounyuu
 
 import (
-	"context"
 	"fmt"
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/DaRealFreak/watcher-go/pkg/fp"
-	"log/slog"
 	"path"
 )
 
 func (m *chounyuu) processDownloadQueue(downloadQueue []models.DownloadQueueItem, trackedItem *models.TrackedItem, notifications ...*models.Notification) error {
-	slog.Info(fmt.Sprintf("found %d new items for uri: \"%s\"", len(downloadQueue), trackedItem.URI), "module", m.Key)
-
-	for _, notification := range notifications {
-		slog.Log(context.Background(),
-			notification.Level, notification.Message, "module", m.Key)
-	}
+	m.ReportNewItems(trackedItem.URI, len(downloadQueue), notifications...)
 
 	for index, data := range downloadQueue {
-		slog.Info(fmt.Sprintf(
+		m.Log().Info(fmt.Sprintf(
 			"downloading updates for uri: \"%s\" (%0.2f%%)",
 			trackedItem.URI,
 			float64(index+1)/float64(len(downloadQueue))*100,
-		), "module", m.Key)
+		))
 
 		err := m.Session.DownloadFile(
 			path.Join(
@@ -37,8 +30,8 @@ func (m *chounyuu) processDownloadQueue(downloadQueue []models.DownloadQueueItem
 		if err != nil {
 			switch err.(type) {
 			case DeletedMediaError:
-				slog.Warn(fmt.Sprintf("received 404 status code for URI \"%s\", content got most likely deleted, skipping",
-					data.FileURI), "module", m.ModuleKey())
+				m.Log().Warn(fmt.Sprintf("received 404 status code for URI \"%s\", content got most likely deleted, skipping",
+					data.FileURI))
 			default:
 				return err
 			}
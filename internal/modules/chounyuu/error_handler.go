@@ -12,6 +12,10 @@ func (e DeletedMediaError) Error() string {
 	return "content got deleted"
 }
 
+func (e DeletedMediaError) Gone() bool {
+	return true
+}
+
 type errorHandler struct{}
 
 func (e errorHandler) CheckResponse(response *http.Response) (err error, fatal bool) {
@@ -7,13 +7,13 @@ import (
 	"fmt"
 	formatter "github.com/DaRealFreak/colored-nested-formatter/v2"
 	"github.com/DaRealFreak/watcher-go/internal/http/tls_session"
+	"github.com/DaRealFreak/watcher-go/internal/logging"
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/DaRealFreak/watcher-go/internal/modules"
 	"github.com/DaRealFreak/watcher-go/internal/modules/chounyuu/api"
 	"github.com/DaRealFreak/watcher-go/internal/raven"
 	http "github.com/bogdanfinn/fhttp"
 	"github.com/spf13/cobra"
-	"log/slog"
 	"net/url"
 	"regexp"
 	"strings"
@@ -152,17 +152,14 @@ func (m *chounyuu) addRunCommand(command *cobra.Command) {
 						continue
 					}
 
-					slog.Info(
-						fmt.Sprintf("parsing item %s (current id: %s)", item.URI, item.CurrentItem),
-						"module", item.Module,
-					)
+					m.SetItemLogger(logging.Item(item.Module, item.ID, item.URI))
+					m.Log().Info(fmt.Sprintf("parsing item %s (current id: %s)", item.URI, item.CurrentItem))
 
 					if err := m.Parse(item); err != nil {
-						slog.Warn(
-							fmt.Sprintf("error occurred parsing item %s (%s), skipping", item.URI, err.Error()),
-							"module", item.Module,
-						)
+						m.Log().Warn(fmt.Sprintf("error occurred parsing item %s (%s), skipping", item.URI, err.Error()))
 					}
+
+					m.SetItemLogger(nil)
 				}
 			}
 		},
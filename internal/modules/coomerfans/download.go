@@ -1,9 +1,7 @@
 package coomerfans
 
 import (
-	"context"
 	"fmt"
-	"log/slog"
 	"path"
 	"strings"
 
@@ -58,18 +56,14 @@ func scrapeUsername(doc *goquery.Document) string {
 // processDownloadQueue downloads each queued post in order, advancing the
 // tracked item's progress after each post completes.
 func (m *coomerfans) processDownloadQueue(item *models.TrackedItem, queue []postRef, notifications ...*models.Notification) error {
-	slog.Info(fmt.Sprintf("found %d new items for uri: %q", len(queue), item.URI), "module", m.Key)
-
-	for _, notification := range notifications {
-		slog.Log(context.Background(), notification.Level, notification.Message, "module", m.Key)
-	}
+	m.ReportNewItems(item.URI, len(queue), notifications...)
 
 	for index, ref := range queue {
-		slog.Info(fmt.Sprintf(
+		m.Log().Info(fmt.Sprintf(
 			"downloading updates for uri: %q (%0.2f%%)",
 			item.URI,
 			float64(index+1)/float64(len(queue))*100,
-		), "module", m.Key)
+		))
 
 		if err := m.downloadPost(item, ref); err != nil {
 			return err
@@ -36,6 +36,19 @@ func init() {
 
 // NewBareModule returns a bare module implementation for the CLI options.
 func NewBareModule() *models.Module {
+	module := newModule()
+
+	// register module to log formatter
+	formatter.AddFieldMatchColorScheme("module", &formatter.FieldMatch{
+		Value: module.Key,
+		Color: "232:213",
+	})
+
+	return module
+}
+
+// newModule returns a new instance of the module, also used for the workers of the module.
+func newModule() *models.Module {
 	module := &models.Module{
 		Key:           "coomerfans.com",
 		RequiresLogin: false,
@@ -48,12 +61,7 @@ func NewBareModule() *models.Module {
 	module.ModuleInterface = &coomerfans{
 		Module: module,
 	}
-
-	// register module to log formatter
-	formatter.AddFieldMatchColorScheme("module", &formatter.FieldMatch{
-		Value: module.Key,
-		Color: "232:213",
-	})
+	module.NewWorker = newModule
 
 	return module
 }
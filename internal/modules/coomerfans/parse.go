@@ -3,7 +3,6 @@ package coomerfans
 
 import (
 	"fmt"
-	"log/slog"
 	"regexp"
 	"strings"
 
@@ -133,7 +132,7 @@ func (m *coomerfans) parseUser(item *models.TrackedItem) error {
 		queue[i], queue[j] = queue[j], queue[i]
 	}
 
-	slog.Info(fmt.Sprintf("found user %s (%s/%s) with %d new posts", username, service, userID, len(queue)), "module", m.Key)
+	m.Log().Info(fmt.Sprintf("found user %s (%s/%s) with %d new posts", username, service, userID, len(queue)))
 
 	return m.processDownloadQueue(item, queue)
 }
@@ -6,7 +6,6 @@ import (
 	"github.com/DaRealFreak/watcher-go/internal/modules/deviantart/napi"
 	"github.com/DaRealFreak/watcher-go/internal/raven"
 	"github.com/DaRealFreak/watcher-go/pkg/fp"
-	"log/slog"
 	"net/url"
 	"path/filepath"
 	"strconv"
@@ -88,9 +87,9 @@ func (m *deviantArt) parseCollectionNapi(item *models.TrackedItem) error {
 			collectionIntID,
 			strings.ToLower(url.PathEscape(strings.ReplaceAll(collectionFolder.Name, " ", "-"))),
 		)
-		slog.Warn(fmt.Sprintf("collection owner changed its name, updated tracked uri from \"%s\" to \"%s\"",
+		m.Log().Warn(fmt.Sprintf("collection owner changed its name, updated tracked uri from \"%s\" to \"%s\"",
 			item.URI,
-			uri), "module", m.ModuleKey())
+			uri))
 
 		m.DbIO.ChangeTrackedItemUri(item, uri)
 	}
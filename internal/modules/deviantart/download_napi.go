@@ -1,7 +1,6 @@
 package deviantart
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"math"
@@ -14,7 +13,6 @@ import (
 	"github.com/DaRealFreak/watcher-go/internal/modules/deviantart/napi"
 	"github.com/DaRealFreak/watcher-go/pkg/fp"
 	"github.com/DaRealFreak/watcher-go/pkg/imaging/duplication"
-	"log/slog"
 	"net/url"
 	"os"
 	"path"
@@ -95,7 +93,7 @@ func scaleWixmpURL(uri string, maxPixels int64) string {
 	}
 
 	// apply a 0.5% safety margin to account for server-side rounding of dimensions
-	scale := math.Sqrt(float64(maxPixels)*0.995 / float64(w*h))
+	scale := math.Sqrt(float64(maxPixels) * 0.995 / float64(w*h))
 	newW := int64(math.Floor(float64(w) * scale))
 	newH := int64(math.Floor(float64(h) * scale))
 
@@ -133,7 +131,7 @@ func (m *deviantArt) downloadFile(session http.TlsClientSessionInterface, filepa
 			maxPixels := parseMaxPixels(scErr.Body)
 			scaledURI := scaleWixmpURL(uri, maxPixels)
 			if scaledURI != uri {
-				slog.Warn(fmt.Sprintf("pixel limit exceeded, retrying with scaled URL: %s", scaledURI), "module", m.Key)
+				m.Log().Warn(fmt.Sprintf("pixel limit exceeded, retrying with scaled URL: %s", scaledURI))
 				return m.downloadFile(session, filepath, scaledURI)
 			}
 		}
@@ -154,8 +152,8 @@ func (m *deviantArt) processDownloadQueueNapi(downloadQueue []downloadQueueItemN
 				if scErr.StatusCode == 404 || scErr.StatusCode == 400 {
 					// 400 (invalid crop, expired token) or 404 (expired session)
 					// tear down and re-login
-					slog.Warn(fmt.Sprintf("error occurred downloading item %s (%s) with multi-proxy: %s, tearing down sessions and re-login (CSRF: %s)",
-						trackedItem.URI, downloadQueue[0].itemID, err.Error(), m.nAPI.CSRFToken), "module", m.Key)
+					m.Log().Warn(fmt.Sprintf("error occurred downloading item %s (%s) with multi-proxy: %s, tearing down sessions and re-login (CSRF: %s)",
+						trackedItem.URI, downloadQueue[0].itemID, err.Error(), m.nAPI.CSRFToken))
 
 					// fully tear down old proxy sessions and multi-proxy state
 					// so Login + initializeProxySessions starts completely fresh
@@ -172,19 +170,14 @@ func (m *deviantArt) processDownloadQueueNapi(downloadQueue []downloadQueueItemN
 			return err
 		}
 	} else {
-		slog.Info(fmt.Sprintf("found %d new items for uri: %s", len(downloadQueue), trackedItem.URI), "module", m.Key)
-
-		for _, notification := range notifications {
-			slog.Log(context.Background(),
-				notification.Level, notification.Message, "module", m.Key)
-		}
+		m.ReportNewItems(trackedItem.URI, len(downloadQueue), notifications...)
 
 		for index, deviationItem := range downloadQueue {
-			slog.Info(fmt.Sprintf(
+			m.Log().Info(fmt.Sprintf(
 				"downloading updates for uri: %s (%0.2f%%)",
 				trackedItem.URI,
 				float64(index+1)/float64(len(downloadQueue))*100,
-			), "module", m.Key)
+			))
 
 			if err := m.downloadDeviationNapi(trackedItem, deviationItem, nil, true); err != nil {
 				return err
@@ -227,7 +220,7 @@ func (m *deviantArt) downloadDeviationNapi(
 			}
 
 			if watchRes.Success {
-				slog.Info(fmt.Sprintf("followed user \"%s\" for deviation", res.Deviation.Author.Username), "module", m.Key)
+				m.Log().Info(fmt.Sprintf("followed user \"%s\" for deviation", res.Deviation.Author.Username))
 			} else {
 				return fmt.Errorf("unable to follow user \"%s\" for deviation, skipping", res.Deviation.Author.Username)
 			}
@@ -243,7 +236,7 @@ func (m *deviantArt) downloadDeviationNapi(
 				}
 
 				if watchRes.Success {
-					slog.Info(fmt.Sprintf("unfollowed user \"%s\" after downloading deviation", res.Deviation.Author.Username), "module", m.Key)
+					m.Log().Info(fmt.Sprintf("unfollowed user \"%s\" after downloading deviation", res.Deviation.Author.Username))
 				} else {
 					return fmt.Errorf("unable to unfollow user \"%s\" for deviation, skipping", res.Deviation.Author.Username)
 				}
@@ -252,9 +245,9 @@ func (m *deviantArt) downloadDeviationNapi(
 			return nil
 		}
 
-		slog.Warn(fmt.Sprintf("no access to deviation \"%s\", deviation is only available to %s, skipping",
+		m.Log().Warn(fmt.Sprintf("no access to deviation \"%s\", deviation is only available to %s, skipping",
 			deviationItem.deviation.URL,
-			res.Deviation.PremiumFolderData.Type), "module", m.Key)
+			res.Deviation.PremiumFolderData.Type))
 		return nil
 	}
 
@@ -280,12 +273,12 @@ func (m *deviantArt) downloadDeviationNapi(
 	if deviationItem.deviation.IsDownloadable && deviationItem.deviation.Extended != nil {
 		if m.settings.Download.SkipSourceDownloads {
 			skippedSourceDownload = true
-			slog.Warn(fmt.Sprintf(
+			m.Log().Warn(fmt.Sprintf(
 				"skipping source download (download limit) for deviation %s by %s: %s",
 				deviationItem.deviation.DeviationId.String(),
 				deviationItem.deviation.Author.Username,
 				deviationItem.deviation.Extended.Download.URL,
-			), "module", m.Key)
+			))
 		} else {
 			dst := path.Join(
 				m.GetDownloadDirectory(),
@@ -323,9 +316,9 @@ func (m *deviantArt) downloadDeviationNapi(
 	// ──────────────────────────────────────────────────────────────
 	for _, additionalMedia := range deviationItem.deviation.Extended.AdditionalMedia {
 		if additionalMedia.Media.BaseUri != "" {
-			slog.Debug(fmt.Sprintf("downloading additional media: %s (%s bytes)",
+			m.Log().Debug(fmt.Sprintf("downloading additional media: %s (%s bytes)",
 				additionalMedia.Media.BaseUri,
-				additionalMedia.FileSize.String()), "module", m.Key)
+				additionalMedia.FileSize.String()))
 
 			if additionalMedia.Media.Token != nil && additionalMedia.Media.Token.GetToken() != "" {
 				fileUri, _ := url.Parse(additionalMedia.Media.BaseUri)
@@ -404,6 +397,14 @@ func (m *deviantArt) downloadDeviationNapi(
 		m.DbIO.UpdateTrackedItem(trackedItem, deviationItem.itemID)
 	}
 
+	// ──────────────────────────────────────────────────────────────
+	// save the metadata (including the description) of the downloaded files
+	// ──────────────────────────────────────────────────────────────
+	metadata := m.getDeviationMetadata(deviationItem.deviation)
+	for _, f := range downloadedFiles {
+		m.SaveMetadata(f, deviationItem.deviation.URL, metadata)
+	}
+
 	// ──────────────────────────────────────────────────────────────
 	// now that all files are downloaded, reset their timestamps
 	// ──────────────────────────────────────────────────────────────
@@ -419,7 +420,7 @@ func (m *deviantArt) downloadDeviationNapi(
 
 		if info, infoErr := os.Stat(f); infoErr == nil && !info.IsDir() {
 			if err = os.Chtimes(f, t, t); err != nil {
-				slog.Warn(fmt.Sprintf("failed to reset timestamp for %s: %v", f, err), "module", m.Key)
+				m.Log().Warn(fmt.Sprintf("failed to reset timestamp for %s: %v", f, err))
 			}
 		}
 	}
@@ -427,6 +428,39 @@ func (m *deviantArt) downloadDeviationNapi(
 	return nil
 }
 
+// getDeviationMetadata returns the cross-module metadata of the passed deviation
+func (m *deviantArt) getDeviationMetadata(deviation *napi.Deviation) *models.Metadata {
+	metadata := &models.Metadata{
+		SourceURL: deviation.URL,
+		PostID:    deviation.DeviationId.String(),
+		Title:     deviation.Title,
+		Extra:     map[string]any{"type": deviation.Type},
+	}
+
+	if deviation.Author != nil {
+		metadata.Author = deviation.Author.Username
+		metadata.AuthorID = deviation.Author.UserId.String()
+	}
+
+	if publishedAt := deviation.GetPublishedTime(); !publishedAt.IsZero() {
+		metadata.CreatedAt = &publishedAt
+	}
+
+	if deviation.Extended != nil {
+		if deviation.Extended.DescriptionText != nil {
+			if description, err := deviation.Extended.DescriptionText.GetTextContent(); err == nil {
+				metadata.Description = description
+			}
+		}
+
+		for _, tag := range deviation.Extended.Tags {
+			metadata.Tags = append(metadata.Tags, tag.Name)
+		}
+	}
+
+	return metadata
+}
+
 func (m *deviantArt) downloadDescriptionNapi(deviationItem downloadQueueItemNAPI, downloadedFiles *[]string) error {
 	// if we couldn't retrieve the extended response, we can't access the markup anyway
 	if deviationItem.deviation.Extended == nil {
@@ -450,7 +484,13 @@ func (m *deviantArt) downloadDescriptionNapi(deviationItem downloadQueueItemNAPI
 				fp.SanitizePath(deviationItem.deviation.GetPrettyName(), false),
 			),
 		)
-		slog.Debug(fmt.Sprintf("downloading description: \"%s\"", filePath), "module", m.Key)
+		m.Log().Debug(fmt.Sprintf("downloading description: \"%s\"", filePath))
+
+		// the description is written directly, so dry runs have to be handled here
+		if http.GlobalDryRun.Enabled() {
+			http.GlobalDryRun.RecordDownload(m.Key, filePath, deviationItem.deviation.URL)
+			return nil
+		}
 
 		if err = os.WriteFile(filePath, []byte(text), 0644); err != nil {
 			return err
@@ -480,7 +520,13 @@ func (m *deviantArt) downloadLiteratureNapi(deviationItem downloadQueueItemNAPI,
 			fp.SanitizePath(deviationItem.deviation.GetPrettyName(), false),
 		),
 	)
-	slog.Debug(fmt.Sprintf("downloading literature: \"%s\"", filePath), "module", m.Key)
+	m.Log().Debug(fmt.Sprintf("downloading literature: \"%s\"", filePath))
+
+	// the literature is written directly, so dry runs have to be handled here
+	if http.GlobalDryRun.Enabled() {
+		http.GlobalDryRun.RecordDownload(m.Key, filePath, deviationItem.deviation.URL)
+		return nil
+	}
 
 	if err = os.WriteFile(filePath, []byte(text), 0644); err != nil {
 		return err
@@ -595,7 +641,7 @@ func (m *deviantArt) downloadContentNapi(
 		sim, err := duplication.CheckForSimilarity(downloadFilePath, contentFilePath)
 		// if either the file couldn't be converted (probably different file type) or similarity is below 95%
 		if err == nil && sim >= 0.95 {
-			slog.Debug(fmt.Sprintf(`content has higher match between download and content than configured, removing file %f`, sim), "module", m.Key)
+			m.Log().Debug(fmt.Sprintf(`content has higher match between download and content than configured, removing file %f`, sim))
 			return os.Remove(contentFilePath)
 		}
 	}
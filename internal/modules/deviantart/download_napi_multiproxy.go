@@ -7,9 +7,6 @@ import (
 	"strings"
 	"time"
 
-	"context"
-	"log/slog"
-
 	"github.com/DaRealFreak/watcher-go/internal/http"
 	"github.com/DaRealFreak/watcher-go/internal/http/tls_session"
 	"github.com/DaRealFreak/watcher-go/internal/models"
@@ -30,7 +27,7 @@ func (m *deviantArt) initializeProxySessions() {
 	// share the same cookies and stay in sync with Set-Cookie updates
 	mainSession, ok := m.nAPI.UserSession.(*tls_session.TlsClientSession)
 	if !ok {
-		slog.Error("cannot share cookie jar: main session is not a TlsClientSession, falling back to cookie copy")
+		m.Log().Error("cannot share cookie jar: main session is not a TlsClientSession, falling back to cookie copy")
 		m.initializeProxySessionsLegacy()
 		return
 	}
@@ -116,12 +113,7 @@ func (m *deviantArt) resetProxies() {
 }
 
 func (m *deviantArt) processDownloadQueueMultiProxy(downloadQueue []downloadQueueItemNAPI, trackedItem *models.TrackedItem, notifications ...*models.Notification) error {
-	slog.Info(fmt.Sprintf("found %d new items for uri: \"%s\"", len(downloadQueue), trackedItem.URI), "module", m.Key)
-
-	for _, notification := range notifications {
-		slog.Log(context.Background(),
-			notification.Level, notification.Message, "module", m.Key)
-	}
+	m.ReportNewItems(trackedItem.URI, len(downloadQueue), notifications...)
 
 	// track which items completed successfully for progress saving on error
 	completedItems := make([]bool, len(downloadQueue))
@@ -135,17 +127,17 @@ func (m *deviantArt) processDownloadQueueMultiProxy(downloadQueue []downloadQueu
 
 		// handle if errors occurred in previous downloads
 		if erroneousProxy := m.getProxyError(); erroneousProxy != nil {
-			slog.Warn(fmt.Sprintf("error occurred during download for proxy: %s",
-				erroneousProxy.proxy.Host), "module", m.Key)
+			m.Log().Warn(fmt.Sprintf("error occurred during download for proxy: %s",
+				erroneousProxy.proxy.Host))
 			break
 		}
 
 		if m.hasFreeProxy() {
-			slog.Info(fmt.Sprintf(
+			m.Log().Info(fmt.Sprintf(
 				"downloading updates for uri: \"%s\" (%0.2f%%)",
 				trackedItem.URI,
 				float64(index+1)/float64(len(downloadQueue))*100,
-			), "module", m.Key)
+			))
 
 			m.multiProxy.waitGroup.Add(1)
 			proxy := m.getFreeProxy()
@@ -180,8 +172,8 @@ func (m *deviantArt) processDownloadQueueMultiProxy(downloadQueue []downloadQueu
 
 	// handle if errors occurred in previous downloads
 	if erroneousProxy := m.getProxyError(); erroneousProxy != nil {
-		slog.Warn(fmt.Sprintf("error occurred during download for proxy: %s",
-			erroneousProxy.proxy.Host), "module", m.Key)
+		m.Log().Warn(fmt.Sprintf("error occurred during download for proxy: %s",
+			erroneousProxy.proxy.Host))
 		return m.getProxyError().occurredError
 	}
 
@@ -205,15 +197,15 @@ func (m *deviantArt) downloadItemSessionNapi(
 			}
 		}
 	} else {
-		slog.Error(fmt.Sprintf("error occurred downloading item %s (%s) with proxy %s: %s (CSRF: %s)",
-			trackedItem.URI, deviationItem.itemID, downloadSession.proxy.Host, downloadSession.occurredError.Error(), m.nAPI.CSRFToken), "module", m.Key)
+		m.Log().Error(fmt.Sprintf("error occurred downloading item %s (%s) with proxy %s: %s (CSRF: %s)",
+			trackedItem.URI, deviationItem.itemID, downloadSession.proxy.Host, downloadSession.occurredError.Error(), m.nAPI.CSRFToken))
 
 		var scErr tls_session.StatusError
 		if errors.As(downloadSession.occurredError, &scErr) {
 			if scErr.StatusCode == 400 && strings.Contains(scErr.Body, "image is invalid") {
 				// broken/corrupt image on DA's side, skip and continue
-				slog.Warn(fmt.Sprintf("skipping invalid image for deviation %s (%s): %s",
-					deviationItem.deviation.URL, deviationItem.itemID, scErr.Body), "module", m.Key)
+				m.Log().Warn(fmt.Sprintf("skipping invalid image for deviation %s (%s): %s",
+					deviationItem.deviation.URL, deviationItem.itemID, scErr.Body))
 				downloadSession.occurredError = nil
 				completedItems[index] = true
 
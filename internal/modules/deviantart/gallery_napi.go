@@ -6,7 +6,6 @@ import (
 	"github.com/DaRealFreak/watcher-go/internal/modules/deviantart/napi"
 	"github.com/DaRealFreak/watcher-go/internal/raven"
 	"github.com/DaRealFreak/watcher-go/pkg/fp"
-	"log/slog"
 	"net/url"
 	"path/filepath"
 	"strconv"
@@ -61,9 +60,9 @@ func (m *deviantArt) parseGalleryNapi(item *models.TrackedItem) error {
 			galleryIntID,
 			strings.ToLower(url.PathEscape(strings.ReplaceAll(galleryFolder.Name, " ", "-"))),
 		)
-		slog.Warn(fmt.Sprintf("gallery owner changed its name, updated tracked uri from \"%s\" to \"%s\"",
+		m.Log().Warn(fmt.Sprintf("gallery owner changed its name, updated tracked uri from \"%s\" to \"%s\"",
 			item.URI,
-			uri), "module", m.ModuleKey())
+			uri))
 
 		m.DbIO.ChangeTrackedItemUri(item, uri)
 	}
@@ -15,7 +15,6 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"golang.org/x/time/rate"
-	"log/slog"
 	"regexp"
 	"sync"
 	"time"
@@ -144,7 +143,7 @@ func (m *deviantArt) Login(account *models.Account) bool {
 
 	// set the proxy if requested
 	if m.settings.Cloudflare.LoginWithoutProxy {
-		slog.Debug("setting proxy to false for login without proxy", "module", m.Key)
+		m.Log().Debug("setting proxy to false for login without proxy")
 		raven.CheckError(m.nAPI.UserSession.SetProxy(&http.ProxySettings{Enable: false}))
 	}
 
@@ -175,33 +174,32 @@ func (m *deviantArt) Login(account *models.Account) bool {
 func (m *deviantArt) authenticate(account *models.Account) bool {
 	// fast path: reuse a persisted session without launching a browser
 	if m.loadStoredCookies() && m.nAPI.IsLoggedIn() {
-		slog.Info("authenticated via stored session cookies", "module", m.Key)
+		m.Log().Info("authenticated via stored session cookies")
 		return true
 	}
 
 	if account == nil {
-		slog.Error(
+		m.Log().Error(
 			"no valid session cookies and no account configured for the browser login",
-			"module", m.Key,
 		)
 		return false
 	}
 
 	cookies, err := m.browserLogin(account)
 	if err != nil {
-		slog.Error(fmt.Sprintf("browser login failed: %v", err), "module", m.Key)
+		m.Log().Error(fmt.Sprintf("browser login failed: %v", err))
 		return false
 	}
 
 	m.nAPI.SetSessionCookies(cookies)
 
 	if !m.nAPI.IsLoggedIn() {
-		slog.Error("browser login completed but the session did not validate", "module", m.Key)
+		m.Log().Error("browser login completed but the session did not validate")
 		return false
 	}
 
 	m.persistCookies(cookies)
-	slog.Info("authenticated via browser login", "module", m.Key)
+	m.Log().Info("authenticated via browser login")
 
 	return true
 }
@@ -215,7 +213,7 @@ func (m *deviantArt) browserLogin(account *models.Account) ([]*fhttp.Cookie, err
 		headless = *m.settings.Login.Headless
 	}
 
-	slog.Info("performing browser login for deviantart (PerimeterX)", "module", m.Key)
+	m.Log().Info("performing browser login for deviantart (PerimeterX)")
 
 	return browserlogin.Login(account.Username, account.Password, browserlogin.Options{
 		ChromePath: m.settings.Login.BrowserPath,
@@ -302,7 +300,7 @@ func (m *deviantArt) Parse(item *models.TrackedItem) (err error) {
 	}
 
 	m.nAPI.CSRFToken = csrfToken
-	slog.Debug(fmt.Sprintf("extracted new CSRF token: %s", csrfToken), "module", m.Key)
+	m.Log().Debug(fmt.Sprintf("extracted new CSRF token: %s", csrfToken))
 
 	switch {
 	case m.daPattern.artPattern.MatchString(item.URI):
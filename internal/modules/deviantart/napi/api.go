@@ -135,6 +135,9 @@ type Extended struct {
 		Media    *Media      `json:"media"`
 	} `json:"additionalMedia"`
 	DescriptionText *TextContent `json:"descriptionText"`
+	Tags            []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
 }
 
 type Media struct {
@@ -6,7 +6,6 @@ import (
 	"github.com/DaRealFreak/watcher-go/internal/modules/deviantart/napi"
 	"github.com/DaRealFreak/watcher-go/internal/raven"
 	"github.com/DaRealFreak/watcher-go/pkg/fp"
-	"log/slog"
 	"strconv"
 	"strings"
 )
@@ -29,9 +28,9 @@ func (m *deviantArt) parseUserNapi(item *models.TrackedItem) error {
 
 	if strings.ToLower(userInfo.User.Username) != username {
 		uri := fmt.Sprintf("https://www.deviantart.com/%s", userInfo.User.GetUsernameUrl())
-		slog.Warn(fmt.Sprintf("author changed its name, updated tracked uri from \"%s\" to \"%s\"",
+		m.Log().Warn(fmt.Sprintf("author changed its name, updated tracked uri from \"%s\" to \"%s\"",
 			item.URI,
-			uri), "module", m.ModuleKey())
+			uri))
 
 		m.DbIO.ChangeTrackedItemUri(item, uri)
 	}
@@ -9,7 +9,6 @@ import (
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/DaRealFreak/watcher-go/pkg/fp"
 	"github.com/PuerkitoBio/goquery"
-	"log/slog"
 )
 
 // imageGalleryItem contains the relevant data of gallery items
@@ -53,9 +52,9 @@ func (m *ehentai) parseGallery(item *models.TrackedItem) error {
 	if !whitelisted && !m.Cfg.Run.Force {
 		for _, blacklistedTag := range m.settings.Search.BlacklistedTags {
 			if strings.Contains(strings.ToLower(galleryTitle), strings.ToLower(blacklistedTag)) {
-				slog.Warn(fmt.Sprintf("gallery title \"%s\" contains blacklisted tag \"%s\", setting item to complete",
+				m.Log().Warn(fmt.Sprintf("gallery title \"%s\" contains blacklisted tag \"%s\", setting item to complete",
 					galleryTitle,
-					blacklistedTag), "module", m.Key)
+					blacklistedTag))
 				m.DbIO.ChangeTrackedItemCompleteStatus(item, true)
 				return nil
 			}
@@ -145,7 +144,7 @@ func (m *ehentai) getGalleryImageUrls(html string, galleryTitle string) []*image
 // hasGalleryErrors checks if the gallery has any errors and should be skipped
 func (m *ehentai) hasGalleryErrors(item *models.TrackedItem, html string) (bool, *models.TrackedItem) {
 	if strings.Contains(html, "There are newer versions of this gallery available") {
-		slog.Info("newer version of gallery available, updating uri of: "+item.URI, "module", m.Key)
+		m.Log().Info("newer version of gallery available, updating uri of: " + item.URI)
 
 		document, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
 		newGalleryLinks := document.Find("#gnd > a")
@@ -156,7 +155,7 @@ func (m *ehentai) hasGalleryErrors(item *models.TrackedItem, html string) (bool,
 			url, exists := row.Attr("href")
 			if exists {
 				newGalleryItem = m.DbIO.GetFirstOrCreateTrackedItem(url, m.getSubFolder(item), m)
-				slog.Info("added gallery to tracked items: "+url, "module", m.Key)
+				m.Log().Info("added gallery to tracked items: " + url)
 			}
 		})
 
@@ -164,7 +163,7 @@ func (m *ehentai) hasGalleryErrors(item *models.TrackedItem, html string) (bool,
 	}
 
 	if strings.Contains(html, "document.location = \"https://exhentai.org/\";") {
-		slog.Warn("this gallery has been removed due to a copyright claim", "module", m.Key)
+		m.Log().Warn("this gallery has been removed due to a copyright claim")
 		return true, nil
 	}
 
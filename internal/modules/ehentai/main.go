@@ -2,7 +2,6 @@
 package ehentai
 
 import (
-	"context"
 	"fmt"
 	http2 "net/http"
 	"net/url"
@@ -13,6 +12,7 @@ import (
 	"time"
 
 	formatter "github.com/DaRealFreak/colored-nested-formatter/v2"
+	"github.com/DaRealFreak/watcher-go/internal/hooks"
 	"github.com/DaRealFreak/watcher-go/internal/http"
 	"github.com/DaRealFreak/watcher-go/internal/http/std_session"
 	"github.com/DaRealFreak/watcher-go/internal/models"
@@ -22,7 +22,6 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"golang.org/x/time/rate"
-	"log/slog"
 )
 
 // ehentai contains the implementation of the ModuleInterface and extends it by custom-required values
@@ -74,7 +73,7 @@ func NewBareModule() *models.Module {
 		URISchemas: []*regexp.Regexp{
 			regexp.MustCompile(`.*e[\-x]hentai.org`),
 		},
-		ProxyLoopIndex:  -1,
+		ProxyLoopIndex: -1,
 		SettingsSchema: ehentaiSettings{},
 	}
 	module.ModuleInterface = &ehentai{
@@ -229,23 +228,15 @@ func (m *ehentai) Parse(item *models.TrackedItem) (err error) {
 
 // processDownloadQueue processes the download queue consisting of gallery items
 func (m *ehentai) processDownloadQueue(downloadQueue []*imageGalleryItem, trackedItem *models.TrackedItem, notifications ...*models.Notification) error {
-	slog.Info(
-		fmt.Sprintf("found %d new items for uri: \"%s\"", len(downloadQueue), trackedItem.URI),
-		"module", m.Key,
-	)
-
-	for _, notification := range notifications {
-		slog.Log(context.Background(), notification.Level, notification.Message, "module", m.Key)
-	}
+	m.ReportNewItems(trackedItem.URI, len(downloadQueue), notifications...)
 
 	for index, data := range downloadQueue {
-		slog.Info(
+		m.Log().Info(
 			fmt.Sprintf(
 				"downloading updates for uri: \"%s\" (%0.2f%%)",
 				trackedItem.URI,
 				float64(index+1)/float64(len(downloadQueue))*100,
 			),
-			"module", m.Key,
 		)
 
 		if err := m.downloadItem(trackedItem, data); err != nil {
@@ -263,18 +254,18 @@ func (m *ehentai) downloadItem(trackedItem *models.TrackedItem, data *imageGalle
 	}
 
 	if err = m.downloadImage(trackedItem, downloadQueueItem); err != nil {
-		if downloadQueueItem.FallbackFileURI != "" {
+		// aborts of the post download hooks happen after the image got downloaded successfully
+		if downloadQueueItem.FallbackFileURI != "" && !hooks.IsAbortError(err) {
 			data.uri = downloadQueueItem.FallbackFileURI
 			fallback, fallbackErr := m.getDownloadQueueItem(m.Session, trackedItem, data)
 			if fallbackErr != nil {
 				return fallbackErr
 			}
 
-			slog.Warn(
+			m.Log().Warn(
 				fmt.Sprintf("received status code 404 on gallery url \"%s\", trying fallback url \"%s\"",
 					data.uri,
 					fallback.FileURI),
-				"module", m.Key,
 			)
 
 			downloadQueueItem.FileURI = fallback.FileURI
@@ -305,7 +296,7 @@ func (m *ehentai) downloadImage(trackedItem *models.TrackedItem, downloadQueueIt
 			return m.downloadImage(trackedItem, downloadQueueItem)
 		}
 
-		slog.Info("download limit reached, skipping galleries from now on", "module", m.Key)
+		m.Log().Info("download limit reached, skipping galleries from now on")
 		m.downloadLimitReached = true
 
 		return fmt.Errorf("download limit reached")
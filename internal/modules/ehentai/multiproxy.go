@@ -6,14 +6,13 @@ import (
 	"strings"
 	"time"
 
-	"context"
+	"github.com/DaRealFreak/watcher-go/internal/hooks"
 	"github.com/DaRealFreak/watcher-go/internal/http"
 	"github.com/DaRealFreak/watcher-go/internal/http/std_session"
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/DaRealFreak/watcher-go/internal/raven"
 	"github.com/DaRealFreak/watcher-go/pkg/fp"
 	"golang.org/x/time/rate"
-	"log/slog"
 )
 
 type proxySession struct {
@@ -31,7 +30,7 @@ func (m *ehentai) initializeProxySessions() {
 	// share the cookie jar from the main session so all proxy sessions stay in sync
 	mainSession, ok := m.Session.(*std_session.StdClientSession)
 	if !ok {
-		slog.Error("cannot share cookie jar: main session is not a StdClientSession")
+		m.Log().Error("cannot share cookie jar: main session is not a StdClientSession")
 		return
 	}
 
@@ -152,12 +151,7 @@ func (m *ehentai) removeCurrentIndex(index int) {
 }
 
 func (m *ehentai) processDownloadQueueMultiProxy(downloadQueue []*imageGalleryItem, trackedItem *models.TrackedItem, notifications ...*models.Notification) error {
-	slog.Info(fmt.Sprintf("found %d new items for uri: \"%s\"", len(downloadQueue), trackedItem.URI), "module", m.Key)
-
-	for _, notification := range notifications {
-		slog.Log(context.Background(),
-			notification.Level, notification.Message, "module", m.Key)
-	}
+	m.ReportNewItems(trackedItem.URI, len(downloadQueue), notifications...)
 
 	for index, data := range downloadQueue {
 		// wait until we have a free proxy, a proxy error, or every proxy exhausted its quota
@@ -167,15 +161,15 @@ func (m *ehentai) processDownloadQueueMultiProxy(downloadQueue []*imageGalleryIt
 
 		// if every proxy has hit its download limit, stop scheduling more work
 		if m.allProxiesLimitReached() {
-			slog.Info("download limit reached across all proxies, skipping galleries from now on", "module", m.Key)
+			m.Log().Info("download limit reached across all proxies, skipping galleries from now on")
 			m.downloadLimitReached = true
 			break
 		}
 
 		// handle if errors occurred in previous downloads
 		if erroneousProxy := m.getProxyError(); erroneousProxy != nil {
-			slog.Warn(fmt.Sprintf("error occurred during download for proxy: %s",
-				erroneousProxy.proxy.Host), "module", m.Key)
+			m.Log().Warn(fmt.Sprintf("error occurred during download for proxy: %s",
+				erroneousProxy.proxy.Host))
 			m.multiProxy.waitGroup.Wait()
 			return erroneousProxy.occurredError
 		}
@@ -186,11 +180,11 @@ func (m *ehentai) processDownloadQueueMultiProxy(downloadQueue []*imageGalleryIt
 			continue
 		}
 
-		slog.Info(fmt.Sprintf(
+		m.Log().Info(fmt.Sprintf(
 			"downloading updates for uri: \"%s\" (%0.2f%%)",
 			trackedItem.URI,
 			float64(index+1)/float64(len(downloadQueue))*100,
-		), "module", m.Key)
+		))
 
 		m.multiProxy.waitGroup.Add(1)
 
@@ -205,8 +199,8 @@ func (m *ehentai) processDownloadQueueMultiProxy(downloadQueue []*imageGalleryIt
 
 	// handle if errors occurred in previous downloads
 	if erroneousProxy := m.getProxyError(); erroneousProxy != nil {
-		slog.Warn(fmt.Sprintf("error occurred during download for proxy: %s",
-			erroneousProxy.proxy.Host), "module", m.Key)
+		m.Log().Warn(fmt.Sprintf("error occurred during download for proxy: %s",
+			erroneousProxy.proxy.Host))
 		return erroneousProxy.occurredError
 	}
 
@@ -242,9 +236,9 @@ func (m *ehentai) downloadItemSession(
 		// an empty file URI typically means the proxy got rate-limited and the image page
 		// no longer rendered the expected img tag - swap proxies rather than failing the item
 		if downloadQueueItem.FileURI == "" {
-			slog.Warn(fmt.Sprintf(
+			m.Log().Warn(fmt.Sprintf(
 				"empty download URI for proxy: %s (likely rate-limited), excluding from rotation",
-				currentSession.proxy.Host), "module", m.Key)
+				currentSession.proxy.Host))
 			currentSession.limitReached = true
 			currentSession.inUse = false
 
@@ -276,7 +270,8 @@ func (m *ehentai) downloadItemSession(
 		}
 
 		if downloadErr != nil {
-			if downloadQueueItem.FallbackFileURI == "" {
+			// aborts of the post download hooks happen after the image got downloaded successfully
+			if downloadQueueItem.FallbackFileURI == "" || hooks.IsAbortError(downloadErr) {
 				currentSession.occurredError = downloadErr
 				return
 			}
@@ -289,9 +284,9 @@ func (m *ehentai) downloadItemSession(
 				return
 			}
 
-			slog.Warn(fmt.Sprintf("received status code 404 on gallery url \"%s\", trying fallback url \"%s\"",
+			m.Log().Warn(fmt.Sprintf("received status code 404 on gallery url \"%s\", trying fallback url \"%s\"",
 				data.uri,
-				fallback.FileURI), "module", m.Key)
+				fallback.FileURI))
 
 			downloadQueueItem.FileURI = fallback.FileURI
 			downloadQueueItem.FallbackFileURI = ""
@@ -310,9 +305,9 @@ func (m *ehentai) downloadImageSession(
 	// check for per-proxy download limit
 	if downloadQueueItem.FileURI == "https://exhentai.org/img/509.gif" ||
 		downloadQueueItem.FileURI == "https://e-hentai.org/img/509.gif" {
-		slog.Warn(fmt.Sprintf(
+		m.Log().Warn(fmt.Sprintf(
 			"download limit reached for proxy: %s, excluding from rotation",
-			downloadSession.proxy.Host), "module", m.Key)
+			downloadSession.proxy.Host))
 		downloadSession.limitReached = true
 
 		return fmt.Errorf("download limit reached for proxy %s", downloadSession.proxy.Host)
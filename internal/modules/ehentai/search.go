@@ -10,7 +10,6 @@ import (
 
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/PuerkitoBio/goquery"
-	"log/slog"
 )
 
 // searchGalleryItem contains the required variables for gallery items of the search function
@@ -123,12 +122,12 @@ func (m *ehentai) parseSearch(item *models.TrackedItem) error {
 		itemQueue[i], itemQueue[j] = itemQueue[j], itemQueue[i]
 	}
 
-	slog.Info(fmt.Sprintf("found %d new items for uri: %s", len(itemQueue), item.URI), "module", m.Key)
+	m.ReportNewItems(item.URI, len(itemQueue))
 
 	for _, gallery := range itemQueue {
 		galleryItem := m.DbIO.GetFirstOrCreateTrackedItem(gallery.uri, m.getSubFolder(item), m)
 		if (m.Cfg.Run.Force || m.Cfg.Run.ResetProgress) && galleryItem.CurrentItem != "" {
-			slog.Info(fmt.Sprintf("resetting progress for item %s (current id: %s)", galleryItem.URI, galleryItem.CurrentItem), "module", m.Key)
+			m.Log().Info(fmt.Sprintf("resetting progress for item %s (current id: %s)", galleryItem.URI, galleryItem.CurrentItem))
 			galleryItem.CurrentItem = ""
 			m.DbIO.ChangeTrackedItemCompleteStatus(galleryItem, false)
 			m.DbIO.UpdateTrackedItem(galleryItem, "")
@@ -139,24 +138,24 @@ func (m *ehentai) parseSearch(item *models.TrackedItem) error {
 	for index, gallery := range itemQueue {
 		galleryItem := m.DbIO.GetFirstOrCreateTrackedItem(gallery.uri, m.getSubFolder(item), m)
 		if m.ipBanned {
-			slog.Info(fmt.Sprintf(
+			m.Log().Info(fmt.Sprintf(
 				"download limit reached, skipping galleries for search item: \"%s\" (%0.2f%%)",
 				item.URI,
 				float64(index+1)/float64(len(itemQueue))*100,
-			), "module", m.Key)
+			))
 			break
 		}
 
 		if !galleryItem.Complete {
-			slog.Info(fmt.Sprintf(
+			m.Log().Info(fmt.Sprintf(
 				"added gallery to tracked items: \"%s\", search item: \"%s\" (%0.2f%%)",
 				gallery.uri,
 				item.URI,
 				float64(index+1)/float64(len(itemQueue))*100,
-			), "module", m.Key)
+			))
 
 			if err = m.Parse(galleryItem); err != nil {
-				slog.Warn(fmt.Sprintf("error occurred parsing item %s (%s), skipping", galleryItem.URI, err.Error()), "module", item.Module)
+				m.Log().Warn(fmt.Sprintf("error occurred parsing item %s (%s), skipping", galleryItem.URI, err.Error()))
 				return err
 			}
 		}
@@ -2,7 +2,6 @@ package fantia
 
 import (
 	"fmt"
-	"log/slog"
 	"path"
 	"regexp"
 	"strconv"
@@ -22,9 +21,8 @@ type mediaItem struct {
 func (m *fantia) parseFanclub(item *models.TrackedItem) error {
 	fanclubID := m.extractFanclubID(item.URI)
 
-	slog.Info(
+	m.Log().Info(
 		fmt.Sprintf("parsing fanclub %s", fanclubID),
-		"module", m.Key,
 	)
 
 	currentPostID := 0
@@ -71,25 +69,22 @@ func (m *fantia) parseFanclub(item *models.TrackedItem) error {
 		newPostIDs[i], newPostIDs[j] = newPostIDs[j], newPostIDs[i]
 	}
 
-	slog.Info(
+	m.Log().Info(
 		fmt.Sprintf("found %d new posts for uri: \"%s\"", len(newPostIDs), item.URI),
-		"module", m.Key,
 	)
 
 	for i, postID := range newPostIDs {
-		slog.Info(
+		m.Log().Info(
 			fmt.Sprintf(
 				"downloading updates for uri: \"%s\" (%0.2f%%)",
 				item.URI,
 				float64(i+1)/float64(len(newPostIDs))*100,
 			),
-			"module", m.Key,
 		)
 
 		if err := m.downloadPost(postID, item); err != nil {
-			slog.Warn(
+			m.Log().Warn(
 				fmt.Sprintf("failed to process post %s, skipping: %s", postID, err.Error()),
-				"module", m.Key,
 			)
 			continue
 		}
@@ -162,9 +157,8 @@ func (m *fantia) extractMediaFromPost(post *postData) []mediaItem {
 			if content.Plan != nil {
 				planName = fmt.Sprintf(" (plan: %s, %d JPY)", content.Plan.Name, content.Plan.Price)
 			}
-			slog.Warn(
+			m.Log().Warn(
 				fmt.Sprintf("post %d content \"%s\" is locked%s, skipping", post.ID, content.Title, planName),
-				"module", m.Key,
 			)
 			continue
 		}
@@ -13,7 +13,6 @@ import (
 	"github.com/DaRealFreak/watcher-go/internal/raven"
 	"github.com/DaRealFreak/watcher-go/pkg/fp"
 	"golang.org/x/time/rate"
-	"log/slog"
 )
 
 type proxySession struct {
@@ -27,7 +26,7 @@ func (m *fourChan) initializeProxySessions() {
 	// share the cookie jar from the main session so all proxy sessions stay in sync
 	mainSession, ok := m.Session.(*tls_session.TlsClientSession)
 	if !ok {
-		slog.Error("cannot share cookie jar: main session is not a TlsClientSession")
+		m.Log().Error("cannot share cookie jar: main session is not a TlsClientSession")
 		return
 	}
 
@@ -99,7 +98,7 @@ func (m *fourChan) resetProxies() {
 }
 
 func (m *fourChan) processDownloadQueueMultiProxy(downloadQueue []models.DownloadQueueItem, trackedItem *models.TrackedItem) error {
-	slog.Info(fmt.Sprintf("found %d new items for uri: \"%s\"", len(downloadQueue), trackedItem.URI), "module", m.Key)
+	m.ReportNewItems(trackedItem.URI, len(downloadQueue))
 
 	for index, data := range downloadQueue {
 		// sleep until we have a free proxy again
@@ -110,17 +109,17 @@ func (m *fourChan) processDownloadQueueMultiProxy(downloadQueue []models.Downloa
 
 		// handle if errors occurred in previous downloads
 		if erroneousProxy := m.getProxyError(); erroneousProxy != nil {
-			slog.Warn(fmt.Sprintf("error occurred during download for proxy: %s",
-				erroneousProxy.proxy.Host), "module", m.Key)
+			m.Log().Warn(fmt.Sprintf("error occurred during download for proxy: %s",
+				erroneousProxy.proxy.Host))
 			return m.getProxyError().occurredError
 		}
 
 		if m.hasFreeProxy() {
-			slog.Info(fmt.Sprintf(
+			m.Log().Info(fmt.Sprintf(
 				"downloading updates for uri: \"%s\" (%0.2f%%)",
 				trackedItem.URI,
 				float64(index+1)/float64(len(downloadQueue))*100,
-			), "module", m.Key)
+			))
 
 			m.multiProxy.waitGroup.Add(1)
 			proxy := m.getFreeProxy()
@@ -140,8 +139,8 @@ func (m *fourChan) processDownloadQueueMultiProxy(downloadQueue []models.Downloa
 
 	// handle if errors occurred in previous downloads
 	if erroneousProxy := m.getProxyError(); erroneousProxy != nil {
-		slog.Warn(fmt.Sprintf("error occurred during download for proxy: %s",
-			erroneousProxy.proxy.Host), "module", m.Key)
+		m.Log().Warn(fmt.Sprintf("error occurred during download for proxy: %s",
+			erroneousProxy.proxy.Host))
 		return m.getProxyError().occurredError
 	}
 
@@ -164,8 +163,8 @@ func (m *fourChan) downloadItemSession(
 			return
 		}
 
-		slog.Warn(fmt.Sprintf("received status code 404 on gallery url \"%s\"",
-			downloadQueueItem.FileURI), "module", m.Key)
+		m.Log().Warn(fmt.Sprintf("received status code 404 on gallery url \"%s\"",
+			downloadQueueItem.FileURI))
 	}
 
 	downloadSession.inUse = false
@@ -187,8 +186,8 @@ func (m *fourChan) downloadImageSession(
 	}
 
 	if res.StatusCode == 429 {
-		slog.Warn(fmt.Sprintf("received status code 429 on gallery url \"%s\"",
-			downloadQueueItem.FileURI), "module", m.Key)
+		m.Log().Warn(fmt.Sprintf("received status code 429 on gallery url \"%s\"",
+			downloadQueueItem.FileURI))
 		time.Sleep(time.Second * 5)
 
 		return m.downloadImageSession(downloadSession, trackedItem, downloadQueueItem, index)
@@ -207,8 +206,8 @@ func (m *fourChan) downloadImageSession(
 			// bump the file’s mtime so that ordering by time == ordering by index
 			if info, statErr := os.Stat(dst); statErr == nil && !info.IsDir() {
 				if chtErr := os.Chtimes(dst, startTime, startTime); chtErr != nil {
-					slog.Warn(fmt.Sprintf("failed to reset timestamp for %s: %v",
-						dst, chtErr), "module", m.Key)
+					m.Log().Warn(fmt.Sprintf("failed to reset timestamp for %s: %v",
+						dst, chtErr))
 				}
 			}
 
@@ -231,8 +230,8 @@ func (m *fourChan) downloadImageSession(
 
 		return downloadErr
 	} else {
-		slog.Warn(fmt.Sprintf("received status code 404 on gallery url \"%s\"",
-			downloadQueueItem.FileURI), "module", m.Key)
+		m.Log().Warn(fmt.Sprintf("received status code 404 on gallery url \"%s\"",
+			downloadQueueItem.FileURI))
 
 		// it's completely normal for 404 errors to occur on that website. the image just doesn't exist anymore,
 		// so log a warning and return nil
@@ -3,7 +3,6 @@ package fourchan
 import (
 	"errors"
 	"fmt"
-	"log/slog"
 
 	"github.com/DaRealFreak/watcher-go/internal/http"
 	"github.com/DaRealFreak/watcher-go/internal/http/tls_session"
@@ -93,15 +92,15 @@ func (m *fourChan) getPage(uri string) (*fhttp.Response, error) {
 		m.evictCurrentProxy()
 
 		if !m.hasLiveLoopProxy() {
-			slog.Warn(fmt.Sprintf(
+			m.Log().Warn(fmt.Sprintf(
 				"proxy \"%s\" returned 403 and no usable proxies remain, skipping uri: %s",
-				evictedHost, uri), "module", m.Key)
+				evictedHost, uri))
 
 			return res, err
 		}
 
-		slog.Warn(fmt.Sprintf(
+		m.Log().Warn(fmt.Sprintf(
 			"proxy \"%s\" returned 403, evicting it and retrying with another proxy for uri: %s",
-			evictedHost, uri), "module", m.Key)
+			evictedHost, uri))
 	}
 }
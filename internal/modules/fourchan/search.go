@@ -8,7 +8,6 @@ import (
 
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/PuerkitoBio/goquery"
-	"log/slog"
 )
 
 // parseSearch parses searches
@@ -74,26 +73,26 @@ func (m *fourChan) parseSearch(item *models.TrackedItem) error {
 		itemQueue[i], itemQueue[j] = itemQueue[j], itemQueue[i]
 	}
 
-	slog.Info(fmt.Sprintf("found %d new items for uri: %s", len(itemQueue), item.URI), "module", m.Key)
+	m.ReportNewItems(item.URI, len(itemQueue))
 
 	// add items
 	for index, gallery := range itemQueue {
-		slog.Info(fmt.Sprintf(
+		m.Log().Info(fmt.Sprintf(
 			"added gallery to tracked items: \"%s\" (%0.2f%%)",
 			gallery,
 			float64(index+1)/float64(len(itemQueue))*100,
-		), "module", m.Key)
+		))
 
 		galleryItem := m.DbIO.GetFirstOrCreateTrackedItem(gallery, m.getSubFolder(item), m)
 		if m.Cfg.Run.Force && galleryItem.CurrentItem != "" {
-			slog.Info(fmt.Sprintf("resetting progress for item %s (current id: %s)", galleryItem.URI, galleryItem.CurrentItem), "module", m.Key)
+			m.Log().Info(fmt.Sprintf("resetting progress for item %s (current id: %s)", galleryItem.URI, galleryItem.CurrentItem))
 			galleryItem.CurrentItem = ""
 			m.DbIO.ChangeTrackedItemCompleteStatus(item, false)
 			m.DbIO.UpdateTrackedItem(item, "")
 		}
 
 		if err = m.Parse(galleryItem); err != nil {
-			slog.Warn(fmt.Sprintf("error occurred parsing item %s (%s), skipping", galleryItem.URI, err.Error()), "module", item.Module)
+			m.Log().Warn(fmt.Sprintf("error occurred parsing item %s (%s), skipping", galleryItem.URI, err.Error()))
 			continue
 		}
 
@@ -6,8 +6,6 @@ import (
 	"strconv"
 	"strings"
 
-	"log/slog"
-
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/DaRealFreak/watcher-go/pkg/fp"
 	"github.com/PuerkitoBio/goquery"
@@ -37,9 +35,9 @@ func (m *fourChan) parseThread(item *models.TrackedItem) error {
 		threadTitle := m.getThreadTitle(html)
 		for _, blacklistedTag := range m.settings.Search.BlacklistedTags {
 			if strings.Contains(strings.ToLower(threadTitle), strings.ToLower(blacklistedTag)) {
-				slog.Warn(fmt.Sprintf("thread title \"%s\" contains blacklisted tag \"%s\", setting item to complete",
+				m.Log().Warn(fmt.Sprintf("thread title \"%s\" contains blacklisted tag \"%s\", setting item to complete",
 					threadTitle,
-					blacklistedTag), "module", m.Key)
+					blacklistedTag))
 				m.DbIO.ChangeTrackedItemCompleteStatus(item, true)
 				return nil
 			}
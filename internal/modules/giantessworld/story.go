@@ -10,11 +10,11 @@ import (
 	"strings"
 	"unicode/utf8"
 
+	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/DaRealFreak/watcher-go/pkg/fp"
 	"github.com/PuerkitoBio/goquery"
 	"github.com/jaytaylor/html2text"
-	"log/slog"
 )
 
 // parseStory parses single stories
@@ -27,18 +27,18 @@ func (m *giantessWorld) parseStory(item *models.TrackedItem) error {
 	}
 
 	if bytes.Contains(htmlContent, []byte("Access denied. This story has not been validated by the adminstrators of this site.")) {
-		slog.Warn(fmt.Sprintf("story has been deleted: \"%s\", setting item to completed",
-			item.URI), "module", m.Key)
+		m.Log().Warn(fmt.Sprintf("story has been deleted: \"%s\", setting item to completed",
+			item.URI))
 		m.DbIO.ChangeTrackedItemCompleteStatus(item, true)
 
 		return nil
 	}
 
 	if item.CurrentItem == "" {
-		slog.Info(fmt.Sprintf(
+		m.Log().Info(fmt.Sprintf(
 			"downloading initial chapter for uri: \"%s\"",
 			item.URI,
-		), "module", m.Key)
+		))
 
 		err = m.downloadChapter(htmlContent, item)
 		if err != nil {
@@ -56,11 +56,11 @@ func (m *giantessWorld) parseStory(item *models.TrackedItem) error {
 		}
 
 		// download chapter updating the item and repeat the function for recursively going through story pages
-		slog.Info(fmt.Sprintf(
+		m.Log().Info(fmt.Sprintf(
 			"downloading updates for uri: \"%s\" (%0.2f%%)",
 			item.URI,
 			float64(index+1)/float64(len(newChapters))*100,
-		), "module", m.Key)
+		))
 
 		err = m.downloadChapter(htmlContent, item)
 		if err != nil {
@@ -137,6 +137,14 @@ func (m *giantessWorld) downloadChapter(htmlContent []byte, item *models.Tracked
 		fp.SanitizePath(storyId[0]+"_"+m.getStoryName(doc)+"_"+m.getChapterTitle(doc)+".txt", false),
 	)
 
+	// the chapter is written directly, so dry runs have to be handled here
+	if watcherHttp.GlobalDryRun.Enabled() {
+		watcherHttp.GlobalDryRun.RecordDownload(m.Key, filePath, item.URI)
+		m.DbIO.UpdateTrackedItem(item, m.getChapterID(doc))
+
+		return nil
+	}
+
 	// ensure download directory since we directly create the files
 	m.Session.EnsureDownloadDirectory(filePath)
 
@@ -6,7 +6,6 @@ import (
 
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/PuerkitoBio/goquery"
-	"log/slog"
 )
 
 // storyMetaData contains the required meta data for the module
@@ -112,7 +111,7 @@ func (m *giantessWorld) addNewStories(item *models.TrackedItem, newStories []sto
 		newItem := m.DbIO.GetFirstOrCreateTrackedItem(story.chapterURL, "", m)
 		if newItem.CurrentItem == "" {
 			// if story doesn't have a current item yet, it's probably a new story
-			slog.Info("added story to tracked items: "+story.chapterURL, "module", m.Key)
+			m.Log().Info("added story to tracked items: " + story.chapterURL)
 		}
 	}
 }
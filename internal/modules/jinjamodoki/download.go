@@ -10,11 +10,9 @@ import (
 	"strings"
 	"time"
 
-	"context"
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/PuerkitoBio/goquery"
 	"golang.org/x/time/rate"
-	"log/slog"
 )
 
 type downloadQueueItem struct {
@@ -27,19 +25,14 @@ func (m *jinjaModoki) processDownloadQueue(queue []downloadQueueItem, item *mode
 	// only the downloads have a rate limit, so we only set it here
 	m.defaultSession.RateLimiter = rate.NewLimiter(rate.Every(5*time.Second), 1)
 
-	slog.Info(fmt.Sprintf("found %d new items for uri: \"%s\"", len(queue), item.URI), "module", m.Key)
-
-	for _, notification := range notifications {
-		slog.Log(context.Background(),
-			notification.Level, notification.Message, "module", m.Key)
-	}
+	m.ReportNewItems(item.URI, len(queue), notifications...)
 
 	for index, data := range queue {
-		slog.Info(fmt.Sprintf(
+		m.Log().Info(fmt.Sprintf(
 			"downloading updates for uri: \"%s\" (%0.2f%%)",
 			item.URI,
 			float64(index+1)/float64(len(queue))*100,
-		), "module", m.Key)
+		))
 
 		if err := m.downloadItem(data, item); err != nil {
 			return err
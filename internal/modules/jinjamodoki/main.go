@@ -15,7 +15,6 @@ import (
 	"github.com/DaRealFreak/watcher-go/internal/raven"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"log/slog"
 	"os"
 )
 
@@ -89,12 +88,12 @@ func (m *jinjaModoki) InitializeModule() {
 	content, _ := io.ReadAll(res.Body)
 	if strings.Contains(string(content),
 		"You are not allowed to change your access settings for browsing-restricted contents") {
-		slog.Error("could not change browsing restrictions, your IP is most likely blacklisted", "module", m.Key)
+		m.Log().Error("could not change browsing restrictions, your IP is most likely blacklisted")
 		os.Exit(1)
 	}
 
 	if !strings.Contains(string(content), "Access settings for browsing-restricted contents have been changed.") {
-		slog.Error("unable to change browsing restrictions, please check settings", "module", m.Key)
+		m.Log().Error("unable to change browsing restrictions, please check settings")
 		os.Exit(1)
 	}
 }
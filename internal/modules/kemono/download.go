@@ -9,9 +9,10 @@ import (
 	"os"
 	"path"
 	"strings"
-	"time"
+	"sync"
 
-	"context"
+	"github.com/DaRealFreak/watcher-go/internal/hooks"
+	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
 	"github.com/DaRealFreak/watcher-go/internal/http/tls_session"
 	"github.com/DaRealFreak/watcher-go/internal/jdownloader"
 	"github.com/DaRealFreak/watcher-go/internal/models"
@@ -20,10 +21,24 @@ import (
 	"github.com/DaRealFreak/watcher-go/pkg/fp"
 	"github.com/DaRealFreak/watcher-go/pkg/linkfinder"
 	"github.com/PuerkitoBio/goquery"
-	"github.com/bogdanfinn/fhttp/http2"
-	"log/slog"
 )
 
+// getTemplateFilePath returns the path of the passed file of the post based on the download template.
+// Returns false if no valid download template is configured
+func (m *kemono) getTemplateFilePath(
+	item *models.TrackedItem, data api.QuickPost, postFolderPath string, metadata *models.Metadata,
+	fileURI string, index int, fileName string,
+) (string, bool) {
+	return m.GetTemplateDownloadPath(item, &models.DownloadQueueItem{
+		ItemID:         data.ID,
+		DownloadTag:    postFolderPath,
+		FileName:       fileName,
+		FileURI:        fileURI,
+		Metadata:       metadata,
+		TemplateValues: map[string]string{"service": data.Service},
+	}, index)
+}
+
 // joinDataURL builds a "{host}/data/{path}" URL with exactly one slash between segments.
 // API responses return paths with a leading slash (e.g. "/bd/86/...jpg"); naive
 // fmt.Sprintf("%s/data/%s") would produce "host/data//bd/86/...jpg".
@@ -103,7 +118,7 @@ func isNetworkError(err error) bool {
 	// substring matching since the underlying types are unexported.
 	msg := err.Error()
 	for _, needle := range []string{
-		"connectex",                       // Windows winsock connect failures
+		"connectex", // Windows winsock connect failures
 		"connection refused",
 		"connection reset",
 		"no such host",
@@ -120,22 +135,18 @@ func isNetworkError(err error) bool {
 	return false
 }
 
-func (m *kemono) processDownloadQueue(item *models.TrackedItem, downloadQueue []api.QuickPost, notifications ...*models.Notification) error {
-	slog.Info(fmt.Sprintf("found %d new items for uri: \"%s\"", len(downloadQueue), item.URI), "module", m.Key)
-
-	for _, notification := range notifications {
-		slog.Log(context.Background(),
-			notification.Level, notification.Message, "module", m.Key)
-	}
+// processDownloadQueue downloads the passed posts of the passed author name
+func (m *kemono) processDownloadQueue(item *models.TrackedItem, author string, downloadQueue []api.QuickPost, notifications ...*models.Notification) error {
+	m.ReportNewItems(item.URI, len(downloadQueue), notifications...)
 
 	for index, data := range downloadQueue {
-		slog.Info(fmt.Sprintf(
+		m.Log().Info(fmt.Sprintf(
 			"downloading updates for uri: \"%s\" (%0.2f%%)",
 			item.URI,
 			float64(index+1)/float64(len(downloadQueue))*100,
-		), "module", m.Key)
+		))
 
-		if err := m.downloadPost(item, data); err != nil {
+		if err := m.downloadPost(item, author, data); err != nil {
 			return err
 		}
 
@@ -145,7 +156,7 @@ func (m *kemono) processDownloadQueue(item *models.TrackedItem, downloadQueue []
 	return nil
 }
 
-func (m *kemono) downloadPost(item *models.TrackedItem, data api.QuickPost) error {
+func (m *kemono) downloadPost(item *models.TrackedItem, author string, data api.QuickPost) error {
 	webUrl := fmt.Sprintf("%s/%s/user/%s/post/%s", m.baseUrl.String(), data.Service, data.User, data.ID)
 	post, err := m.api.GetPostDetails(data.Service, data.User, data.ID)
 	if err != nil {
@@ -164,6 +175,19 @@ func (m *kemono) downloadPost(item *models.TrackedItem, data api.QuickPost) erro
 		postFolderPath += " - " + sanitizedPostTitle
 	}
 
+	metadata := &models.Metadata{
+		SourceURL:   webUrl,
+		PostID:      data.ID,
+		Author:      author,
+		AuthorID:    data.User,
+		Title:       data.Title,
+		Description: post.Post.Content,
+		Extra:       map[string]any{"service": data.Service},
+	}
+	if !post.Post.Published.IsZero() {
+		metadata.CreatedAt = &post.Post.Published.Time
+	}
+
 	downloadLinks := m.getDownloadLinks(post)
 	for index, downloadItem := range downloadLinks {
 		parsedLink, parsedErr := url.Parse(downloadItem.FileURI)
@@ -185,85 +209,87 @@ func (m *kemono) downloadPost(item *models.TrackedItem, data api.QuickPost) erro
 			continue
 		}
 
-		file := path.Join(
-			m.GetDownloadDirectory(),
-			m.Key,
-			fp.TruncateMaxLength(m.getSubFolder(item)),
-			fp.TruncateMaxLength(postFolderPath),
-			fp.TruncateMaxLength(strings.TrimSpace(fmt.Sprintf("%s_%d_%s", data.ID, index+1, fileName))),
-		)
-		if err = m.Session.DownloadFile(file, downloadItem.FileURI); err != nil {
-			var scErr http2.StreamError
-			if errors.As(err, &scErr) {
-				slog.Warn(fmt.Sprintf("received stream error \"%s\", trying to download in chunks", err.Error()), "module", m.Key)
-				err = m.downloadChunks(
-					downloadItem.FileURI,
-					file,
-					1*1024*1024,
-					5,
-					5*time.Second,
-				)
-			}
-
+		file, ok := m.getTemplateFilePath(item, data, postFolderPath, metadata, downloadItem.FileURI, index+1, fileName)
+		if !ok {
+			file = path.Join(
+				m.GetDownloadDirectory(),
+				m.Key,
+				fp.TruncateMaxLength(m.getSubFolder(item)),
+				fp.TruncateMaxLength(postFolderPath),
+				fp.TruncateMaxLength(strings.TrimSpace(fmt.Sprintf("%s_%d_%s", data.ID, index+1, fileName))),
+			)
+		}
+		fileURI := downloadItem.FileURI
+		// interrupted transfers (f.e. stream errors of the CDN nodes) are resumed by the session
+		if err = m.Session.DownloadFile(file, fileURI); err != nil {
 			// On 404 OR a transport-level network error against a CDN node, retry
 			// the download through the main host (which 302s back to the origin's
 			// preferred CDN node, possibly a different one).
 			var statusErr tls_session.StatusError
 			is404 := errors.As(err, &statusErr) && statusErr.StatusCode == 404
 			isNetErr := isNetworkError(err)
-			if (is404 || isNetErr) && downloadItem.FallbackFileURI != "" {
+			// aborts of the post download hooks happen after the file got downloaded successfully
+			if (is404 || isNetErr) && downloadItem.FallbackFileURI != "" && !hooks.IsAbortError(err) {
 				reason := "404"
 				if isNetErr && !is404 {
 					reason = "network error"
 				}
-				slog.Warn(fmt.Sprintf("received %s \"%s\" downloading \"%s\", retrying via fallback url \"%s\"",
+				m.Log().Warn(fmt.Sprintf("received %s \"%s\" downloading \"%s\", retrying via fallback url \"%s\"",
 					reason,
 					err.Error(),
 					downloadItem.FileURI,
-					downloadItem.FallbackFileURI), "module", m.Key)
+					downloadItem.FallbackFileURI))
 
-				err = m.Session.DownloadFile(
-					path.Join(
+				file, ok = m.getTemplateFilePath(
+					item, data, postFolderPath, metadata, downloadItem.FallbackFileURI, index+1, "fallback_"+fileName,
+				)
+				if !ok {
+					file = path.Join(
 						m.GetDownloadDirectory(),
 						m.Key,
 						fp.TruncateMaxLength(m.getSubFolder(item)),
 						fp.TruncateMaxLength(fp.SanitizePath(data.ID, false)),
 						fp.TruncateMaxLength(strings.TrimSpace(fmt.Sprintf("%s_%d_fallback_%s", data.ID, index+1, fileName))),
-					),
-					downloadItem.FallbackFileURI,
-				)
+					)
+				}
+				fileURI = downloadItem.FallbackFileURI
+				err = m.Session.DownloadFile(file, fileURI)
 			}
 
 			// Last-resort fallback for images: the dedicated img.<site>/thumbnail
 			// host serves a downscaled JPEG rendered from the same content hash.
 			// We use this only when both the CDN node and the origin redirect are
 			// unreachable, since the result is a degraded version of the file.
-			if err != nil {
+			if err != nil && !hooks.IsAbortError(err) {
 				var s tls_session.StatusError
 				stillRetryable := errors.As(err, &s) && s.StatusCode == 404
 				stillRetryable = stillRetryable || isNetworkError(err)
 				if stillRetryable {
 					if thumbURL := m.buildThumbnailURL(downloadItem, fileName); thumbURL != "" {
-						slog.Warn(fmt.Sprintf("primary and fallback downloads failed for \"%s\" (last error: %s), saving thumbnail from \"%s\" as degraded fallback",
+						m.Log().Warn(fmt.Sprintf("primary and fallback downloads failed for \"%s\" (last error: %s), saving thumbnail from \"%s\" as degraded fallback",
 							downloadItem.FileURI,
 							err.Error(),
-							thumbURL), "module", m.Key)
+							thumbURL))
 
-						if thumbErr := m.Session.DownloadFile(
-							path.Join(
+						thumbFile, templateOk := m.getTemplateFilePath(
+							item, data, postFolderPath, metadata, thumbURL, index+1, "thumbnail_"+fileName,
+						)
+						if !templateOk {
+							thumbFile = path.Join(
 								m.GetDownloadDirectory(),
 								m.Key,
 								fp.TruncateMaxLength(m.getSubFolder(item)),
 								fp.TruncateMaxLength(fp.SanitizePath(data.ID, false)),
 								fp.TruncateMaxLength(strings.TrimSpace(fmt.Sprintf("%s_%d_thumbnail_%s", data.ID, index+1, fileName))),
-							),
-							thumbURL,
-						); thumbErr == nil {
+							)
+						}
+						if thumbErr := m.Session.DownloadFile(thumbFile, thumbURL); thumbErr == nil {
 							// thumbnail saved - treat the item as recovered
+							file, fileURI = thumbFile, thumbURL
 							err = nil
 						} else {
-							slog.Warn(fmt.Sprintf("thumbnail fallback also failed for \"%s\": %s",
-								thumbURL, thumbErr.Error()), "module", m.Key)
+							m.Log().Warn(fmt.Sprintf("thumbnail fallback also failed for \"%s\": %s",
+								thumbURL, thumbErr.Error()))
 						}
 					}
 				}
@@ -273,15 +299,17 @@ func (m *kemono) downloadPost(item *models.TrackedItem, data api.QuickPost) erro
 				return err
 			}
 		}
+
+		m.SaveMetadata(file, fileURI, metadata)
 	}
 
 	externalLinks := m.getExternalLinks(post, postComments)
 	factory := modules.GetModuleFactory()
 	for _, externalURL := range externalLinks {
 		if m.settings.ExternalURLs.PrintExternalItems {
-			slog.Info(fmt.Sprintf("found external URL: \"%s\" in post \"%s\"",
+			m.Log().Info(fmt.Sprintf("found external URL: \"%s\" in post \"%s\"",
 				externalURL,
-				webUrl), "module", m.Key)
+				webUrl))
 		}
 
 		// hand links we can't parse ourselves to JDownloader (independent of DownloadExternalItems)
@@ -300,47 +328,19 @@ func (m *kemono) downloadPost(item *models.TrackedItem, data api.QuickPost) erro
 
 		if m.settings.ExternalURLs.DownloadExternalItems {
 			if factory.CanParse(externalURL) {
-				module := modules.GetModuleFactory().GetModuleFromURI(externalURL)
-				if err = module.Load(); err != nil {
+				if err = m.parseExternalItem(externalURL, webUrl); err != nil {
 					return err
 				}
-				newItem := m.DbIO.GetFirstOrCreateTrackedItem(externalURL, "", module)
-				// don't delete previously already added items
-				deleteAfter := newItem.CurrentItem == ""
-				if m.Cfg.Run.Force && newItem.CurrentItem != "" {
-					slog.Info(fmt.Sprintf("resetting progress for item %s (current id: %s)", newItem.URI, newItem.CurrentItem), "module", m.Key)
-					newItem.CurrentItem = ""
-					m.DbIO.ChangeTrackedItemCompleteStatus(newItem, false)
-					m.DbIO.UpdateTrackedItem(newItem, "")
-				}
-
-				if err = module.Parse(newItem); err != nil {
-					slog.Warn(fmt.Sprintf("unable to parse external URL \"%s\" found in post \"%s\" with error \"%s\", skipping",
-						newItem.URI,
-						webUrl,
-						err.Error()), "module", m.Key)
-					if !m.settings.ExternalURLs.SkipErrorsForExternalURLs {
-						if deleteAfter {
-							m.DbIO.DeleteTrackedItem(newItem)
-						}
-						return err
-					}
-				}
-
-				// delete newly created item after we parsed it
-				if deleteAfter {
-					m.DbIO.DeleteTrackedItem(newItem)
-				}
 			} else {
-				slog.Warn(fmt.Sprintf("unable to parse URL \"%s\" found in post \"%s\"",
+				m.Log().Warn(fmt.Sprintf("unable to parse URL \"%s\" found in post \"%s\"",
 					externalURL,
-					webUrl), "module", m.Key)
+					webUrl))
 			}
 		}
 	}
 
-	// try to create the download folder if we have external links but no direct download links
-	if len(downloadLinks) == 0 && len(externalLinks) > 0 {
+	// try to create the download folder if we have external links but no direct download links (not during dry runs)
+	if len(downloadLinks) == 0 && len(externalLinks) > 0 && !watcherHttp.GlobalDryRun.Enabled() {
 		downloadFolder := path.Join(
 			m.GetDownloadDirectory(),
 			m.Key,
@@ -356,6 +356,51 @@ func (m *kemono) downloadPost(item *models.TrackedItem, data api.QuickPost) erro
 	return nil
 }
 
+// externalItemsMutex serializes the parsing of external items, the modules of the external items are shared
+// between all workers of the module
+var externalItemsMutex sync.Mutex
+
+// parseExternalItem parses the passed external URL found in the post with the module responsible for the URL.
+// Items which weren't tracked before get removed again after parsing them
+func (m *kemono) parseExternalItem(externalURL string, webUrl string) error {
+	externalItemsMutex.Lock()
+	defer externalItemsMutex.Unlock()
+
+	module := modules.GetModuleFactory().GetModuleFromURI(externalURL)
+	if err := module.Load(); err != nil {
+		return err
+	}
+	newItem := m.DbIO.GetFirstOrCreateTrackedItem(externalURL, "", module)
+	// don't delete previously already added items
+	deleteAfter := newItem.CurrentItem == ""
+	if m.Cfg.Run.Force && newItem.CurrentItem != "" {
+		m.Log().Info(fmt.Sprintf("resetting progress for item %s (current id: %s)", newItem.URI, newItem.CurrentItem))
+		newItem.CurrentItem = ""
+		m.DbIO.ChangeTrackedItemCompleteStatus(newItem, false)
+		m.DbIO.UpdateTrackedItem(newItem, "")
+	}
+
+	if err := module.Parse(newItem); err != nil {
+		m.Log().Warn(fmt.Sprintf("unable to parse external URL \"%s\" found in post \"%s\" with error \"%s\", skipping",
+			newItem.URI,
+			webUrl,
+			err.Error()))
+		if !m.settings.ExternalURLs.SkipErrorsForExternalURLs {
+			if deleteAfter {
+				m.DbIO.DeleteTrackedItem(newItem)
+			}
+			return err
+		}
+	}
+
+	// delete newly created item after we parsed it
+	if deleteAfter {
+		m.DbIO.DeleteTrackedItem(newItem)
+	}
+
+	return nil
+}
+
 func (m *kemono) getExternalLinks(post *api.PostRoot, comments []api.Comment) (links []string) {
 	if !m.settings.ExternalURLs.DownloadExternalItems &&
 		!m.settings.ExternalURLs.PrintExternalItems &&
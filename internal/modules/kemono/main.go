@@ -41,6 +41,20 @@ func init() {
 
 // NewBareModule returns a bare module implementation for the CLI options
 func NewBareModule() *models.Module {
+	module := newModule()
+
+	// register module to log formatter
+	formatter.AddFieldMatchColorScheme("module", &formatter.FieldMatch{
+		Value: module.Key,
+		Color: "232:208",
+	})
+
+	return module
+}
+
+// newModule returns a new instance of the module. The base URL and API client are set per parsed item,
+// so every worker keeps them in its own instance
+func newModule() *models.Module {
 	module := &models.Module{
 		// Key stays "kemono.su" even though the canonical host is now kemono.cr;
 		// it persists in tracked_items rows for existing users and changing it
@@ -55,16 +69,12 @@ func NewBareModule() *models.Module {
 			regexp.MustCompile(`coomer.st`),
 		},
 		SettingsSchema: kemonoSettings{},
+		TemplateFields: []string{"service"},
 	}
 	module.ModuleInterface = &kemono{
 		Module: module,
 	}
-
-	// register module to log formatter
-	formatter.AddFieldMatchColorScheme("module", &formatter.FieldMatch{
-		Value: module.Key,
-		Color: "232:208",
-	})
+	module.NewWorker = newModule
 
 	return module
 }
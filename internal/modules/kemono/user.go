@@ -6,7 +6,6 @@ import (
 
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/DaRealFreak/watcher-go/internal/modules/kemono/api"
-	"log/slog"
 )
 
 func (m *kemono) parseUser(item *models.TrackedItem) error {
@@ -27,7 +26,7 @@ func (m *kemono) parseUser(item *models.TrackedItem) error {
 		return fmt.Errorf("failed to fetch user profile: %w", err)
 	}
 
-	slog.Info(fmt.Sprintf("Found user %s (%s) with %d posts", profile.Name, profile.ID, profile.PostCount), "module", m.Key)
+	m.Log().Info(fmt.Sprintf("Found user %s (%s) with %d posts", profile.Name, profile.ID, profile.PostCount))
 
 	userPosts, err := m.api.GetUserPosts(service, userId, 0)
 	if err != nil {
@@ -71,7 +70,7 @@ func (m *kemono) parseUser(item *models.TrackedItem) error {
 		downloadQueue[i], downloadQueue[j] = downloadQueue[j], downloadQueue[i]
 	}
 
-	return m.processDownloadQueue(item, downloadQueue)
+	return m.processDownloadQueue(item, profile.Name, downloadQueue)
 }
 
 func (m *kemono) parsePost(item *models.TrackedItem) error {
@@ -81,9 +80,21 @@ func (m *kemono) parsePost(item *models.TrackedItem) error {
 		return fmt.Errorf("could not extract post ID from URL: %s", item.URI)
 	}
 
-	return m.processDownloadQueue(item, []api.QuickPost{{
+	return m.processDownloadQueue(item, m.getAuthorName(postId[1], postId[2]), []api.QuickPost{{
 		Service: postId[1],
 		User:    postId[2],
 		ID:      postId[3],
 	}})
 }
+
+// getAuthorName returns the name of the passed user for the metadata of the downloaded posts,
+// the posts are still downloaded if the profile couldn't be retrieved
+func (m *kemono) getAuthorName(service string, userID string) string {
+	profile, err := m.api.GetUserProfile(service, userID)
+	if err != nil {
+		m.Log().Warn(fmt.Sprintf("failed to fetch user profile of \"%s\", author name is unknown: %s", userID, err.Error()))
+		return ""
+	}
+
+	return profile.Name
+}
@@ -2,7 +2,6 @@ package momonga
 
 import (
 	"fmt"
-	"log/slog"
 	"sort"
 	"strconv"
 	"strings"
@@ -109,9 +108,9 @@ func (m *momonga) parseGallery(item *models.TrackedItem) error {
 
 	if !m.Cfg.Run.Force {
 		if blacklisted, term := m.isBlacklisted(html, title); blacklisted {
-			slog.Warn(fmt.Sprintf(
+			m.Log().Warn(fmt.Sprintf(
 				"gallery \"%s\" contains blacklisted tag \"%s\", setting item to complete",
-				title, term), "module", m.Key)
+				title, term))
 			m.DbIO.ChangeTrackedItemCompleteStatus(item, true)
 			return nil
 		}
@@ -140,7 +139,7 @@ func (m *momonga) parseGallery(item *models.TrackedItem) error {
 	// a fresh gallery that yielded no images likely indicates a markup/parsing change;
 	// surface it rather than silently marking the item complete with nothing downloaded
 	if len(downloadQueue) == 0 && item.CurrentItem == "" {
-		slog.Warn(fmt.Sprintf("gallery \"%s\" yielded no images, not marking complete", title), "module", m.Key)
+		m.Log().Warn(fmt.Sprintf("gallery \"%s\" yielded no images, not marking complete", title))
 		return nil
 	}
 
@@ -2,7 +2,6 @@ package momonga
 
 import (
 	"fmt"
-	"log/slog"
 	"net/url"
 	"strconv"
 	"strings"
@@ -114,14 +113,14 @@ func (m *momonga) parseListing(item *models.TrackedItem) error {
 		itemQueue[i], itemQueue[j] = itemQueue[j], itemQueue[i]
 	}
 
-	slog.Info(fmt.Sprintf("found %d new items for uri: %s", len(itemQueue), item.URI), "module", m.Key)
+	m.ReportNewItems(item.URI, len(itemQueue))
 
 	// reset progress on discovered galleries when forced
 	for _, listing := range itemQueue {
 		galleryItem := m.DbIO.GetFirstOrCreateTrackedItem(listing.uri, m.getSubFolder(item), m)
 		if (m.Cfg.Run.Force || m.Cfg.Run.ResetProgress) && galleryItem.CurrentItem != "" {
-			slog.Info(fmt.Sprintf("resetting progress for item %s (current id: %s)",
-				galleryItem.URI, galleryItem.CurrentItem), "module", m.Key)
+			m.Log().Info(fmt.Sprintf("resetting progress for item %s (current id: %s)",
+				galleryItem.URI, galleryItem.CurrentItem))
 			galleryItem.CurrentItem = ""
 			m.DbIO.ChangeTrackedItemCompleteStatus(galleryItem, false)
 			m.DbIO.UpdateTrackedItem(galleryItem, "")
@@ -131,16 +130,16 @@ func (m *momonga) parseListing(item *models.TrackedItem) error {
 	for index, listing := range itemQueue {
 		galleryItem := m.DbIO.GetFirstOrCreateTrackedItem(listing.uri, m.getSubFolder(item), m)
 		if !galleryItem.Complete {
-			slog.Info(fmt.Sprintf(
+			m.Log().Info(fmt.Sprintf(
 				"added gallery to tracked items: \"%s\", search item: \"%s\" (%0.2f%%)",
 				listing.uri,
 				item.URI,
 				float64(index+1)/float64(len(itemQueue))*100,
-			), "module", m.Key)
+			))
 
 			if err = m.Parse(galleryItem); err != nil {
-				slog.Warn(fmt.Sprintf("error occurred parsing item %s (%s), skipping",
-					galleryItem.URI, err.Error()), "module", m.Key)
+				m.Log().Warn(fmt.Sprintf("error occurred parsing item %s (%s), skipping",
+					galleryItem.URI, err.Error()))
 				return err
 			}
 		}
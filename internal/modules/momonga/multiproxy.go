@@ -7,8 +7,6 @@ import (
 	"strings"
 	"time"
 
-	"log/slog"
-
 	"github.com/DaRealFreak/watcher-go/internal/http"
 	"github.com/DaRealFreak/watcher-go/internal/http/tls_session"
 	"github.com/DaRealFreak/watcher-go/internal/models"
@@ -30,7 +28,7 @@ func (m *momonga) initializeProxySessions() {
 
 	mainSession, ok := m.Session.(*tls_session.TlsClientSession)
 	if !ok {
-		slog.Error("cannot share cookie jar: main session is not a TlsClientSession")
+		m.Log().Error("cannot share cookie jar: main session is not a TlsClientSession")
 		return
 	}
 	sharedJar := mainSession.Jar
@@ -129,7 +127,7 @@ func (m *momonga) removeCurrentIndex(index int) {
 }
 
 func (m *momonga) processDownloadQueueMultiProxy(downloadQueue []models.DownloadQueueItem, trackedItem *models.TrackedItem) error {
-	slog.Info(fmt.Sprintf("found %d new items for uri: \"%s\"", len(downloadQueue), trackedItem.URI), "module", m.Key)
+	m.ReportNewItems(trackedItem.URI, len(downloadQueue))
 
 	for index, data := range downloadQueue {
 		// wait until a proxy frees up or a previous download errored
@@ -138,8 +136,8 @@ func (m *momonga) processDownloadQueueMultiProxy(downloadQueue []models.Download
 		}
 
 		if erroneousProxy := m.getProxyError(); erroneousProxy != nil {
-			slog.Warn(fmt.Sprintf("error occurred during download for proxy: %s",
-				erroneousProxy.proxy.Host), "module", m.Key)
+			m.Log().Warn(fmt.Sprintf("error occurred during download for proxy: %s",
+				erroneousProxy.proxy.Host))
 			m.multiProxy.waitGroup.Wait()
 			return erroneousProxy.occurredError
 		}
@@ -151,11 +149,11 @@ func (m *momonga) processDownloadQueueMultiProxy(downloadQueue []models.Download
 			continue
 		}
 
-		slog.Info(fmt.Sprintf(
+		m.Log().Info(fmt.Sprintf(
 			"downloading updates for uri: \"%s\" (%0.2f%%)",
 			trackedItem.URI,
 			float64(index+1)/float64(len(downloadQueue))*100,
-		), "module", m.Key)
+		))
 
 		m.multiProxy.waitGroup.Add(1)
 
@@ -169,8 +167,8 @@ func (m *momonga) processDownloadQueueMultiProxy(downloadQueue []models.Download
 	m.multiProxy.waitGroup.Wait()
 
 	if erroneousProxy := m.getProxyError(); erroneousProxy != nil {
-		slog.Warn(fmt.Sprintf("error occurred during download for proxy: %s",
-			erroneousProxy.proxy.Host), "module", m.Key)
+		m.Log().Warn(fmt.Sprintf("error occurred during download for proxy: %s",
+			erroneousProxy.proxy.Host))
 		return erroneousProxy.occurredError
 	}
 
@@ -212,8 +210,8 @@ func (m *momonga) downloadImageSession(
 	}
 
 	if res.StatusCode == 429 {
-		slog.Warn(fmt.Sprintf("received status code 429 on image url \"%s\"",
-			downloadQueueItem.FileURI), "module", m.Key)
+		m.Log().Warn(fmt.Sprintf("received status code 429 on image url \"%s\"",
+			downloadQueueItem.FileURI))
 		time.Sleep(time.Second * 5)
 
 		return m.downloadImageSession(downloadSession, trackedItem, downloadQueueItem, index)
@@ -221,8 +219,8 @@ func (m *momonga) downloadImageSession(
 
 	if res.StatusCode == 404 {
 		// the image just doesn't exist (anymore); log and skip rather than fail the gallery
-		slog.Warn(fmt.Sprintf("received status code 404 on image url \"%s\"",
-			downloadQueueItem.FileURI), "module", m.Key)
+		m.Log().Warn(fmt.Sprintf("received status code 404 on image url \"%s\"",
+			downloadQueueItem.FileURI))
 		return nil
 	}
 
@@ -241,7 +239,7 @@ func (m *momonga) downloadImageSession(
 	// bump the file's mtime so that ordering by time == ordering by page index
 	if info, statErr := os.Stat(dst); statErr == nil && !info.IsDir() {
 		if chtErr := os.Chtimes(dst, startTime, startTime); chtErr != nil {
-			slog.Warn(fmt.Sprintf("failed to reset timestamp for %s: %v", dst, chtErr), "module", m.Key)
+			m.Log().Warn(fmt.Sprintf("failed to reset timestamp for %s: %v", dst, chtErr))
 		}
 	}
 
@@ -7,7 +7,6 @@ import (
 
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/DaRealFreak/watcher-go/pkg/fp"
-	"log/slog"
 )
 
 func (m *nhentai) parseGalleryFromApiResponse(
@@ -17,9 +16,9 @@ func (m *nhentai) parseGalleryFromApiResponse(
 	galleryTitle := fmt.Sprintf("%s [%s]", apiResponse.GetTitle(), apiResponse.GetLanguage())
 	for _, blacklistedTag := range m.settings.Search.BlacklistedTags {
 		if strings.Contains(strings.ToLower(galleryTitle), strings.ToLower(blacklistedTag)) {
-			slog.Warn(fmt.Sprintf("gallery title \"%s\" contains blacklisted tag \"%s\", setting item to complete",
+			m.Log().Warn(fmt.Sprintf("gallery title \"%s\" contains blacklisted tag \"%s\", setting item to complete",
 				galleryTitle,
-				blacklistedTag), "module", m.Key)
+				blacklistedTag))
 			m.DbIO.ChangeTrackedItemCompleteStatus(item, true)
 			return nil
 		}
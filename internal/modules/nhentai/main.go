@@ -45,6 +45,20 @@ func init() {
 
 // NewBareModule returns a bare module implementation for the CLI options
 func NewBareModule() *models.Module {
+	module := newModule()
+
+	// register module to log formatter
+	formatter.AddFieldMatchColorScheme("module", &formatter.FieldMatch{
+		Value: module.Key,
+		Color: "255:197",
+	})
+
+	return module
+}
+
+// newModule returns a new instance of the module. Galleries and searches are parsed without
+// keeping any state in the module, so it can parse multiple items at once
+func newModule() *models.Module {
 	module := &models.Module{
 		Key:           "nhentai.net",
 		RequiresLogin: false,
@@ -59,12 +73,7 @@ func NewBareModule() *models.Module {
 		Module:                 module,
 		searchGalleryIDPattern: regexp.MustCompile(`/g/(\d+)/`),
 	}
-
-	// register module to log formatter
-	formatter.AddFieldMatchColorScheme("module", &formatter.FieldMatch{
-		Value: module.Key,
-		Color: "255:197",
-	})
+	module.NewWorker = newModule
 
 	return module
 }
@@ -3,7 +3,6 @@ package nhentai
 import (
 	"fmt"
 	"github.com/DaRealFreak/watcher-go/internal/models"
-	"log/slog"
 	"net/url"
 	"regexp"
 	"strconv"
@@ -148,12 +147,12 @@ func (m *nhentai) collectSearchResults(
 
 // processSearchQueue processes the collected search results by fetching full gallery data
 func (m *nhentai) processSearchQueue(item *models.TrackedItem, itemQueue []*searchResultItem) error {
-	slog.Info(fmt.Sprintf("found %d new items for uri: %s", len(itemQueue), item.URI), "module", m.Key)
+	m.ReportNewItems(item.URI, len(itemQueue))
 
 	for _, result := range itemQueue {
 		galleryItem := m.DbIO.GetFirstOrCreateTrackedItem(result.GetURL(), m.getSubFolder(item), m)
 		if (m.Cfg.Run.Force || m.Cfg.Run.ResetProgress) && galleryItem.CurrentItem != "" {
-			slog.Info(fmt.Sprintf("resetting progress for item %s (current id: %s)", galleryItem.URI, galleryItem.CurrentItem), "module", m.Key)
+			m.Log().Info(fmt.Sprintf("resetting progress for item %s (current id: %s)", galleryItem.URI, galleryItem.CurrentItem))
 			galleryItem.CurrentItem = ""
 			m.DbIO.ChangeTrackedItemCompleteStatus(galleryItem, false)
 			m.DbIO.UpdateTrackedItem(galleryItem, "")
@@ -162,24 +161,24 @@ func (m *nhentai) processSearchQueue(item *models.TrackedItem, itemQueue []*sear
 
 	// add items
 	for index, result := range itemQueue {
-		slog.Info(fmt.Sprintf(
+		m.Log().Info(fmt.Sprintf(
 			"added gallery to tracked items: \"%s\", search item: \"%s\" (%0.2f%%)",
 			result.GetURL(),
 			item.URI,
 			float64(index+1)/float64(len(itemQueue))*100,
-		), "module", m.Key)
+		))
 
 		galleryItem := m.DbIO.GetFirstOrCreateTrackedItem(result.GetURL(), m.getSubFolder(item), m)
 
 		// fetch full gallery data for download
 		fullGallery, err := m.getGallery(result.ID.String())
 		if err != nil {
-			slog.Warn(fmt.Sprintf("error occurred fetching gallery %s (%s), skipping", result.GetURL(), err.Error()), "module", item.Module)
+			m.Log().Warn(fmt.Sprintf("error occurred fetching gallery %s (%s), skipping", result.GetURL(), err.Error()))
 			continue
 		}
 
 		if err = m.parseGalleryFromApiResponse(galleryItem, fullGallery); err != nil {
-			slog.Warn(fmt.Sprintf("error occurred parsing item %s (%s), skipping", galleryItem.URI, err.Error()), "module", item.Module)
+			m.Log().Warn(fmt.Sprintf("error occurred parsing item %s (%s), skipping", galleryItem.URI, err.Error()))
 			return err
 		}
 
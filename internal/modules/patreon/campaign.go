@@ -13,7 +13,6 @@ import (
 	"github.com/DaRealFreak/watcher-go/internal/modules"
 	"github.com/DaRealFreak/watcher-go/internal/raven"
 	"github.com/PuerkitoBio/goquery"
-	"log/slog"
 )
 
 // campaignResponse contains the struct for most relevant data of posts
@@ -186,7 +185,7 @@ func (m *patreon) extractPostDownload(
 				embedUrl.RawQuery = parsedQueryString.Encode()
 				download.ExternalURLs = append(download.ExternalURLs, embedUrl.String())
 			} else {
-				slog.Warn(fmt.Sprintf("unable to parse found URL: \"%s\"", externalUrl), "module", m.Key)
+				m.Log().Warn(fmt.Sprintf("unable to parse found URL: \"%s\"", externalUrl))
 			}
 		}
 	}
@@ -199,7 +198,7 @@ func (m *patreon) extractPostDownload(
 			embedUrl.RawQuery = parsedQueryString.Encode()
 			download.ExternalURLs = append(download.ExternalURLs, embedUrl.String())
 		} else {
-			slog.Warn(fmt.Sprintf("unable to parse found URL: \"%s\"", post.Attributes.Embed.URL), "module", m.Key)
+			m.Log().Warn(fmt.Sprintf("unable to parse found URL: \"%s\"", post.Attributes.Embed.URL))
 		}
 	}
 
@@ -6,12 +6,10 @@ import (
 	"strconv"
 	"strings"
 
-	"context"
 	"github.com/DaRealFreak/watcher-go/internal/jdownloader"
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/DaRealFreak/watcher-go/internal/modules"
 	"github.com/DaRealFreak/watcher-go/pkg/fp"
-	"log/slog"
 )
 
 // postDownload is the struct used for downloading post contents
@@ -26,23 +24,15 @@ type postDownload struct {
 }
 
 func (m *patreon) processDownloadQueue(downloadQueue []*postDownload, item *models.TrackedItem, notifications ...*models.Notification) error {
-	slog.Info(
-		fmt.Sprintf("found %d new items for uri: \"%s\"", len(downloadQueue), item.URI),
-		"module", m.Key,
-	)
-
-	for _, notification := range notifications {
-		slog.Log(context.Background(), notification.Level, notification.Message, "module", m.Key)
-	}
+	m.ReportNewItems(item.URI, len(downloadQueue), notifications...)
 
 	for index, data := range downloadQueue {
-		slog.Info(
+		m.Log().Info(
 			fmt.Sprintf(
 				"downloading updates for uri: \"%s\" (%0.2f%%)",
 				item.URI,
 				float64(index+1)/float64(len(downloadQueue))*100,
 			),
-			"module", m.Key,
 		)
 
 		for _, attachment := range data.Attachments {
@@ -63,9 +53,9 @@ func (m *patreon) processDownloadQueue(downloadQueue []*postDownload, item *mode
 			default:
 				// if no download URL is returned from the API we don't have the reward unlocked and can't download it
 				if attachment.Attributes.DownloadURL == "" {
-					slog.Warn(fmt.Sprintf("post %s not unlocked, skipping attachment %s",
+					m.Log().Warn(fmt.Sprintf("post %s not unlocked, skipping attachment %s",
 						data.PatreonURL,
-						attachment.ID), "module", m.Key)
+						attachment.ID))
 
 					continue
 				}
@@ -88,9 +78,9 @@ func (m *patreon) processDownloadQueue(downloadQueue []*postDownload, item *mode
 		factory := modules.GetModuleFactory()
 		for _, externalURL := range data.ExternalURLs {
 			if m.settings.ExternalURLs.PrintExternalItems {
-				slog.Info(fmt.Sprintf("found external URL: \"%s\" in post \"%s\"",
+				m.Log().Info(fmt.Sprintf("found external URL: \"%s\" in post \"%s\"",
 					externalURL,
-					data.PatreonURL), "module", m.Key)
+					data.PatreonURL))
 			}
 
 			// hand links we can't parse ourselves to JDownloader (independent of DownloadExternalItems)
@@ -105,7 +95,7 @@ func (m *patreon) processDownloadQueue(downloadQueue []*postDownload, item *mode
 					// crawljob off or blacklisted: warn but DON'T fall through to the native
 					// block below, which would fatally call GetModuleFromURI on an unparseable URL.
 					if m.settings.ExternalURLs.DownloadExternalItems {
-						slog.Warn(fmt.Sprintf("unable to parse URL \"%s\" found in post \"%s\"", externalURL, data.PatreonURL), "module", m.Key)
+						m.Log().Warn(fmt.Sprintf("unable to parse URL \"%s\" found in post \"%s\"", externalURL, data.PatreonURL))
 					}
 				}
 				continue
@@ -120,17 +110,17 @@ func (m *patreon) processDownloadQueue(downloadQueue []*postDownload, item *mode
 				// don't delete previously already added items
 				deleteAfter := newItem.CurrentItem == ""
 				if m.Cfg.Run.Force && newItem.CurrentItem != "" {
-					slog.Info(fmt.Sprintf("resetting progress for item %s (current id: %s)", newItem.URI, newItem.CurrentItem), "module", m.Key)
+					m.Log().Info(fmt.Sprintf("resetting progress for item %s (current id: %s)", newItem.URI, newItem.CurrentItem))
 					newItem.CurrentItem = ""
 					m.DbIO.ChangeTrackedItemCompleteStatus(newItem, false)
 					m.DbIO.UpdateTrackedItem(newItem, "")
 				}
 
 				if err := module.Parse(newItem); err != nil {
-					slog.Warn(fmt.Sprintf("unable to parse external URL \"%s\" found in post \"%s\" with error \"%s\", skipping",
+					m.Log().Warn(fmt.Sprintf("unable to parse external URL \"%s\" found in post \"%s\" with error \"%s\", skipping",
 						newItem.URI,
 						data.PatreonURL,
-						err.Error()), "module", m.Key)
+						err.Error()))
 					if !m.settings.ExternalURLs.SkipErrorsForExternalURLs {
 						if deleteAfter {
 							m.DbIO.DeleteTrackedItem(newItem)
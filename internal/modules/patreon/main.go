@@ -20,7 +20,6 @@ import (
 	"github.com/DaRealFreak/watcher-go/internal/raven"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"log/slog"
 )
 
 // patreon contains the implementation of the ModuleInterface
@@ -196,7 +195,7 @@ func (m *patreon) Login(account *models.Account) bool {
 	}
 
 	if foundCookie {
-		slog.Info("using existing session cookie", "module", m.Key)
+		m.Log().Info("using existing session cookie")
 		m.LoggedIn = true
 		return m.LoggedIn
 	}
@@ -222,18 +221,18 @@ func (m *patreon) Login(account *models.Account) bool {
 
 	data, err := json.Marshal(formData)
 	if err != nil {
-		slog.Error(err.Error(), "module", m.Key)
+		m.Log().Error(err.Error())
 	}
 
 	res, err := m.get("https://www.patreon.com/login")
 	if err != nil {
-		slog.Error(err.Error(), "module", m.Key)
+		m.Log().Error(err.Error())
 		return false
 	}
 
 	loginCsrfMatches := m.loginCsrfPattern.FindStringSubmatch(m.Session.GetDocument(res).Text())
 	if len(loginCsrfMatches) != 2 {
-		slog.Error("unexpected amount of matches in search of login CSRF token", "module", m.Key)
+		m.Log().Error("unexpected amount of matches in search of login CSRF token")
 		os.Exit(1)
 		return false
 	}
@@ -247,10 +246,10 @@ func (m *patreon) Login(account *models.Account) bool {
 
 	res, err = m.Session.GetClient().Do(req)
 	if err != nil {
-		slog.Error(err.Error(), "module", m.Key)
+		m.Log().Error(err.Error())
 	}
 	if res.StatusCode != 200 {
-		slog.Error("unable to login to patreon.com", "module", m.Key)
+		m.Log().Error("unable to login to patreon.com")
 	}
 
 	loginRes := m.Session.GetDocument(res).Text()
@@ -261,7 +260,7 @@ func (m *patreon) Login(account *models.Account) bool {
 	)
 
 	if err = json.Unmarshal([]byte(loginRes), &loginError); err != nil {
-		slog.Error(err.Error(), "module", m.Key)
+		m.Log().Error(err.Error())
 	}
 
 	if len(loginError.Errors) > 0 {
@@ -276,7 +275,7 @@ func (m *patreon) Login(account *models.Account) bool {
 				})
 				verificationRes, verificationError := m.get(strings.TrimSuffix(text, "\n"))
 				if verificationError != nil {
-					slog.Error(fmt.Sprintf("error occurred during login (code: %s): %s", loginErr.Code, loginErr.Detail), "module", m.Key)
+					m.Log().Error(fmt.Sprintf("error occurred during login (code: %s): %s", loginErr.Code, loginErr.Detail))
 					os.Exit(1)
 					return false
 				}
@@ -286,13 +285,13 @@ func (m *patreon) Login(account *models.Account) bool {
 				}
 			}
 
-			slog.Error(fmt.Sprintf("error occurred during login (code: %s): %s", loginErr.Code, loginErr.Detail), "module", m.Key)
+			m.Log().Error(fmt.Sprintf("error occurred during login (code: %s): %s", loginErr.Code, loginErr.Detail))
 			os.Exit(1)
 		}
 	}
 
 	if err = json.Unmarshal([]byte(loginRes), &loginSuccess); err != nil {
-		slog.Error(err.Error(), "module", m.Key)
+		m.Log().Error(err.Error())
 	}
 
 	m.LoggedIn = loginSuccess.Data.ID.String() != ""
@@ -307,9 +306,8 @@ func (m *patreon) Parse(item *models.TrackedItem) error {
 		if err == nil {
 			m.DbIO.ChangeTrackedItemUri(item, newUri)
 		} else {
-			slog.Warn(
+			m.Log().Warn(
 				fmt.Sprintf("unable to convert campaign URL to ID for %s (%s)", item.URI, err.Error()),
-				"module", item.Module,
 			)
 		}
 	}
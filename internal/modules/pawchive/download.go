@@ -7,8 +7,7 @@ import (
 	"path"
 	"strings"
 
-	"log/slog"
-
+	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
 	"github.com/DaRealFreak/watcher-go/internal/jdownloader"
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/DaRealFreak/watcher-go/internal/modules"
@@ -192,14 +191,14 @@ func (m *pawchive) getExternalLinks(post *api.Post, comments []api.Comment) (lin
 }
 
 func (m *pawchive) processDownloadQueue(item *models.TrackedItem, downloadQueue []api.Post) error {
-	slog.Info(fmt.Sprintf("found %d new items for uri: \"%s\"", len(downloadQueue), item.URI), "module", m.Key)
+	m.ReportNewItems(item.URI, len(downloadQueue))
 
 	for index, data := range downloadQueue {
-		slog.Info(fmt.Sprintf(
+		m.Log().Info(fmt.Sprintf(
 			"downloading updates for uri: \"%s\" (%0.2f%%)",
 			item.URI,
 			float64(index+1)/float64(len(downloadQueue))*100,
-		), "module", m.Key)
+		))
 
 		if err := m.downloadPost(item, data); err != nil {
 			return err
@@ -248,17 +247,17 @@ func (m *pawchive) downloadPost(item *models.TrackedItem, post api.Post) error {
 
 		downloadURL, isThumbnail, skip := m.fileDownloadTarget(&post, downloadItem, fileName)
 		if skip {
-			slog.Warn(fmt.Sprintf(
+			m.Log().Warn(fmt.Sprintf(
 				"post \"%s\" is not archived yet (has_full=false) and file \"%s\" has no thumbnail fallback, skipping file",
-				webUrl, downloadItem.FileURI), "module", m.Key)
+				webUrl, downloadItem.FileURI))
 			continue
 		}
 
 		namePart := fileName
 		if isThumbnail {
-			slog.Warn(fmt.Sprintf(
+			m.Log().Warn(fmt.Sprintf(
 				"post \"%s\" is not archived yet (has_full=false); saving thumbnail \"%s\" as degraded fallback for full file \"%s\"",
-				webUrl, downloadURL, downloadItem.FileURI), "module", m.Key)
+				webUrl, downloadURL, downloadItem.FileURI))
 			namePart = "thumbnail_" + fileName
 		}
 
@@ -274,9 +273,9 @@ func (m *pawchive) downloadPost(item *models.TrackedItem, post api.Post) error {
 			// and move on rather than fataling the parse. A failed full-res download
 			// on an archived post is a genuine error and still propagates.
 			if isThumbnail {
-				slog.Warn(fmt.Sprintf(
+				m.Log().Warn(fmt.Sprintf(
 					"thumbnail fallback failed for \"%s\": %s, skipping file",
-					downloadURL, err.Error()), "module", m.Key)
+					downloadURL, err.Error()))
 				continue
 			}
 			return err
@@ -287,7 +286,7 @@ func (m *pawchive) downloadPost(item *models.TrackedItem, post api.Post) error {
 	factory := modules.GetModuleFactory()
 	for _, externalURL := range externalLinks {
 		if m.settings.ExternalURLs.PrintExternalItems {
-			slog.Info(fmt.Sprintf("found external URL: \"%s\" in post \"%s\"", externalURL, webUrl), "module", m.Key)
+			m.Log().Info(fmt.Sprintf("found external URL: \"%s\" in post \"%s\"", externalURL, webUrl))
 		}
 
 		// hand links we can't parse ourselves to JDownloader (independent of DownloadExternalItems)
@@ -314,15 +313,15 @@ func (m *pawchive) downloadPost(item *models.TrackedItem, post api.Post) error {
 				// don't delete previously already added items
 				deleteAfter := newItem.CurrentItem == ""
 				if m.Cfg.Run.Force && newItem.CurrentItem != "" {
-					slog.Info(fmt.Sprintf("resetting progress for item %s (current id: %s)", newItem.URI, newItem.CurrentItem), "module", m.Key)
+					m.Log().Info(fmt.Sprintf("resetting progress for item %s (current id: %s)", newItem.URI, newItem.CurrentItem))
 					newItem.CurrentItem = ""
 					m.DbIO.ChangeTrackedItemCompleteStatus(newItem, false)
 					m.DbIO.UpdateTrackedItem(newItem, "")
 				}
 
 				if err := module.Parse(newItem); err != nil {
-					slog.Warn(fmt.Sprintf("unable to parse external URL \"%s\" found in post \"%s\" with error \"%s\", skipping",
-						newItem.URI, webUrl, err.Error()), "module", m.Key)
+					m.Log().Warn(fmt.Sprintf("unable to parse external URL \"%s\" found in post \"%s\" with error \"%s\", skipping",
+						newItem.URI, webUrl, err.Error()))
 					if !m.settings.ExternalURLs.SkipErrorsForExternalURLs {
 						if deleteAfter {
 							m.DbIO.DeleteTrackedItem(newItem)
@@ -335,13 +334,13 @@ func (m *pawchive) downloadPost(item *models.TrackedItem, post api.Post) error {
 					m.DbIO.DeleteTrackedItem(newItem)
 				}
 			} else {
-				slog.Warn(fmt.Sprintf("unable to parse URL \"%s\" found in post \"%s\"", externalURL, webUrl), "module", m.Key)
+				m.Log().Warn(fmt.Sprintf("unable to parse URL \"%s\" found in post \"%s\"", externalURL, webUrl))
 			}
 		}
 	}
 
-	// create the post folder if we only found external links (no direct downloads)
-	if len(downloadLinks) == 0 && len(externalLinks) > 0 {
+	// create the post folder if we only found external links (no direct downloads, not during dry runs)
+	if len(downloadLinks) == 0 && len(externalLinks) > 0 && !watcherHttp.GlobalDryRun.Enabled() {
 		downloadFolder := path.Join(
 			m.GetDownloadDirectory(),
 			m.Key,
@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"regexp"
 
-	"log/slog"
-
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/DaRealFreak/watcher-go/internal/modules/pawchive/api"
 )
@@ -27,7 +25,7 @@ func (m *pawchive) parseUser(item *models.TrackedItem) error {
 		return fmt.Errorf("failed to fetch user profile: %w", err)
 	}
 
-	slog.Info(fmt.Sprintf("found user %s (%s)", profile.Name, profile.ID), "module", m.Key)
+	m.Log().Info(fmt.Sprintf("found user %s (%s)", profile.Name, profile.ID))
 
 	userPosts, err := m.api.GetUserPosts(service, userId, 0)
 	if err != nil {
@@ -13,15 +13,15 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
-	"context"
+	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
 	"github.com/DaRealFreak/watcher-go/internal/http/tls_session"
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	fanboxapi "github.com/DaRealFreak/watcher-go/internal/modules/pixiv/fanbox_api"
 	mobileapi "github.com/DaRealFreak/watcher-go/internal/modules/pixiv/mobile_api"
 	"github.com/DaRealFreak/watcher-go/pkg/fp"
 	"github.com/DaRealFreak/watcher-go/pkg/imaging/animation"
-	"log/slog"
 )
 
 type downloadQueueItem struct {
@@ -31,19 +31,14 @@ type downloadQueueItem struct {
 }
 
 func (m *pixiv) processDownloadQueue(downloadQueue []*downloadQueueItem, trackedItem *models.TrackedItem, notifications ...*models.Notification) error {
-	slog.Info(fmt.Sprintf("found %d new items for uri: \"%s\"", len(downloadQueue), trackedItem.URI), "module", m.Key)
-
-	for _, notification := range notifications {
-		slog.Log(context.Background(),
-			notification.Level, notification.Message, "module", m.Key)
-	}
+	m.ReportNewItems(trackedItem.URI, len(downloadQueue), notifications...)
 
 	for index, data := range downloadQueue {
-		slog.Info(fmt.Sprintf(
+		m.Log().Info(fmt.Sprintf(
 			"downloading updates for uri: \"%s\" (%0.2f%%)",
 			trackedItem.URI,
 			float64(index+1)/float64(len(downloadQueue))*100,
-		), "module", m.Key)
+		))
 
 		switch item := data.DownloadItem.(type) {
 		case mobileapi.Illustration:
@@ -51,9 +46,9 @@ func (m *pixiv) processDownloadQueue(downloadQueue []*downloadQueueItem, tracked
 
 			switch item.Type {
 			case Ugoira:
-				err = m.downloadUgoira(data, item.ID)
+				err = m.downloadUgoira(data, item)
 			default:
-				err = m.downloadIllustration(data, item)
+				err = m.downloadIllustration(trackedItem, data, item)
 			}
 
 			if m.settings.ExternalUrls.PrintExternalItems || jdownloader.Enabled() {
@@ -63,9 +58,9 @@ func (m *pixiv) processDownloadQueue(downloadQueue []*downloadQueueItem, tracked
 				if item.Caption == "" {
 					detail, detailErr := m.mobileAPI.GetIllustDetail(item.ID)
 					if detailErr != nil {
-						slog.Error(fmt.Sprintf("failed to get illustration details for ID %d: %v",
+						m.Log().Error(fmt.Sprintf("failed to get illustration details for ID %d: %v",
 							item.ID,
-							detailErr), "module", m.Key)
+							detailErr))
 					} else {
 						checkedIllustration = detail.Illustration
 					}
@@ -78,9 +73,9 @@ func (m *pixiv) processDownloadQueue(downloadQueue []*downloadQueueItem, tracked
 					link = strings.Replace(link, "http://", "https://", 1)
 
 					if m.settings.ExternalUrls.PrintExternalItems {
-						slog.Info(fmt.Sprintf("found external URL: \"%s\" in post \"https://www.pixiv.net/en/artworks/%d\"",
+						m.Log().Info(fmt.Sprintf("found external URL: \"%s\" in post \"https://www.pixiv.net/en/artworks/%d\"",
 							link,
-							checkedIllustration.ID), "module", m.Key)
+							checkedIllustration.ID))
 					}
 
 					// pixiv never downloads caption links natively, so every link is a crawljob
@@ -94,9 +89,9 @@ func (m *pixiv) processDownloadQueue(downloadQueue []*downloadQueueItem, tracked
 
 			if err != nil {
 				if e, ok := err.(tls_session.StatusError); ok && e.StatusCode == 404 {
-					slog.Warn(fmt.Sprintf("received 404 status code for URI \"https://www.pixiv.net/en/artworks/%d\", "+
+					m.Log().Warn(fmt.Sprintf("received 404 status code for URI \"https://www.pixiv.net/en/artworks/%d\", "+
 						"content got most likely deleted, skipping",
-						data.ItemID), "module", m.ModuleKey())
+						data.ItemID))
 				} else {
 					return err
 				}
@@ -136,7 +131,7 @@ func (m *pixiv) downloadFanboxPost(data *downloadQueueItem, post fanboxapi.Fanbo
 		if len(urlMatches) > 0 {
 			for _, match := range urlMatches {
 				q, _ := url.Parse(match[0])
-				slog.Warn(fmt.Sprintf("found URL from author in comments: %s (%d)", q.String(), postID), "module", m.Key)
+				m.Log().Warn(fmt.Sprintf("found URL from author in comments: %s (%d)", q.String(), postID))
 			}
 		}
 	}
@@ -145,19 +140,16 @@ func (m *pixiv) downloadFanboxPost(data *downloadQueueItem, post fanboxapi.Fanbo
 	if len(urlMatches) > 0 {
 		for _, match := range urlMatches {
 			q, _ := url.Parse(match[0])
-			slog.Warn(fmt.Sprintf("found URL from author in text body: %s (%d)", q.String(), postID), "module", m.Key)
+			m.Log().Warn(fmt.Sprintf("found URL from author in text body: %s (%d)", q.String(), postID))
 		}
 	}
 
+	metadata := m.getFanboxMetadata(postInfo)
+
 	if postInfo.Body.ImageForShare != "" {
 		fileName := fmt.Sprintf("0_%s", fp.GetFileName(postInfo.Body.ImageForShare))
 
-		if err = m.fanboxAPI.DownloadFile(
-			path.Join(
-				m.GetDownloadDirectory(), m.Key, data.DownloadTag, postInfo.Body.ID.String(), fileName,
-			),
-			postInfo.Body.ImageForShare,
-		); err != nil {
+		if err = m.downloadFanboxFile(data, postInfo, metadata, fileName, postInfo.Body.ImageForShare); err != nil {
 			return err
 		}
 	}
@@ -165,12 +157,7 @@ func (m *pixiv) downloadFanboxPost(data *downloadQueueItem, post fanboxapi.Fanbo
 	for i, image := range postInfo.Body.PostBody.Images {
 		fileName := fmt.Sprintf("%d_%s", i+1, fp.GetFileName(image.OriginalURL))
 
-		if err = m.fanboxAPI.DownloadFile(
-			path.Join(
-				m.GetDownloadDirectory(), m.Key, data.DownloadTag, postInfo.Body.ID.String(), fileName,
-			),
-			image.OriginalURL,
-		); err != nil {
+		if err = m.downloadFanboxFile(data, postInfo, metadata, fileName, image.OriginalURL); err != nil {
 			// if download was not successful return the occurred error here
 			return err
 		}
@@ -179,12 +166,7 @@ func (m *pixiv) downloadFanboxPost(data *downloadQueueItem, post fanboxapi.Fanbo
 	for i, file := range postInfo.Body.PostBody.Files {
 		fileName := fmt.Sprintf("%d_%s.%s", i+1, file.Name, file.Extension)
 
-		if err = m.fanboxAPI.DownloadFile(
-			path.Join(
-				m.GetDownloadDirectory(), m.Key, data.DownloadTag, postInfo.Body.ID.String(), fileName,
-			),
-			file.URL,
-		); err != nil {
+		if err = m.downloadFanboxFile(data, postInfo, metadata, fileName, file.URL); err != nil {
 			// if download was not successful return the occurred error here
 			return err
 		}
@@ -195,12 +177,7 @@ func (m *pixiv) downloadFanboxPost(data *downloadQueueItem, post fanboxapi.Fanbo
 		i += 1
 		fileName := fmt.Sprintf("%d_%s.%s", i, file.Name, file.Extension)
 
-		if err = m.fanboxAPI.DownloadFile(
-			path.Join(
-				m.GetDownloadDirectory(), m.Key, data.DownloadTag, postInfo.Body.ID.String(), fileName,
-			),
-			file.URL,
-		); err != nil {
+		if err = m.downloadFanboxFile(data, postInfo, metadata, fileName, file.URL); err != nil {
 			// if download was not successful return the occurred error here
 			return err
 		}
@@ -209,12 +186,7 @@ func (m *pixiv) downloadFanboxPost(data *downloadQueueItem, post fanboxapi.Fanbo
 	for i, file := range postInfo.ImagesFromBlocks() {
 		fileName := fmt.Sprintf("%d_%s", i+1, fp.GetFileName(file))
 
-		if err = m.fanboxAPI.DownloadFile(
-			path.Join(
-				m.GetDownloadDirectory(), m.Key, data.DownloadTag, postInfo.Body.ID.String(), fileName,
-			),
-			file,
-		); err != nil {
+		if err = m.downloadFanboxFile(data, postInfo, metadata, fileName, file); err != nil {
 			// if download was not successful return the occurred error here
 			return err
 		}
@@ -223,34 +195,117 @@ func (m *pixiv) downloadFanboxPost(data *downloadQueueItem, post fanboxapi.Fanbo
 	return nil
 }
 
-func (m *pixiv) downloadIllustration(data *downloadQueueItem, illust mobileapi.Illustration) error {
-	for _, metaPage := range illust.MetaPages {
-		fileName := fp.GetFileName(metaPage.ImageURLs.Original)
+// downloadFanboxFile downloads the passed file of the fanbox post and saves the metadata of the post
+func (m *pixiv) downloadFanboxFile(
+	data *downloadQueueItem, postInfo *fanboxapi.FanboxPostInfo, metadata *models.Metadata, fileName string, fileURL string,
+) error {
+	filePath := path.Join(m.GetDownloadDirectory(), m.Key, data.DownloadTag, postInfo.Body.ID.String(), fileName)
+
+	if err := m.fanboxAPI.DownloadFile(filePath, fileURL); err != nil {
+		return err
+	}
+
+	m.SaveMetadata(filePath, fileURL, metadata)
+
+	return nil
+}
+
+// getFanboxMetadata returns the cross-module metadata of the passed fanbox post
+func (m *pixiv) getFanboxMetadata(postInfo *fanboxapi.FanboxPostInfo) *models.Metadata {
+	metadata := &models.Metadata{
+		SourceURL:   fmt.Sprintf("https://www.fanbox.cc/@%s/posts/%s", postInfo.Body.CreatorID, postInfo.Body.ID.String()),
+		PostID:      postInfo.Body.ID.String(),
+		Author:      postInfo.Body.User.Name,
+		AuthorID:    postInfo.Body.User.UserId,
+		Title:       postInfo.Body.Title,
+		Description: postInfo.Text(),
+		Tags:        postInfo.Body.Tags,
+		Extra:       map[string]any{"creator_id": postInfo.Body.CreatorID},
+	}
+
+	if publishedAt, err := time.Parse(time.RFC3339, postInfo.Body.PublishedDatetime); err == nil {
+		metadata.CreatedAt = &publishedAt
+	}
 
-		if err := m.mobileAPI.DownloadFile(
-			path.Join(m.GetDownloadDirectory(), m.Key, data.DownloadTag, fileName),
-			metaPage.ImageURLs.Original,
-		); err != nil {
+	if updatedAt, err := time.Parse(time.RFC3339, postInfo.Body.UpdatedDatetime); err == nil {
+		metadata.UpdatedAt = &updatedAt
+	}
+
+	return metadata
+}
+
+func (m *pixiv) downloadIllustration(trackedItem *models.TrackedItem, data *downloadQueueItem, illust mobileapi.Illustration) error {
+	metadata := m.getIllustrationMetadata(illust)
+
+	for index, metaPage := range illust.MetaPages {
+		filePath := m.getIllustrationPath(trackedItem, data, metadata, metaPage.ImageURLs.Original, index+1)
+
+		if err := m.mobileAPI.DownloadFile(filePath, metaPage.ImageURLs.Original); err != nil {
 			// if download was not successful return the occurred error here
 			return err
 		}
+
+		m.SaveMetadata(filePath, metaPage.ImageURLs.Original, metadata)
 	}
 
 	if illust.MetaSinglePage.OriginalImageURL != nil {
-		fileName := fp.GetFileName(*illust.MetaSinglePage.OriginalImageURL)
+		filePath := m.getIllustrationPath(trackedItem, data, metadata, *illust.MetaSinglePage.OriginalImageURL, 1)
+
+		if err := m.mobileAPI.DownloadFile(filePath, *illust.MetaSinglePage.OriginalImageURL); err != nil {
+			return err
+		}
 
-		return m.mobileAPI.DownloadFile(
-			path.Join(m.GetDownloadDirectory(), m.Key, data.DownloadTag, fileName),
-			*illust.MetaSinglePage.OriginalImageURL,
-		)
+		m.SaveMetadata(filePath, *illust.MetaSinglePage.OriginalImageURL, metadata)
 	}
 
 	return nil
 }
 
+// getIllustrationPath returns the download path of the passed illustration page,
+// uses the download template if configured
+func (m *pixiv) getIllustrationPath(
+	trackedItem *models.TrackedItem, data *downloadQueueItem, metadata *models.Metadata, fileURI string, index int,
+) string {
+	downloadItem := &models.DownloadQueueItem{
+		ItemID:      strconv.Itoa(data.ItemID),
+		DownloadTag: data.DownloadTag,
+		FileName:    fp.GetFileName(fileURI),
+		FileURI:     fileURI,
+		Metadata:    metadata,
+	}
+
+	if filePath, ok := m.GetTemplateDownloadPath(trackedItem, downloadItem, index); ok {
+		return filePath
+	}
+
+	return path.Join(m.GetDownloadDirectory(), m.Key, data.DownloadTag, downloadItem.FileName)
+}
+
+// getIllustrationMetadata returns the cross-module metadata of the passed illustration
+func (m *pixiv) getIllustrationMetadata(illust mobileapi.Illustration) *models.Metadata {
+	metadata := &models.Metadata{
+		SourceURL:   fmt.Sprintf("https://www.pixiv.net/en/artworks/%d", illust.ID),
+		PostID:      strconv.Itoa(illust.ID),
+		Author:      illust.User.Name,
+		AuthorID:    strconv.Itoa(illust.User.ID),
+		Title:       illust.Title,
+		Description: illust.Caption,
+	}
+
+	if !illust.CreateDate.IsZero() {
+		metadata.CreatedAt = &illust.CreateDate
+	}
+
+	for _, tag := range illust.Tags {
+		metadata.Tags = append(metadata.Tags, tag.Name)
+	}
+
+	return metadata
+}
+
 // downloadUgoira handles the download process of ugoira illustration types
-func (m *pixiv) downloadUgoira(data *downloadQueueItem, illustID int) (err error) {
-	apiRes, err := m.mobileAPI.GetUgoiraMetadata(illustID)
+func (m *pixiv) downloadUgoira(data *downloadQueueItem, illust mobileapi.Illustration) (err error) {
+	apiRes, err := m.mobileAPI.GetUgoiraMetadata(illust.ID)
 	if err != nil {
 		return err
 	}
@@ -261,6 +316,14 @@ func (m *pixiv) downloadUgoira(data *downloadQueueItem, illustID int) (err error
 		m.settings.Animation.Format,
 	)
 
+	filepath := path.Join(m.GetDownloadDirectory(), m.Key, data.DownloadTag, fileName)
+
+	// the converted animation is written directly, so dry runs have to be handled here
+	if watcherHttp.GlobalDryRun.Enabled() {
+		watcherHttp.GlobalDryRun.RecordDownload(m.Key, filepath, apiRes.Metadata.ZipURLs.Medium)
+		return nil
+	}
+
 	resp, err := m.mobileAPI.Get(apiRes.Metadata.ZipURLs.Medium)
 	if err != nil {
 		return err
@@ -303,8 +366,7 @@ func (m *pixiv) downloadUgoira(data *downloadQueueItem, illustID int) (err error
 		return err
 	}
 
-	filepath := path.Join(m.GetDownloadDirectory(), m.Key, data.DownloadTag, fileName)
-	slog.Debug(fmt.Sprintf("saving converted animation: %s (frames: %d)", filepath, len(animationData.Frames)), "module", m.Key)
+	m.Log().Debug(fmt.Sprintf("saving converted animation: %s (frames: %d)", filepath, len(animationData.Frames)))
 
 	m.mobileAPI.Session.EnsureDownloadDirectory(filepath)
 
@@ -312,6 +374,8 @@ func (m *pixiv) downloadUgoira(data *downloadQueueItem, illustID int) (err error
 		return err
 	}
 
+	m.SaveMetadata(filepath, apiRes.Metadata.ZipURLs.Medium, m.getIllustrationMetadata(illust))
+
 	return nil
 }
 
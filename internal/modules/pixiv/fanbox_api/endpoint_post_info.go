@@ -3,6 +3,7 @@ package fanboxapi
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 // FanboxPostInfo contains the relevant fanbox post information
@@ -42,10 +43,14 @@ type FanboxPostInfo struct {
 				URL       string `json:"url"`
 			} `json:"fileMap"`
 		} `json:"body"`
-		ID            json.Number `json:"id"`
-		Title         string      `json:"title"`
-		ImageForShare string      `json:"imageForShare"`
-		CommentList   struct {
+		ID                json.Number `json:"id"`
+		Title             string      `json:"title"`
+		CreatorID         string      `json:"creatorId"`
+		Tags              []string    `json:"tags"`
+		PublishedDatetime string      `json:"publishedDatetime"`
+		UpdatedDatetime   string      `json:"updatedDatetime"`
+		ImageForShare     string      `json:"imageForShare"`
+		CommentList       struct {
 			Items []struct {
 				ID   string `json:"id"`
 				Body string `json:"body"`
@@ -84,6 +89,23 @@ func (i *FanboxPostInfo) ImagesFromBlocks() []string {
 	return imageURLs
 }
 
+// Text returns the text of the fanbox post, the text of block based posts is joined by new lines
+func (i *FanboxPostInfo) Text() string {
+	if i.Body.PostBody.Text != "" {
+		return i.Body.PostBody.Text
+	}
+
+	var paragraphs []string
+
+	for _, block := range i.Body.PostBody.Blocks {
+		if block.Text != "" {
+			paragraphs = append(paragraphs, block.Text)
+		}
+	}
+
+	return strings.Join(paragraphs, "\n")
+}
+
 // GetPostInfo requests the fanbox post info from the API for the passed post ID
 func (a *FanboxAPI) GetPostInfo(postID int) (*FanboxPostInfo, error) {
 	var postInfo FanboxPostInfo
@@ -2,9 +2,9 @@ package fanboxapi
 
 import (
 	"fmt"
+	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
 	http "github.com/bogdanfinn/fhttp"
 	"log/slog"
-	"os"
 	"time"
 )
 
@@ -35,6 +35,12 @@ func (a *FanboxAPI) do(req *http.Request) (*http.Response, error) {
 }
 
 func (a *FanboxAPI) DownloadFile(filepath string, uri string) (err error) {
+	// only record the download during dry runs without requesting the file
+	if watcherHttp.GlobalDryRun.Enabled() {
+		watcherHttp.GlobalDryRun.RecordDownload(a.Key, filepath, uri)
+		return nil
+	}
+
 	for try := 1; try <= 3; try++ {
 		slog.Debug(fmt.Sprintf("downloading file: \"%s\" (uri: %s, try: %d)", filepath, uri, try), "module", a.Key)
 
@@ -49,10 +55,8 @@ func (a *FanboxAPI) DownloadFile(filepath string, uri string) (err error) {
 	}
 
 	if err != nil {
-		// try to clean up failed file if it exists
-		if _, statErr := os.Stat(filepath); statErr == nil {
-			_ = os.Remove(filepath)
-		}
+		// the requests require the API headers, so partial downloads are not resumed on later runs
+		watcherHttp.RemovePartialDownload(filepath)
 	}
 
 	return err
@@ -3,11 +3,11 @@ package pixiv
 
 import (
 	"fmt"
-	"log/slog"
 	"os"
 	"regexp"
 
 	formatter "github.com/DaRealFreak/colored-nested-formatter/v2"
+	"github.com/DaRealFreak/watcher-go/internal/logging"
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/DaRealFreak/watcher-go/internal/modules"
 	fanboxapi "github.com/DaRealFreak/watcher-go/internal/modules/pixiv/fanbox_api"
@@ -119,9 +119,8 @@ func (m *pixiv) AddModuleCommand(command *cobra.Command) {
 func (m *pixiv) Login(_ *models.Account) bool {
 	oauthClient := m.DbIO.GetOAuthClient(m)
 	if oauthClient == nil || oauthClient.ClientID == "" || oauthClient.ClientSecret == "" {
-		slog.Error(
+		m.Log().Error(
 			"module requires an OAuth2 consumer ID and token",
-			"module", m.Key,
 		)
 		os.Exit(1)
 	}
@@ -223,17 +222,14 @@ func (m *pixiv) addRunCommand(command *cobra.Command) {
 						continue
 					}
 
-					slog.Info(
-						fmt.Sprintf("parsing item %s (current id: %s)", item.URI, item.CurrentItem),
-						"module", item.Module,
-					)
+					m.SetItemLogger(logging.Item(item.Module, item.ID, item.URI))
+					m.Log().Info(fmt.Sprintf("parsing item %s (current id: %s)", item.URI, item.CurrentItem))
 
 					if err := m.Parse(item); err != nil {
-						slog.Warn(
-							fmt.Sprintf("error occurred parsing item %s (%s), skipping", item.URI, err.Error()),
-							"module", item.Module,
-						)
+						m.Log().Warn(fmt.Sprintf("error occurred parsing item %s (%s), skipping", item.URI, err.Error()))
 					}
+
+					m.SetItemLogger(nil)
 				}
 			}
 		},
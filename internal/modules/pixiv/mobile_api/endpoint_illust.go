@@ -22,7 +22,11 @@ type Illustration struct {
 			Original string `json:"original"`
 		} `json:"image_urls"`
 	} `json:"meta_pages"`
-	User       UserInfo  `json:"user"`
+	User UserInfo `json:"user"`
+	Tags []struct {
+		Name           string  `json:"name"`
+		TranslatedName *string `json:"translated_name"`
+	} `json:"tags"`
 	CreateDate time.Time `json:"create_date"`
 }
 
@@ -3,10 +3,10 @@ package pixivapi
 import (
 	"crypto/md5"
 	"fmt"
+	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
 	http "github.com/bogdanfinn/fhttp"
 	"log/slog"
 	"net/url"
-	"os"
 	"strings"
 	"time"
 )
@@ -61,6 +61,12 @@ func (a *PixivAPI) Do(req *http.Request) (*http.Response, error) {
 }
 
 func (a *PixivAPI) DownloadFile(filepath string, uri string) (err error) {
+	// only record the download during dry runs without requesting the file
+	if watcherHttp.GlobalDryRun.Enabled() {
+		watcherHttp.GlobalDryRun.RecordDownload(a.moduleKey, filepath, uri)
+		return nil
+	}
+
 	for try := 1; try <= 3; try++ {
 		slog.Debug(fmt.Sprintf("downloading file: \"%s\" (uri: %s, try: %d)", filepath, uri, try), "module", a.moduleKey)
 
@@ -75,10 +81,8 @@ func (a *PixivAPI) DownloadFile(filepath string, uri string) (err error) {
 	}
 
 	if err != nil {
-		// try to clean up failed file if it exists
-		if _, statErr := os.Stat(filepath); statErr == nil {
-			_ = os.Remove(filepath)
-		}
+		// the requests require the API headers, so partial downloads are not resumed on later runs
+		watcherHttp.RemovePartialDownload(filepath)
 	}
 
 	return err
@@ -8,7 +8,6 @@ import (
 	mobileapi "github.com/DaRealFreak/watcher-go/internal/modules/pixiv/mobile_api"
 	pixivapi "github.com/DaRealFreak/watcher-go/internal/modules/pixiv/pixiv_api"
 	"github.com/DaRealFreak/watcher-go/pkg/fp"
-	"log/slog"
 )
 
 func (m *pixiv) parseUser(item *models.TrackedItem) error {
@@ -18,8 +17,8 @@ func (m *pixiv) parseUser(item *models.TrackedItem) error {
 	if err != nil {
 		switch err.(type) {
 		case pixivapi.UserUnavailableError:
-			slog.Warn(fmt.Sprintf("couldn't retrieve user details, changing artist to complete (%s)",
-				item.URI), "module", m.Key)
+			m.Log().Warn(fmt.Sprintf("couldn't retrieve user details, changing artist to complete (%s)",
+				item.URI))
 			m.DbIO.ChangeTrackedItemCompleteStatus(item, true)
 
 			return nil
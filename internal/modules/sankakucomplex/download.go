@@ -10,11 +10,10 @@ import (
 	"strings"
 	"time"
 
-	"context"
+	"github.com/DaRealFreak/watcher-go/internal/hooks"
 	"github.com/DaRealFreak/watcher-go/internal/http/tls_session"
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/DaRealFreak/watcher-go/pkg/fp"
-	"log/slog"
 )
 
 type downloadQueue struct {
@@ -84,10 +83,10 @@ func (m *sankakuComplex) downloadDownloadQueueItem(trackedItem *models.TrackedIt
 		}
 
 		if time.Now().Unix() >= expiration {
-			slog.Info(fmt.Sprintf(
+			m.Log().Info(fmt.Sprintf(
 				"links expired for uri, refreshing progress: \"%s\"",
 				trackedItem.URI,
-			), "module", m.Key)
+			))
 
 			return true, m.Parse(trackedItem)
 		}
@@ -98,9 +97,10 @@ func (m *sankakuComplex) downloadDownloadQueueItem(trackedItem *models.TrackedIt
 		galleryItem.item.FileURI,
 	)
 
-	if err != nil && galleryItem.item.FallbackFileURI != "" && galleryItem.item.FallbackFileURI != galleryItem.item.FileURI {
+	if err != nil && galleryItem.item.FallbackFileURI != "" && galleryItem.item.FallbackFileURI != galleryItem.item.FileURI &&
+		!hooks.IsAbortError(err) {
 		// fallback to resized image
-		slog.Warn(fmt.Sprintf("error occurred: %s, using fallback URI", err.Error()), "module", m.Key)
+		m.Log().Warn(fmt.Sprintf("error occurred: %s, using fallback URI", err.Error()))
 
 		galleryItem.item.FileURI = galleryItem.item.FallbackFileURI
 		ext := fp.GetFileExtension(galleryItem.item.FileName)
@@ -111,10 +111,10 @@ func (m *sankakuComplex) downloadDownloadQueueItem(trackedItem *models.TrackedIt
 	}
 
 	if expiration > 0 && time.Now().Unix() >= expiration {
-		slog.Info(fmt.Sprintf(
+		m.Log().Info(fmt.Sprintf(
 			"links expired for uri, refreshing progress: \"%s\"",
 			trackedItem.URI,
-		), "module", m.Key)
+		))
 
 		return true, m.Parse(trackedItem)
 	}
@@ -122,7 +122,7 @@ func (m *sankakuComplex) downloadDownloadQueueItem(trackedItem *models.TrackedIt
 	if e, ok := err.(tls_session.StatusError); ok && e.StatusCode == 404 &&
 		galleryItem.item.FallbackFileURI == galleryItem.item.FileURI &&
 		regexp.MustCompile(`/books\?`).MatchString(trackedItem.URI) {
-		slog.Warn(fmt.Sprintf("skipping book galleryItem: %s, status code was 404", galleryItem.item.ItemID), "module", m.Key)
+		m.Log().Warn(fmt.Sprintf("skipping book galleryItem: %s, status code was 404", galleryItem.item.ItemID))
 		return false, nil
 	}
 
@@ -130,28 +130,23 @@ func (m *sankakuComplex) downloadDownloadQueueItem(trackedItem *models.TrackedIt
 }
 
 func (m *sankakuComplex) processDownloadQueue(downloadQueue *downloadQueue, trackedItem *models.TrackedItem, notifications ...*models.Notification) error {
-	slog.Info(fmt.Sprintf("found %d new items for uri: \"%s\"", len(downloadQueue.items)+len(downloadQueue.books), trackedItem.URI), "module", m.Key)
-
-	for _, notification := range notifications {
-		slog.Log(context.Background(),
-			notification.Level, notification.Message, "module", m.Key)
-	}
+	m.ReportNewItems(trackedItem.URI, len(downloadQueue.items)+len(downloadQueue.books), notifications...)
 
 	for index, data := range downloadQueue.items {
-		slog.Info(fmt.Sprintf(
+		m.Log().Info(fmt.Sprintf(
 			"downloading updates for uri: \"%s\" (%0.2f%%)",
 			trackedItem.URI,
 			float64(index+1)/float64(len(downloadQueue.items))*100,
-		), "module", m.Key)
+		))
 
 		if expired, err := m.downloadDownloadQueueItem(trackedItem, data); expired || err != nil {
 			if err != nil {
 				if e, ok := err.(tls_session.StatusError); ok && e.StatusCode == 404 {
 					// continue on 404 errors, since they most likely won't get fixed
-					slog.Warn(fmt.Sprintf("skipping item: %s, status code was 404", data.item.ItemID), "module", m.Key)
+					m.Log().Warn(fmt.Sprintf("skipping item: %s, status code was 404", data.item.ItemID))
 				} else if m.settings.Download.SkipBrokenStreams && strings.HasPrefix(err.Error(), "stream error: stream ID") {
 					// skip broken streams if configured to do so
-					slog.Warn(fmt.Sprintf("skipping item: %s, download stream is broken", data.item.ItemID), "module", m.Key)
+					m.Log().Warn(fmt.Sprintf("skipping item: %s, download stream is broken", data.item.ItemID))
 				} else {
 					return err
 				}
@@ -171,11 +166,11 @@ func (m *sankakuComplex) processDownloadQueue(downloadQueue *downloadQueue, trac
 			return err
 		}
 
-		slog.Info(fmt.Sprintf(
+		m.Log().Info(fmt.Sprintf(
 			"downloading updates for uri: \"%s\" (%0.2f%%)",
 			trackedItem.URI,
 			float64(index+1)/float64(len(downloadQueue.books))*100,
-		), "module", m.Key)
+		))
 
 		bookLanguage := ""
 		if len(data.bookLanguage) > 0 {
@@ -8,7 +8,6 @@ import (
 
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/DaRealFreak/watcher-go/pkg/fp"
-	"log/slog"
 )
 
 // parseGallery parses galleries based on the tags in the tracked item
@@ -42,7 +41,7 @@ func (m *sankakuComplex) parseGallery(item *models.TrackedItem) (galleryItems []
 				// the canonical search is tracked by another item; nothing to do here
 				return nil, nil
 			default:
-				slog.Warn(fmt.Sprintf("first request has no results, tag probably changed for uri %s", item.URI), "module", m.Key)
+				m.Log().Warn(fmt.Sprintf("first request has no results, tag probably changed for uri %s", item.URI))
 			}
 		}
 
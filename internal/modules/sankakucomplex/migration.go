@@ -2,7 +2,6 @@ package sankakucomplex
 
 import (
 	"fmt"
-	"log/slog"
 	"net/url"
 	"strings"
 
@@ -135,7 +134,7 @@ func (m *sankakuComplex) resolveTagAlias(tag string) (canonical string, aliased
 func (m *sankakuComplex) resolveTagAliasLogged(tag string) (string, bool) {
 	canonical, aliased, err := m.resolveTagAlias(tag)
 	if err != nil {
-		slog.Debug(fmt.Sprintf("tag-and-wiki lookup failed for %q: %s", tag, err.Error()), "module", m.Key)
+		m.Log().Debug(fmt.Sprintf("tag-and-wiki lookup failed for %q: %s", tag, err.Error()))
 
 		return tag, false
 	}
@@ -170,13 +169,13 @@ func (m *sankakuComplex) applyMigratedURI(item *models.TrackedItem, newURI strin
 	}
 
 	if m.canonicalAlreadyTracked(item, newURI) {
-		slog.Info(fmt.Sprintf("canonical uri %q already tracked, removing stale item %q", newURI, item.URI), "module", m.Key)
+		m.Log().Info(fmt.Sprintf("canonical uri %q already tracked, removing stale item %q", newURI, item.URI))
 		m.DbIO.DeleteTrackedItem(item)
 
 		return tagMigrationSuperseded
 	}
 
-	slog.Info(fmt.Sprintf("migrating aliased uri %q -> %q", item.URI, newURI), "module", m.Key)
+	m.Log().Info(fmt.Sprintf("migrating aliased uri %q -> %q", item.URI, newURI))
 	m.DbIO.ChangeTrackedItemUri(item, newURI)
 
 	return tagMigrationRewritten
@@ -5,12 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log/slog"
 	"net/url"
 	"strings"
 
-	http "github.com/bogdanfinn/fhttp"
 	"github.com/DaRealFreak/watcher-go/internal/http/tls_session"
+	http "github.com/bogdanfinn/fhttp"
 )
 
 type bookDetailResponse struct {
@@ -164,7 +163,7 @@ func (m *schaleNetwork) rotateToClearProxy() error {
 	// try clearance with the current main session first
 	err := m.tryClearanceWithSession(mainSession)
 	if err == nil {
-		slog.Debug("clearance validated with current proxy", "module", m.Key)
+		m.Log().Debug("clearance validated with current proxy")
 		m.clearanceValidated = true
 		return nil
 	}
@@ -174,7 +173,7 @@ func (m *schaleNetwork) rotateToClearProxy() error {
 		return fmt.Errorf("clearance validation failed: %w", err)
 	}
 
-	slog.Warn("current proxy returned 403 on clearance", "module", m.Key)
+	m.Log().Warn("current proxy returned 403 on clearance")
 
 	// no multi-proxy available, cannot rotate
 	if !m.settings.MultiProxy || len(m.proxies) == 0 {
@@ -187,10 +186,10 @@ func (m *schaleNetwork) rotateToClearProxy() error {
 			continue
 		}
 
-		slog.Info(fmt.Sprintf("trying clearance with proxy %s", proxy.proxy.Host), "module", m.Key)
+		m.Log().Info(fmt.Sprintf("trying clearance with proxy %s", proxy.proxy.Host))
 		err = m.tryClearanceWithSession(proxy.session)
 		if err == nil {
-			slog.Info(fmt.Sprintf("clearance succeeded with proxy %s, switching main session", proxy.proxy.Host), "module", m.Key)
+			m.Log().Info(fmt.Sprintf("clearance succeeded with proxy %s, switching main session", proxy.proxy.Host))
 			if setErr := m.Session.SetProxy(&proxy.proxy); setErr != nil {
 				return fmt.Errorf("failed to switch proxy: %w", setErr)
 			}
@@ -200,13 +199,13 @@ func (m *schaleNetwork) rotateToClearProxy() error {
 		}
 
 		if m.isStatusError(err, http.StatusForbidden) {
-			slog.Warn(fmt.Sprintf("proxy %s returned 403 on clearance, excluding", proxy.proxy.Host), "module", m.Key)
+			m.Log().Warn(fmt.Sprintf("proxy %s returned 403 on clearance, excluding", proxy.proxy.Host))
 			proxy.excluded = true
 			continue
 		}
 
 		// non-403 error on this proxy, skip it
-		slog.Warn(fmt.Sprintf("proxy %s clearance failed: %s", proxy.proxy.Host, err.Error()), "module", m.Key)
+		m.Log().Warn(fmt.Sprintf("proxy %s clearance failed: %s", proxy.proxy.Host, err.Error()))
 	}
 
 	return fmt.Errorf("all proxies excluded due to 403 errors")
@@ -218,14 +217,14 @@ func (m *schaleNetwork) rotateToClearProxy() error {
 // If clearance was never validated (startup), prompt for a new crt token first.
 func (m *schaleNetwork) recoverFrom403() error {
 	if m.clearanceValidated {
-		slog.Warn("received 403 after successful clearance, trying proxy rotation first...", "module", m.Key)
+		m.Log().Warn("received 403 after successful clearance, trying proxy rotation first...")
 		if err := m.rotateToClearProxy(); err == nil {
 			return nil
 		}
 
-		slog.Warn("proxy rotation failed, requesting new crt token...", "module", m.Key)
+		m.Log().Warn("proxy rotation failed, requesting new crt token...")
 	} else {
-		slog.Warn("received 403, requesting new crt token...", "module", m.Key)
+		m.Log().Warn("received 403, requesting new crt token...")
 	}
 
 	m.promptCrtRefresh()
@@ -239,7 +238,7 @@ func (m *schaleNetwork) recoverFrom403() error {
 
 func (m *schaleNetwork) getBookData(id, key string) (*bookDataResponse, error) {
 	if m.crt == "" {
-		slog.Warn("crt token is not set, requesting token now...", "module", m.Key)
+		m.Log().Warn("crt token is not set, requesting token now...")
 		m.promptCrtRefresh()
 
 		if m.crt == "" {
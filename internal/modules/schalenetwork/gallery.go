@@ -2,7 +2,6 @@ package schalenetwork
 
 import (
 	"fmt"
-	"log/slog"
 	"path"
 	"strconv"
 
@@ -86,19 +85,15 @@ func (m *schaleNetwork) parseGalleryFromDetail(
 
 // processDownloadQueue downloads files with the required headers for the CDN
 func (m *schaleNetwork) processDownloadQueue(downloadQueue []models.DownloadQueueItem, trackedItem *models.TrackedItem) error {
-	slog.Info(
-		fmt.Sprintf("found %d new items for uri: \"%s\"", len(downloadQueue), trackedItem.URI),
-		"module", m.Key,
-	)
+	m.ReportNewItems(trackedItem.URI, len(downloadQueue))
 
 	for index, data := range downloadQueue {
-		slog.Info(
+		m.Log().Info(
 			fmt.Sprintf(
 				"downloading updates for uri: \"%s\" (%0.2f%%)",
 				trackedItem.URI,
 				float64(index+1)/float64(len(downloadQueue))*100,
 			),
-			"module", m.Key,
 		)
 
 		filePath := path.Join(
@@ -130,7 +125,7 @@ func (m *schaleNetwork) selectFormat(bookData *bookDataResponse) (bookFormat, st
 
 	for _, fmtW := range formatPriority {
 		if f, ok := bookData.Data[fmtW]; ok && f.ID.String() != "" {
-			slog.Debug(fmt.Sprintf("selected format %s", fmtW), "module", m.Key)
+			m.Log().Debug(fmt.Sprintf("selected format %s", fmtW))
 			return f, fmtW, nil
 		}
 	}
@@ -4,7 +4,6 @@ package schalenetwork
 import (
 	"bufio"
 	"fmt"
-	"log/slog"
 	"os"
 	"regexp"
 	"strings"
@@ -158,8 +157,8 @@ func (m *schaleNetwork) addCrtCommand(command *cobra.Command) {
 func (m *schaleNetwork) promptCrtRefresh() {
 	reader := bufio.NewReader(os.Stdin)
 
-	slog.Info("open one of the following URLs in your browser: https://niyaniya.moe or https://hdoujin.org", "module", m.Key)
-	slog.Info("then open DevTools (F12) -> Application -> Local Storage -> select the site and copy the \"clearance\" value", "module", m.Key)
+	m.Log().Info("open one of the following URLs in your browser: https://niyaniya.moe or https://hdoujin.org")
+	m.Log().Info("then open DevTools (F12) -> Application -> Local Storage -> select the site and copy the \"clearance\" value")
 	fmt.Print("Paste the crt token: ")
 
 	crt, _ := reader.ReadString('\n')
@@ -169,12 +168,12 @@ func (m *schaleNetwork) promptCrtRefresh() {
 	crt = strings.TrimSpace(crt)
 
 	if crt == "" {
-		slog.Warn("no token provided, keeping old token", "module", m.Key)
+		m.Log().Warn("no token provided, keeping old token")
 		return
 	}
 
-	slog.Info("the crt token is tied to your browser's User-Agent, so it must match", "module", m.Key)
-	slog.Info("find it in DevTools (F12) -> Console -> type: navigator.userAgent", "module", m.Key)
+	m.Log().Info("the crt token is tied to your browser's User-Agent, so it must match")
+	m.Log().Info("find it in DevTools (F12) -> Console -> type: navigator.userAgent")
 	fmt.Printf("Paste your browser User-Agent (leave empty to keep current): ")
 
 	ua, _ := reader.ReadString('\n')
@@ -195,7 +194,7 @@ func (m *schaleNetwork) promptCrtRefresh() {
 	}
 
 	raven.CheckError(viper.WriteConfig())
-	slog.Info("crt token saved successfully", "module", m.Key)
+	m.Log().Info("crt token saved successfully")
 }
 
 // SetCookies loads stored cookies and the crt token from the database
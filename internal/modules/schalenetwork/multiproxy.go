@@ -1,10 +1,8 @@
 package schalenetwork
 
 import (
-	"context"
 	"errors"
 	"fmt"
-	"log/slog"
 	"path"
 	"time"
 
@@ -12,10 +10,10 @@ import (
 	"github.com/DaRealFreak/watcher-go/internal/http/tls_session"
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/DaRealFreak/watcher-go/internal/raven"
+	"github.com/DaRealFreak/watcher-go/pkg/fp"
 	http "github.com/bogdanfinn/fhttp"
 	tls_client "github.com/bogdanfinn/tls-client"
 	"github.com/bogdanfinn/tls-client/profiles"
-	"github.com/DaRealFreak/watcher-go/pkg/fp"
 	"golang.org/x/time/rate"
 )
 
@@ -32,7 +30,7 @@ func (m *schaleNetwork) initializeProxySessions() {
 
 	mainSession, ok := m.Session.(*tls_session.TlsClientSession)
 	if !ok {
-		slog.Error("cannot share cookie jar: main session is not a TlsClientSession", "module", m.Key)
+		m.Log().Error("cannot share cookie jar: main session is not a TlsClientSession")
 		return
 	}
 
@@ -129,14 +127,7 @@ func (m *schaleNetwork) processDownloadQueueMultiProxy(
 	trackedItem *models.TrackedItem,
 	notifications ...*models.Notification,
 ) error {
-	slog.Info(
-		fmt.Sprintf("found %d new items for uri: \"%s\"", len(downloadQueue), trackedItem.URI),
-		"module", m.Key,
-	)
-
-	for _, notification := range notifications {
-		slog.Log(context.Background(), notification.Level, notification.Message, "module", m.Key)
-	}
+	m.ReportNewItems(trackedItem.URI, len(downloadQueue), notifications...)
 
 	for index := 0; index < len(downloadQueue); index++ {
 		data := downloadQueue[index]
@@ -150,8 +141,8 @@ func (m *schaleNetwork) processDownloadQueueMultiProxy(
 		if erroneousProxy := m.getProxyError(); erroneousProxy != nil {
 			var statusErr tls_session.StatusError
 			if errors.As(erroneousProxy.occurredError, &statusErr) && statusErr.StatusCode == 403 {
-				slog.Warn(fmt.Sprintf("proxy %s returned 403, excluding it from rotation",
-					erroneousProxy.proxy.Host), "module", m.Key)
+				m.Log().Warn(fmt.Sprintf("proxy %s returned 403, excluding it from rotation",
+					erroneousProxy.proxy.Host))
 				erroneousProxy.excluded = true
 				erroneousProxy.occurredError = nil
 				erroneousProxy.inUse = false
@@ -165,17 +156,17 @@ func (m *schaleNetwork) processDownloadQueueMultiProxy(
 				continue
 			}
 
-			slog.Warn(fmt.Sprintf("error occurred during download for proxy: %s",
-				erroneousProxy.proxy.Host), "module", m.Key)
+			m.Log().Warn(fmt.Sprintf("error occurred during download for proxy: %s",
+				erroneousProxy.proxy.Host))
 			return erroneousProxy.occurredError
 		}
 
 		if m.hasFreeProxy() {
-			slog.Info(fmt.Sprintf(
+			m.Log().Info(fmt.Sprintf(
 				"downloading updates for uri: \"%s\" (%0.2f%%)",
 				trackedItem.URI,
 				float64(index+1)/float64(len(downloadQueue))*100,
-			), "module", m.Key)
+			))
 
 			m.multiProxy.waitGroup.Add(1)
 			proxy := m.getFreeProxy()
@@ -195,13 +186,13 @@ func (m *schaleNetwork) processDownloadQueueMultiProxy(
 	if erroneousProxy := m.getProxyError(); erroneousProxy != nil {
 		var statusErr tls_session.StatusError
 		if errors.As(erroneousProxy.occurredError, &statusErr) && statusErr.StatusCode == 403 {
-			slog.Warn(fmt.Sprintf("proxy %s returned 403, excluding it from rotation",
-				erroneousProxy.proxy.Host), "module", m.Key)
+			m.Log().Warn(fmt.Sprintf("proxy %s returned 403, excluding it from rotation",
+				erroneousProxy.proxy.Host))
 			erroneousProxy.excluded = true
 			erroneousProxy.occurredError = nil
 		} else {
-			slog.Warn(fmt.Sprintf("error occurred during download for proxy: %s",
-				erroneousProxy.proxy.Host), "module", m.Key)
+			m.Log().Warn(fmt.Sprintf("error occurred during download for proxy: %s",
+				erroneousProxy.proxy.Host))
 			return erroneousProxy.occurredError
 		}
 	}
@@ -242,8 +233,8 @@ func (m *schaleNetwork) downloadItemSession(
 			}
 		}
 	} else {
-		slog.Error(fmt.Sprintf("error occurred downloading item %s (%s) with proxy %s: %s",
-			trackedItem.URI, data.ItemID, downloadSession.proxy.Host, downloadSession.occurredError.Error()), "module", m.Key)
+		m.Log().Error(fmt.Sprintf("error occurred downloading item %s (%s) with proxy %s: %s",
+			trackedItem.URI, data.ItemID, downloadSession.proxy.Host, downloadSession.occurredError.Error()))
 	}
 
 	downloadSession.inUse = false
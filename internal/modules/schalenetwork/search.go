@@ -2,7 +2,6 @@ package schalenetwork
 
 import (
 	"fmt"
-	"log/slog"
 	"net/url"
 	"regexp"
 	"strconv"
@@ -60,15 +59,14 @@ func (m *schaleNetwork) parseSearch(item *models.TrackedItem) error {
 		itemQueue[i], itemQueue[j] = itemQueue[j], itemQueue[i]
 	}
 
-	slog.Info(fmt.Sprintf("found %d new items for uri: %s", len(itemQueue), item.URI), "module", m.Key)
+	m.ReportNewItems(item.URI, len(itemQueue))
 
 	for _, entry := range itemQueue {
 		galleryURL := fmt.Sprintf("%s/g/%d/%s", m.siteBaseURL(), entry.ID, entry.Key)
 		galleryItem := m.DbIO.GetFirstOrCreateTrackedItem(galleryURL, m.getSubFolder(item), m)
 		if (m.Cfg.Run.Force || m.Cfg.Run.ResetProgress) && galleryItem.CurrentItem != "" {
-			slog.Info(
+			m.Log().Info(
 				fmt.Sprintf("resetting progress for item %s (current id: %s)", galleryItem.URI, galleryItem.CurrentItem),
-				"module", m.Key,
 			)
 			galleryItem.CurrentItem = ""
 			m.DbIO.ChangeTrackedItemCompleteStatus(galleryItem, false)
@@ -79,29 +77,27 @@ func (m *schaleNetwork) parseSearch(item *models.TrackedItem) error {
 	for index, entry := range itemQueue {
 		galleryURL := fmt.Sprintf("%s/g/%d/%s", m.siteBaseURL(), entry.ID, entry.Key)
 
-		slog.Info(fmt.Sprintf(
+		m.Log().Info(fmt.Sprintf(
 			"added gallery to tracked items: \"%s\", search item: \"%s\" (%0.2f%%)",
 			galleryURL,
 			item.URI,
 			float64(index+1)/float64(len(itemQueue))*100,
-		), "module", m.Key)
+		))
 
 		galleryItem := m.DbIO.GetFirstOrCreateTrackedItem(galleryURL, m.getSubFolder(item), m)
 
 		id := strconv.Itoa(entry.ID)
 		detail, err := m.getBookDetail(id, entry.Key)
 		if err != nil {
-			slog.Warn(
+			m.Log().Warn(
 				fmt.Sprintf("error occurred getting book detail for %s (%s), skipping", galleryURL, err.Error()),
-				"module", item.Module,
 			)
 			continue
 		}
 
 		if err = m.parseGalleryFromDetail(galleryItem, detail, id, entry.Key); err != nil {
-			slog.Warn(
+			m.Log().Warn(
 				fmt.Sprintf("error occurred parsing item %s (%s), skipping", galleryItem.URI, err.Error()),
-				"module", item.Module,
 			)
 			return err
 		}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log/slog"
 	"net/url"
 	"strings"
 
@@ -30,6 +29,7 @@ type workResponse struct {
 	Body            string    `json:"body"`
 	Genre           string    `json:"genre"`
 	Creator         skebUser  `json:"creator"`
+	Client          skebUser  `json:"client"`
 	Previews        []preview `json:"previews"`
 	OGImageURL      string    `json:"og_image_url"`
 	ArticleImageURL string    `json:"article_image_url"`
@@ -116,7 +116,7 @@ func (m *skeb) apiGet(apiURL string) ([]byte, error) {
 		}
 
 		if resp.StatusCode == http.StatusTooManyRequests {
-			slog.Warn("received 429, extracting request_key and retrying", "module", m.Key)
+			m.Log().Warn("received 429, extracting request_key and retrying")
 			m.handle429(resp, body)
 			continue
 		}
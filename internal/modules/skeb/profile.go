@@ -2,7 +2,6 @@ package skeb
 
 import (
 	"fmt"
-	"log/slog"
 	"path"
 	"strconv"
 	"strings"
@@ -19,9 +18,8 @@ type mediaItem struct {
 func (m *skeb) parseProfile(item *models.TrackedItem) error {
 	username := m.extractUsername(item.URI)
 
-	slog.Info(
+	m.Log().Info(
 		fmt.Sprintf("parsing profile \"@%s\"", username),
-		"module", m.Key,
 	)
 
 	currentNum := 0
@@ -82,27 +80,22 @@ func (m *skeb) parseProfile(item *models.TrackedItem) error {
 		newWorks[i], newWorks[j] = newWorks[j], newWorks[i]
 	}
 
-	slog.Info(
-		fmt.Sprintf("found %d new items for uri: \"%s\"", len(newWorks), item.URI),
-		"module", m.Key,
-	)
+	m.ReportNewItems(item.URI, len(newWorks))
 
 	// fetch each work individually to get previews, then download
 	for i, pw := range newWorks {
-		slog.Info(
+		m.Log().Info(
 			fmt.Sprintf(
 				"downloading updates for uri: \"%s\" (%0.2f%%)",
 				item.URI,
 				float64(i+1)/float64(len(newWorks))*100,
 			),
-			"module", m.Key,
 		)
 
 		work, err := m.getWork(pw.username, pw.postNum)
 		if err != nil {
-			slog.Warn(
+			m.Log().Warn(
 				fmt.Sprintf("failed to fetch work %s, skipping: %s", pw.postNum, err.Error()),
-				"module", m.Key,
 			)
 			continue
 		}
@@ -116,6 +109,7 @@ func (m *skeb) parseProfile(item *models.TrackedItem) error {
 			tag = work.Creator.ScreenName
 		}
 
+		metadata := m.getWorkMetadata(*work, pw.postNum)
 		items := m.extractMediaFromWork(*work)
 		for _, mi := range items {
 			filePath := path.Join(
@@ -129,6 +123,8 @@ func (m *skeb) parseProfile(item *models.TrackedItem) error {
 			if err = m.Session.DownloadFile(filePath, mi.fileURI); err != nil {
 				return err
 			}
+
+			m.SaveMetadata(filePath, mi.fileURI, metadata)
 		}
 
 		m.DbIO.UpdateTrackedItem(item, pw.postNum)
@@ -146,9 +142,8 @@ func (m *skeb) parseWork(item *models.TrackedItem) error {
 	}
 
 	if work.Private {
-		slog.Warn(
+		m.Log().Warn(
 			fmt.Sprintf("work %s is private, skipping", postNum),
-			"module", m.Key,
 		)
 		return nil
 	}
@@ -163,11 +158,9 @@ func (m *skeb) parseWork(item *models.TrackedItem) error {
 		tag = work.Creator.ScreenName
 	}
 
-	slog.Info(
-		fmt.Sprintf("found %d new items for uri: \"%s\"", len(items), item.URI),
-		"module", m.Key,
-	)
+	m.ReportNewItems(item.URI, len(items))
 
+	metadata := m.getWorkMetadata(*work, postNum)
 	for _, mi := range items {
 		filePath := path.Join(
 			m.GetDownloadDirectory(),
@@ -180,6 +173,8 @@ func (m *skeb) parseWork(item *models.TrackedItem) error {
 		if err = m.Session.DownloadFile(filePath, mi.fileURI); err != nil {
 			return err
 		}
+
+		m.SaveMetadata(filePath, mi.fileURI, metadata)
 	}
 
 	m.DbIO.UpdateTrackedItem(item, postNum)
@@ -187,6 +182,22 @@ func (m *skeb) parseWork(item *models.TrackedItem) error {
 	return nil
 }
 
+// getWorkMetadata returns the cross-module metadata of the passed work, the request is saved as description
+func (m *skeb) getWorkMetadata(work workResponse, postNum string) *models.Metadata {
+	return &models.Metadata{
+		SourceURL:   "https://skeb.jp" + work.Path,
+		PostID:      postNum,
+		Author:      work.Creator.Name,
+		AuthorID:    work.Creator.ScreenName,
+		Description: work.Body,
+		Extra: map[string]any{
+			"genre":  work.Genre,
+			"nsfw":   work.NSFW,
+			"client": work.Client.ScreenName,
+		},
+	}
+}
+
 func (m *skeb) extractUsername(uri string) string {
 	uri = strings.TrimRight(uri, "/")
 	parts := strings.Split(uri, "/")
@@ -3,7 +3,6 @@ package tapas
 import (
 	"fmt"
 	"html"
-	"log/slog"
 	"net/url"
 	"path"
 	"strings"
@@ -82,15 +81,13 @@ func (m *tapas) downloadEpisode(item *models.TrackedItem, episodeID string) (adv
 		if when == "" {
 			when = "soon"
 		}
-		slog.Warn(
+		m.Log().Warn(
 			fmt.Sprintf("episode %s is scheduled for a future release (%s), stopping", episodeID, when),
-			"module", m.Key,
 		)
 		return false, nil
 	case actionSkipAndAdvance:
-		slog.Warn(
+		m.Log().Warn(
 			fmt.Sprintf("episode %s requires payment and is not unlocked, skipping", episodeID),
-			"module", m.Key,
 		)
 		return true, nil
 	}
@@ -101,9 +98,8 @@ func (m *tapas) downloadEpisode(item *models.TrackedItem, episodeID string) (adv
 	}
 
 	if len(imageURLs) == 0 {
-		slog.Warn(
+		m.Log().Warn(
 			fmt.Sprintf("episode %s contained no downloadable images", episodeID),
-			"module", m.Key,
 		)
 		return true, nil
 	}
@@ -2,7 +2,6 @@ package tapas
 
 import (
 	"fmt"
-	"log/slog"
 	"strconv"
 
 	"github.com/DaRealFreak/watcher-go/internal/models"
@@ -38,13 +37,12 @@ func (m *tapas) parseSeries(item *models.TrackedItem) error {
 		return nil
 	}
 
-	slog.Info(
+	m.Log().Info(
 		fmt.Sprintf("found %d new episodes for series \"%s\"", len(newEpisodes), item.URI),
-		"module", m.Key,
 	)
 
 	for index, episode := range newEpisodes {
-		slog.Info(
+		m.Log().Info(
 			fmt.Sprintf(
 				"downloading episode %s (%s) for series \"%s\" (%0.2f%%)",
 				episode.ID,
@@ -52,7 +50,6 @@ func (m *tapas) parseSeries(item *models.TrackedItem) error {
 				item.URI,
 				float64(index+1)/float64(len(newEpisodes))*100,
 			),
-			"module", m.Key,
 		)
 
 		advance, err := m.downloadEpisode(item, episode.ID)
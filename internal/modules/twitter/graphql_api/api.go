@@ -90,6 +90,16 @@ func (a *TwitterGraphQlAPI) mapAPIResponse(res *http.Response, apiRes interface{
 	return nil
 }
 
+// RateLimiter returns the rate limiter of the API requests
+func (a *TwitterGraphQlAPI) RateLimiter() *rate.Limiter {
+	return a.rateLimiter
+}
+
+// SetRateLimiter replaces the rate limiter of the API requests, used to share the rate limit between multiple APIs
+func (a *TwitterGraphQlAPI) SetRateLimiter(rateLimiter *rate.Limiter) {
+	a.rateLimiter = rateLimiter
+}
+
 // applyRateLimit waits until the leaky bucket can pass another request again
 func (a *TwitterGraphQlAPI) applyRateLimit() {
 	raven.CheckError(a.rateLimiter.Wait(a.ctx))
@@ -58,8 +58,34 @@ func (t *TwitterUserTimeline) BottomCursor() string {
 	return t.Data.User.Result.Timeline.Timeline.BottomCursor()
 }
 
+// Metadata returns the cross-module metadata of the tweet
+func (tw *Tweet) Metadata() *models.Metadata {
+	tweet := tw.Item.ItemContent.TweetResults.Result.TweetData()
+	metadata := &models.Metadata{
+		PostID:      tweet.RestID.String(),
+		Description: tweet.Legacy.FullText,
+	}
+
+	if user := tweet.Core.UserResults.Result; user != nil {
+		metadata.Author = user.Core.ScreenName
+		metadata.AuthorID = user.RestID.String()
+		metadata.SourceURL = fmt.Sprintf("https://x.com/%s/status/%s", user.Core.ScreenName, tweet.RestID.String())
+	}
+
+	if tweet.Legacy.CreatedAt != nil {
+		metadata.CreatedAt = &tweet.Legacy.CreatedAt.Time
+	}
+
+	for _, hashtag := range tweet.Legacy.Entities.Hashtags {
+		metadata.Tags = append(metadata.Tags, hashtag.Text)
+	}
+
+	return metadata
+}
+
 // DownloadItems returns the normalized DownloadQueueItems from the tweet objects
 func (tw *Tweet) DownloadItems() (items []*models.DownloadQueueItem) {
+	metadata := tw.Metadata()
 	for _, mediaEntry := range tw.Item.ItemContent.TweetResults.Result.TweetData().Legacy.ExtendedEntities.Media {
 		if mediaEntry.Type == "video" || mediaEntry.Type == "animated_gif" {
 			highestBitRateIndex := 0
@@ -81,7 +107,9 @@ func (tw *Tweet) DownloadItems() (items []*models.DownloadQueueItem) {
 					len(items)+1,
 					fp.GetFileName(mediaEntry.VideoInfo.Variants[highestBitRateIndex].URL),
 				),
-				FileURI: mediaEntry.VideoInfo.Variants[highestBitRateIndex].URL,
+				FileURI:        mediaEntry.VideoInfo.Variants[highestBitRateIndex].URL,
+				Metadata:       metadata,
+				TemplateValues: map[string]string{"media_type": mediaEntry.Type},
 			})
 		} else {
 			fileType := strings.TrimLeft(fp.GetFileExtension(mediaEntry.MediaURL), ".")
@@ -95,7 +123,9 @@ func (tw *Tweet) DownloadItems() (items []*models.DownloadQueueItem) {
 					len(items)+1,
 					fp.GetFileName(mediaEntry.MediaURL),
 				),
-				FileURI: mediaEntry.MediaURL + "?format=" + fileType + "&name=orig",
+				FileURI:        mediaEntry.MediaURL + "?format=" + fileType + "&name=orig",
+				Metadata:       metadata,
+				TemplateValues: map[string]string{"media_type": mediaEntry.Type},
 			})
 		}
 	}
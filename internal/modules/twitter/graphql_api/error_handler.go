@@ -14,6 +14,10 @@ func (e DMCAError) Error() string {
 	return "content got most likely DMCAed"
 }
 
+func (e DMCAError) Gone() bool {
+	return true
+}
+
 type DeletedMediaError struct {
 }
 
@@ -21,6 +25,10 @@ func (e DeletedMediaError) Error() string {
 	return "content got deleted"
 }
 
+func (e DeletedMediaError) Gone() bool {
+	return true
+}
+
 type RateLimitError struct {
 }
 
@@ -27,7 +27,13 @@ type SingleTweet struct {
 		} `json:"user_results"`
 	} `json:"core"`
 	Legacy struct {
-		CreatedAt        *TwitterTime `json:"created_at"`
+		CreatedAt *TwitterTime `json:"created_at"`
+		FullText  string       `json:"full_text"`
+		Entities  struct {
+			Hashtags []struct {
+				Text string `json:"text"`
+			} `json:"hashtags"`
+		} `json:"entities"`
 		ExtendedEntities struct {
 			Media []struct {
 				ID        json.Number `json:"id_str"`
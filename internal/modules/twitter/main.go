@@ -12,7 +12,6 @@ import (
 	http "github.com/bogdanfinn/fhttp"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"log/slog"
 	"os"
 	"regexp"
 	"strings"
@@ -34,6 +33,20 @@ func init() {
 
 // NewBareModule returns a bare module implementation for the CLI options
 func NewBareModule() *models.Module {
+	module := newModule()
+
+	// register module to log formatter
+	formatter.AddFieldMatchColorScheme("module", &formatter.FieldMatch{
+		Value: module.Key,
+		Color: "232:39",
+	})
+
+	return module
+}
+
+// newModule returns a new instance of the module. Every worker uses its own API session
+// and waits for the API rate limiter of the module (see ShareAPIRateLimiters)
+func newModule() *models.Module {
 	module := &models.Module{
 		Key:           "twitter.com",
 		RequiresLogin: false,
@@ -44,17 +57,13 @@ func NewBareModule() *models.Module {
 			regexp.MustCompile(`twitter:(graphQL|api)/\d+/.*`),
 		},
 		SettingsSchema: twitter_settings.TwitterSettings{},
+		TemplateFields: []string{"media_type"},
 	}
 	module.ModuleInterface = &twitter{
 		Module:              module,
 		normalizedUriRegexp: regexp.MustCompile(`twitter:(graphQL|api)/\d+/.*`),
 	}
-
-	// register module to log formatter
-	formatter.AddFieldMatchColorScheme("module", &formatter.FieldMatch{
-		Value: module.Key,
-		Color: "232:39",
-	})
+	module.NewWorker = newModule
 
 	return module
 }
@@ -87,12 +96,21 @@ func (m *twitter) InitializeModule() {
 	// ToDo: guest cookie
 
 	if err := m.twitterGraphQlAPI.InitializeSession(); err != nil {
-		slog.Error(
+		m.Log().Error(
 			fmt.Sprintf("unable to initialize graphQL session: %s", err.Error()),
-			"module", m.Key,
 		)
 		os.Exit(1)
 	}
+
+	// the downloads use the API session, so the downloads of the module are attributed to this session
+	m.Session = m.twitterGraphQlAPI.Session
+}
+
+// ShareAPIRateLimiters lets the API of the passed worker wait for the API rate limiter of the module
+func (m *twitter) ShareAPIRateLimiters(worker *models.Module) {
+	if workerModule, ok := worker.ModuleInterface.(*twitter); ok && m.twitterGraphQlAPI != nil && workerModule.twitterGraphQlAPI != nil {
+		workerModule.twitterGraphQlAPI.SetRateLimiter(m.twitterGraphQlAPI.RateLimiter())
+	}
 }
 
 // AddModuleCommand adds custom module specific settings and commands to our application
@@ -112,9 +130,8 @@ func (m *twitter) Parse(item *models.TrackedItem) error {
 		if err == nil {
 			m.DbIO.ChangeTrackedItemUri(item, newUri)
 		} else {
-			slog.Warn(
+			m.Log().Warn(
 				fmt.Sprintf("unable to convert screen name to ID for URI %s (%s)", item.URI, err.Error()),
-				"module", item.Module,
 			)
 		}
 	}
@@ -143,9 +160,8 @@ func (m *twitter) AddItem(uri string) (string, error) {
 				return uri, screenNameErr
 			}
 
-			slog.Info(
+			m.Log().Info(
 				fmt.Sprintf("converting twitter username \"%s\"", screenName),
-				"module", m.Key,
 			)
 
 			userInformation, userErr := m.twitterGraphQlAPI.UserByUsername(screenName)
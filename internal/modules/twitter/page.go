@@ -9,7 +9,6 @@ import (
 
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/DaRealFreak/watcher-go/internal/modules/twitter/graphql_api"
-	"log/slog"
 )
 
 func (m *twitter) parsePageGraphQLApi(item *models.TrackedItem, screenName string) (err error) {
@@ -81,15 +80,15 @@ func (m *twitter) parsePageGraphQLApi(item *models.TrackedItem, screenName strin
 		}
 
 		if nilCount := timeline.NilItemCount(); nilCount > 0 {
-			slog.Warn(fmt.Sprintf("encountered %d nil tweet items in timeline response for user %s (uri: %s, cursor: %s)",
-				nilCount, screenName, item.URI, bottomCursor), "module", m.ModuleKey())
+			m.Log().Warn(fmt.Sprintf("encountered %d nil tweet items in timeline response for user %s (uri: %s, cursor: %s)",
+				nilCount, screenName, item.URI, bottomCursor))
 		}
 
 		tombstoneEntries := timeline.TombstoneEntries()
 		if len(tombstoneEntries) > 0 {
-			slog.Warn(fmt.Sprintf("found %d tombstone entries for user %s, check your profile for location settings and IP location, skipping",
+			m.Log().Warn(fmt.Sprintf("found %d tombstone entries for user %s, check your profile for location settings and IP location, skipping",
 				len(tombstoneEntries),
-				screenName), "module", m.ModuleKey())
+				screenName))
 			return nil
 		}
 
@@ -111,21 +110,21 @@ func (m *twitter) parsePageGraphQLApi(item *models.TrackedItem, screenName strin
 			if user.Data.User.Result.Privacy.Protected {
 				followRequestSent := user.Data.User.Result.Legacy.FollowRequestSent
 				if followRequestSent == nil || !*followRequestSent {
-					slog.Warn(fmt.Sprintf("user %s is protected, but no follow request sent, skipping",
-						screenName), "module", m.ModuleKey())
+					m.Log().Warn(fmt.Sprintf("user %s is protected, but no follow request sent, skipping",
+						screenName))
 				} else {
-					slog.Warn(fmt.Sprintf("user %s is protected, but follow request sent, waiting for approval",
-						screenName), "module", m.ModuleKey())
+					m.Log().Warn(fmt.Sprintf("user %s is protected, but follow request sent, waiting for approval",
+						screenName))
 				}
 			} else if user.Data.User.Result.TypeName != nil && *user.Data.User.Result.TypeName == "UserUnavailable" {
 				// we only want to check entries if we are not in search mode, and we should have at least one entry
-				slog.Warn(fmt.Sprintf("user %s is unavailable anymore, reason from twitter: %s",
+				m.Log().Warn(fmt.Sprintf("user %s is unavailable anymore, reason from twitter: %s",
 					screenName,
-					*user.Data.User.Result.Reason), "module", m.ModuleKey())
+					*user.Data.User.Result.Reason))
 			} else {
 				// we only want to check entries if we are not in search mode, and we should have at least one entry
-				slog.Warn(fmt.Sprintf("no tweet entries found for user %s, possibly deleted",
-					screenName), "module", m.ModuleKey())
+				m.Log().Warn(fmt.Sprintf("no tweet entries found for user %s, possibly deleted",
+					screenName))
 			}
 
 			return nil
@@ -142,9 +141,9 @@ func (m *twitter) parsePageGraphQLApi(item *models.TrackedItem, screenName strin
 						tweet.Item.ItemContent.TweetResults.Result.TweetData().Core.UserResults.Result.Core.ScreenName,
 					)
 
-					slog.Warn(fmt.Sprintf("author changed its name, updated tracked uri from \"%s\" to \"%s\"",
+					m.Log().Warn(fmt.Sprintf("author changed its name, updated tracked uri from \"%s\" to \"%s\"",
 						item.URI,
-						uri), "module", m.ModuleKey())
+						uri))
 
 					m.DbIO.ChangeTrackedItemUri(item, uri)
 				}
@@ -209,8 +208,8 @@ func (m *twitter) parsePageGraphQLApi(item *models.TrackedItem, screenName strin
 	if isNormalizedURI {
 		finalUser, finalErr := m.twitterGraphQlAPI.UserByUsername(screenName)
 		if finalErr != nil {
-			slog.Warn(fmt.Sprintf("unable to fetch user info for %s: %s",
-				screenName, finalErr.Error()), "module", m.ModuleKey())
+			m.Log().Warn(fmt.Sprintf("unable to fetch user info for %s: %s",
+				screenName, finalErr.Error()))
 		} else {
 			if applyErr := m.applyProfileResponse(item, userId, screenName, finalUser.Data.User.Result); applyErr != nil {
 				return applyErr
@@ -242,7 +241,7 @@ func (m *twitter) applyProfileResponse(item *models.TrackedItem, userId string,
 				return followErr
 			}
 
-			slog.Info(fmt.Sprintf("followed user %s", screenName), "module", m.ModuleKey())
+			m.Log().Info(fmt.Sprintf("followed user %s", screenName))
 		}
 	}
 
@@ -9,9 +9,11 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	formatter "github.com/DaRealFreak/colored-nested-formatter/v2"
+	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
 	"github.com/DaRealFreak/watcher-go/internal/http/tls_session"
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/DaRealFreak/watcher-go/internal/modules"
@@ -19,7 +21,6 @@ import (
 	"github.com/DaRealFreak/watcher-go/pkg/fp"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"log/slog"
 )
 
 type youtube struct {
@@ -72,13 +73,12 @@ func (m *youtube) InitializeModule() {
 
 	if m.settings.CookieFileLocation != "" {
 		if _, err := os.Stat(m.settings.CookieFileLocation); os.IsNotExist(err) {
-			slog.Error("the cookie file could not be stated but could not be read", "module", m.Key)
+			m.Log().Error("the cookie file could not be stated but could not be read")
 			os.Exit(1)
 		}
 	} else {
-		slog.Warn(
+		m.Log().Warn(
 			"no cookie file location is set, potentially unable to download private/adult videos",
-			"module", m.Key,
 		)
 	}
 
@@ -90,9 +90,8 @@ func (m *youtube) InitializeModule() {
 			raven.CheckError(err)
 		}
 	} else {
-		slog.Warn(
+		m.Log().Warn(
 			"no archive file location is set, it'll download videos of lists and users multiple times",
-			"module", m.Key,
 		)
 	}
 
@@ -125,7 +124,13 @@ func (m *youtube) Parse(item *models.TrackedItem) error {
 		return cmdErr
 	}
 
-	slog.Debug(fmt.Sprintf("running command: yt-dlp %s", strings.Join(args, " ")))
+	// yt-dlp resolves the file names itself, so only the output template can be recorded during dry runs
+	if watcherHttp.GlobalDryRun.Enabled() {
+		watcherHttp.GlobalDryRun.RecordDownload(m.Key, args[slices.Index(args, "-o")+1], item.URI)
+		return nil
+	}
+
+	m.Log().Debug(fmt.Sprintf("running command: yt-dlp %s", strings.Join(args, " ")))
 	_, stderr, err := executeCommand(exec.Command("yt-dlp", args...))
 	if stderr.Len() > 0 {
 		return fmt.Errorf("running command returned error: %s", stderr.String())
@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// sink types of the notification subsystem
+const (
+	// SinkTypeWebhook posts the messages as JSON to the configured URL
+	SinkTypeWebhook = "webhook"
+	// SinkTypeDiscord posts the messages to a Discord compatible webhook
+	SinkTypeDiscord = "discord"
+	// SinkTypeSlack posts the messages to a Slack compatible webhook
+	SinkTypeSlack = "slack"
+	// SinkTypeSMTP sends the messages as email
+	SinkTypeSMTP = "smtp"
+	// SinkTypeDesktop displays the messages as desktop notification using notify-send
+	SinkTypeDesktop = "desktop"
+)
+
+// defaultTimeout is the timeout for sending the messages to a sink if no timeout is configured
+const defaultTimeout = 30 * time.Second
+
+// Config holds the global "notifications" settings block.
+type Config struct {
+	Sinks []SinkConfig `mapstructure:"sinks"`
+}
+
+// SinkConfig is the configuration of a single notification sink
+type SinkConfig struct {
+	Name string `mapstructure:"name"`
+	Type string `mapstructure:"type"`
+	// Level is the minimum level of the messages sent to the sink (info, warn or error), defaults to info
+	Level string `mapstructure:"level"`
+	// Digest batches all messages of a run into a single message sent after the run
+	Digest  bool          `mapstructure:"digest"`
+	Timeout time.Duration `mapstructure:"timeout"`
+	// URL and Headers are used by the webhook, Discord and Slack sinks
+	URL     string            `mapstructure:"url"`
+	Headers map[string]string `mapstructure:"headers"`
+	// SMTP settings of the SMTP sink
+	Host     string   `mapstructure:"host"`
+	Port     int      `mapstructure:"port"`
+	Username string   `mapstructure:"username"`
+	Password string   `mapstructure:"password"`
+	From     string   `mapstructure:"from"`
+	To       []string `mapstructure:"to"`
+}
+
+// LoadConfig reads the global "notifications" config from viper. Invalid sinks are logged and skipped
+func LoadConfig() Config {
+	var cfg Config
+	if err := viper.UnmarshalKey("notifications", &cfg); err != nil {
+		slog.Warn(fmt.Sprintf("failed to parse notifications config: %v", err))
+	}
+
+	var sinks []SinkConfig
+
+	for index, sink := range cfg.Sinks {
+		if err := sink.Validate(); err != nil {
+			slog.Warn(fmt.Sprintf("skipping invalid notification sink %d: %v", index+1, err))
+			continue
+		}
+
+		sinks = append(sinks, sink)
+	}
+
+	cfg.Sinks = sinks
+
+	return cfg
+}
+
+// Validate checks if the sink is configured correctly
+func (c SinkConfig) Validate() error {
+	if _, err := ParseLevel(c.Level); err != nil {
+		return err
+	}
+
+	switch strings.ToLower(c.Type) {
+	case SinkTypeWebhook, SinkTypeDiscord, SinkTypeSlack:
+		if c.URL == "" {
+			return fmt.Errorf("%s sink requires an url", c.Type)
+		}
+	case SinkTypeSMTP:
+		if c.Host == "" || c.From == "" || len(c.To) == 0 {
+			return fmt.Errorf("smtp sink requires a host, a sender and at least one recipient")
+		}
+	case SinkTypeDesktop:
+	default:
+		return fmt.Errorf(
+			"unknown sink type \"%s\", expected %s, %s, %s, %s or %s",
+			c.Type, SinkTypeWebhook, SinkTypeDiscord, SinkTypeSlack, SinkTypeSMTP, SinkTypeDesktop,
+		)
+	}
+
+	return nil
+}
+
+// String returns the name of the sink or its type if no name is configured
+func (c SinkConfig) String() string {
+	if c.Name != "" {
+		return c.Name
+	}
+
+	return strings.ToLower(c.Type)
+}
+
+// timeout returns the configured timeout of the sink or the default timeout
+func (c SinkConfig) timeout() time.Duration {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+
+	return defaultTimeout
+}
+
+// ParseLevel parses the passed minimum level of a sink, empty levels default to info
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unknown level \"%s\", expected info, warn or error", level)
+	}
+}
@@ -0,0 +1,23 @@
+package notify
+
+import (
+	"context"
+	"log/slog"
+	"os/exec"
+)
+
+// desktopSink displays the messages as desktop notification using notify-send
+type desktopSink struct{}
+
+// Send displays the messages as a single desktop notification
+func (s *desktopSink) Send(ctx context.Context, title string, messages []Message) error {
+	urgency := "normal"
+	for _, message := range messages {
+		if message.Level >= slog.LevelError {
+			urgency = "critical"
+		}
+	}
+
+	// #nosec
+	return exec.CommandContext(ctx, "notify-send", "--app-name=watcher-go", "--urgency="+urgency, title, formatText(messages)).Run()
+}
@@ -0,0 +1,192 @@
+// Package notify sends notifications about new content and failures to the configured sinks
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is a single notification
+type Message struct {
+	Title  string     `json:"title"`
+	Text   string     `json:"text"`
+	Level  slog.Level `json:"level"`
+	Module string     `json:"module,omitempty"`
+	URI    string     `json:"uri,omitempty"`
+	Time   time.Time  `json:"time"`
+}
+
+// Sink sends messages to a notification target. Digests pass all messages of a run at once
+type Sink interface {
+	Send(ctx context.Context, title string, messages []Message) error
+}
+
+// sink is a configured sink with its level filter and digest buffer
+type sink struct {
+	cfg    SinkConfig
+	level  slog.Level
+	sender Sink
+	digest []Message
+}
+
+// Notifier dispatches messages to all configured sinks. It is safe for concurrent use
+type Notifier struct {
+	mu      sync.Mutex
+	sinks   []*sink
+	pending sync.WaitGroup
+}
+
+// NewNotifier returns a notifier for the given config.
+func NewNotifier(cfg Config) *Notifier {
+	notifier := &Notifier{}
+
+	for _, sinkCfg := range cfg.Sinks {
+		level, _ := ParseLevel(sinkCfg.Level)
+		notifier.sinks = append(notifier.sinks, &sink{cfg: sinkCfg, level: level, sender: newSender(sinkCfg)})
+	}
+
+	return notifier
+}
+
+var (
+	defaultNotifier *Notifier
+	defaultOnce     sync.Once
+)
+
+// Default returns the process-wide notifier built from the global config.
+func Default() *Notifier {
+	defaultOnce.Do(func() {
+		defaultNotifier = NewNotifier(LoadConfig())
+	})
+
+	return defaultNotifier
+}
+
+// newSender returns the sink implementation of the passed sink config
+func newSender(cfg SinkConfig) Sink {
+	switch strings.ToLower(cfg.Type) {
+	case SinkTypeDiscord, SinkTypeSlack:
+		return &chatSink{cfg: cfg}
+	case SinkTypeSMTP:
+		return &smtpSink{cfg: cfg}
+	case SinkTypeDesktop:
+		return &desktopSink{}
+	default:
+		return &webhookSink{cfg: cfg}
+	}
+}
+
+// Enabled checks if any sink is configured
+func (n *Notifier) Enabled() bool {
+	return n != nil && len(n.sinks) > 0
+}
+
+// Notify sends the passed message to all sinks accepting its level in the background to not block the caller.
+// Sinks in digest mode collect the message until the notifier gets flushed
+func (n *Notifier) Notify(message Message) {
+	if !n.Enabled() {
+		return
+	}
+
+	if message.Time.IsZero() {
+		message.Time = time.Now()
+	}
+
+	var immediate []*sink
+
+	n.mu.Lock()
+	for _, s := range n.sinks {
+		if message.Level < s.level {
+			continue
+		}
+
+		if s.cfg.Digest {
+			s.digest = append(s.digest, message)
+		} else {
+			immediate = append(immediate, s)
+		}
+	}
+	n.mu.Unlock()
+
+	for _, s := range immediate {
+		n.pending.Add(1)
+
+		go func(s *sink) {
+			defer n.pending.Done()
+
+			n.send(s, message.Title, []Message{message})
+		}(s)
+	}
+}
+
+// Wait blocks until all notifications sent in the background are completed
+func (n *Notifier) Wait() {
+	if !n.Enabled() {
+		return
+	}
+
+	n.pending.Wait()
+}
+
+// Flush waits for the notifications sent in the background and sends the collected messages
+// of all sinks in digest mode as one message per sink
+func (n *Notifier) Flush() {
+	if !n.Enabled() {
+		return
+	}
+
+	n.pending.Wait()
+
+	type digest struct {
+		sink     *sink
+		messages []Message
+	}
+
+	var digests []digest
+
+	n.mu.Lock()
+	for _, s := range n.sinks {
+		if len(s.digest) > 0 {
+			digests = append(digests, digest{sink: s, messages: s.digest})
+			s.digest = nil
+		}
+	}
+	n.mu.Unlock()
+
+	for _, d := range digests {
+		n.send(d.sink, fmt.Sprintf("watcher-go run summary (%d notifications)", len(d.messages)), d.messages)
+	}
+}
+
+// send sends the passed messages to the passed sink, failures are only logged
+func (n *Notifier) send(s *sink, title string, messages []Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.timeout())
+	defer cancel()
+
+	if err := s.sender.Send(ctx, title, messages); err != nil {
+		slog.Warn(fmt.Sprintf("unable to send notification to sink \"%s\": %s", s.cfg, err.Error()))
+	}
+}
+
+// formatText returns the messages as plain text, one message per line
+func formatText(messages []Message) string {
+	lines := make([]string, len(messages))
+	for i, message := range messages {
+		line := message.Text
+		if message.Module != "" {
+			line = fmt.Sprintf("[%s] %s", message.Module, line)
+		}
+
+		if len(messages) > 1 {
+			line = fmt.Sprintf("%s %s", strings.ToUpper(message.Level.String()), line)
+		}
+
+		lines[i] = line
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,144 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingServer records the JSON payloads posted to it
+type recordingServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	payloads []map[string]any
+	headers  []http.Header
+}
+
+func newRecordingServer(t *testing.T) *recordingServer {
+	server := &recordingServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		var payload map[string]any
+		assert.NoError(t, json.Unmarshal(content, &payload))
+
+		server.mu.Lock()
+		server.payloads = append(server.payloads, payload)
+		server.headers = append(server.headers, r.Header.Clone())
+		server.mu.Unlock()
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestNotifier_Webhook(t *testing.T) {
+	server := newRecordingServer(t)
+	notifier := NewNotifier(Config{Sinks: []SinkConfig{{
+		Type:    SinkTypeWebhook,
+		URL:     server.URL,
+		Headers: map[string]string{"Authorization": "Bearer token"},
+	}}})
+
+	notifier.Notify(Message{Title: "3 new items", Text: "found 3 new items", Level: slog.LevelInfo, Module: "test"})
+	notifier.Wait()
+
+	assert.Len(t, server.payloads, 1)
+	assert.Equal(t, "3 new items", server.payloads[0]["title"])
+	assert.Len(t, server.payloads[0]["messages"], 1)
+	assert.Equal(t, "Bearer token", server.headers[0].Get("Authorization"))
+}
+
+func TestNotifier_ChatSinks(t *testing.T) {
+	discord := newRecordingServer(t)
+	slack := newRecordingServer(t)
+	notifier := NewNotifier(Config{Sinks: []SinkConfig{
+		{Type: SinkTypeDiscord, URL: discord.URL},
+		{Type: SinkTypeSlack, URL: slack.URL},
+	}})
+
+	notifier.Notify(Message{Title: "login failed", Text: "login not successful", Level: slog.LevelError, Module: "test"})
+	notifier.Wait()
+
+	assert.Len(t, discord.payloads, 1)
+	assert.Equal(t, "**login failed**\n[test] login not successful", discord.payloads[0]["content"])
+	assert.Len(t, slack.payloads, 1)
+	assert.Equal(t, "*login failed*\n[test] login not successful", slack.payloads[0]["text"])
+}
+
+func TestNotifier_DiscordTruncation(t *testing.T) {
+	server := newRecordingServer(t)
+	notifier := NewNotifier(Config{Sinks: []SinkConfig{{Type: SinkTypeDiscord, URL: server.URL}}})
+
+	notifier.Notify(Message{Title: "long", Text: strings.Repeat("ä", 2500), Level: slog.LevelInfo})
+	notifier.Wait()
+
+	assert.Len(t, server.payloads, 1)
+
+	content := server.payloads[0]["content"].(string)
+	assert.True(t, utf8.ValidString(content))
+	assert.Equal(t, 2000, utf8.RuneCountInString(content))
+	assert.True(t, strings.HasSuffix(content, "ää..."))
+}
+
+func TestNotifier_LevelFilter(t *testing.T) {
+	server := newRecordingServer(t)
+	notifier := NewNotifier(Config{Sinks: []SinkConfig{{Type: SinkTypeWebhook, URL: server.URL, Level: "warn"}}})
+
+	notifier.Notify(Message{Title: "new items", Level: slog.LevelInfo})
+	notifier.Wait()
+	assert.Len(t, server.payloads, 0)
+
+	notifier.Notify(Message{Title: "failing item", Level: slog.LevelWarn})
+	notifier.Notify(Message{Title: "quarantined item", Level: slog.LevelError})
+	notifier.Wait()
+	assert.Len(t, server.payloads, 2)
+}
+
+func TestNotifier_Digest(t *testing.T) {
+	digest := newRecordingServer(t)
+	immediate := newRecordingServer(t)
+	notifier := NewNotifier(Config{Sinks: []SinkConfig{
+		{Type: SinkTypeDiscord, URL: digest.URL, Digest: true},
+		{Type: SinkTypeWebhook, URL: immediate.URL},
+	}})
+
+	notifier.Notify(Message{Title: "first", Text: "first message", Level: slog.LevelInfo})
+	notifier.Notify(Message{Title: "second", Text: "second message", Level: slog.LevelWarn})
+	notifier.Wait()
+
+	assert.Len(t, digest.payloads, 0)
+	assert.Len(t, immediate.payloads, 2)
+
+	notifier.Flush()
+	assert.Len(t, digest.payloads, 1)
+
+	content := digest.payloads[0]["content"].(string)
+	assert.True(t, strings.HasPrefix(content, "**watcher-go run summary (2 notifications)**"))
+	assert.Contains(t, content, "INFO first message")
+	assert.Contains(t, content, "WARN second message")
+
+	// the digest buffer is emptied after a flush
+	notifier.Flush()
+	assert.Len(t, digest.payloads, 1)
+}
+
+func TestSinkConfig_Validate(t *testing.T) {
+	assert.NoError(t, SinkConfig{Type: SinkTypeDesktop}.Validate())
+	assert.NoError(t, SinkConfig{Type: SinkTypeSMTP, Host: "localhost", From: "a@b.c", To: []string{"d@e.f"}}.Validate())
+	assert.Error(t, SinkConfig{Type: SinkTypeWebhook}.Validate())
+	assert.Error(t, SinkConfig{Type: SinkTypeSMTP, Host: "localhost"}.Validate())
+	assert.Error(t, SinkConfig{Type: "pager"}.Validate())
+	assert.Error(t, SinkConfig{Type: SinkTypeDesktop, Level: "debug"}.Validate())
+}
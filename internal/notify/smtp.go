@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// smtpSink sends the messages as plain text email
+type smtpSink struct {
+	cfg SinkConfig
+}
+
+// Send sends the messages as email to all configured recipients
+func (s *smtpSink) Send(ctx context.Context, title string, messages []Message) error {
+	port := s.cfg.Port
+	if port == 0 {
+		port = 587
+	}
+
+	address := net.JoinHostPort(s.cfg.Host, strconv.Itoa(port))
+
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+	}
+
+	mail := strings.Join([]string{
+		"From: " + s.cfg.From,
+		"To: " + strings.Join(s.cfg.To, ", "),
+		"Subject: " + title,
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		formatText(messages),
+	}, "\r\n")
+
+	// net/smtp doesn't support contexts, so the mail is sent in the background to respect the timeout
+	result := make(chan error, 1)
+	go func() {
+		result <- smtp.SendMail(address, auth, s.cfg.From, s.cfg.To, []byte(mail))
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("sending mail to %s: %w", address, ctx.Err())
+	}
+}
@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// webhookSink posts the title and messages as JSON to the configured URL
+type webhookSink struct {
+	cfg SinkConfig
+}
+
+// webhookPayload is the JSON payload of the generic webhook sink
+type webhookPayload struct {
+	Title    string    `json:"title"`
+	Messages []Message `json:"messages"`
+}
+
+// Send posts the messages to the webhook
+func (s *webhookSink) Send(ctx context.Context, title string, messages []Message) error {
+	return postJSON(ctx, s.cfg.URL, s.cfg.Headers, webhookPayload{Title: title, Messages: messages})
+}
+
+// chatSink posts the messages as text to a Discord or Slack compatible webhook
+type chatSink struct {
+	cfg SinkConfig
+}
+
+// Send posts the messages to the chat webhook
+func (s *chatSink) Send(ctx context.Context, title string, messages []Message) error {
+	text := fmt.Sprintf("**%s**\n%s", title, formatText(messages))
+
+	if strings.EqualFold(s.cfg.Type, SinkTypeSlack) {
+		return postJSON(ctx, s.cfg.URL, s.cfg.Headers, map[string]string{"text": strings.ReplaceAll(text, "**", "*")})
+	}
+
+	// Discord rejects messages longer than 2000 characters, cut on rune boundaries to keep the text valid UTF-8
+	if utf8.RuneCountInString(text) > 2000 {
+		text = string([]rune(text)[:1997]) + "..."
+	}
+
+	return postJSON(ctx, s.cfg.URL, s.cfg.Headers, map[string]string{"content": text})
+}
+
+// postJSON posts the passed payload as JSON to the passed URL
+func postJSON(ctx context.Context, url string, headers map[string]string, payload any) error {
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status code %d", res.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,309 @@
+// Package secrets encrypts credentials like passwords, OAuth tokens and cookie values
+// with AES-GCM before they get stored in the database
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/argon2"
+)
+
+// Prefix marks encrypted values, values without the prefix are treated as plaintext
+const Prefix = "enc:v1:"
+
+// KeySize is the size of the AES-256 keys
+const KeySize = 32
+
+// environment variables containing the key or the passphrase, the passphrase is intentionally not
+// supported in the configuration file since the settings are part of the backups
+const (
+	EnvKey        = "WATCHER_SECRETS_KEY"
+	EnvPassphrase = "WATCHER_SECRETS_PASSPHRASE"
+)
+
+// key derivation functions used in the encrypted values
+const (
+	kdfRaw      = "raw"
+	kdfArgon2id = "argon2id"
+)
+
+// argon2id parameters for deriving keys from passphrases
+const (
+	argon2Time    = 1
+	argon2Memory  = 64 * 1024
+	argon2Threads = 4
+	saltSize      = 16
+)
+
+// ErrNoKey is returned when encrypted values are read without a configured key
+var ErrNoKey = errors.New(
+	"secrets are encrypted but no key is configured, set " + EnvKey + ", " + EnvPassphrase + " or secrets.keyfile",
+)
+
+// Cipher encrypts and decrypts secrets. A nil cipher stores secrets in plaintext
+type Cipher struct {
+	kdf        string
+	key        []byte
+	passphrase []byte
+	salt       []byte
+
+	mu      sync.Mutex
+	derived map[string][]byte
+}
+
+// NewKeyCipher returns a cipher using the passed raw 32 byte key
+func NewKeyCipher(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("secrets key must be %d bytes long, got %d bytes", KeySize, len(key))
+	}
+
+	return &Cipher{kdf: kdfRaw, key: key}, nil
+}
+
+// NewPassphraseCipher returns a cipher deriving its keys from the passed passphrase using argon2id.
+// Every cipher encrypts with its own random salt, keys of other salts are derived on demand for decryption
+func NewPassphraseCipher(passphrase string) (*Cipher, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("secrets passphrase must not be empty")
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	c := &Cipher{kdf: kdfArgon2id, passphrase: []byte(passphrase), salt: salt, derived: make(map[string][]byte)}
+	c.key = c.deriveKey(salt)
+
+	return c, nil
+}
+
+// LoadCipher returns the cipher of the configured key source. The raw key of the WATCHER_SECRETS_KEY
+// environment variable takes precedence over the secrets.keyfile setting and the WATCHER_SECRETS_PASSPHRASE
+// environment variable. Returns nil if no key is configured
+func LoadCipher() (*Cipher, error) {
+	if key := os.Getenv(EnvKey); key != "" {
+		parsed, err := ParseKey([]byte(key))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvKey, err)
+		}
+
+		return NewKeyCipher(parsed)
+	}
+
+	if keyFile := viper.GetString("secrets.keyfile"); keyFile != "" {
+		content, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed, err := ParseKey(content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key file %s: %w", keyFile, err)
+		}
+
+		return NewKeyCipher(parsed)
+	}
+
+	if passphrase := os.Getenv(EnvPassphrase); passphrase != "" {
+		return NewPassphraseCipher(passphrase)
+	}
+
+	return nil, nil
+}
+
+var (
+	defaultCipher *Cipher
+	defaultErr    error
+	defaultOnce   sync.Once
+)
+
+// Default returns the process-wide cipher of the configured key source, nil if no key is configured.
+// Returns an error if a key source is configured but invalid, secrets must never fall back to plaintext then
+func Default() (*Cipher, error) {
+	defaultOnce.Do(func() {
+		defaultCipher, defaultErr = LoadCipher()
+		if defaultErr != nil {
+			defaultErr = fmt.Errorf("unable to load secrets key: %w", defaultErr)
+		}
+	})
+
+	return defaultCipher, defaultErr
+}
+
+// GenerateKey returns a new random key
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	_, err := rand.Read(key)
+
+	return key, err
+}
+
+// EncodeKey returns the passed key encoded as base64 for key files and the environment variable
+func EncodeKey(key []byte) string {
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+// ParseKey parses a key encoded as base64 or hex or a raw 32 byte key
+func ParseKey(content []byte) ([]byte, error) {
+	if len(content) == KeySize {
+		return content, nil
+	}
+
+	trimmed := strings.TrimSpace(string(content))
+	if decoded, err := base64.StdEncoding.DecodeString(trimmed); err == nil && len(decoded) == KeySize {
+		return decoded, nil
+	}
+
+	if decoded, err := hex.DecodeString(trimmed); err == nil && len(decoded) == KeySize {
+		return decoded, nil
+	}
+
+	return nil, fmt.Errorf("expected a base64 or hex encoded %d byte key", KeySize)
+}
+
+// IsEncrypted checks if the passed value is encrypted
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// Enabled checks if the cipher encrypts secrets
+func (c *Cipher) Enabled() bool {
+	return c != nil
+}
+
+// Encrypt encrypts the passed value. Empty and already encrypted values are returned unchanged,
+// a nil cipher returns the plaintext
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	if c == nil || plaintext == "" || IsEncrypted(plaintext) {
+		return plaintext, nil
+	}
+
+	sealed, err := seal(c.key, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+
+	parts := []string{kdfRaw, base64.StdEncoding.EncodeToString(sealed)}
+	if c.kdf == kdfArgon2id {
+		parts = []string{kdfArgon2id, base64.StdEncoding.EncodeToString(c.salt), parts[1]}
+	}
+
+	return Prefix + strings.Join(parts, ":"), nil
+}
+
+// Decrypt decrypts the passed value, plaintext values are returned unchanged
+func (c *Cipher) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	if c == nil {
+		return "", ErrNoKey
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, Prefix), ":")
+
+	var (
+		key     []byte
+		payload string
+	)
+
+	switch {
+	case parts[0] == kdfRaw && len(parts) == 2:
+		if c.kdf != kdfRaw {
+			return "", fmt.Errorf("secret is encrypted with a key, but a passphrase is configured")
+		}
+
+		key, payload = c.key, parts[1]
+	case parts[0] == kdfArgon2id && len(parts) == 3:
+		if c.kdf != kdfArgon2id {
+			return "", fmt.Errorf("secret is encrypted with a passphrase, but a key is configured")
+		}
+
+		salt, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return "", fmt.Errorf("invalid salt of encrypted secret: %w", err)
+		}
+
+		key, payload = c.deriveKey(salt), parts[2]
+	default:
+		return "", fmt.Errorf("unsupported format of encrypted secret")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", fmt.Errorf("invalid encoding of encrypted secret: %w", err)
+	}
+
+	plaintext, err := open(key, sealed)
+	if err != nil {
+		return "", fmt.Errorf("unable to decrypt secret, the configured key is most likely wrong: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// deriveKey derives the key of the passphrase for the passed salt, derived keys are cached
+func (c *Cipher) deriveKey(salt []byte) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key, ok := c.derived[string(salt)]; ok {
+		return key
+	}
+
+	key := argon2.IDKey(c.passphrase, salt, argon2Time, argon2Memory, argon2Threads, KeySize)
+	c.derived[string(salt)] = key
+
+	return key
+}
+
+// seal encrypts the passed plaintext with AES-GCM and prepends the random nonce
+func seal(key []byte, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts the passed nonce prefixed AES-GCM ciphertext
+func open(key []byte, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+
+	return gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+}
+
+// newGCM returns the AES-GCM AEAD of the passed key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
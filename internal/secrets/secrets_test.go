@@ -0,0 +1,115 @@
+package secrets
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestKeyCipher(t *testing.T) *Cipher {
+	key, err := GenerateKey()
+	assert.NoError(t, err)
+
+	c, err := NewKeyCipher(key)
+	assert.NoError(t, err)
+
+	return c
+}
+
+func TestKeyCipherRoundTrip(t *testing.T) {
+	c := newTestKeyCipher(t)
+
+	encrypted, err := c.Encrypt("hunter2")
+	assert.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+	assert.NotContains(t, encrypted, "hunter2")
+
+	// random nonces result in different ciphertexts for the same plaintext
+	again, err := c.Encrypt("hunter2")
+	assert.NoError(t, err)
+	assert.NotEqual(t, encrypted, again)
+
+	decrypted, err := c.Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", decrypted)
+
+	// already encrypted values are not encrypted twice
+	unchanged, err := c.Encrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, encrypted, unchanged)
+}
+
+func TestPassphraseCipherRoundTrip(t *testing.T) {
+	c, err := NewPassphraseCipher("correct horse battery staple")
+	assert.NoError(t, err)
+
+	encrypted, err := c.Encrypt("token")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, Prefix+kdfArgon2id+":"))
+
+	// a new cipher uses a different salt, but can still decrypt values of the old salt
+	other, err := NewPassphraseCipher("correct horse battery staple")
+	assert.NoError(t, err)
+
+	decrypted, err := other.Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "token", decrypted)
+
+	wrong, err := NewPassphraseCipher("wrong passphrase")
+	assert.NoError(t, err)
+
+	_, err = wrong.Decrypt(encrypted)
+	assert.Error(t, err)
+}
+
+func TestWrongKey(t *testing.T) {
+	encrypted, err := newTestKeyCipher(t).Encrypt("secret")
+	assert.NoError(t, err)
+
+	_, err = newTestKeyCipher(t).Decrypt(encrypted)
+	assert.Error(t, err)
+
+	_, err = NewKeyCipher([]byte("too short"))
+	assert.Error(t, err)
+}
+
+func TestNilCipher(t *testing.T) {
+	var c *Cipher
+	assert.False(t, c.Enabled())
+
+	plaintext, err := c.Encrypt("secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", plaintext)
+
+	plaintext, err = c.Decrypt("secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", plaintext)
+
+	encrypted, err := newTestKeyCipher(t).Encrypt("secret")
+	assert.NoError(t, err)
+
+	_, err = c.Decrypt(encrypted)
+	assert.ErrorIs(t, err, ErrNoKey)
+}
+
+func TestParseKey(t *testing.T) {
+	key, err := GenerateKey()
+	assert.NoError(t, err)
+
+	parsed, err := ParseKey([]byte(EncodeKey(key) + "\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, key, parsed)
+
+	parsed, err = ParseKey([]byte(hex.EncodeToString(key)))
+	assert.NoError(t, err)
+	assert.Equal(t, key, parsed)
+
+	parsed, err = ParseKey(key)
+	assert.NoError(t, err)
+	assert.Equal(t, key, parsed)
+
+	_, err = ParseKey([]byte("invalid"))
+	assert.Error(t, err)
+}
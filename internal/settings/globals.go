@@ -3,7 +3,10 @@ package settings
 import (
 	"reflect"
 
+	"github.com/DaRealFreak/watcher-go/internal/hooks"
+	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
 	"github.com/DaRealFreak/watcher-go/internal/jdownloader"
+	"github.com/DaRealFreak/watcher-go/internal/notify"
 )
 
 // globalEntries returns the loose top-level scalars manageable via `config`.
@@ -11,8 +14,28 @@ func globalEntries() []Entry {
 	str := reflect.TypeOf("")
 	return []Entry{
 		{Key: "download.directory", Type: str, Kind: KindScalar, Group: "global"},
+		{Key: "download.dedupe", Type: str, Kind: KindScalar, Group: "global"},
+		{Key: "download.sidecar", Type: str, Kind: KindScalar, Group: "global"},
+		{Key: "download.bandwidth.limit", Type: str, Kind: KindScalar, Group: "global"},
+		{Key: "download.bandwidth.windows", Type: reflect.TypeOf([]watcherHttp.BandwidthWindow{}), Kind: KindComplex, Group: "global", ReadOnly: true},
 		{Key: "database.path", Type: str, Kind: KindScalar, Group: "global"},
+		{Key: "database.driver", Type: str, Kind: KindScalar, Group: "global", Default: "sqlite3"},
+		{Key: "database.dsn", Type: str, Kind: KindScalar, Group: "global"},
+		{Key: "database.auto_migrate", Type: reflect.TypeOf(true), Kind: KindScalar, Group: "global", Default: true},
 		{Key: "watcher.sentry", Type: reflect.TypeOf(true), Kind: KindScalar, Group: "global"},
+		{Key: "daemon.schedule", Type: str, Kind: KindScalar, Group: "global"},
+		{Key: "daemon.poll_interval", Type: str, Kind: KindScalar, Group: "global"},
+		{Key: "run.failure_backoff", Type: str, Kind: KindScalar, Group: "global"},
+		{Key: "run.failure_backoff_max", Type: str, Kind: KindScalar, Group: "global"},
+		{Key: "run.quarantine_after", Type: reflect.TypeOf(0), Kind: KindScalar, Group: "global"},
+		{Key: "run.quarantine_after_strong", Type: reflect.TypeOf(0), Kind: KindScalar, Group: "global"},
+		{Key: "run.order", Type: str, Kind: KindScalar, Group: "global"},
+		{Key: "api.enabled", Type: reflect.TypeOf(true), Kind: KindScalar, Group: "global"},
+		{Key: "api.address", Type: str, Kind: KindScalar, Group: "global"},
+		{Key: "api.token", Type: str, Kind: KindScalar, Group: "global"},
+		{Key: "metrics.address", Type: str, Kind: KindScalar, Group: "global"},
+		{Key: "metrics.textfile", Type: str, Kind: KindScalar, Group: "global"},
+		{Key: "secrets.keyfile", Type: str, Kind: KindScalar, Group: "global"},
 	}
 }
 
@@ -40,3 +63,37 @@ func crawljobEntries() []Entry {
 	}
 	return out
 }
+
+// hookEntries reflects over hooks.Config to register the hooks block. The steps are lists,
+// so they are only displayed and have to be edited in the configuration file.
+func hookEntries() []Entry {
+	var out []Entry
+	for _, f := range walkSchema(hooks.Config{}) {
+		k := classify(f.Type)
+		out = append(out, Entry{
+			Key:      "hooks." + f.Path,
+			Type:     f.Type,
+			Kind:     k,
+			Group:    "hooks",
+			ReadOnly: k == KindComplex,
+		})
+	}
+	return out
+}
+
+// notificationEntries reflects over notify.Config to register the notifications block.
+// The sinks are a list, so they are only displayed and have to be edited in the configuration file.
+func notificationEntries() []Entry {
+	var out []Entry
+	for _, f := range walkSchema(notify.Config{}) {
+		k := classify(f.Type)
+		out = append(out, Entry{
+			Key:      "notifications." + f.Path,
+			Type:     f.Type,
+			Kind:     k,
+			Group:    "notifications",
+			ReadOnly: k == KindComplex,
+		})
+	}
+	return out
+}
@@ -5,6 +5,7 @@ import (
 	"sort"
 	"strings"
 
+	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
 	"github.com/DaRealFreak/watcher-go/internal/modules"
 	"github.com/spf13/viper"
 )
@@ -63,6 +64,12 @@ func Build() *Registry {
 	for _, e := range crawljobEntries() {
 		r.add(e)
 	}
+	for _, e := range hookEntries() {
+		r.add(e)
+	}
+	for _, e := range notificationEntries() {
+		r.add(e)
+	}
 
 	// Module entries, sorted by module key for stable output.
 	mods := modules.GetModuleFactory().GetAllModules()
@@ -86,6 +93,48 @@ func Build() *Registry {
 			Kind:  KindScalar,
 			Group: m.Key,
 		})
+		// per-module sidecar metadata override (not part of any schema)
+		r.add(Entry{
+			Key:   prefix + "download.sidecar",
+			Type:  reflect.TypeOf(""),
+			Kind:  KindScalar,
+			Group: m.Key,
+		})
+		// per-module download path template (not part of any schema)
+		r.add(Entry{
+			Key:   prefix + "download.template",
+			Type:  reflect.TypeOf(""),
+			Kind:  KindScalar,
+			Group: m.Key,
+		})
+		// per-module bandwidth limit, applied additionally to the global limit (not part of any schema)
+		r.add(Entry{
+			Key:   prefix + "download.bandwidth.limit",
+			Type:  reflect.TypeOf(""),
+			Kind:  KindScalar,
+			Group: m.Key,
+		})
+		r.add(Entry{
+			Key:      prefix + "download.bandwidth.windows",
+			Type:     reflect.TypeOf([]watcherHttp.BandwidthWindow{}),
+			Kind:     KindComplex,
+			Group:    m.Key,
+			ReadOnly: true,
+		})
+		// per-module daemon schedule override (not part of any schema)
+		r.add(Entry{
+			Key:   prefix + "schedule",
+			Type:  reflect.TypeOf(""),
+			Kind:  KindScalar,
+			Group: m.Key,
+		})
+		// per-module amount of items parsed at the same time, only for modules supporting workers (not part of any schema)
+		r.add(Entry{
+			Key:   prefix + "workers",
+			Type:  reflect.TypeOf(0),
+			Kind:  KindScalar,
+			Group: m.Key,
+		})
 	}
 
 	return r
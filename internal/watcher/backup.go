@@ -2,25 +2,52 @@ package watcher
 
 import (
 	"bytes"
+	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"path"
 	"runtime"
 	"strings"
 
 	"github.com/DaRealFreak/watcher-go/internal/configuration"
+	"github.com/DaRealFreak/watcher-go/internal/database"
 
 	"github.com/DaRealFreak/watcher-go/internal/raven"
 	"github.com/DaRealFreak/watcher-go/pkg/archive"
 	"github.com/DaRealFreak/watcher-go/pkg/archive/gzip"
 	"github.com/DaRealFreak/watcher-go/pkg/archive/tar"
 	"github.com/DaRealFreak/watcher-go/pkg/archive/zip"
+	// register the tar.zst format, which is only used through the format registry
+	_ "github.com/DaRealFreak/watcher-go/pkg/archive/zstd"
 	"github.com/spf13/viper"
 )
 
-// Backup backs up the full database and the configuration
+// BackupPasswordEnv is the environment variable containing the password of encrypted backups
+const BackupPasswordEnv = "WATCHER_BACKUP_PASSWORD"
+
+// Backup backs up the full database and the configuration.
+// Backups of the whole database contain a manifest, allowing incremental backups, retention and verification
 func (app *Watcher) Backup(archiveName string, cfg *configuration.AppConfiguration) {
-	writer, err := app.getArchiveWriter(archiveName, cfg)
+	archiveName = app.getArchiveName(archiveName, cfg)
+
+	var (
+		manifest *backupManifest
+		parent   *backupArchive
+		err      error
+	)
+
+	if isFullDatabaseBackup(cfg) && app.DbCon.Driver() == database.SQLiteDriver {
+		manifest, parent, err = app.newBackupManifest(archiveName, cfg)
+		raven.CheckError(err)
+	} else if cfg.Backup.Incremental || cfg.Backup.Verify {
+		raven.CheckError(fmt.Errorf("incremental and verified backups require a backup of the whole SQLite database"))
+	}
+
+	password, err := getBackupPassword(cfg)
+	raven.CheckError(err)
+
+	writer, err := app.getArchiveWriter(archiveName, password)
 	raven.CheckError(err)
 
 	if cfg.Backup.Settings {
@@ -31,25 +58,58 @@ func (app *Watcher) Backup(archiveName string, cfg *configuration.AppConfigurati
 		cfg.Backup.Database.Items.Enabled ||
 		cfg.Backup.Database.OAuth2Clients.Enabled ||
 		cfg.Backup.Database.Cookies.Enabled {
-		raven.CheckError(app.backupDatabase(writer, cfg))
+		app.warnPlaintextSecrets()
+
+		if parent != nil {
+			raven.CheckError(app.backupChanges(writer, manifest, parent))
+		} else {
+			raven.CheckError(app.backupDatabase(writer, cfg))
+		}
+
+		if manifest != nil {
+			raven.CheckError(app.backupManifest(writer, manifest))
+		}
 	}
 
 	raven.CheckError(writer.Close())
+
+	if manifest == nil {
+		return
+	}
+
+	slog.Info(fmt.Sprintf("created %s backup %s", manifest.Type, archiveName))
+	raven.CheckError(app.finishBackupChain(archiveName, cfg))
+
+	if cfg.Backup.Verify {
+		raven.CheckError(app.verifyBackup(archiveName))
+	}
 }
 
 // backupDatabase generates an SQL file for items/accounts and adds it to the archive
 // if items and accounts are exported and SQL mode is not active we just archive the db file
 func (app *Watcher) backupDatabase(writer archive.Writer, cfg *configuration.AppConfiguration) (err error) {
+	if app.DbCon.Driver() != database.SQLiteDriver {
+		return fmt.Errorf(
+			"backups of the %s database driver are not supported, use the tools of the database system instead",
+			app.DbCon.Driver(),
+		)
+	}
+
 	switch {
 	case cfg.Backup.Database.Accounts.Enabled &&
 		cfg.Backup.Database.Items.Enabled &&
 		cfg.Backup.Database.OAuth2Clients.Enabled &&
 		cfg.Backup.Database.Cookies.Enabled:
 		if cfg.Backup.Database.SQL {
-			for _, table := range []string{"accounts", "tracked_items", "oauth_clients", "cookies"} {
+			for _, table := range []string{"accounts", "tracked_items", "item_tags", "oauth_clients", "cookies"} {
 				app.backupTableAsSQL(writer, table)
 			}
 		} else {
+			// write the content of the write-ahead log into the database file before copying it
+			if err = app.DbCon.Checkpoint(); err != nil {
+				return err
+			}
+
 			_, err = writer.AddFileByPath(
 				path.Base(viper.GetString("Database.Path")),
 				viper.GetString("Database.Path"),
@@ -59,6 +119,7 @@ func (app *Watcher) backupDatabase(writer archive.Writer, cfg *configuration.App
 		app.backupTableAsSQL(writer, "accounts")
 	case cfg.Backup.Database.Items.Enabled:
 		app.backupTableAsSQL(writer, "tracked_items")
+		app.backupTableAsSQL(writer, "item_tags")
 	case cfg.Backup.Database.OAuth2Clients.Enabled:
 		app.backupTableAsSQL(writer, "oauth_clients")
 	case cfg.Backup.Database.Cookies.Enabled:
@@ -90,14 +151,14 @@ func (app *Watcher) backupSettings(writer archive.Writer, cfg *configuration.App
 	return err
 }
 
-// getArchiveWriter returns the used archive based on the passed app configuration
-func (app *Watcher) getArchiveWriter(archiveName string, cfg *configuration.AppConfiguration) (writer archive.Writer, err error) {
-	var archiveWriter archive.Writer
-
-	// retrieve the archive extension type and attach it if not already set by the user
-	archiveExt := app.getArchiveExtension(cfg)
-	if !strings.HasSuffix(archiveName, archiveExt) {
-		archiveName += archiveExt
+// getArchiveWriter returns the archive writer based on the extension of the passed archive name
+func (app *Watcher) getArchiveWriter(archiveName string, password string) (writer archive.Writer, err error) {
+	// retrieve the archive format based on the archive extension
+	format, ok := archive.GetFormatByFileName(archiveName)
+	if !ok {
+		return nil, fmt.Errorf(
+			"no archive format found for %s, supported formats: %s", archiveName, strings.Join(archive.Formats(), ", "),
+		)
 	}
 
 	// create our archive
@@ -106,21 +167,39 @@ func (app *Watcher) getArchiveWriter(archiveName string, cfg *configuration.AppC
 		return nil, err
 	}
 
-	// retrieve the archive writer based on the archive extension
-	switch archiveExt {
-	case gzip.FileExt:
-		archiveWriter = gzip.NewWriter(f)
-	case tar.FileExt:
-		archiveWriter = tar.NewWriter(f)
-	case zip.FileExt:
-		archiveWriter = zip.NewWriter(f)
+	archiveWriter, err := format.Writer(f, archive.Options{Password: password})
+	if err != nil {
+		raven.CheckClosure(f)
+		raven.CheckError(os.Remove(archiveName))
+
+		return nil, err
 	}
 
 	return archiveWriter, nil
 }
 
+// getBackupPassword returns the password from the environment if the archive should be encrypted
+func getBackupPassword(cfg *configuration.AppConfiguration) (password string, err error) {
+	if !cfg.Backup.Archive.Encrypt {
+		return "", nil
+	}
+
+	if password = os.Getenv(BackupPasswordEnv); password == "" {
+		return "", fmt.Errorf("encrypted backups require a password in the %s environment variable", BackupPasswordEnv)
+	}
+
+	return password, nil
+}
+
 // getArchiveExtension returns the archive extension based on the app configuration
 func (app *Watcher) getArchiveExtension(cfg *configuration.AppConfiguration) (ext string) {
+	if cfg.Backup.Archive.Format != "" {
+		format, err := archive.GetFormat(cfg.Backup.Archive.Format)
+		raven.CheckError(err)
+
+		return format.Extension
+	}
+
 	switch {
 	case cfg.Backup.Archive.Gzip, cfg.Backup.Archive.Tar && cfg.Backup.Archive.Zip:
 		return gzip.FileExt
@@ -128,6 +207,9 @@ func (app *Watcher) getArchiveExtension(cfg *configuration.AppConfiguration) (ex
 		return tar.FileExt
 	case cfg.Backup.Archive.Zip:
 		return zip.FileExt
+	case cfg.Backup.Archive.Encrypt:
+		// zip is the only format supporting encryption
+		return zip.FileExt
 	default:
 		// not directly passed archive type, use zip on windows, gzip on other systems
 		if runtime.GOOS == "windows" {
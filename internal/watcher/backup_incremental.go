@@ -0,0 +1,542 @@
+package watcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/configuration"
+	"github.com/DaRealFreak/watcher-go/internal/database"
+	"github.com/DaRealFreak/watcher-go/pkg/archive"
+	"github.com/spf13/viper"
+)
+
+const (
+	// backupManifestName is the file in the archive describing backups of the whole database
+	backupManifestName = "backup.json"
+	// backupChangesName is the file in the archive of incremental backups containing the changed rows
+	backupChangesName = "changes.sql"
+	// backupTimestampPlaceholder is replaced with the current time in archive names for scheduled backups
+	backupTimestampPlaceholder = "{timestamp}"
+
+	backupTypeFull        = "full"
+	backupTypeIncremental = "incremental"
+)
+
+// backupTables are the tables contained in backups of the whole database
+var backupTables = []string{"accounts", "tracked_items", "item_tags", "oauth_clients", "cookies"}
+
+// errNoBackupManifest is returned for archives without manifest, f.e. partial backups or backups of older versions
+var errNoBackupManifest = errors.New("archive contains no backup manifest")
+
+// backupManifest describes a backup of the whole database. Incremental backups reference the previous backup
+// as parent, restoring them replays the chain from the full backup up to the incremental backup
+type backupManifest struct {
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	// Parent is the file name of the previous backup in the same directory, only set for incremental backups
+	Parent string `json:"parent,omitempty"`
+	// ChangeLogPosition is the position of the change log the backup contains all changes of
+	ChangeLogPosition int64 `json:"change_log_position"`
+	SchemaVersion     int   `json:"schema_version"`
+	// Database is the file name of the database file, full backups in SQL mode contain a .sql file per table instead
+	Database string   `json:"database,omitempty"`
+	Tables   []string `json:"tables"`
+	// Checksums are the checksums of the tables at the time of the backup, used to verify the restored backup
+	Checksums map[string]string `json:"checksums"`
+}
+
+// backupArchive is an archive containing a backup manifest
+type backupArchive struct {
+	path     string
+	manifest *backupManifest
+}
+
+// getArchiveName replaces the timestamp placeholder and attaches the archive extension if not set by the user
+func (app *Watcher) getArchiveName(archiveName string, cfg *configuration.AppConfiguration) string {
+	archiveName = strings.ReplaceAll(archiveName, backupTimestampPlaceholder, time.Now().Format("20060102-150405"))
+
+	if archiveExt := app.getArchiveExtension(cfg); !strings.HasSuffix(archiveName, archiveExt) {
+		archiveName += archiveExt
+	}
+
+	return archiveName
+}
+
+// newBackupManifest returns the manifest of the new backup of the whole database. Incremental backups are based on
+// the latest backup in the directory of the archive, the full backup is used if no suitable previous backup exists
+func (app *Watcher) newBackupManifest(
+	archiveName string, cfg *configuration.AppConfiguration,
+) (manifest *backupManifest, parent *backupArchive, err error) {
+	manifest = &backupManifest{Type: backupTypeFull, CreatedAt: time.Now(), Tables: backupTables}
+
+	if manifest.ChangeLogPosition, err = app.DbCon.ChangeLogPosition(); err != nil {
+		return nil, nil, err
+	}
+
+	if manifest.SchemaVersion, err = app.DbCon.SchemaVersion(); err != nil {
+		return nil, nil, err
+	}
+
+	if !cfg.Backup.Database.SQL {
+		manifest.Database = path.Base(viper.GetString("Database.Path"))
+	}
+
+	if !cfg.Backup.Incremental {
+		return manifest, nil, nil
+	}
+
+	// incremental backups must never replace their own parent
+	if _, err = os.Stat(archiveName); err == nil {
+		return nil, nil, fmt.Errorf(
+			"archive %s already exists, use the %s placeholder for unique archive names",
+			archiveName, backupTimestampPlaceholder,
+		)
+	}
+
+	backups, err := app.listBackups(filepath.Dir(archiveName))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(backups) == 0 {
+		slog.Info(fmt.Sprintf("no previous backup found next to %s, creating a full backup", archiveName))
+		return manifest, nil, nil
+	}
+
+	latest := backups[len(backups)-1]
+
+	switch {
+	case latest.manifest.SchemaVersion != manifest.SchemaVersion:
+		slog.Info(fmt.Sprintf(
+			"database schema changed since the previous backup %s, creating a full backup", latest.path,
+		))
+	case latest.manifest.ChangeLogPosition > manifest.ChangeLogPosition:
+		// the database got restored from an older backup, the changes can't be related to the previous backup
+		slog.Warn(fmt.Sprintf(
+			"change log of the database is older than the previous backup %s, creating a full backup", latest.path,
+		))
+	default:
+		manifest.Type = backupTypeIncremental
+		manifest.Parent = filepath.Base(latest.path)
+		manifest.Database = ""
+
+		return manifest, &latest, nil
+	}
+
+	return manifest, nil, nil
+}
+
+// backupChanges adds the rows changed since the parent backup to the archive
+func (app *Watcher) backupChanges(writer archive.Writer, manifest *backupManifest, parent *backupArchive) (err error) {
+	buffer := new(bytes.Buffer)
+
+	manifest.ChangeLogPosition, err = app.DbCon.DumpChanges(
+		buffer, parent.manifest.ChangeLogPosition, parent.manifest.CreatedAt, manifest.Tables...,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.AddFile(backupChangesName, buffer.Bytes())
+
+	return err
+}
+
+// backupManifest adds the passed manifest with the current checksums of the backed up tables to the archive
+func (app *Watcher) backupManifest(writer archive.Writer, manifest *backupManifest) (err error) {
+	if manifest.Checksums, err = app.DbCon.TableChecksums(manifest.Tables...); err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.AddFile(backupManifestName, content)
+
+	return err
+}
+
+// readBackupManifest returns the backup manifest of the passed archive
+func readBackupManifest(reader archive.Reader) (*backupManifest, error) {
+	if exists, err := reader.HasFile(backupManifestName); err != nil || !exists {
+		if err == nil {
+			err = errNoBackupManifest
+		}
+
+		return nil, err
+	}
+
+	content, err := readArchiveFile(reader, backupManifestName)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := new(backupManifest)
+	if err = json.Unmarshal(content, manifest); err != nil {
+		return nil, fmt.Errorf("unable to parse backup manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// readArchiveFile returns the content of the passed file in the archive
+func readArchiveFile(reader archive.Reader, fileName string) ([]byte, error) {
+	file, err := reader.GetFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(file)
+}
+
+// readBackupArchive returns the passed archive with its backup manifest
+func (app *Watcher) readBackupArchive(archiveName string) (*backupArchive, error) {
+	reader, err := app.getArchiveReader(archiveName)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := readBackupManifest(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return &backupArchive{path: archiveName, manifest: manifest}, nil
+}
+
+// listBackups returns all archives with backup manifest in the passed directory ordered by their creation time
+func (app *Watcher) listBackups(directory string) (backups []backupArchive, err error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		backup, readErr := app.readBackupArchive(filepath.Join(directory, entry.Name()))
+		if readErr != nil {
+			var noReaderFoundError NoReaderFoundError
+			if !errors.As(readErr, &noReaderFoundError) && !errors.Is(readErr, errNoBackupManifest) {
+				slog.Warn(fmt.Sprintf("skipping unreadable archive %s: %s", entry.Name(), readErr.Error()))
+			}
+
+			continue
+		}
+
+		backups = append(backups, *backup)
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].manifest.CreatedAt.Before(backups[j].manifest.CreatedAt)
+	})
+
+	return backups, nil
+}
+
+// resolveBackupChain returns the backups required to restore the passed archive,
+// starting with the full backup followed by the incremental backups in the order they have to be applied
+func (app *Watcher) resolveBackupChain(archiveName string) (chain []backupArchive, err error) {
+	backup, err := app.readBackupArchive(archiveName)
+	if err != nil {
+		return nil, err
+	}
+
+	chain = []backupArchive{*backup}
+	visited := map[string]bool{filepath.Base(archiveName): true}
+
+	for chain[0].manifest.Type == backupTypeIncremental {
+		parent := chain[0].manifest.Parent
+		if parent == "" || visited[parent] {
+			return nil, fmt.Errorf("invalid parent of incremental backup %s", chain[0].path)
+		}
+
+		visited[parent] = true
+
+		backup, err = app.readBackupArchive(filepath.Join(filepath.Dir(chain[0].path), parent))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read parent backup %s of %s: %w", parent, chain[0].path, err)
+		}
+
+		chain = append([]backupArchive{*backup}, chain...)
+	}
+
+	return chain, nil
+}
+
+// restoreBackupChain restores the full backup of the chain of the passed incremental backup
+// and replays the changes of the following incremental backups
+func (app *Watcher) restoreBackupChain(archiveName string, cfg *configuration.AppConfiguration) error {
+	if !cfg.Restore.Database.Accounts.Enabled ||
+		!cfg.Restore.Database.Items.Enabled ||
+		!cfg.Restore.Database.OAuth2Clients.Enabled ||
+		!cfg.Restore.Database.Cookies.Enabled {
+		return fmt.Errorf("incremental backups can only be restored completely")
+	}
+
+	chain, err := app.resolveBackupChain(archiveName)
+	if err != nil {
+		return err
+	}
+
+	for i, backup := range chain {
+		reader, err := app.getArchiveReader(backup.path)
+		if err != nil {
+			return err
+		}
+
+		if i == 0 {
+			err = app.restoreDatabase(reader, cfg)
+		} else {
+			err = app.restoreTablesFromArchive(reader, backupChangesName)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		slog.Info(fmt.Sprintf("restored %s backup %s", backup.manifest.Type, backup.path))
+	}
+
+	return nil
+}
+
+// verifyBackup restores the chain of the passed backup into a temporary database
+// and compares the restored tables with the checksums of the backed up tables
+func (app *Watcher) verifyBackup(archiveName string) error {
+	chain, err := app.resolveBackupChain(archiveName)
+	if err != nil {
+		return err
+	}
+
+	directory, err := os.MkdirTemp("", "watcher-verify-*")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.RemoveAll(directory) }()
+
+	db, err := app.restoreTemporaryDatabase(filepath.Join(directory, "verify.db"), chain)
+	if err != nil {
+		return err
+	}
+
+	defer db.CloseConnection()
+
+	manifest := chain[len(chain)-1].manifest
+
+	checksums, err := db.TableChecksums(manifest.Tables...)
+	if err != nil {
+		return err
+	}
+
+	var mismatches []string
+
+	for _, table := range manifest.Tables {
+		if checksums[table] != manifest.Checksums[table] {
+			mismatches = append(mismatches, table)
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf(
+			"restored backup %s doesn't match the backed up tables: %s", archiveName, strings.Join(mismatches, ", "),
+		)
+	}
+
+	slog.Info(fmt.Sprintf("verified backup %s (restored %d archives)", archiveName, len(chain)))
+
+	return nil
+}
+
+// restoreTemporaryDatabase restores the passed backup chain into a new database at the passed path
+func (app *Watcher) restoreTemporaryDatabase(databasePath string, chain []backupArchive) (*database.DbIO, error) {
+	if base := chain[0]; base.manifest.Database != "" {
+		reader, err := app.getArchiveReader(base.path)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := readArchiveFile(reader, base.manifest.Database)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = os.WriteFile(databasePath, content, 0600); err != nil {
+			return nil, err
+		}
+	}
+
+	db, err := database.NewSQLiteConnection(databasePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = app.replayBackupChain(db, chain); err != nil {
+		db.CloseConnection()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// replayBackupChain executes the SQL files of the passed backup chain in the passed database,
+// the database file of full backups not in SQL mode has to be restored before
+func (app *Watcher) replayBackupChain(db *database.DbIO, chain []backupArchive) error {
+	for i, backup := range chain {
+		scripts := []string{backupChangesName}
+
+		if i == 0 {
+			if backup.manifest.Database != "" {
+				continue
+			}
+
+			scripts = make([]string, len(backup.manifest.Tables))
+			for j, table := range backup.manifest.Tables {
+				scripts[j] = table + ".sql"
+			}
+		}
+
+		reader, err := app.getArchiveReader(backup.path)
+		if err != nil {
+			return err
+		}
+
+		for _, script := range scripts {
+			content, err := readArchiveFile(reader, script)
+			if err == nil {
+				err = db.ExecuteScript(string(content))
+			}
+
+			if err != nil {
+				return fmt.Errorf("unable to restore %s of backup %s: %w", script, backup.path, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyBackupRetention removes the backups of the passed list not kept by the retention policy
+// and returns the remaining backups
+func applyBackupRetention(backups []backupArchive, keepDaily int, keepWeekly int) (remaining []backupArchive) {
+	retained := selectRetainedBackups(backups, keepDaily, keepWeekly)
+
+	for _, backup := range backups {
+		if retained[backup.path] {
+			remaining = append(remaining, backup)
+			continue
+		}
+
+		if err := os.Remove(backup.path); err != nil {
+			slog.Warn(fmt.Sprintf("unable to remove backup %s: %s", backup.path, err.Error()))
+			remaining = append(remaining, backup)
+
+			continue
+		}
+
+		slog.Info(fmt.Sprintf("removed backup %s due to the retention policy", backup.path))
+	}
+
+	return remaining
+}
+
+// selectRetainedBackups returns the paths of the backups kept by the retention policy. The latest backup of each of
+// the last keepDaily days and keepWeekly weeks containing backups is kept, the latest backup is always kept.
+// Incremental backups depend on all previous backups of their chain, so these are kept as well
+func selectRetainedBackups(backups []backupArchive, keepDaily int, keepWeekly int) map[string]bool {
+	retained := make(map[string]bool)
+	if len(backups) == 0 {
+		return retained
+	}
+
+	retained[backups[len(backups)-1].path] = true
+
+	retainPeriods := func(count int, period func(createdAt time.Time) string) {
+		periods := make(map[string]bool)
+
+		for i := len(backups) - 1; i >= 0 && len(periods) < count; i-- {
+			if key := period(backups[i].manifest.CreatedAt.Local()); !periods[key] {
+				periods[key] = true
+				retained[backups[i].path] = true
+			}
+		}
+	}
+
+	retainPeriods(keepDaily, func(createdAt time.Time) string {
+		return createdAt.Format("2006-01-02")
+	})
+	retainPeriods(keepWeekly, func(createdAt time.Time) string {
+		year, week := createdAt.ISOWeek()
+		return fmt.Sprintf("%d-%02d", year, week)
+	})
+
+	backupsByPath := make(map[string]backupArchive, len(backups))
+	for _, backup := range backups {
+		backupsByPath[backup.path] = backup
+	}
+
+	for _, backup := range backups {
+		if !retained[backup.path] {
+			continue
+		}
+
+		for backup.manifest.Parent != "" {
+			parent, ok := backupsByPath[filepath.Join(filepath.Dir(backup.path), backup.manifest.Parent)]
+			if !ok || retained[parent.path] {
+				break
+			}
+
+			retained[parent.path] = true
+			backup = parent
+		}
+	}
+
+	return retained
+}
+
+// finishBackupChain applies the retention policy to the backups next to the passed archive
+// and prunes the change log entries which are contained in all remaining backups
+func (app *Watcher) finishBackupChain(archiveName string, cfg *configuration.AppConfiguration) error {
+	backups, err := app.listBackups(filepath.Dir(archiveName))
+	if err != nil {
+		return err
+	}
+
+	if cfg.Backup.KeepDaily > 0 || cfg.Backup.KeepWeekly > 0 {
+		backups = applyBackupRetention(backups, cfg.Backup.KeepDaily, cfg.Backup.KeepWeekly)
+	}
+
+	if len(backups) == 0 {
+		return nil
+	}
+
+	position := backups[0].manifest.ChangeLogPosition
+	for _, backup := range backups[1:] {
+		position = min(position, backup.manifest.ChangeLogPosition)
+	}
+
+	return app.DbCon.PruneChangeLog(position)
+}
+
+// isFullDatabaseBackup returns true if all tables of the database are backed up
+func isFullDatabaseBackup(cfg *configuration.AppConfiguration) bool {
+	return cfg.Backup.Database.Accounts.Enabled &&
+		cfg.Backup.Database.Items.Enabled &&
+		cfg.Backup.Database.OAuth2Clients.Enabled &&
+		cfg.Backup.Database.Cookies.Enabled
+}
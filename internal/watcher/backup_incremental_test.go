@@ -0,0 +1,122 @@
+package watcher
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/configuration"
+	"github.com/DaRealFreak/watcher-go/internal/database"
+	"github.com/DaRealFreak/watcher-go/internal/modules"
+	"github.com/spf13/viper"
+)
+
+func TestSelectRetainedBackups(t *testing.T) {
+	day := func(days int, typ string, parent string) *backupManifest {
+		return &backupManifest{
+			Type:      typ,
+			CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local).AddDate(0, 0, days),
+			Parent:    parent,
+		}
+	}
+
+	// full backups every monday (2024-01-01 is a monday), incremental backups on the other days
+	backups := []backupArchive{
+		{path: "full-1", manifest: day(0, backupTypeFull, "")},
+		{path: "inc-1", manifest: day(1, backupTypeIncremental, "full-1")},
+		{path: "inc-2", manifest: day(2, backupTypeIncremental, "inc-1")},
+		{path: "full-2", manifest: day(7, backupTypeFull, "")},
+		{path: "inc-3", manifest: day(8, backupTypeIncremental, "full-2")},
+		{path: "full-3", manifest: day(14, backupTypeFull, "")},
+		{path: "full-4", manifest: day(14, backupTypeFull, "")},
+		{path: "inc-4", manifest: day(15, backupTypeIncremental, "full-4")},
+	}
+
+	expected := []struct {
+		daily    int
+		weekly   int
+		retained []string
+	}{
+		{0, 0, []string{"inc-4", "full-4"}},
+		{2, 0, []string{"inc-4", "full-4"}},
+		{3, 0, []string{"inc-4", "full-4", "inc-3", "full-2"}},
+		{0, 2, []string{"inc-4", "full-4", "inc-3", "full-2"}},
+		{1, 3, []string{"inc-4", "full-4", "inc-3", "full-2", "inc-2", "inc-1", "full-1"}},
+	}
+
+	for _, test := range expected {
+		retained := selectRetainedBackups(backups, test.daily, test.weekly)
+		if len(retained) != len(test.retained) {
+			t.Fatalf(
+				"expected %d retained backups for %d/%d, got %v", len(test.retained), test.daily, test.weekly, retained,
+			)
+		}
+
+		for _, backup := range test.retained {
+			if !retained[backup] {
+				t.Fatalf("expected backup %s to be retained for %d/%d, got %v", backup, test.daily, test.weekly, retained)
+			}
+		}
+	}
+}
+
+func TestIncrementalBackup(t *testing.T) {
+	for _, sqlMode := range []bool{true, false} {
+		directory := t.TempDir()
+		databasePath := filepath.Join(directory, "watcher.db")
+
+		db, err := database.NewSQLiteConnection(databasePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		viper.Set("Database.Path", databasePath)
+
+		app := &Watcher{DbCon: db}
+		module := modules.GetModuleFactory().GetAllModules()[0]
+
+		cfg := &configuration.AppConfiguration{}
+		cfg.Backup.Database.Accounts.Enabled = true
+		cfg.Backup.Database.Items.Enabled = true
+		cfg.Backup.Database.OAuth2Clients.Enabled = true
+		cfg.Backup.Database.Cookies.Enabled = true
+		cfg.Backup.Database.SQL = sqlMode
+		cfg.Backup.Archive.Zip = true
+		cfg.Backup.Incremental = true
+		cfg.Backup.Verify = true
+
+		db.GetFirstOrCreateAccount("user", "password", module)
+		item := db.GetFirstOrCreateTrackedItem("https://example.com/1", "", module)
+		db.AddTrackedItemTag(item, "nightly")
+
+		// without previous backup a full backup is created
+		app.Backup(filepath.Join(directory, "backup-1"), cfg)
+
+		db.UpdateTrackedItem(item, "42")
+		db.RemoveTrackedItemTag(item, "nightly")
+		db.GetFirstOrCreateTrackedItem("https://example.com/2", "", module)
+
+		app.Backup(filepath.Join(directory, "backup-2"), cfg)
+
+		db.DeleteTrackedItem(item)
+
+		app.Backup(filepath.Join(directory, "backup-3"), cfg)
+
+		chain, err := app.resolveBackupChain(filepath.Join(directory, "backup-3.zip"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(chain) != 3 || chain[0].manifest.Type != backupTypeFull || chain[2].manifest.Type != backupTypeIncremental {
+			t.Fatalf("expected a chain of a full and two incremental backups, got %d backups", len(chain))
+		}
+
+		if err = app.verifyBackup(chain[1].path); err != nil {
+			t.Fatal(err)
+		}
+
+		db.CloseConnection()
+	}
+
+	viper.Set("Database.Path", "")
+}
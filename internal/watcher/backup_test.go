@@ -0,0 +1,76 @@
+package watcher
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/DaRealFreak/watcher-go/internal/configuration"
+	"github.com/DaRealFreak/watcher-go/internal/database"
+	"github.com/DaRealFreak/watcher-go/internal/modules"
+	"github.com/spf13/viper"
+)
+
+func TestBackupArchiveFormats(t *testing.T) {
+	directory := t.TempDir()
+	databasePath := filepath.Join(directory, "watcher.db")
+
+	db, err := database.NewSQLiteConnection(databasePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.CloseConnection()
+
+	viper.Set("Database.Path", databasePath)
+	defer viper.Set("Database.Path", "")
+
+	app := &Watcher{DbCon: db}
+	db.GetFirstOrCreateTrackedItem("https://example.com/1", "", modules.GetModuleFactory().GetAllModules()[0])
+
+	expected := []struct {
+		name     string
+		format   string
+		encrypt  bool
+		fileName string
+	}{
+		{"backup-zstd", "tar.zst", false, "backup-zstd.tar.zst"},
+		{"backup-encrypted", "", true, "backup-encrypted.zip"},
+	}
+
+	t.Setenv(BackupPasswordEnv, "password")
+
+	for _, test := range expected {
+		cfg := &configuration.AppConfiguration{}
+		cfg.Backup.Database.Items.Enabled = true
+		cfg.Backup.Database.SQL = true
+		cfg.Backup.Archive.Format = test.format
+		cfg.Backup.Archive.Encrypt = test.encrypt
+
+		app.Backup(filepath.Join(directory, test.name), cfg)
+
+		reader, err := app.getArchiveReader(filepath.Join(directory, test.fileName))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if exists, err := reader.HasFile("tracked_items.sql"); err != nil || !exists {
+			t.Fatalf("expected tracked_items.sql in %s, got %v", test.fileName, err)
+		}
+
+		if _, err = reader.GetFile("tracked_items.sql"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// encrypted archives can't be read without the password
+	t.Setenv(BackupPasswordEnv, "")
+
+	reader, err := app.getArchiveReader(filepath.Join(directory, "backup-encrypted.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = reader.GetFile("tracked_items.sql"); err == nil {
+		t.Fatal("expected an error reading the encrypted archive without password")
+	}
+}
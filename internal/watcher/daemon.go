@@ -0,0 +1,120 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/models"
+	"github.com/spf13/viper"
+)
+
+// DefaultDaemonSchedule is the default interval between two updates of a tracked item in daemon mode
+const DefaultDaemonSchedule = 6 * time.Hour
+
+// DefaultDaemonPollInterval is the default interval in which the daemon checks for due tracked items
+const DefaultDaemonPollInterval = time.Minute
+
+// Daemon keeps the modules loaded and updates the tracked items whenever their schedule is due.
+// The schedule of an item is resolved from the item itself, the module setting and the global setting
+// in this order. The last update of every item is taken from the run history, so restarting the daemon
+// doesn't update every item again. Returns after the passed context is done and the item in progress got finished.
+func (app *Watcher) Daemon(ctx context.Context) {
+	lastRuns := app.DbCon.GetLastItemRunTimes()
+
+	slog.Info(fmt.Sprintf(
+		"starting daemon (default schedule: %s, poll interval: %s)",
+		app.getGlobalSchedule(), app.getDaemonPollInterval(),
+	))
+
+	for {
+		if dueItems := app.getDueTrackedItems(lastRuns, time.Now()); len(dueItems) > 0 {
+			slog.Info(fmt.Sprintf("%d tracked items are due for an update", len(dueItems)))
+
+			for _, item := range dueItems {
+				lastRuns[item.ID] = time.Now()
+			}
+
+			app.runItems(ctx, dueItems)
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("daemon stopped")
+			return
+		case <-time.After(app.getDaemonPollInterval()):
+		}
+	}
+}
+
+// getDueTrackedItems returns all relevant tracked items which weren't updated within their schedule
+func (app *Watcher) getDueTrackedItems(lastRuns map[int]time.Time, now time.Time) (dueItems []*models.TrackedItem) {
+	for _, item := range app.getRelevantTrackedItems() {
+		lastRun, ok := lastRuns[item.ID]
+		if !ok || !now.Before(lastRun.Add(app.getItemSchedule(item))) {
+			dueItems = append(dueItems, item)
+		}
+	}
+
+	return dueItems
+}
+
+// getItemSchedule returns the update interval of the passed item,
+// falling back to the module schedule and the global schedule if not set
+func (app *Watcher) getItemSchedule(item *models.TrackedItem) time.Duration {
+	if schedule, ok := parseSchedule(item.Schedule); ok {
+		return schedule
+	}
+
+	if module := app.ModuleFactory.GetModule(item.Module); module != nil {
+		moduleSchedule := viper.GetString(fmt.Sprintf("Modules.%s.schedule", module.GetViperModuleKey()))
+		if schedule, ok := parseSchedule(moduleSchedule); ok {
+			return schedule
+		}
+	}
+
+	return app.getGlobalSchedule()
+}
+
+// getGlobalSchedule returns the passed schedule flag, the configured daemon.schedule or the default schedule
+func (app *Watcher) getGlobalSchedule() time.Duration {
+	for _, globalSchedule := range []string{app.Cfg.Daemon.Schedule, viper.GetString("daemon.schedule")} {
+		if schedule, ok := parseSchedule(globalSchedule); ok {
+			return schedule
+		}
+	}
+
+	return DefaultDaemonSchedule
+}
+
+// getDaemonPollInterval returns the passed poll interval flag, the configured daemon.poll_interval
+// or the default poll interval
+func (app *Watcher) getDaemonPollInterval() time.Duration {
+	for _, pollInterval := range []string{app.Cfg.Daemon.PollInterval, viper.GetString("daemon.poll_interval")} {
+		if interval, ok := parseSchedule(pollInterval); ok {
+			return interval
+		}
+	}
+
+	return DefaultDaemonPollInterval
+}
+
+// parseSchedule parses the passed schedule as duration (f.e. "30m" or "12h"),
+// empty or invalid schedules are reported as not set
+func parseSchedule(schedule string) (time.Duration, bool) {
+	if schedule == "" {
+		return 0, false
+	}
+
+	duration, err := time.ParseDuration(schedule)
+	if err != nil || duration <= 0 {
+		slog.Warn(fmt.Sprintf(
+			"ignoring invalid schedule \"%s\", expected a positive duration like \"30m\" or \"12h\"", schedule,
+		))
+
+		return 0, false
+	}
+
+	return duration, true
+}
@@ -0,0 +1,53 @@
+package watcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/configuration"
+	"github.com/DaRealFreak/watcher-go/internal/models"
+	"github.com/DaRealFreak/watcher-go/internal/modules"
+	"github.com/spf13/viper"
+)
+
+// TestGetItemSchedule checks the precedence of item, module and global schedules
+func TestGetItemSchedule(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	app := &Watcher{
+		ModuleFactory: modules.GetModuleFactory(),
+		Cfg:           new(configuration.AppConfiguration),
+	}
+	module := app.ModuleFactory.GetAllModules()[0]
+	item := &models.TrackedItem{Module: module.Key}
+
+	if schedule := app.getItemSchedule(item); schedule != DefaultDaemonSchedule {
+		t.Fatalf("expected default schedule, got %s", schedule)
+	}
+
+	viper.Set("daemon.schedule", "2h")
+	if schedule := app.getItemSchedule(item); schedule != 2*time.Hour {
+		t.Fatalf("expected global schedule of 2h, got %s", schedule)
+	}
+
+	app.Cfg.Daemon.Schedule = "3h"
+	if schedule := app.getItemSchedule(item); schedule != 3*time.Hour {
+		t.Fatalf("expected schedule flag of 3h to override the setting, got %s", schedule)
+	}
+
+	viper.Set("Modules."+module.GetViperModuleKey()+".schedule", "45m")
+	if schedule := app.getItemSchedule(item); schedule != 45*time.Minute {
+		t.Fatalf("expected module schedule of 45m, got %s", schedule)
+	}
+
+	item.Schedule = "invalid"
+	if schedule := app.getItemSchedule(item); schedule != 45*time.Minute {
+		t.Fatalf("expected invalid item schedule to fall back to the module schedule, got %s", schedule)
+	}
+
+	item.Schedule = "10m"
+	if schedule := app.getItemSchedule(item); schedule != 10*time.Minute {
+		t.Fatalf("expected item schedule of 10m, got %s", schedule)
+	}
+}
@@ -0,0 +1,248 @@
+package watcher
+
+import (
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
+	"github.com/DaRealFreak/watcher-go/internal/models"
+	"github.com/DaRealFreak/watcher-go/pkg/imaging/duplication"
+)
+
+// imageExtensions contains the file extensions of the pictures added to the perceptual hash index
+var imageExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".gif":  true,
+	".webp": true,
+	".bmp":  true,
+}
+
+// DedupeScanResult contains the statistics of a content index scan
+type DedupeScanResult struct {
+	ScannedFiles    int
+	HashedFiles     int
+	RemovedEntries  int
+	DuplicateGroups int
+	DuplicateFiles  int
+	DuplicateBytes  int64
+}
+
+// SimilarImagePair contains two pictures with perceptual hashes within the threshold of each other
+type SimilarImagePair struct {
+	Path        string
+	SimilarPath string
+	Distance    int
+}
+
+// ScanContentHashes backfills the content index with the hashes of all files below the passed directory
+// and removes index entries of files which don't exist anymore.
+// Already indexed files are only hashed again if their size changed or rehash is set
+func (app *Watcher) ScanContentHashes(directory string, rehash bool) (result DedupeScanResult, err error) {
+	// the content index stores absolute paths, so the walked paths have to be absolute as well
+	if directory, err = filepath.Abs(directory); err != nil {
+		return result, err
+	}
+
+	indexedSizes := make(map[string]int64)
+	for _, fileHash := range app.DbCon.GetFileHashes() {
+		if _, statErr := os.Lstat(fileHash.Path); statErr != nil {
+			app.DbCon.DeleteFileHash(fileHash.Path)
+			result.RemovedEntries++
+
+			continue
+		}
+
+		indexedSizes[fileHash.Path] = fileHash.Size
+	}
+
+	err = filepath.WalkDir(directory, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			slog.Warn(fmt.Sprintf("unable to access \"%s\": %s", path, walkErr.Error()))
+			return nil
+		}
+
+		// symbolic links and unfinished downloads are not indexed
+		if !entry.Type().IsRegular() || isPartialDownloadFile(path) {
+			return nil
+		}
+
+		result.ScannedFiles++
+
+		info, infoErr := entry.Info()
+		if infoErr != nil {
+			return nil
+		}
+
+		if size, indexed := indexedSizes[path]; indexed && size == info.Size() && !rehash {
+			return nil
+		}
+
+		sum, size, hashErr := watcherHttp.HashFile(path)
+		if hashErr != nil {
+			slog.Warn(fmt.Sprintf("unable to hash \"%s\": %s", path, hashErr.Error()))
+			return nil
+		}
+
+		app.DbCon.AddFileHash(path, sum, size, app.getModuleKeyFromPath(directory, path))
+		result.HashedFiles++
+
+		if result.HashedFiles%1000 == 0 {
+			slog.Info(fmt.Sprintf("hashed %d files", result.HashedFiles))
+		}
+
+		return nil
+	})
+
+	groups := make(map[string][]int64)
+	for _, fileHash := range app.DbCon.GetFileHashes() {
+		key := fmt.Sprintf("%s:%d", fileHash.SHA256, fileHash.Size)
+		groups[key] = append(groups[key], fileHash.Size)
+	}
+
+	for _, sizes := range groups {
+		if len(sizes) > 1 {
+			result.DuplicateGroups++
+			result.DuplicateFiles += len(sizes) - 1
+			result.DuplicateBytes += int64(len(sizes)-1) * sizes[0]
+		}
+	}
+
+	return result, err
+}
+
+// FindSimilarImages updates the perceptual hash index with all pictures below the passed directory
+// and returns all pairs of pictures with a hamming distance of their hashes below or equal to the passed threshold.
+// Already indexed pictures are only hashed again if their size or modification time changed or rehash is set
+func (app *Watcher) FindSimilarImages(directory string, threshold int, rehash bool) (pairs []SimilarImagePair, err error) {
+	indexed := make(map[string]*models.ImageHash)
+	for _, imageHash := range app.DbCon.GetImageHashes() {
+		if _, statErr := os.Lstat(imageHash.Path); statErr != nil {
+			app.DbCon.DeleteImageHash(imageHash.Path)
+			continue
+		}
+
+		indexed[imageHash.Path] = imageHash
+	}
+
+	tree := new(duplication.HashTree)
+	hashedFiles := 0
+
+	err = filepath.WalkDir(directory, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			slog.Warn(fmt.Sprintf("unable to access \"%s\": %s", path, walkErr.Error()))
+			return nil
+		}
+
+		if !entry.Type().IsRegular() || !imageExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+
+		info, infoErr := entry.Info()
+		if infoErr != nil {
+			return nil
+		}
+
+		imageHash, ok := indexed[path]
+		if !ok || rehash || imageHash.Size != info.Size() || imageHash.ModifiedAt.Unix() != info.ModTime().Unix() {
+			hash, hashErr := duplication.HashSource(path)
+			if hashErr != nil {
+				slog.Debug(fmt.Sprintf("unable to hash picture \"%s\": %s", path, hashErr.Error()))
+				return nil
+			}
+
+			imageHash = &models.ImageHash{
+				Path:       path,
+				PHash:      hash.PHash,
+				DHash:      hash.DHash,
+				Size:       info.Size(),
+				ModifiedAt: info.ModTime(),
+				Module:     app.getModuleKeyFromPath(directory, path),
+			}
+			app.DbCon.AddImageHash(imageHash)
+
+			hashedFiles++
+			if hashedFiles%1000 == 0 {
+				slog.Info(fmt.Sprintf("hashed %d pictures", hashedFiles))
+			}
+		}
+
+		hash := duplication.ImageHash{PHash: imageHash.PHash, DHash: imageHash.DHash}
+		for _, match := range tree.Search(hash, threshold) {
+			pairs = append(pairs, SimilarImagePair{Path: match.Value, SimilarPath: path, Distance: match.Distance})
+		}
+
+		tree.Add(hash, path)
+
+		return nil
+	})
+
+	slog.Info(fmt.Sprintf("compared %d pictures, hashed %d new or changed pictures", tree.Len(), hashedFiles))
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].Distance < pairs[j].Distance
+	})
+
+	return pairs, err
+}
+
+// PrintSimilarImages prints the passed pairs of similar pictures
+func (app *Watcher) PrintSimilarImages(pairs []SimilarImagePair) {
+	if len(pairs) == 0 {
+		fmt.Println("no similar pictures found")
+		return
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+	_, _ = fmt.Fprintln(w, "Distance\tSimilarity\tFile\tSimilar File")
+
+	for _, pair := range pairs {
+		_, _ = fmt.Fprintf(
+			w, "%d\t%.1f%%\t%s\t%s\n",
+			pair.Distance, (1-float64(pair.Distance)/duplication.HashBits)*100, pair.Path, pair.SimilarPath,
+		)
+	}
+
+	_ = w.Flush()
+}
+
+// PrintDedupeScanResult prints the statistics of a content index scan
+func (app *Watcher) PrintDedupeScanResult(result DedupeScanResult) {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+	_, _ = fmt.Fprintf(w, "Scanned Files\t%d\n", result.ScannedFiles)
+	_, _ = fmt.Fprintf(w, "Hashed Files\t%d\n", result.HashedFiles)
+	_, _ = fmt.Fprintf(w, "Removed Index Entries\t%d\n", result.RemovedEntries)
+	_, _ = fmt.Fprintf(w, "Duplicate Groups\t%d\n", result.DuplicateGroups)
+	_, _ = fmt.Fprintf(w, "Duplicate Files\t%d\n", result.DuplicateFiles)
+	_, _ = fmt.Fprintf(w, "Duplicate Size\t%s\n", formatBytes(result.DuplicateBytes))
+	_ = w.Flush()
+}
+
+// getModuleKeyFromPath returns the module key of the passed path, files are downloaded into [directory]/[module key]/
+func (app *Watcher) getModuleKeyFromPath(directory string, path string) string {
+	relativePath, err := filepath.Rel(directory, path)
+	if err != nil {
+		return ""
+	}
+
+	moduleKey, _, _ := strings.Cut(filepath.ToSlash(relativePath), "/")
+	if app.ModuleFactory.GetModule(moduleKey) == nil {
+		return ""
+	}
+
+	return moduleKey
+}
+
+// isPartialDownloadFile checks if the passed path is a .part file or the resume state of an unfinished download
+func isPartialDownloadFile(path string) bool {
+	return strings.HasSuffix(path, watcherHttp.PartialFileExt) || strings.HasSuffix(path, watcherHttp.PartialFileExt+".json")
+}
@@ -0,0 +1,329 @@
+package watcher
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/database"
+	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
+	"github.com/DaRealFreak/watcher-go/internal/models"
+	"github.com/DaRealFreak/watcher-go/internal/raven"
+)
+
+// dryRunDatabase wraps the database implementation passed to the modules during dry runs.
+// All changes of the tracked items are only applied to the passed items in memory and items, accounts,
+// OAuth clients and cookies created by the modules are only kept in memory,
+// so the modules behave like in a normal run without changing the persisted data.
+type dryRunDatabase struct {
+	models.DatabaseInterface
+	mu sync.Mutex
+	// trackedItems are the tracked items created during the dry run, using negative IDs
+	// to never collide with persisted items
+	trackedItems []*models.TrackedItem
+}
+
+// newDryRunDatabase returns the dry run wrapper of the passed database implementation
+func newDryRunDatabase(db models.DatabaseInterface) *dryRunDatabase {
+	return &dryRunDatabase{DatabaseInterface: db}
+}
+
+// GetTrackedItem returns the tracked item of the passed ID, including the items created during the dry run
+func (db *dryRunDatabase) GetTrackedItem(id int) *models.TrackedItem {
+	if id >= 0 {
+		return db.DatabaseInterface.GetTrackedItem(id)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for _, item := range db.trackedItems {
+		if item.ID == id {
+			return item
+		}
+	}
+
+	return nil
+}
+
+// GetFirstOrCreateTrackedItem returns the persisted tracked item or creates the tracked item in memory
+func (db *dryRunDatabase) GetFirstOrCreateTrackedItem(
+	uri string, subFolder string, module models.ModuleInterface,
+) *models.TrackedItem {
+	for _, item := range db.DatabaseInterface.GetTrackedItems(module, true) {
+		if item.URI == uri && item.SubFolder == subFolder {
+			return item
+		}
+	}
+
+	return db.createTrackedItem(uri, subFolder, module)
+}
+
+// GetAllOrCreateTrackedItemIgnoreSubFolder returns the persisted tracked items of the passed URI
+// regardless of their sub folder or creates the tracked item without sub folder in memory
+func (db *dryRunDatabase) GetAllOrCreateTrackedItemIgnoreSubFolder(
+	uri string, module models.ModuleInterface,
+) (items []*models.TrackedItem) {
+	for _, item := range db.DatabaseInterface.GetTrackedItems(module, true) {
+		if item.URI == uri {
+			items = append(items, item)
+		}
+	}
+
+	if len(items) == 0 {
+		items = append(items, db.createTrackedItem(uri, "", module))
+	}
+
+	return items
+}
+
+// CreateTrackedItem only creates the tracked item in memory
+func (db *dryRunDatabase) CreateTrackedItem(uri string, subFolder string, module models.ModuleInterface) {
+	db.createTrackedItem(uri, subFolder, module)
+}
+
+// createTrackedItem returns the tracked item created during the dry run for the passed URI, sub folder and module
+// and creates it in memory if it doesn't exist yet
+func (db *dryRunDatabase) createTrackedItem(
+	uri string, subFolder string, module models.ModuleInterface,
+) *models.TrackedItem {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for _, item := range db.trackedItems {
+		if item.URI == uri && item.SubFolder == subFolder && item.Module == module.ModuleKey() {
+			return item
+		}
+	}
+
+	slog.Debug(fmt.Sprintf("dry run, not persisting new tracked item %s", uri), "module", module.ModuleKey())
+
+	item := &models.TrackedItem{
+		ID:        -(len(db.trackedItems) + 1),
+		URI:       uri,
+		SubFolder: subFolder,
+		Module:    module.ModuleKey(),
+	}
+	db.trackedItems = append(db.trackedItems, item)
+
+	return item
+}
+
+// UpdateTrackedItem only updates the current item of the passed tracked item in memory
+func (db *dryRunDatabase) UpdateTrackedItem(trackedItem *models.TrackedItem, currentItem string) {
+	slog.Debug(
+		fmt.Sprintf("dry run, not updating current item of %s to %s", trackedItem.URI, currentItem),
+		"module", trackedItem.Module,
+	)
+
+	trackedItem.CurrentItem = currentItem
+}
+
+// UpdateTrackedItemNotes only updates the notes of the passed tracked item in memory
+func (db *dryRunDatabase) UpdateTrackedItemNotes(trackedItem *models.TrackedItem, notes string) {
+	trackedItem.Notes = notes
+}
+
+// UpdateTrackedItemGeneratedNotes only updates the generated notes of the passed tracked item in memory
+func (db *dryRunDatabase) UpdateTrackedItemGeneratedNotes(trackedItem *models.TrackedItem, generatedNotes string) {
+	trackedItem.GeneratedNotes = generatedNotes
+}
+
+// ChangeTrackedItemUri only updates the URI of the passed tracked item in memory
+func (db *dryRunDatabase) ChangeTrackedItemUri(trackedItem *models.TrackedItem, uri string) {
+	trackedItem.URI = uri
+}
+
+// ChangeTrackedItemCompleteStatus only updates the complete status of the passed tracked item in memory
+func (db *dryRunDatabase) ChangeTrackedItemCompleteStatus(trackedItem *models.TrackedItem, complete bool) {
+	trackedItem.Complete = complete
+}
+
+// ChangeTrackedItemSubFolder only updates the sub folder of the passed tracked item in memory
+func (db *dryRunDatabase) ChangeTrackedItemSubFolder(trackedItem *models.TrackedItem, subFolder string) {
+	trackedItem.SubFolder = subFolder
+}
+
+// ChangeTrackedItemFavoriteStatus only updates the favorite status of the passed tracked item in memory
+func (db *dryRunDatabase) ChangeTrackedItemFavoriteStatus(trackedItem *models.TrackedItem, favorite bool) {
+	trackedItem.Favorite = favorite
+}
+
+// ChangeTrackedItemSchedule only updates the schedule of the passed tracked item in memory
+func (db *dryRunDatabase) ChangeTrackedItemSchedule(trackedItem *models.TrackedItem, schedule string) {
+	trackedItem.Schedule = schedule
+}
+
+// ChangeTrackedItemPriority only updates the priority of the passed tracked item in memory
+func (db *dryRunDatabase) ChangeTrackedItemPriority(trackedItem *models.TrackedItem, priority int) {
+	trackedItem.Priority = priority
+}
+
+// UpdateTrackedItemFailures only updates the failure counters of the passed tracked item in memory
+func (db *dryRunDatabase) UpdateTrackedItemFailures(trackedItem *models.TrackedItem, failures int, strongFailures int) {
+	trackedItem.Failures = failures
+	trackedItem.StrongFailures = strongFailures
+}
+
+// ChangeTrackedItemQuarantineStatus only updates the quarantine status of the passed tracked item in memory
+func (db *dryRunDatabase) ChangeTrackedItemQuarantineStatus(trackedItem *models.TrackedItem, quarantined bool) {
+	trackedItem.Quarantined = quarantined
+}
+
+// DeleteTrackedItem doesn't delete the tracked item during dry runs
+func (db *dryRunDatabase) DeleteTrackedItem(trackedItem *models.TrackedItem) {
+	slog.Debug(fmt.Sprintf("dry run, not deleting tracked item %s", trackedItem.URI), "module", trackedItem.Module)
+}
+
+// CreateAccount doesn't persist the account during dry runs
+func (db *dryRunDatabase) CreateAccount(string, string, models.ModuleInterface) {}
+
+// GetFirstOrCreateAccount returns the persisted account or an account which is only created in memory
+func (db *dryRunDatabase) GetFirstOrCreateAccount(
+	user string, password string, module models.ModuleInterface,
+) *models.Account {
+	for _, account := range db.DatabaseInterface.GetAllAccounts(module) {
+		if account.Username == user {
+			return account
+		}
+	}
+
+	return &models.Account{Username: user, Password: password, Module: module.ModuleKey()}
+}
+
+// UpdateAccount doesn't update the account during dry runs
+func (db *dryRunDatabase) UpdateAccount(string, string, models.ModuleInterface) {}
+
+// UpdateAccountDisabledStatus doesn't update the account during dry runs
+func (db *dryRunDatabase) UpdateAccountDisabledStatus(string, bool, models.ModuleInterface) {}
+
+// DeleteAccount doesn't delete the account during dry runs
+func (db *dryRunDatabase) DeleteAccount(string, models.ModuleInterface) {}
+
+// CreateOAuthClient doesn't persist the OAuth client during dry runs
+func (db *dryRunDatabase) CreateOAuthClient(string, string, string, string, models.ModuleInterface) {}
+
+// GetFirstOrCreateOAuthClient returns the persisted OAuth client or an OAuth client which is only created in memory
+func (db *dryRunDatabase) GetFirstOrCreateOAuthClient(
+	id string, secret string, accessToken string, refreshToken string, module models.ModuleInterface,
+) *models.OAuthClient {
+	for _, oAuthClient := range db.DatabaseInterface.GetAllOAuthClients(module) {
+		if oAuthClient.ClientID == id && oAuthClient.AccessToken == accessToken {
+			return oAuthClient
+		}
+	}
+
+	return &models.OAuthClient{
+		ClientID:     id,
+		ClientSecret: secret,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		Module:       module.ModuleKey(),
+	}
+}
+
+// UpdateOAuthClient doesn't update the OAuth client during dry runs
+func (db *dryRunDatabase) UpdateOAuthClient(string, string, string, string, models.ModuleInterface) {}
+
+// UpdateOAuthClientDisabledStatus doesn't update the OAuth client during dry runs
+func (db *dryRunDatabase) UpdateOAuthClientDisabledStatus(string, string, bool, models.ModuleInterface) {
+}
+
+// DeleteOAuthClient doesn't delete the OAuth client during dry runs
+func (db *dryRunDatabase) DeleteOAuthClient(string, models.ModuleInterface) {}
+
+// GetFirstOrCreateCookie returns the persisted cookie or a cookie which is only created in memory
+func (db *dryRunDatabase) GetFirstOrCreateCookie(
+	name string, value string, expirationString string, module models.ModuleInterface,
+) *models.Cookie {
+	for _, cookie := range db.DatabaseInterface.GetAllCookies(module) {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+
+	return &models.Cookie{
+		Name:       name,
+		Value:      value,
+		Expiration: database.CookieExpiration(expirationString),
+		Module:     module.ModuleKey(),
+	}
+}
+
+// CreateCookie doesn't persist the cookie during dry runs
+func (db *dryRunDatabase) CreateCookie(string, string, sql.NullTime, models.ModuleInterface) {}
+
+// UpdateCookie doesn't update the cookie during dry runs
+func (db *dryRunDatabase) UpdateCookie(string, string, string, models.ModuleInterface) {}
+
+// UpdateCookieDisabledStatus doesn't update the cookie during dry runs
+func (db *dryRunDatabase) UpdateCookieDisabledStatus(string, bool, models.ModuleInterface) {}
+
+// DeleteCookie doesn't delete the cookie during dry runs
+func (db *dryRunDatabase) DeleteCookie(string, models.ModuleInterface) {}
+
+// CreateRun returns a run which is only created in memory
+func (db *dryRunDatabase) CreateRun() *models.Run {
+	return &models.Run{StartedAt: time.Now()}
+}
+
+// FinishRun doesn't persist the run during dry runs
+func (db *dryRunDatabase) FinishRun(*models.Run) {}
+
+// CreateItemRun doesn't persist the item run during dry runs
+func (db *dryRunDatabase) CreateItemRun(*models.ItemRun) {}
+
+// AddTrackedItemTag doesn't persist the tag during dry runs
+func (db *dryRunDatabase) AddTrackedItemTag(*models.TrackedItem, string) {}
+
+// RemoveTrackedItemTag doesn't remove the tag during dry runs
+func (db *dryRunDatabase) RemoveTrackedItemTag(*models.TrackedItem, string) {}
+
+// AddFileHash doesn't add the file to the content index during dry runs
+func (db *dryRunDatabase) AddFileHash(string, string, int64, string) {}
+
+// DeleteFileHash doesn't remove the file from the content index during dry runs
+func (db *dryRunDatabase) DeleteFileHash(string) {}
+
+// AddImageHash doesn't add the image to the perceptual hash index during dry runs
+func (db *dryRunDatabase) AddImageHash(*models.ImageHash) {}
+
+// DeleteImageHash doesn't remove the image from the perceptual hash index during dry runs
+func (db *dryRunDatabase) DeleteImageHash(string) {}
+
+// printDryRun prints the recorded downloads of the dry run either as table or as JSON
+func (app *Watcher) printDryRun() {
+	downloads := watcherHttp.GlobalDryRun.Downloads()
+
+	if app.Cfg.Run.DryRunFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		raven.CheckError(encoder.Encode(downloads))
+
+		return
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+	_, _ = fmt.Fprintln(w, "Module\tItem ID\tPath\tUrl")
+
+	for _, download := range downloads {
+		_, _ = fmt.Fprintf(
+			w,
+			"%s\t%d\t%s\t%s\n",
+			download.Module,
+			download.ItemID,
+			download.Path,
+			download.URI,
+		)
+	}
+
+	_ = w.Flush()
+
+	slog.Info(fmt.Sprintf("dry run finished, %d files would have been downloaded", len(downloads)))
+}
@@ -0,0 +1,88 @@
+package watcher
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/DaRealFreak/watcher-go/internal/database"
+	"github.com/DaRealFreak/watcher-go/internal/models"
+)
+
+// searchModule creates a tracked item for every search result during parsing like the search of f.e. nhentai
+type searchModule struct {
+	*models.Module
+	results []string
+}
+
+// Parse creates the tracked items of the search results and updates the progress of the search item
+func (m *searchModule) Parse(item *models.TrackedItem) error {
+	for _, result := range m.results {
+		resultItem := m.DbIO.GetFirstOrCreateTrackedItem(result, item.SubFolder, m)
+		m.DbIO.AddTrackedItemTag(resultItem, "search")
+		m.DbIO.UpdateTrackedItem(resultItem, "1")
+	}
+
+	m.DbIO.UpdateTrackedItemFailures(item, 1, 0)
+	m.DbIO.GetFirstOrCreateCookie("session", "value", "", m)
+	m.DbIO.UpdateTrackedItem(item, m.results[len(m.results)-1])
+
+	return nil
+}
+
+func TestDryRunDatabase_CreatesItemsInMemory(t *testing.T) {
+	db, err := database.NewSQLiteConnection(filepath.Join(t.TempDir(), "watcher.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.CloseConnection()
+
+	module := &searchModule{
+		Module:  &models.Module{Key: "search.test"},
+		results: []string{"https://example.com/a", "https://example.com/b"},
+	}
+	searchItem := db.GetFirstOrCreateTrackedItem("https://example.com/search", "", module)
+
+	dryRunDb := newDryRunDatabase(db)
+	module.SetDbIO(dryRunDb)
+
+	if err = module.Parse(searchItem); err != nil {
+		t.Fatal(err)
+	}
+
+	// neither the created items nor the progress or any other write reached the database
+	items := db.GetTrackedItems(module, true)
+	if len(items) != 1 || items[0].CurrentItem != "" || items[0].Failures != 0 {
+		t.Fatalf("expected only the unchanged search item to be persisted, got %d items", len(items))
+	}
+
+	if len(db.GetAllCookies(module)) != 0 || len(db.GetTags()) != 0 {
+		t.Fatalf("expected no cookies or tags to be persisted during the dry run")
+	}
+
+	// the progress of the parsed item is still updated in memory
+	if searchItem.CurrentItem != "https://example.com/b" || searchItem.Failures != 1 {
+		t.Fatalf("expected the search item to be updated in memory, got %s", searchItem.CurrentItem)
+	}
+
+	// items created during the dry run are returned again without being created twice
+	for _, result := range module.results {
+		item := dryRunDb.GetFirstOrCreateTrackedItem(result, "", module)
+		if item.ID >= 0 || item.CurrentItem != "1" {
+			t.Fatalf("expected in-memory item with negative ID for %s, got ID %d", result, item.ID)
+		}
+
+		if dryRunDb.GetTrackedItem(item.ID) != item {
+			t.Fatalf("expected in-memory item %d to be returned by its ID", item.ID)
+		}
+	}
+
+	if len(dryRunDb.trackedItems) != len(module.results) {
+		t.Fatalf("expected %d in-memory items, got %d", len(module.results), len(dryRunDb.trackedItems))
+	}
+
+	// already persisted items are returned from the database
+	if item := dryRunDb.GetFirstOrCreateTrackedItem(searchItem.URI, "", module); item.ID != searchItem.ID {
+		t.Fatalf("expected persisted item %d, got %d", searchItem.ID, item.ID)
+	}
+}
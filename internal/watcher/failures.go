@@ -0,0 +1,155 @@
+package watcher
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
+	"github.com/DaRealFreak/watcher-go/internal/logging"
+	"github.com/DaRealFreak/watcher-go/internal/models"
+	"github.com/DaRealFreak/watcher-go/internal/notify"
+	"github.com/spf13/viper"
+)
+
+const (
+	// DefaultFailureBackoff is the skip duration after the first failure, doubled with every consecutive failure
+	DefaultFailureBackoff = time.Hour
+	// DefaultMaxFailureBackoff is the maximum skip duration of failing items
+	DefaultMaxFailureBackoff = 7 * 24 * time.Hour
+	// DefaultQuarantineAfter is the amount of consecutive failures after which an item gets quarantined
+	DefaultQuarantineAfter = 10
+	// DefaultQuarantineAfterStrong is the amount of consecutive failures indicating that the source got deleted
+	// (f.e. 404 status codes) after which an item gets quarantined
+	DefaultQuarantineAfterStrong = 3
+)
+
+// recordItemResult updates the consecutive failure counters of the passed item after it got parsed
+// and quarantines the item if it failed too often in a row
+func (app *Watcher) recordItemResult(item *models.TrackedItem, err error) {
+	if err == nil {
+		if item.Failures > 0 {
+			app.DbCon.UpdateTrackedItemFailures(item, 0, 0)
+		}
+
+		return
+	}
+
+	// interrupted runs don't say anything about the item itself
+	if classifyError(err) == "shutdown" {
+		return
+	}
+
+	// strong failures have to occur consecutively as well, any other failure resets them
+	strongFailures := 0
+	if watcherHttp.IsGoneError(err) {
+		strongFailures = item.StrongFailures + 1
+	}
+
+	app.DbCon.UpdateTrackedItemFailures(item, item.Failures+1, strongFailures)
+
+	quarantineAfter := getIntSetting("run.quarantine_after", DefaultQuarantineAfter)
+	quarantineAfterStrong := getIntSetting("run.quarantine_after_strong", DefaultQuarantineAfterStrong)
+
+	if (quarantineAfter > 0 && item.Failures >= quarantineAfter) ||
+		(quarantineAfterStrong > 0 && item.StrongFailures >= quarantineAfterStrong) {
+		slog.Warn(
+			fmt.Sprintf(
+				"quarantining item %s after %d consecutive failures, reset it with \"watcher update item reset\"",
+				item.URI, item.Failures,
+			),
+			logging.ItemAttrs(item.Module, item.ID, item.URI)...,
+		)
+		app.DbCon.ChangeTrackedItemQuarantineStatus(item, true)
+		notify.Default().Notify(notify.Message{
+			Title:  fmt.Sprintf("quarantined item %s", item.URI),
+			Text:   fmt.Sprintf("quarantined item %s after %d consecutive failures: %s", item.URI, item.Failures, err.Error()),
+			Level:  slog.LevelError,
+			Module: item.Module,
+			URI:    item.URI,
+		})
+
+		return
+	}
+
+	slog.Info(
+		fmt.Sprintf(
+			"item %s failed %d times in a row, skipping it for %s",
+			item.URI, item.Failures, getFailureBackoff(item.Failures),
+		),
+		logging.ItemAttrs(item.Module, item.ID, item.URI)...,
+	)
+	notify.Default().Notify(notify.Message{
+		Title:  fmt.Sprintf("item %s failed", item.URI),
+		Text:   fmt.Sprintf("item %s failed %d times in a row: %s", item.URI, item.Failures, err.Error()),
+		Level:  slog.LevelWarn,
+		Module: item.Module,
+		URI:    item.URI,
+	})
+}
+
+// filterBackedOffItems removes all items which failed recently and are still within their backoff duration
+func (app *Watcher) filterBackedOffItems(trackedItems []*models.TrackedItem, now time.Time) (results []*models.TrackedItem) {
+	for _, item := range trackedItems {
+		if isBackingOff(item, now) {
+			slog.Debug(
+				fmt.Sprintf(
+					"skipping item %s after %d consecutive failures until %s",
+					item.URI, item.Failures,
+					item.LastFailure.Time.Add(getFailureBackoff(item.Failures)).Local().Format(time.DateTime),
+				),
+				"module", item.Module,
+			)
+
+			continue
+		}
+
+		results = append(results, item)
+	}
+
+	return results
+}
+
+// isBackingOff returns true if the passed item failed and the backoff duration since the last failure didn't pass yet
+func isBackingOff(item *models.TrackedItem, now time.Time) bool {
+	if item.Failures == 0 || !item.LastFailure.Valid {
+		return false
+	}
+
+	return now.Before(item.LastFailure.Time.Add(getFailureBackoff(item.Failures)))
+}
+
+// getFailureBackoff returns the exponential backoff duration for the passed amount of consecutive failures,
+// starting at run.failure_backoff and capped at run.failure_backoff_max
+func getFailureBackoff(failures int) time.Duration {
+	backoff := getDurationSetting("run.failure_backoff", DefaultFailureBackoff)
+	maxBackoff := getDurationSetting("run.failure_backoff_max", DefaultMaxFailureBackoff)
+
+	for i := 1; i < failures && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, maxBackoff)
+}
+
+// getDurationSetting returns the configured duration of the passed key or the default value if not set or invalid
+func getDurationSetting(key string, defaultValue time.Duration) time.Duration {
+	if value := viper.GetString(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+			return duration
+		}
+
+		slog.Warn(fmt.Sprintf("ignoring invalid duration \"%s\" of setting %s", value, key))
+	}
+
+	return defaultValue
+}
+
+// getIntSetting returns the configured integer of the passed key or the default value if not set
+func getIntSetting(key string, defaultValue int) int {
+	if viper.IsSet(key) {
+		return viper.GetInt(key)
+	}
+
+	return defaultValue
+}
@@ -0,0 +1,134 @@
+package watcher
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/database"
+	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
+	"github.com/DaRealFreak/watcher-go/internal/http/tls_session"
+	"github.com/DaRealFreak/watcher-go/internal/models"
+	"github.com/DaRealFreak/watcher-go/internal/modules"
+	"github.com/DaRealFreak/watcher-go/internal/modules/chounyuu"
+	"github.com/DaRealFreak/watcher-go/internal/modules/twitter/graphql_api"
+	"github.com/spf13/viper"
+)
+
+func TestGetFailureBackoff(t *testing.T) {
+	viper.Set("run.failure_backoff", "1h")
+	viper.Set("run.failure_backoff_max", "10h")
+
+	defer func() {
+		viper.Set("run.failure_backoff", "")
+		viper.Set("run.failure_backoff_max", "")
+	}()
+
+	expected := map[int]time.Duration{
+		1: time.Hour,
+		2: 2 * time.Hour,
+		3: 4 * time.Hour,
+		4: 8 * time.Hour,
+		5: 10 * time.Hour,
+		6: 10 * time.Hour,
+	}
+
+	for failures, backoff := range expected {
+		if got := getFailureBackoff(failures); got != backoff {
+			t.Fatalf("expected backoff %s for %d failures, got %s", backoff, failures, got)
+		}
+	}
+
+	now := time.Now()
+	item := &models.TrackedItem{
+		Failures:    3,
+		LastFailure: sql.NullTime{Time: now.Add(-3 * time.Hour), Valid: true},
+	}
+
+	if !isBackingOff(item, now) {
+		t.Fatalf("expected item to still be backing off 3h after its 3rd failure")
+	}
+
+	if isBackingOff(item, now.Add(2*time.Hour)) {
+		t.Fatalf("expected item to not be backing off anymore 5h after its 3rd failure")
+	}
+}
+
+func TestIsGoneError(t *testing.T) {
+	cases := map[error]bool{
+		tls_session.StatusError{StatusCode: 404}:                            true,
+		fmt.Errorf("wrapped: %w", tls_session.StatusError{StatusCode: 410}): true,
+		tls_session.StatusError{StatusCode: 429}:                            false,
+		graphql_api.DMCAError{}:                                             true,
+		graphql_api.DeletedMediaError{}:                                     true,
+		chounyuu.DeletedMediaError{}:                                        true,
+		fmt.Errorf("connection reset"):                                      false,
+	}
+
+	for err, expected := range cases {
+		if watcherHttp.IsGoneError(err) != expected {
+			t.Fatalf("expected strong failure %t for error \"%s\"", expected, err.Error())
+		}
+	}
+}
+
+func TestRecordItemResult_Quarantine(t *testing.T) {
+	db, err := database.NewSQLiteConnection(filepath.Join(t.TempDir(), "watcher.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.CloseConnection()
+
+	viper.Set("run.quarantine_after", 4)
+	viper.Set("run.quarantine_after_strong", 2)
+
+	defer func() {
+		viper.Set("run.quarantine_after", nil)
+		viper.Set("run.quarantine_after_strong", nil)
+	}()
+
+	app := &Watcher{DbCon: db}
+	module := modules.GetModuleFactory().GetAllModules()[0]
+	goneErr := tls_session.StatusError{StatusCode: 404}
+	weakErr := fmt.Errorf("connection reset")
+
+	// strong failures are only counted consecutively
+	item := db.GetFirstOrCreateTrackedItem("https://example.com/strong", "", module)
+	app.recordItemResult(item, goneErr)
+	app.recordItemResult(item, weakErr)
+	app.recordItemResult(item, goneErr)
+
+	if item.Quarantined || item.Failures != 3 || item.StrongFailures != 1 {
+		t.Fatalf("expected 3 failures and 1 strong failure without quarantine, got %d/%d (quarantined: %t)",
+			item.Failures, item.StrongFailures, item.Quarantined)
+	}
+
+	app.recordItemResult(item, goneErr)
+
+	if !item.Quarantined {
+		t.Fatalf("expected item to be quarantined after 2 consecutive strong failures")
+	}
+
+	// weak failures quarantine the item after reaching their own threshold, a success resets the counters
+	item = db.GetFirstOrCreateTrackedItem("https://example.com/weak", "", module)
+	for i := 0; i < 3; i++ {
+		app.recordItemResult(item, weakErr)
+	}
+
+	app.recordItemResult(item, nil)
+
+	if item.Failures != 0 || item.Quarantined {
+		t.Fatalf("expected successful parse to reset the failures, got %d", item.Failures)
+	}
+
+	for i := 0; i < 4; i++ {
+		app.recordItemResult(item, weakErr)
+	}
+
+	if !item.Quarantined {
+		t.Fatalf("expected item to be quarantined after 4 consecutive failures")
+	}
+}
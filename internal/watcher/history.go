@@ -0,0 +1,206 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
+	"github.com/DaRealFreak/watcher-go/internal/http/std_session"
+	"github.com/DaRealFreak/watcher-go/internal/http/tls_session"
+	"github.com/DaRealFreak/watcher-go/internal/metrics"
+	"github.com/DaRealFreak/watcher-go/internal/models"
+)
+
+// startItemRun starts recording the parsing of the passed item during the passed run
+func (app *Watcher) startItemRun(run *models.Run, item *models.TrackedItem) *models.ItemRun {
+	return &models.ItemRun{
+		RunID:     run.ID,
+		ItemID:    item.ID,
+		Module:    item.Module,
+		URI:       item.URI,
+		StartedAt: time.Now(),
+	}
+}
+
+// finishItemRun saves the result of the parsed item with the not yet claimed downloads of the module,
+// or only of the session of the module if the item got parsed by one of multiple workers
+func (app *Watcher) finishItemRun(itemRun *models.ItemRun, module *models.Module, worker bool, err error) {
+	itemRun.FinishedAt = time.Now()
+
+	var downloads watcherHttp.DownloadCounter
+	if worker {
+		downloads = watcherHttp.GlobalDownloadStats.ClaimSession(itemRun.Module, module.Session)
+	} else {
+		downloads = watcherHttp.GlobalDownloadStats.Claim(itemRun.Module)
+	}
+	itemRun.FilesDownloaded = downloads.Files
+	itemRun.BytesDownloaded = downloads.Bytes
+
+	if err != nil {
+		itemRun.Error = err.Error()
+		itemRun.ErrorClass = classifyError(err)
+		metrics.ItemsParsed.Inc(itemRun.Module, "failed")
+		metrics.ItemErrors.Inc(itemRun.Module, itemRun.ErrorClass)
+	} else {
+		metrics.ItemsParsed.Inc(itemRun.Module, "success")
+	}
+
+	app.DbCon.CreateItemRun(itemRun)
+}
+
+// classifyError returns a short class of the passed error to group similar errors in the run history
+func classifyError(err error) string {
+	var (
+		tlsStatusError tls_session.StatusError
+		stdStatusError std_session.StatusError
+		netError       net.Error
+	)
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, watcherHttp.ErrShutdownRequested), errors.Is(err, context.Canceled):
+		return "shutdown"
+	case errors.As(err, &tlsStatusError):
+		return fmt.Sprintf("http_%d", tlsStatusError.StatusCode)
+	case errors.As(err, &stdStatusError):
+		return fmt.Sprintf("http_%d", stdStatusError.StatusCode)
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netError) && netError.Timeout():
+		return "timeout"
+	case errors.As(err, &netError):
+		return "network"
+	}
+
+	// use the type of the innermost error, f.e. "graphql_api.DMCAError"
+	for unwrapped := errors.Unwrap(err); unwrapped != nil; unwrapped = errors.Unwrap(err) {
+		err = unwrapped
+	}
+
+	errorClass := strings.TrimPrefix(fmt.Sprintf("%T", err), "*")
+	if errorClass == "errors.errorString" {
+		return "error"
+	}
+
+	return errorClass
+}
+
+// ListRuns lists the latest runs with the aggregated results of their items
+func (app *Watcher) ListRuns(limit int) {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+	_, _ = fmt.Fprintln(w, "ID\tStarted\tDuration\tItems\tFailed\tFiles\tBytes")
+
+	for _, run := range app.DbCon.GetRuns(limit) {
+		duration := "running"
+		if run.FinishedAt.Valid {
+			duration = run.FinishedAt.Time.Sub(run.StartedAt).String()
+		}
+
+		_, _ = fmt.Fprintf(
+			w,
+			"%d\t%s\t%s\t%d\t%d\t%d\t%s\n",
+			run.ID,
+			run.StartedAt.Local().Format(time.DateTime),
+			duration,
+			run.Items,
+			run.FailedItems,
+			run.FilesDownloaded,
+			formatBytes(run.BytesDownloaded),
+		)
+	}
+
+	_ = w.Flush()
+}
+
+// ListItemRuns lists the latest item runs with the option to limit it to an item, a module or failed item runs
+func (app *Watcher) ListItemRuns(itemID int, uri string, onlyFailed bool, limit int) {
+	var module models.ModuleInterface
+	if uri != "" {
+		module = app.ModuleFactory.GetModuleFromURI(uri)
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+	_, _ = fmt.Fprintln(w, "Run\tItem ID\tModule\tUrl\tStarted\tDuration\tFiles\tBytes\tError Class\tError")
+
+	for _, itemRun := range app.DbCon.GetItemRuns(itemID, module, onlyFailed, limit) {
+		_, _ = fmt.Fprintf(
+			w,
+			"%d\t%d\t%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\n",
+			itemRun.RunID,
+			itemRun.ItemID,
+			itemRun.Module,
+			itemRun.URI,
+			itemRun.StartedAt.Local().Format(time.DateTime),
+			itemRun.FinishedAt.Sub(itemRun.StartedAt).String(),
+			itemRun.FilesDownloaded,
+			formatBytes(itemRun.BytesDownloaded),
+			itemRun.ErrorClass,
+			itemRun.Error,
+		)
+	}
+
+	_ = w.Flush()
+}
+
+// ListFailingItems lists all tracked items whose last run failed, sorted by the module,
+// with the amount of consecutive failures and the time of the last successful run
+func (app *Watcher) ListFailingItems(uri string, minFailures int) {
+	var module models.ModuleInterface
+	if uri != "" {
+		module = app.ModuleFactory.GetModuleFromURI(uri)
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+	_, _ = fmt.Fprintln(w, "Item ID\tModule\tUrl\tFailures\tRuns\tLast Run\tLast Success\tError Class\tLast Error")
+
+	for _, summary := range app.DbCon.GetItemRunSummaries(module) {
+		if summary.ConsecutiveFailures == 0 || summary.ConsecutiveFailures < minFailures {
+			continue
+		}
+
+		lastSuccess := "never"
+		if summary.LastSuccess.Valid {
+			lastSuccess = summary.LastSuccess.Time.Local().Format(time.DateTime)
+		}
+
+		_, _ = fmt.Fprintf(
+			w,
+			"%d\t%s\t%s\t%d\t%d\t%s\t%s\t%s\t%s\n",
+			summary.ItemID,
+			summary.Module,
+			summary.URI,
+			summary.ConsecutiveFailures,
+			summary.Runs,
+			summary.LastRun.Local().Format(time.DateTime),
+			lastSuccess,
+			summary.LastErrorClass,
+			summary.LastError,
+		)
+	}
+
+	_ = w.Flush()
+}
+
+// formatBytes returns the passed amount of bytes in a human-readable format
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -37,7 +37,8 @@ func (app *Watcher) ListRegisteredModules(uri string) {
 }
 
 // ListTrackedItems lists all tracked items with the option to limit it to a module
-func (app *Watcher) ListTrackedItems(uri string, includeCompleted bool, partial string) {
+// and to items having any of the passed tags
+func (app *Watcher) ListTrackedItems(uri string, includeCompleted bool, partial string, tags []string) {
 	var trackedItems []*models.TrackedItem
 	if uri == "" {
 		trackedItems = app.DbCon.GetTrackedItems(nil, includeCompleted)
@@ -46,10 +47,18 @@ func (app *Watcher) ListTrackedItems(uri string, includeCompleted bool, partial
 		trackedItems = app.DbCon.GetTrackedItems(module, includeCompleted)
 	}
 
+	tagMap := app.DbCon.GetTrackedItemTagMap()
+	if len(tags) > 0 {
+		trackedItems = filterTaggedItems(trackedItems, tagMap, tags, nil)
+	}
+
 	// initialize tab writer
 	w := new(tabwriter.Writer)
 	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
-	_, _ = fmt.Fprintln(w, "ID\tModule\tUrl\tCurrent Item\tSub Folder\tFavorite\tCompleted")
+	_, _ = fmt.Fprintln(
+		w,
+		"ID\tModule\tUrl\tCurrent Item\tSub Folder\tSchedule\tPriority\tTags\tFavorite\tCompleted\tFailures\tQuarantined\tNotes",
+	)
 
 	for _, item := range trackedItems {
 		if partial != "" {
@@ -60,20 +69,58 @@ func (app *Watcher) ListTrackedItems(uri string, includeCompleted bool, partial
 
 		_, _ = fmt.Fprintf(
 			w,
-			"%d\t%s\t%s\t%s\t%s\t%t\t%t\n",
+			"%d\t%s\t%s\t%s\t%s\t%s\t%d\t%s\t%t\t%t\t%d\t%t\t%s\n",
 			item.ID,
 			item.Module,
 			item.URI,
 			item.CurrentItem,
 			item.SubFolder,
+			item.Schedule,
+			item.Priority,
+			strings.Join(tagMap[item.ID], ","),
 			item.Favorite,
 			item.Complete,
+			item.Failures,
+			item.Quarantined,
+			formatNotes(item.Notes, 50),
+		)
+	}
+
+	_ = w.Flush()
+}
+
+// SearchTrackedItems lists all tracked items whose notes or generated notes contain all passed terms
+func (app *Watcher) SearchTrackedItems(terms []string) {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+	_, _ = fmt.Fprintln(w, "ID\tModule\tUrl\tNotes\tGenerated Notes")
+
+	for _, item := range app.DbCon.SearchTrackedItems(terms) {
+		_, _ = fmt.Fprintf(
+			w,
+			"%d\t%s\t%s\t%s\t%s\n",
+			item.ID,
+			item.Module,
+			item.URI,
+			formatNotes(item.Notes, 50),
+			formatNotes(item.GeneratedNotes, 50),
 		)
 	}
 
 	_ = w.Flush()
 }
 
+// formatNotes collapses the whitespaces of the passed notes into single spaces to display them in a single line
+// and truncates them to the passed maximum length
+func formatNotes(notes string, maxLength int) string {
+	notes = strings.Join(strings.Fields(notes), " ")
+	if runes := []rune(notes); len(runes) > maxLength {
+		return string(runes[:maxLength-3]) + "..."
+	}
+
+	return notes
+}
+
 // ListAccounts lists all accounts with the option to limit it to a module
 func (app *Watcher) ListAccounts(uri string) {
 	var accounts []*models.Account
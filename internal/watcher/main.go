@@ -2,15 +2,24 @@
 package watcher
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
+	"time"
 
 	"github.com/DaRealFreak/watcher-go/internal/configuration"
 	"github.com/DaRealFreak/watcher-go/internal/database"
+	"github.com/DaRealFreak/watcher-go/internal/hooks"
+	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
+	"github.com/DaRealFreak/watcher-go/internal/logging"
+	"github.com/DaRealFreak/watcher-go/internal/metrics"
 	"github.com/DaRealFreak/watcher-go/internal/models"
 	"github.com/DaRealFreak/watcher-go/internal/modules"
+	"github.com/DaRealFreak/watcher-go/internal/notify"
 	"github.com/DaRealFreak/watcher-go/internal/raven"
+	"github.com/spf13/viper"
 
 	// registered modules imported for registering into the module factory
 	_ "github.com/DaRealFreak/watcher-go/internal/modules/bsky"
@@ -47,27 +56,93 @@ type Watcher struct {
 	DbCon         *database.DbIO
 	ModuleFactory *modules.ModuleFactory
 	Cfg           *configuration.AppConfiguration
+	// runMutex prevents the daemon and runs triggered by the control API from parsing items at the same time
+	runMutex sync.Mutex
 }
 
 // NewWatcher initializes a new Watcher with the default settings
 func NewWatcher(cfg *configuration.AppConfiguration) *Watcher {
+	newConnection := database.NewConnection
+	if cfg.SkipAutoMigrate {
+		newConnection = database.NewConnectionWithoutAutoMigrate
+	}
+
 	watcher := &Watcher{
-		DbCon:         database.NewConnection(),
+		DbCon:         newConnection(),
 		ModuleFactory: modules.GetModuleFactory(),
 		Cfg:           cfg,
 	}
 
+	var moduleDbIO models.DatabaseInterface = shutdownAwareDatabase{DatabaseInterface: watcher.DbCon}
+	if cfg.Run.DryRun {
+		moduleDbIO = newDryRunDatabase(watcher.DbCon)
+	}
+
 	for _, module := range watcher.ModuleFactory.GetAllModules() {
-		module.SetDbIO(watcher.DbCon)
+		module.SetDbIO(moduleDbIO)
 		module.SetCfg(cfg)
 	}
 
 	return watcher
 }
 
-// Run is the main functionality, updates all tracked items either parallel or linear
+// Run is the main functionality, updates all tracked items either parallel or linear.
+// The run is limited to the configured amount of items and stops starting new items once the time budget is used up.
+// During dry runs the files which would have been downloaded are printed afterwards
 func (app *Watcher) Run() {
+	ctx := context.Background()
+	if app.Cfg.Run.TimeBudget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, app.Cfg.Run.TimeBudget)
+		defer cancel()
+	}
+
 	trackedItems := app.getRelevantTrackedItems()
+	if app.Cfg.Run.Limit > 0 && len(trackedItems) > app.Cfg.Run.Limit {
+		slog.Info(fmt.Sprintf("limiting run to %d of %d tracked items", app.Cfg.Run.Limit, len(trackedItems)))
+		trackedItems = limitTrackedItems(trackedItems, app.Cfg.Run.Limit)
+	}
+
+	app.runItems(ctx, trackedItems)
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		slog.Info(fmt.Sprintf("stopped run after using up the time budget of %s", app.Cfg.Run.TimeBudget))
+	}
+
+	if app.Cfg.Run.DryRun {
+		app.printDryRun()
+	}
+}
+
+// RunItems updates the passed tracked items, used by the control API to trigger runs for single items or modules
+func (app *Watcher) RunItems(ctx context.Context, trackedItems []*models.TrackedItem) {
+	app.runItems(ctx, trackedItems)
+}
+
+// runItems updates the passed tracked items either parallel or linear.
+// No new item is started anymore once the passed context is done
+func (app *Watcher) runItems(ctx context.Context, trackedItems []*models.TrackedItem) {
+	app.runMutex.Lock()
+	defer app.runMutex.Unlock()
+
+	// dry runs are not saved in the run history
+	run := &models.Run{}
+	if !app.Cfg.Run.DryRun {
+		run = app.DbCon.CreateRun()
+		defer app.DbCon.FinishRun(run)
+	}
+
+	// all records logged during the run carry the run ID
+	if run.ID > 0 {
+		logging.SetRunID(run.ID)
+		defer logging.SetRunID(0)
+	}
+
+	// sinks in digest mode send a single message with all notifications of the run
+	defer notify.Default().Flush()
+
+	// runs without the daemon expose their statistics through the node exporter textfile collector
+	defer app.writeMetricsTextfile()
 
 	if app.Cfg.Run.RunParallel {
 		groupedItems := make(map[string][]*models.TrackedItem)
@@ -80,41 +155,58 @@ func (app *Watcher) Run() {
 		wg.Add(len(groupedItems))
 
 		for moduleKey, items := range groupedItems {
-			go app.runForItems(moduleKey, items, &wg)
+			go app.runForItems(ctx, run, moduleKey, items, &wg)
 		}
 
 		wg.Wait()
 	} else {
-		for _, item := range trackedItems {
-			module := app.ModuleFactory.GetModule(item.Module)
+		// the items of a module share the workers, so the items are grouped by module in order of their first item
+		for _, items := range groupItemsByModule(trackedItems) {
+			if ctx.Err() != nil {
+				return
+			}
+
+			module := app.ModuleFactory.GetModule(items[0].Module)
 			raven.CheckError(module.Load())
 
-			if (app.Cfg.Run.Force || app.Cfg.Run.ResetProgress) && item.CurrentItem != "" {
-				slog.Info(
-					fmt.Sprintf("resetting progress for item %s (current id: %s)", item.URI, item.CurrentItem),
-					"module", module.Key,
-				)
-				item.CurrentItem = ""
-				app.DbCon.ChangeTrackedItemCompleteStatus(item, false)
-				app.DbCon.UpdateTrackedItem(item, "")
-			}
+			app.parseItems(ctx, run, module, items)
+		}
+	}
+}
 
-			slog.Info(
-				fmt.Sprintf("parsing item %s (current id: %s)", item.URI, item.CurrentItem),
-				"module", module.Key,
-			)
+// groupItemsByModule groups the passed items by their module, keeping the order of the items of each module.
+// The groups are ordered by the position of their first item
+func groupItemsByModule(trackedItems []*models.TrackedItem) (groups [][]*models.TrackedItem) {
+	groupIndexes := make(map[string]int)
 
-			if err := module.Parse(item); err != nil {
-				slog.Warn(
-					fmt.Sprintf("error occurred parsing item %s (%s), skipping", item.URI, err.Error()),
-					"module", item.Module,
-				)
-			}
+	for _, item := range trackedItems {
+		index, ok := groupIndexes[item.Module]
+		if !ok {
+			index = len(groups)
+			groupIndexes[item.Module] = index
+			groups = append(groups, nil)
 		}
+
+		groups[index] = append(groups[index], item)
+	}
+
+	return groups
+}
+
+// writeMetricsTextfile writes the metrics to the configured textfile collector path if one is configured
+func (app *Watcher) writeMetricsTextfile() {
+	path := metrics.GetTextfilePath()
+	if path == "" {
+		return
+	}
+
+	if err := metrics.WriteTextfile(metrics.Default(), path); err != nil {
+		slog.Warn(fmt.Sprintf("unable to write metrics textfile %s: %s", path, err.Error()))
 	}
 }
 
-// getRelevantTrackedItems returns the relevant tracked items based on the passed app configuration
+// getRelevantTrackedItems returns the relevant tracked items based on the passed app configuration,
+// limited to the configured tags and ordered by the configured run order
 func (app *Watcher) getRelevantTrackedItems() []*models.TrackedItem {
 	var trackedItems []*models.TrackedItem
 
@@ -142,6 +234,16 @@ func (app *Watcher) getRelevantTrackedItems() []*models.TrackedItem {
 					continue
 				}
 
+				// skip quarantined item if we aren't forcing it
+				if trackedItem.Quarantined && !app.Cfg.Run.Force {
+					slog.Warn(
+						fmt.Sprintf("skipping quarantined item %s, use --force to run it anyways", trackedItem.URI),
+						"module", module.Key,
+					)
+
+					continue
+				}
+
 				trackedItems = append(trackedItems, trackedItem)
 			}
 		}
@@ -154,8 +256,16 @@ func (app *Watcher) getRelevantTrackedItems() []*models.TrackedItem {
 
 			trackedItems = append(trackedItems, app.DbCon.GetTrackedItems(module, false)...)
 		}
+
+		if !app.Cfg.Run.Force {
+			trackedItems = app.filterBackedOffItems(trackedItems, time.Now())
+		}
 	default:
 		trackedItems = app.DbCon.GetTrackedItems(nil, false)
+
+		if !app.Cfg.Run.Force {
+			trackedItems = app.filterBackedOffItems(trackedItems, time.Now())
+		}
 	}
 
 	// remove duplicates
@@ -174,11 +284,16 @@ func (app *Watcher) getRelevantTrackedItems() []*models.TrackedItem {
 		}
 	}
 
+	results = app.filterRunTags(results)
+	app.orderTrackedItems(results, app.getRunOrder())
+
 	return results
 }
 
 // runForItems is the go routine to parse run parallel for groups
-func (app *Watcher) runForItems(moduleKey string, trackedItems []*models.TrackedItem, wg *sync.WaitGroup) {
+func (app *Watcher) runForItems(
+	ctx context.Context, run *models.Run, moduleKey string, trackedItems []*models.TrackedItem, wg *sync.WaitGroup,
+) {
 	defer wg.Done()
 
 	module := app.ModuleFactory.GetModule(moduleKey)
@@ -200,27 +315,180 @@ func (app *Watcher) runForItems(moduleKey string, trackedItems []*models.Tracked
 		}
 	}()
 
+	app.parseItems(ctx, run, module, trackedItems)
+}
+
+// parseItems parses the passed items of the passed module with the configured amount of workers of the module.
+// Every worker parses its items with its own instance of the module, so the connection budget and the leases
+// of the module are respected for all items. No new item is started anymore once the passed context is done
+func (app *Watcher) parseItems(
+	ctx context.Context, run *models.Run, module *models.Module, trackedItems []*models.TrackedItem,
+) {
+	workers := min(getModuleWorkers(module), len(trackedItems))
+	if workers <= 1 {
+		for _, item := range trackedItems {
+			if ctx.Err() != nil {
+				return
+			}
+
+			app.parseItem(run, module, item, false)
+		}
+
+		return
+	}
+
+	slog.Info(
+		fmt.Sprintf("parsing %d items with %d workers", len(trackedItems), workers),
+		"module", module.Key,
+	)
+
+	queue := make(chan *models.TrackedItem)
+
+	var wg sync.WaitGroup
+
+	wg.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		worker := app.newModuleWorker(module)
+
+		go func() {
+			defer wg.Done()
+
+			for item := range queue {
+				app.parseItem(run, worker, item, true)
+			}
+		}()
+	}
+
 	for _, item := range trackedItems {
-		if (app.Cfg.Run.Force || app.Cfg.Run.ResetProgress) && item.CurrentItem != "" {
-			slog.Info(
-				fmt.Sprintf("resetting progress for item %s (current id: %s)", item.URI, item.CurrentItem),
-				"module", module.Key,
-			)
-			item.CurrentItem = ""
-			app.DbCon.ChangeTrackedItemCompleteStatus(item, false)
-			app.DbCon.UpdateTrackedItem(item, "")
+		if ctx.Err() != nil {
+			break
 		}
 
-		slog.Info(
-			fmt.Sprintf("parsing item %s (current id: %s)", item.URI, item.CurrentItem),
+		queue <- item
+	}
+
+	close(queue)
+	wg.Wait()
+}
+
+// newModuleWorker returns a new loaded instance of the passed module for a worker.
+// The worker waits for the rate limiters of the passed module, so the workers share the request rate of the module
+func (app *Watcher) newModuleWorker(module *models.Module) *models.Module {
+	worker := module.NewWorker()
+	worker.SetDbIO(module.DbIO)
+	worker.SetCfg(module.Cfg)
+	raven.CheckError(worker.Load())
+	module.ShareRateLimiters(worker)
+
+	return worker
+}
+
+// getModuleWorkers returns the configured amount of items of the passed module which are parsed at the same time.
+// Modules without support for multiple workers are always parsed with a single worker
+func getModuleWorkers(module *models.Module) int {
+	key := fmt.Sprintf("Modules.%s.workers", module.GetViperModuleKey())
+	if !viper.IsSet(key) {
+		return 1
+	}
+
+	workers := viper.GetInt(key)
+	if workers < 1 {
+		slog.Warn(fmt.Sprintf("ignoring invalid worker count %d, expected at least 1", workers), "module", module.Key)
+		return 1
+	}
+
+	if workers > 1 && module.NewWorker == nil {
+		slog.Warn(
+			fmt.Sprintf("ignoring worker count %d, the module doesn't support parsing multiple items at once", workers),
 			"module", module.Key,
 		)
 
+		return 1
+	}
+
+	return workers
+}
+
+// runItemHooks runs the post item hooks for the passed successfully parsed item.
+// Returns an error if a hook with the abort failure policy failed
+func (app *Watcher) runItemHooks(module *models.Module, item *models.TrackedItem) error {
+	if !hooks.Default().HasItemSteps() {
+		return nil
+	}
+
+	// the tracked item got updated by the module, so the current item has to be retrieved again
+	if updatedItem := app.DbCon.GetTrackedItem(item.ID); updatedItem != nil {
+		item = updatedItem
+	}
+
+	return hooks.Default().RunItem(module.Key, hooks.Item{
+		ID:          item.ID,
+		URI:         item.URI,
+		SubFolder:   item.SubFolder,
+		CurrentItem: item.CurrentItem,
+	})
+}
+
+// parseItem resets the progress of the passed item if requested and lets the module parse it.
+// The result is saved in the run history of the passed run, items parsed by one of multiple workers
+// are only attributed the downloads of the session of their worker
+func (app *Watcher) parseItem(run *models.Run, module *models.Module, item *models.TrackedItem, worker bool) {
+	logger := logging.Item(module.Key, item.ID, item.URI)
+	module.SetItemLogger(logger)
+	defer module.SetItemLogger(nil)
+
+	// on graceful shutdowns every item in progress finishes its current download queue item with its session
+	watcherHttp.GlobalShutdown.StartItem(module.Session, item.ID)
+	defer watcherHttp.GlobalShutdown.FinishItem(item.ID)
+
+	if (app.Cfg.Run.Force || app.Cfg.Run.ResetProgress) && item.CurrentItem != "" {
+		logger.Info(
+			fmt.Sprintf("resetting progress for item %s (current id: %s)", item.URI, item.CurrentItem),
+			"current_item", item.CurrentItem,
+		)
+		item.CurrentItem = ""
+
+		if !app.Cfg.Run.DryRun {
+			app.DbCon.ChangeTrackedItemCompleteStatus(item, false)
+			app.DbCon.UpdateTrackedItem(item, "")
+		}
+	}
+
+	logger.Info(
+		fmt.Sprintf("parsing item %s (current id: %s)", item.URI, item.CurrentItem),
+		"current_item", item.CurrentItem,
+	)
+
+	if app.Cfg.Run.DryRun {
 		if err := module.Parse(item); err != nil {
-			slog.Warn(
-				fmt.Sprintf("error occurred parsing item %s (%s), skipping", item.URI, err.Error()),
-				"module", item.Module,
+			logger.Warn(
+				fmt.Sprintf("error occurred resolving item %s (%s), skipping", item.URI, err.Error()),
+				"error", err,
 			)
 		}
+
+		if worker {
+			watcherHttp.GlobalDryRun.AssignItem(module.Key, module.Session, item.ID, item.URI)
+		} else {
+			watcherHttp.GlobalDryRun.AssignItem(module.Key, nil, item.ID, item.URI)
+		}
+
+		return
 	}
+
+	itemRun := app.startItemRun(run, item)
+
+	err := module.Parse(item)
+	if err != nil {
+		logger.Warn(
+			fmt.Sprintf("error occurred parsing item %s (%s), skipping", item.URI, err.Error()),
+			"error", err,
+		)
+	} else if err = app.runItemHooks(module, item); err != nil {
+		logger.Warn(fmt.Sprintf("post item hooks of item %s failed (%s)", item.URI, err.Error()), "error", err)
+	}
+
+	app.finishItemRun(itemRun, module, worker, err)
+	app.recordItemResult(item, err)
 }
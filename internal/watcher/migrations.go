@@ -0,0 +1,58 @@
+package watcher
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/database"
+	"github.com/DaRealFreak/watcher-go/internal/raven"
+)
+
+// MigrateDatabase applies or reverts the schema migrations until the database reaches the passed version
+func (app *Watcher) MigrateDatabase(target int) {
+	raven.CheckError(app.DbCon.Migrate(target))
+
+	version, err := app.DbCon.SchemaVersion()
+	raven.CheckError(err)
+
+	slog.Info(fmt.Sprintf("database schema is at version %d", version))
+}
+
+// PrintMigrationStatus lists all known schema migrations and whether they are applied to the database
+func (app *Watcher) PrintMigrationStatus() {
+	statuses, err := app.DbCon.MigrationStatus()
+	raven.CheckError(err)
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+	_, _ = fmt.Fprintln(w, "Version\tName\tApplied\tReversible")
+
+	for _, status := range statuses {
+		applied := "pending"
+		if status.Applied {
+			applied = status.AppliedAt.Local().Format(time.DateTime)
+		}
+
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%t\n", status.Version, status.Name, applied, status.Down != nil)
+	}
+
+	_ = w.Flush()
+
+	version, err := app.DbCon.SchemaVersion()
+	raven.CheckError(err)
+
+	fmt.Printf("\nschema version %d of %d\n", version, database.LatestSchemaVersion())
+
+	pinnedVersion, pinned, err := app.DbCon.PinnedSchemaVersion()
+	raven.CheckError(err)
+
+	if pinned {
+		fmt.Printf(
+			"downgraded to version %d, pending migrations are not applied automatically until migrated up again\n",
+			pinnedVersion,
+		)
+	}
+}
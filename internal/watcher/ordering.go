@@ -0,0 +1,154 @@
+package watcher
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/models"
+	"github.com/spf13/viper"
+)
+
+const (
+	// OrderDefault keeps the database order (module, favorites, ID)
+	OrderDefault = "default"
+	// OrderFavorite updates favorite items first
+	OrderFavorite = "favorite"
+	// OrderLeastRecent updates the items with the oldest LastModified date first
+	OrderLeastRecent = "least-recent"
+	// OrderLastRun updates the items which weren't checked for the longest time first based on the run history
+	OrderLastRun = "last-run"
+	// OrderPriority updates items with a higher priority first
+	OrderPriority = "priority"
+)
+
+// itemComparator compares two tracked items and returns a negative value if a has to be updated before b,
+// a positive value if b has to be updated before a and 0 if the next comparator has to decide
+type itemComparator func(a, b *models.TrackedItem) int
+
+// ParseOrder parses the passed comma separated orders (f.e. "priority,least-recent")
+// and returns an error on unknown orders
+func ParseOrder(order string) ([]string, error) {
+	var orders []string
+
+	for _, part := range strings.Split(order, ",") {
+		part = strings.TrimSpace(part)
+		switch part {
+		case "", OrderDefault:
+			continue
+		case OrderFavorite, OrderLeastRecent, OrderLastRun, OrderPriority:
+			orders = append(orders, part)
+		default:
+			return nil, fmt.Errorf(
+				"unsupported order \"%s\", expected %s, %s, %s, %s or %s",
+				part, OrderDefault, OrderFavorite, OrderLeastRecent, OrderLastRun, OrderPriority,
+			)
+		}
+	}
+
+	return orders, nil
+}
+
+// getRunOrder returns the passed order flag or the configured run.order setting
+func (app *Watcher) getRunOrder() []string {
+	order := app.Cfg.Run.Order
+	if order == "" {
+		order = viper.GetString("run.order")
+	}
+
+	orders, err := ParseOrder(order)
+	if err != nil {
+		// the flag is validated before running, so only the setting can be invalid here
+		slog.Warn(fmt.Sprintf("ignoring run.order setting: %s", err.Error()))
+
+		return nil
+	}
+
+	return orders
+}
+
+// orderTrackedItems sorts the passed tracked items by the passed orders, the first order has the highest precedence.
+// The sort is stable, so items comparing equal keep their database order
+func (app *Watcher) orderTrackedItems(trackedItems []*models.TrackedItem, orders []string) {
+	if len(orders) == 0 {
+		return
+	}
+
+	var lastRuns map[int]time.Time
+
+	comparators := make([]itemComparator, 0, len(orders))
+	for _, order := range orders {
+		switch order {
+		case OrderFavorite:
+			comparators = append(comparators, func(a, b *models.TrackedItem) int {
+				return compareBool(a.Favorite, b.Favorite)
+			})
+		case OrderPriority:
+			comparators = append(comparators, func(a, b *models.TrackedItem) int {
+				return b.Priority - a.Priority
+			})
+		case OrderLeastRecent:
+			comparators = append(comparators, func(a, b *models.TrackedItem) int {
+				return compareTimes(a.LastModified.Time, a.LastModified.Valid, b.LastModified.Time, b.LastModified.Valid)
+			})
+		case OrderLastRun:
+			if lastRuns == nil {
+				lastRuns = app.DbCon.GetLastItemRunTimes()
+			}
+
+			comparators = append(comparators, func(a, b *models.TrackedItem) int {
+				lastRunA, okA := lastRuns[a.ID]
+				lastRunB, okB := lastRuns[b.ID]
+
+				return compareTimes(lastRunA, okA, lastRunB, okB)
+			})
+		}
+	}
+
+	sort.SliceStable(trackedItems, func(i, j int) bool {
+		for _, comparator := range comparators {
+			if result := comparator(trackedItems[i], trackedItems[j]); result != 0 {
+				return result < 0
+			}
+		}
+
+		return false
+	})
+}
+
+// compareBool orders true values before false values
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// compareTimes orders unset times before set times and older times before newer times
+func compareTimes(a time.Time, aValid bool, b time.Time, bValid bool) int {
+	switch {
+	case !aValid && !bValid:
+		return 0
+	case !aValid:
+		return -1
+	case !bValid:
+		return 1
+	default:
+		return a.Compare(b)
+	}
+}
+
+// limitTrackedItems returns at most the passed limit of tracked items, a limit of 0 or less disables the limit
+func limitTrackedItems(trackedItems []*models.TrackedItem, limit int) []*models.TrackedItem {
+	if limit <= 0 || len(trackedItems) <= limit {
+		return trackedItems
+	}
+
+	return trackedItems[:limit]
+}
@@ -0,0 +1,108 @@
+package watcher
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DaRealFreak/watcher-go/internal/models"
+)
+
+func TestParseOrder(t *testing.T) {
+	orders, err := ParseOrder("priority, least-recent,default")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if len(orders) != 2 || orders[0] != OrderPriority || orders[1] != OrderLeastRecent {
+		t.Fatalf("unexpected parsed orders: %v", orders)
+	}
+
+	if orders, err = ParseOrder(""); err != nil || len(orders) != 0 {
+		t.Fatalf("expected no orders for an empty order, got %v (%v)", orders, err)
+	}
+
+	if _, err = ParseOrder("random"); err == nil {
+		t.Fatalf("expected error for unknown order")
+	}
+}
+
+func TestOrderTrackedItems(t *testing.T) {
+	now := time.Now()
+	items := []*models.TrackedItem{
+		{ID: 1, LastModified: sql.NullTime{Time: now, Valid: true}},
+		{ID: 2, Favorite: true, LastModified: sql.NullTime{Time: now.Add(-time.Hour), Valid: true}},
+		{ID: 3, Priority: 5, LastModified: sql.NullTime{Time: now.Add(-2 * time.Hour), Valid: true}},
+		{ID: 4},
+		{ID: 5, Favorite: true, Priority: 5},
+	}
+
+	app := &Watcher{}
+	expected := map[string][]int{
+		OrderFavorite:    {2, 5, 1, 3, 4},
+		OrderLeastRecent: {4, 5, 3, 2, 1},
+		OrderPriority:    {3, 5, 1, 2, 4},
+	}
+
+	for order, expectedIDs := range expected {
+		sorted := append([]*models.TrackedItem{}, items...)
+		app.orderTrackedItems(sorted, []string{order})
+		assertItemOrder(t, order, sorted, expectedIDs)
+	}
+
+	sorted := append([]*models.TrackedItem{}, items...)
+	app.orderTrackedItems(sorted, []string{OrderPriority, OrderFavorite, OrderLeastRecent})
+	assertItemOrder(t, "priority,favorite,least-recent", sorted, []int{5, 3, 2, 4, 1})
+
+	if limited := limitTrackedItems(sorted, 2); len(limited) != 2 || limited[0].ID != 5 {
+		t.Fatalf("expected the first 2 items after limiting, got %d items", len(limited))
+	}
+
+	if limited := limitTrackedItems(sorted, 0); len(limited) != len(sorted) {
+		t.Fatalf("expected no limit for 0, got %d items", len(limited))
+	}
+}
+
+func assertItemOrder(t *testing.T, order string, items []*models.TrackedItem, expectedIDs []int) {
+	t.Helper()
+
+	for i, item := range items {
+		if item.ID != expectedIDs[i] {
+			ids := make([]int, len(items))
+			for j, sortedItem := range items {
+				ids[j] = sortedItem.ID
+			}
+
+			t.Fatalf("unexpected order for %s: expected %v, got %v", order, expectedIDs, ids)
+		}
+	}
+}
+
+func TestGroupItemsByModule(t *testing.T) {
+	items := []*models.TrackedItem{
+		{ID: 1, Module: "b"},
+		{ID: 2, Module: "a"},
+		{ID: 3, Module: "b"},
+		{ID: 4, Module: "c"},
+		{ID: 5, Module: "a"},
+	}
+
+	groups := groupItemsByModule(items)
+	expected := [][]int{{1, 3}, {2, 5}, {4}}
+
+	if len(groups) != len(expected) {
+		t.Fatalf("expected %d groups, got %d", len(expected), len(groups))
+	}
+
+	for i, group := range groups {
+		if len(group) != len(expected[i]) {
+			t.Fatalf("expected group %d to contain %v, got %d items", i, expected[i], len(group))
+		}
+
+		for j, item := range group {
+			if item.ID != expected[i][j] {
+				t.Fatalf("expected group %d to contain %v, got item %d at %d", i, expected[i], item.ID, j)
+			}
+		}
+	}
+}
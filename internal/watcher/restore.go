@@ -1,20 +1,16 @@
 package watcher
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/DaRealFreak/watcher-go/internal/configuration"
 
 	"log/slog"
 
-	"github.com/DaRealFreak/watcher-go/pkg/archive/gzip"
-	"github.com/DaRealFreak/watcher-go/pkg/archive/tar"
-	"github.com/DaRealFreak/watcher-go/pkg/archive/zip"
-
 	"github.com/DaRealFreak/watcher-go/internal/raven"
 	"github.com/DaRealFreak/watcher-go/pkg/archive"
 )
@@ -30,7 +26,13 @@ func (e NoReaderFoundError) Error() string {
 }
 
 // Restore restores the database/settings from the passed archive
+// Incremental backups are restored by replaying their chain, the verify option only restores into a temporary database
 func (app *Watcher) Restore(archiveName string, cfg *configuration.AppConfiguration) {
+	if cfg.Restore.Verify {
+		raven.CheckError(app.verifyBackup(archiveName))
+		return
+	}
+
 	reader, err := app.getArchiveReader(archiveName)
 	raven.CheckError(err)
 
@@ -43,26 +45,37 @@ func (app *Watcher) Restore(archiveName string, cfg *configuration.AppConfigurat
 		cfg.Restore.Database.Items.Enabled ||
 		cfg.Restore.Database.OAuth2Clients.Enabled ||
 		cfg.Restore.Database.Cookies.Enabled {
-		raven.CheckError(app.restoreDatabase(reader, cfg))
+		manifest, err := readBackupManifest(reader)
+		if err != nil && !errors.Is(err, errNoBackupManifest) {
+			raven.CheckError(err)
+		}
+
+		if manifest != nil && manifest.Type == backupTypeIncremental {
+			raven.CheckError(app.restoreBackupChain(archiveName, cfg))
+		} else {
+			raven.CheckError(app.restoreDatabase(reader, cfg))
+		}
 	}
 }
 
 // getArchiveReader returns the reader for the passed archive if it exists and can be opened
 func (app *Watcher) getArchiveReader(archiveName string) (reader archive.Reader, err error) {
+	format, ok := archive.GetFormatByFileName(archiveName)
+	if !ok {
+		return nil, NoReaderFoundError{archive: archiveName}
+	}
+
 	// #nosec
 	file, err := os.Open(archiveName)
-	raven.CheckError(err)
-
-	switch {
-	case strings.HasSuffix(archiveName, zip.FileExt):
-		return zip.NewReader(file)
-	case strings.HasSuffix(archiveName, tar.FileExt):
-		return tar.NewReader(file), nil
-	case strings.HasSuffix(archiveName, gzip.FileExt):
-		return gzip.NewReader(file), nil
-	default:
-		return nil, NoReaderFoundError{archive: archiveName}
+	if err != nil {
+		return nil, err
 	}
+
+	// the readers copy the whole archive into memory
+	defer raven.CheckClosure(file)
+
+	// encrypted archives can only be read with the password from the environment
+	return format.Reader(file, archive.Options{Password: os.Getenv(BackupPasswordEnv)})
 }
 
 // restoreDatabase checks
@@ -95,14 +108,14 @@ func (app *Watcher) restoreDatabase(reader archive.Reader, cfg *configuration.Ap
 
 		return app.restoreTablesFromArchive(
 			reader,
-			"accounts.sql", "tracked_items.sql", "oauth_clients.sql", "cookies.sql",
+			"accounts.sql", "tracked_items.sql", "item_tags.sql", "oauth_clients.sql", "cookies.sql",
 		)
 	case cfg.Restore.Database.Accounts.Enabled:
 		// check for accounts.sql in archive
 		return app.restoreTablesFromArchive(reader, "accounts.sql")
 	case cfg.Restore.Database.Items.Enabled:
 		// check for tracked_items.sql in archive
-		return app.restoreTablesFromArchive(reader, "tracked_items.sql")
+		return app.restoreTablesFromArchive(reader, "tracked_items.sql", "item_tags.sql")
 	case cfg.Restore.Database.OAuth2Clients.Enabled:
 		// check for oauth_clients.sql in archive
 		return app.restoreTablesFromArchive(reader, "oauth_clients.sql")
@@ -0,0 +1,50 @@
+package watcher
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/DaRealFreak/watcher-go/internal/raven"
+	"github.com/DaRealFreak/watcher-go/internal/secrets"
+)
+
+// EncryptSecrets encrypts all not yet encrypted credentials in the database with the configured secrets key
+func (app *Watcher) EncryptSecrets() {
+	count, err := app.DbCon.EncryptSecrets()
+	raven.CheckError(err)
+
+	slog.Info(fmt.Sprintf("encrypted %d secrets", count))
+}
+
+// GenerateSecretsKey generates a new random secrets key and writes it into the passed key file
+func (app *Watcher) GenerateSecretsKey(keyFile string) {
+	key, err := secrets.GenerateKey()
+	raven.CheckError(err)
+
+	// never overwrite an existing key file, the secrets encrypted with it would be lost otherwise
+	file, err := os.OpenFile(keyFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	raven.CheckError(err)
+
+	_, err = file.WriteString(secrets.EncodeKey(key) + "\n")
+	raven.CheckError(err)
+	raven.CheckError(file.Close())
+
+	slog.Info(fmt.Sprintf(
+		"generated secrets key in \"%s\", set the secrets.keyfile setting to use it", keyFile,
+	))
+}
+
+// warnPlaintextSecrets warns about plaintext credentials before they end up in a backup.
+// Backups never modify the database, the credentials have to be encrypted with the secrets encrypt command
+func (app *Watcher) warnPlaintextSecrets() {
+	count, err := app.DbCon.CountPlaintextSecrets()
+	raven.CheckError(err)
+
+	if count > 0 {
+		slog.Warn(fmt.Sprintf(
+			"backing up %d credentials in plaintext, run \"secrets encrypt\" with a configured secrets key to encrypt them",
+			count,
+		))
+	}
+}
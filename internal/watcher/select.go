@@ -0,0 +1,214 @@
+package watcher
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/DaRealFreak/watcher-go/internal/models"
+)
+
+// ItemSelector selects tracked items by their ID, their URL or in bulk by their module and a partial match of their URL
+type ItemSelector struct {
+	IDs  []int
+	URLs []string
+	// ModuleURL and Partial select all tracked items of the module containing the partial term in their URL,
+	// if only one of them is set either all items of the module or all items matching the term are selected
+	ModuleURL string
+	Partial   string
+	// CreateMissing creates the tracked items of the passed URLs if they aren't tracked yet
+	CreateMissing bool
+}
+
+// ItemChanges contains the changes applied to the selected tracked items, only set fields are changed
+type ItemChanges struct {
+	URI         *string
+	SubFolder   *string
+	CurrentItem *string
+	Favorite    *bool
+	Complete    *bool
+	Schedule    *string
+	Priority    *int
+	Notes       *string
+}
+
+// IsEmpty checks if no selection criteria is set
+func (s ItemSelector) IsEmpty() bool {
+	return len(s.IDs) == 0 && len(s.URLs) == 0 && !s.IsBulk()
+}
+
+// IsBulk checks if the selector selects items based on their module or a partial match
+func (s ItemSelector) IsBulk() bool {
+	return s.ModuleURL != "" || s.Partial != ""
+}
+
+// SelectTrackedItems returns all tracked items matching the passed selector without duplicates
+func (app *Watcher) SelectTrackedItems(selector ItemSelector) (trackedItems []*models.TrackedItem, err error) {
+	selected := make(map[int]bool)
+	addItems := func(items ...*models.TrackedItem) {
+		for _, item := range items {
+			if !selected[item.ID] {
+				selected[item.ID] = true
+				trackedItems = append(trackedItems, item)
+			}
+		}
+	}
+
+	for _, id := range selector.IDs {
+		item := app.DbCon.GetTrackedItem(id)
+		if item == nil {
+			return nil, fmt.Errorf("no tracked item found with ID %d", id)
+		}
+
+		addItems(item)
+	}
+
+	for _, url := range selector.URLs {
+		items, urlErr := app.selectTrackedItemsByURL(url, selector.CreateMissing)
+		if urlErr != nil {
+			return nil, urlErr
+		}
+
+		addItems(items...)
+	}
+
+	if selector.IsBulk() {
+		var module *models.Module
+		if selector.ModuleURL != "" {
+			if module = app.ModuleFactory.GetModule(selector.ModuleURL); module == nil {
+				if !app.ModuleFactory.CanParse(selector.ModuleURL) {
+					return nil, fmt.Errorf("no module registered for \"%s\"", selector.ModuleURL)
+				}
+
+				module = app.ModuleFactory.GetModuleFromURI(selector.ModuleURL)
+			}
+		}
+
+		var items []*models.TrackedItem
+		if module != nil {
+			items = app.DbCon.GetTrackedItems(module, true)
+		} else {
+			items = app.DbCon.GetTrackedItems(nil, true)
+		}
+
+		for _, item := range items {
+			if strings.Contains(item.URI, selector.Partial) {
+				addItems(item)
+			}
+		}
+	}
+
+	return trackedItems, nil
+}
+
+// selectTrackedItemsByURL returns the tracked items of the passed URL regardless of their sub folder
+func (app *Watcher) selectTrackedItemsByURL(url string, createMissing bool) ([]*models.TrackedItem, error) {
+	if !app.ModuleFactory.CanParse(url) {
+		return nil, fmt.Errorf("no module registered for \"%s\"", url)
+	}
+
+	module := app.ModuleFactory.GetModuleFromURI(url)
+
+	normalizedUri, err := module.ModuleInterface.AddItem(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if createMissing {
+		return app.DbCon.GetAllOrCreateTrackedItemIgnoreSubFolder(normalizedUri, module), nil
+	}
+
+	var items []*models.TrackedItem
+
+	for _, item := range app.DbCon.GetTrackedItems(module, true) {
+		if item.URI == normalizedUri {
+			items = append(items, item)
+		}
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no tracked item found with url %s", url)
+	}
+
+	return items, nil
+}
+
+// ConfirmTrackedItems prints the passed tracked items and asks for confirmation of the passed action
+func ConfirmTrackedItems(trackedItems []*models.TrackedItem, action string, in io.Reader, out io.Writer) bool {
+	w := new(tabwriter.Writer)
+	w.Init(out, 0, 8, 1, '\t', 0)
+	_, _ = fmt.Fprintln(w, "ID\tModule\tUrl\tSub Folder\tCurrent Item")
+
+	for _, item := range trackedItems {
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", item.ID, item.Module, item.URI, item.SubFolder, item.CurrentItem)
+	}
+
+	_ = w.Flush()
+
+	_, _ = fmt.Fprintf(out, "%s %d tracked items? [y/N]: ", action, len(trackedItems))
+
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
+
+// UpdateTrackedItems applies the passed changes to the passed tracked items.
+// Returns an error if a new url should be applied to multiple items
+func (app *Watcher) UpdateTrackedItems(trackedItems []*models.TrackedItem, changes ItemChanges) error {
+	if changes.URI != nil && len(trackedItems) > 1 {
+		return fmt.Errorf("a new url can only be set for a single item, %d items are selected", len(trackedItems))
+	}
+
+	for _, item := range trackedItems {
+		if changes.URI != nil {
+			app.DbCon.ChangeTrackedItemUri(item, *changes.URI)
+		}
+
+		if changes.SubFolder != nil {
+			app.DbCon.ChangeTrackedItemSubFolder(item, *changes.SubFolder)
+		}
+
+		if changes.CurrentItem != nil {
+			slog.Info(
+				fmt.Sprintf("setting current item of %s from \"%s\" to \"%s\"", item.URI, item.CurrentItem, *changes.CurrentItem),
+				"module", item.Module,
+			)
+			app.DbCon.UpdateTrackedItem(item, *changes.CurrentItem)
+		}
+
+		if changes.Favorite != nil {
+			app.DbCon.ChangeTrackedItemFavoriteStatus(item, *changes.Favorite)
+		}
+
+		// updating the current item resets the complete status, so the complete status is applied afterwards
+		if changes.Complete != nil {
+			app.DbCon.ChangeTrackedItemCompleteStatus(item, *changes.Complete)
+		}
+
+		if changes.Schedule != nil {
+			app.DbCon.ChangeTrackedItemSchedule(item, *changes.Schedule)
+		}
+
+		if changes.Priority != nil {
+			app.DbCon.ChangeTrackedItemPriority(item, *changes.Priority)
+		}
+
+		if changes.Notes != nil {
+			app.DbCon.UpdateTrackedItemNotes(item, *changes.Notes)
+		}
+	}
+
+	return nil
+}
+
+// DeleteTrackedItems deletes the passed tracked items
+func (app *Watcher) DeleteTrackedItems(trackedItems []*models.TrackedItem) {
+	for _, item := range trackedItems {
+		slog.Info(fmt.Sprintf("deleting tracked item %s", item.URI), "module", item.Module)
+		app.DbCon.DeleteTrackedItem(item)
+	}
+}
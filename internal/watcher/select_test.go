@@ -0,0 +1,54 @@
+package watcher
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/DaRealFreak/watcher-go/internal/models"
+)
+
+func TestItemSelector(t *testing.T) {
+	if !(ItemSelector{}).IsEmpty() {
+		t.Fatalf("expected selector without criteria to be empty")
+	}
+
+	if selector := (ItemSelector{IDs: []int{1}}); selector.IsEmpty() || selector.IsBulk() {
+		t.Fatalf("expected ID selector to be neither empty nor bulk")
+	}
+
+	if selector := (ItemSelector{Partial: "artist"}); selector.IsEmpty() || !selector.IsBulk() {
+		t.Fatalf("expected partial selector to be a bulk selector")
+	}
+}
+
+func TestConfirmTrackedItems(t *testing.T) {
+	items := []*models.TrackedItem{
+		{ID: 1, Module: "test", URI: "https://example.org/1"},
+		{ID: 2, Module: "test", URI: "https://example.org/2"},
+	}
+
+	for answer, expected := range map[string]bool{"y\n": true, " YES \n": true, "n\n": false, "\n": false, "": false} {
+		out := new(bytes.Buffer)
+		if confirmed := ConfirmTrackedItems(items, "Delete", strings.NewReader(answer), out); confirmed != expected {
+			t.Fatalf("expected confirmation %t for answer %q, got %t", expected, answer, confirmed)
+		}
+
+		if !strings.Contains(out.String(), "https://example.org/2") || !strings.Contains(out.String(), "Delete 2 tracked items?") {
+			t.Fatalf("expected the selected items and the prompt in the output, got %q", out.String())
+		}
+	}
+}
+
+func TestUpdateTrackedItems_NewURL(t *testing.T) {
+	items := []*models.TrackedItem{
+		{ID: 1, Module: "test", URI: "https://example.org/1"},
+		{ID: 2, Module: "test", URI: "https://example.org/2"},
+	}
+
+	// the url can't be changed for multiple items at once, the database is never touched
+	newURL := "https://example.org/3"
+	if err := (&Watcher{}).UpdateTrackedItems(items, ItemChanges{URI: &newURL}); err == nil {
+		t.Fatalf("expected an error changing the url of multiple items")
+	}
+}
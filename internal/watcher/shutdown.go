@@ -0,0 +1,48 @@
+package watcher
+
+import (
+	"fmt"
+	"log/slog"
+
+	watcherHttp "github.com/DaRealFreak/watcher-go/internal/http"
+	"github.com/DaRealFreak/watcher-go/internal/models"
+)
+
+// shutdownAwareDatabase wraps the database implementation passed to the modules.
+// Every module updates the current item of the tracked item after finishing a download queue item,
+// so progress updates mark the point where the global shutdown gate stops allowing new downloads
+// for the session parsing the item. Progress updates after the gate got closed for the item are dropped,
+// so a module continuing after a refused download can never advance the current item past files
+// which were never downloaded.
+type shutdownAwareDatabase struct {
+	models.DatabaseInterface
+}
+
+// UpdateTrackedItem updates the current item of the tracked item unless the shutdown gate is already closed for it
+func (db shutdownAwareDatabase) UpdateTrackedItem(trackedItem *models.TrackedItem, currentItem string) {
+	if watcherHttp.GlobalShutdown.ItemClosed(trackedItem.ID) {
+		slog.Debug(
+			fmt.Sprintf("shutdown in progress, not updating current item of %s to %s", trackedItem.URI, currentItem),
+			"module", trackedItem.Module,
+		)
+
+		return
+	}
+
+	db.DatabaseInterface.UpdateTrackedItem(trackedItem, currentItem)
+	watcherHttp.GlobalShutdown.CompleteItem(trackedItem.ID)
+}
+
+// ChangeTrackedItemCompleteStatus changes the complete status unless the shutdown gate is already closed for it
+func (db shutdownAwareDatabase) ChangeTrackedItemCompleteStatus(trackedItem *models.TrackedItem, complete bool) {
+	if complete && watcherHttp.GlobalShutdown.ItemClosed(trackedItem.ID) {
+		slog.Debug(
+			fmt.Sprintf("shutdown in progress, not marking %s as complete", trackedItem.URI),
+			"module", trackedItem.Module,
+		)
+
+		return
+	}
+
+	db.DatabaseInterface.ChangeTrackedItemCompleteStatus(trackedItem, complete)
+}
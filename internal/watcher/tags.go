@@ -0,0 +1,119 @@
+package watcher
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/DaRealFreak/watcher-go/internal/models"
+	"github.com/DaRealFreak/watcher-go/internal/raven"
+)
+
+// filterTaggedItems returns the tracked items having at least one of the included tags (if any are passed)
+// and none of the excluded tags
+func filterTaggedItems(
+	trackedItems []*models.TrackedItem, tagMap map[int][]string, includedTags []string, excludedTags []string,
+) (filteredItems []*models.TrackedItem) {
+	for _, item := range trackedItems {
+		if len(includedTags) > 0 && !hasAnyTag(tagMap[item.ID], includedTags) {
+			continue
+		}
+
+		if hasAnyTag(tagMap[item.ID], excludedTags) {
+			continue
+		}
+
+		filteredItems = append(filteredItems, item)
+	}
+
+	return filteredItems
+}
+
+// hasAnyTag checks if any of the passed tags is contained in the item tags
+func hasAnyTag(itemTags []string, tags []string) bool {
+	for _, tag := range tags {
+		for _, itemTag := range itemTags {
+			if itemTag == models.NormalizeTag(tag) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// filterRunTags applies the tag constraints of the run configuration to the passed tracked items
+func (app *Watcher) filterRunTags(trackedItems []*models.TrackedItem) []*models.TrackedItem {
+	if len(app.Cfg.Run.Tags) == 0 && len(app.Cfg.Run.ExcludeTags) == 0 {
+		return trackedItems
+	}
+
+	return filterTaggedItems(trackedItems, app.DbCon.GetTrackedItemTagMap(), app.Cfg.Run.Tags, app.Cfg.Run.ExcludeTags)
+}
+
+// ListTags lists all tags with the amount of assigned items or the tags of the passed item
+func (app *Watcher) ListTags(uri string) {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+
+	if uri == "" {
+		_, _ = fmt.Fprintln(w, "Tag\tItems")
+
+		for _, tag := range app.DbCon.GetTags() {
+			_, _ = fmt.Fprintf(w, "%s\t%d\n", tag.Name, tag.Items)
+		}
+	} else {
+		_, _ = fmt.Fprintln(w, "ID\tUrl\tTags")
+
+		items, err := app.getTrackedItemsByURI(uri)
+		raven.CheckError(err)
+
+		for _, item := range items {
+			_, _ = fmt.Fprintf(w, "%d\t%s\t%s\n", item.ID, item.URI, strings.Join(app.DbCon.GetTrackedItemTags(item), ", "))
+		}
+	}
+
+	_ = w.Flush()
+}
+
+// AddTag assigns the passed tag to the tracked items of the passed URIs
+func (app *Watcher) AddTag(tag string, uris []string) {
+	for _, uri := range uris {
+		items, err := app.getTrackedItemsByURI(uri)
+		raven.CheckError(err)
+
+		for _, item := range items {
+			app.DbCon.AddTrackedItemTag(item, tag)
+		}
+	}
+}
+
+// RemoveTag removes the passed tag from the tracked items of the passed URIs
+func (app *Watcher) RemoveTag(tag string, uris []string) {
+	for _, uri := range uris {
+		items, err := app.getTrackedItemsByURI(uri)
+		raven.CheckError(err)
+
+		for _, item := range items {
+			app.DbCon.RemoveTrackedItemTag(item, tag)
+		}
+	}
+}
+
+// getTrackedItemsByURI returns all tracked items of the passed URI regardless of their sub folder.
+// Returns an error if the URI isn't tracked, tags are only assigned to already tracked items
+func (app *Watcher) getTrackedItemsByURI(uri string) ([]*models.TrackedItem, error) {
+	module := app.ModuleFactory.GetModuleFromURI(uri)
+	normalizedUri, err := module.ModuleInterface.AddItem(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	items := app.DbCon.GetTrackedItemsIgnoreSubFolder(normalizedUri, module)
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no tracked item found for uri \"%s\"", uri)
+	}
+
+	return items, nil
+}
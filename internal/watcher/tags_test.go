@@ -0,0 +1,37 @@
+package watcher
+
+import (
+	"testing"
+
+	"github.com/DaRealFreak/watcher-go/internal/models"
+)
+
+func TestFilterTaggedItems(t *testing.T) {
+	items := []*models.TrackedItem{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}
+	tagMap := map[int][]string{
+		1: {"nightly"},
+		2: {"nightly", "slow"},
+		3: {"weekly"},
+	}
+
+	expected := []struct {
+		included []string
+		excluded []string
+		ids      []int
+	}{
+		{nil, nil, []int{1, 2, 3, 4}},
+		{[]string{"Nightly"}, nil, []int{1, 2}},
+		{[]string{"nightly"}, []string{"slow"}, []int{1}},
+		{nil, []string{"slow", "weekly"}, []int{1, 4}},
+		{[]string{"weekly", "nightly"}, nil, []int{1, 2, 3}},
+	}
+
+	for _, test := range expected {
+		filtered := filterTaggedItems(items, tagMap, test.included, test.excluded)
+		if len(filtered) != len(test.ids) {
+			t.Fatalf("expected %d items for tags %v/%v, got %d", len(test.ids), test.included, test.excluded, len(filtered))
+		}
+
+		assertItemOrder(t, "tag filter", filtered, test.ids)
+	}
+}
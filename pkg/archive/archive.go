@@ -1,18 +1,123 @@
 // Package archive provides a reader and writer interface for the different archive types
 package archive
 
-import "io"
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
 
-// Writer is the writer interface for all valid archive types (zip, gzip, tar)
+// Writer is the writer interface for all valid archive types (zip, gzip, tar, zstd)
 type Writer interface {
 	AddFile(name string, fileContent []byte) (writtenSize int64, err error)
 	AddFileByPath(name string, filePath string) (writtenSize int64, err error)
 	Close() error
 }
 
-// Reader is the reader interface for all valid archive types (zip, gzip, tar)
+// Reader is the reader interface for all valid archive types (zip, gzip, tar, zstd)
 type Reader interface {
 	GetFiles() (files []string, err error)
 	HasFile(fileName string) (exists bool, err error)
 	GetFile(fileName string) (reader io.Reader, err error)
 }
+
+// Options are the optional settings passed to the readers and writers of the formats
+type Options struct {
+	// Password encrypts the archived files, only supported by formats with encryption
+	Password string
+}
+
+// Format is an archive format registered under its file extension
+type Format struct {
+	// Extension is the file extension of the format including the leading dot (f.e. ".tar.gz")
+	Extension string
+	// Encryption is true if the format supports password protected archives
+	Encryption bool
+	NewWriter  func(target io.Writer, options Options) (Writer, error)
+	NewReader  func(source io.Reader, options Options) (Reader, error)
+}
+
+// Name returns the name of the format, which is the extension without the leading dot (f.e. "tar.gz")
+func (f Format) Name() string {
+	return strings.TrimPrefix(f.Extension, ".")
+}
+
+// Writer returns the writer of the format for the passed target, encrypted if a password is passed
+func (f Format) Writer(target io.Writer, options Options) (Writer, error) {
+	if options.Password != "" && !f.Encryption {
+		return nil, fmt.Errorf("the %s archive format doesn't support encryption", f.Name())
+	}
+
+	return f.NewWriter(target, options)
+}
+
+// Reader returns the reader of the format for the passed source
+func (f Format) Reader(source io.Reader, options Options) (Reader, error) {
+	return f.NewReader(source, options)
+}
+
+var (
+	formats   = make(map[string]Format)
+	formatsMu sync.RWMutex
+)
+
+// RegisterFormat registers the passed format under its extension, the formats register themselves on import
+func RegisterFormat(format Format) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+
+	formats[format.Extension] = format
+}
+
+// GetFormat returns the format registered under the passed name or extension (f.e. "zip" or ".zip")
+func GetFormat(name string) (Format, error) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	format, ok := formats["."+strings.TrimPrefix(strings.ToLower(name), ".")]
+	if !ok {
+		return Format{}, fmt.Errorf(
+			"unknown archive format \"%s\", supported formats: %s", name, strings.Join(formatNames(), ", "),
+		)
+	}
+
+	return format, nil
+}
+
+// GetFormatByFileName returns the format with the longest extension matching the passed file name
+func GetFormatByFileName(fileName string) (format Format, ok bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	fileName = strings.ToLower(fileName)
+
+	for extension, registeredFormat := range formats {
+		if strings.HasSuffix(fileName, extension) && len(extension) > len(format.Extension) {
+			format, ok = registeredFormat, true
+		}
+	}
+
+	return format, ok
+}
+
+// Formats returns the sorted names of all registered formats
+func Formats() []string {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	return formatNames()
+}
+
+// formatNames returns the sorted names of all registered formats, the caller has to hold the lock
+func formatNames() []string {
+	names := make([]string, 0, len(formats))
+	for _, format := range formats {
+		names = append(names, format.Name())
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,40 @@
+package archivetest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/DaRealFreak/watcher-go/pkg/archive"
+	"github.com/stretchr/testify/assert"
+)
+
+// RoundTrip tests if the shared test files written with the writer of the passed format
+// can be retrieved again with the reader of the format
+func RoundTrip(t *testing.T, format archive.Format, options archive.Options) {
+	assertion := assert.New(t)
+	buffer := new(bytes.Buffer)
+
+	writer, err := format.Writer(buffer, options)
+	assertion.NoError(err)
+	GenerateTestFiles(t, writer)
+
+	reader, err := format.Reader(bytes.NewReader(buffer.Bytes()), options)
+	assertion.NoError(err)
+	GetFiles(t, reader)
+	GetFile(t, reader)
+}
+
+// RegisteredFormat tests if the format is registered under its extension and returns it
+func RegisteredFormat(t *testing.T, extension string) archive.Format {
+	assertion := assert.New(t)
+
+	format, err := archive.GetFormat(extension)
+	assertion.NoError(err)
+	assertion.Equal(extension, format.Extension)
+
+	byFileName, ok := archive.GetFormatByFileName("backup" + extension)
+	assertion.True(ok)
+	assertion.Equal(extension, byFileName.Extension)
+
+	return format
+}
@@ -0,0 +1,19 @@
+package gzip
+
+import (
+	"testing"
+
+	"github.com/DaRealFreak/watcher-go/pkg/archive"
+	"github.com/DaRealFreak/watcher-go/pkg/archive/archivetest"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFormatRoundTrip tests if the registered format can read the archives written by it
+func TestFormatRoundTrip(t *testing.T) {
+	format := archivetest.RegisteredFormat(t, FileExt)
+	archivetest.RoundTrip(t, format, archive.Options{})
+
+	// gzip archives can't be encrypted
+	_, err := format.Writer(nil, archive.Options{Password: "password"})
+	assert.Error(t, err)
+}
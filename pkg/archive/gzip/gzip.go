@@ -1,5 +1,23 @@
 // Package gzip contains the implementation of the gzip archive
 package gzip
 
+import (
+	"io"
+
+	"github.com/DaRealFreak/watcher-go/pkg/archive"
+)
+
 // FileExt is the file extension for gzip archives
 const FileExt = ".tar.gz"
+
+func init() {
+	archive.RegisterFormat(archive.Format{
+		Extension: FileExt,
+		NewWriter: func(target io.Writer, _ archive.Options) (archive.Writer, error) {
+			return NewWriter(target), nil
+		},
+		NewReader: func(source io.Reader, _ archive.Options) (archive.Reader, error) {
+			return NewReader(source), nil
+		},
+	})
+}
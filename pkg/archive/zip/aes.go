@@ -0,0 +1,305 @@
+package zip
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha1" // #nosec G505 -- required by the WinZip AES specification
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// The WinZip AES encryption (https://www.winzip.com/en/support/aes-encryption/) is supported by most archive
+// managers like 7-Zip. Files are marked with the compression method 99 and an extra field containing the actual
+// compression method. The content is prefixed with a random salt and a password verifier
+// and followed by an authentication code of the encrypted content
+const (
+	aesMethod  uint16 = 99
+	aesExtraID uint16 = 0x9901
+	// aesVendorVersion is AE-1, which keeps the CRC-32 of the uncompressed content
+	aesVendorVersion uint16 = 1
+	// aesStrength is the key strength used for writing, 3 is AES-256
+	aesStrength        = 3
+	aesIterations      = 1000
+	aesVerifierLength  = 2
+	aesAuthCodeLength  = 10
+	aesEncryptionFlags = 0x1
+)
+
+// aesExtraField contains the WinZip AES extra field of an encrypted file
+type aesExtraField struct {
+	vendorVersion uint16
+	strength      byte
+	method        uint16
+}
+
+// aesExtra returns the WinZip AES extra field for files with the passed actual compression method
+func aesExtra(method uint16) []byte {
+	extra := make([]byte, 11)
+	binary.LittleEndian.PutUint16(extra[0:], aesExtraID)
+	binary.LittleEndian.PutUint16(extra[2:], 7)
+	binary.LittleEndian.PutUint16(extra[4:], aesVendorVersion)
+	copy(extra[6:], "AE")
+	extra[8] = aesStrength
+	binary.LittleEndian.PutUint16(extra[9:], method)
+
+	return extra
+}
+
+// parseAESExtra returns the WinZip AES extra field from the passed extra fields of a file
+func parseAESExtra(extra []byte) (field aesExtraField, err error) {
+	for len(extra) >= 4 {
+		id := binary.LittleEndian.Uint16(extra[0:])
+		size := int(binary.LittleEndian.Uint16(extra[2:]))
+
+		if len(extra) < 4+size {
+			break
+		}
+
+		if data := extra[4 : 4+size]; id == aesExtraID && size >= 7 {
+			return aesExtraField{
+				vendorVersion: binary.LittleEndian.Uint16(data[0:]),
+				strength:      data[4],
+				method:        binary.LittleEndian.Uint16(data[5:]),
+			}, nil
+		}
+
+		extra = extra[4+size:]
+	}
+
+	return field, errors.New("missing WinZip AES extra field")
+}
+
+// aesKeyLength returns the key length of the passed key strength (1: AES-128, 2: AES-192, 3: AES-256)
+func aesKeyLength(strength byte) (int, error) {
+	if strength < 1 || strength > 3 {
+		return 0, fmt.Errorf("unsupported WinZip AES key strength %d", strength)
+	}
+
+	return 8 + int(strength)*8, nil
+}
+
+// deriveAESKeys derives the encryption key, the authentication key and the password verifier
+func deriveAESKeys(password string, salt []byte, keyLength int) (encryptionKey, authKey, verifier []byte, err error) {
+	key, err := pbkdf2.Key(sha1.New, password, salt, aesIterations, 2*keyLength+aesVerifierLength)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return key[:keyLength], key[keyLength : 2*keyLength], key[2*keyLength:], nil
+}
+
+// aesCTR is the counter mode of WinZip AES, which uses a little endian counter starting at 1
+type aesCTR struct {
+	block   cipher.Block
+	counter [aes.BlockSize]byte
+	stream  [aes.BlockSize]byte
+	used    int
+}
+
+// newAESCTR returns the counter mode stream for the passed key
+func newAESCTR(key []byte) (*aesCTR, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &aesCTR{block: block, used: aes.BlockSize}, nil
+}
+
+// XORKeyStream encrypts or decrypts the passed source into the passed destination
+func (c *aesCTR) XORKeyStream(dst, src []byte) {
+	for i := range src {
+		if c.used == aes.BlockSize {
+			for j := range c.counter {
+				c.counter[j]++
+				if c.counter[j] != 0 {
+					break
+				}
+			}
+
+			c.block.Encrypt(c.stream[:], c.counter[:])
+			c.used = 0
+		}
+
+		dst[i] = src[i] ^ c.stream[c.used]
+		c.used++
+	}
+}
+
+// aesEncrypter encrypts the compressed content and writes it to the archive
+type aesEncrypter struct {
+	target io.Writer
+	ctr    *aesCTR
+	mac    hash.Hash
+	// header contains the salt and password verifier, the zip writer creates the compressor
+	// before writing the local file header, so it can only be written with the first content
+	header []byte
+}
+
+// writeHeader writes the salt and password verifier if not written yet
+func (e *aesEncrypter) writeHeader() error {
+	if e.header == nil {
+		return nil
+	}
+
+	_, err := e.target.Write(e.header)
+	e.header = nil
+
+	return err
+}
+
+// Write encrypts the passed content and writes it to the archive
+func (e *aesEncrypter) Write(p []byte) (int, error) {
+	if err := e.writeHeader(); err != nil {
+		return 0, err
+	}
+
+	encrypted := make([]byte, len(p))
+	e.ctr.XORKeyStream(encrypted, p)
+	_, _ = e.mac.Write(encrypted)
+
+	return e.target.Write(encrypted)
+}
+
+// aesWriter deflates and encrypts the content of a file and appends the authentication code on closing
+type aesWriter struct {
+	*flate.Writer
+	encrypter *aesEncrypter
+}
+
+// newAESWriter returns the writer for the content of a new file encrypted with a random salt
+func newAESWriter(target io.Writer, password string) (io.WriteCloser, error) {
+	keyLength, err := aesKeyLength(aesStrength)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, keyLength/2)
+	if _, err = rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	encryptionKey, authKey, verifier, err := deriveAESKeys(password, salt, keyLength)
+	if err != nil {
+		return nil, err
+	}
+
+	ctr, err := newAESCTR(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := &aesWriter{encrypter: &aesEncrypter{
+		target: target,
+		ctr:    ctr,
+		mac:    hmac.New(sha1.New, authKey),
+		header: append(salt, verifier...),
+	}}
+	if writer.Writer, err = flate.NewWriter(writer.encrypter, flate.DefaultCompression); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+// Close flushes the compressed content and writes the authentication code
+func (w *aesWriter) Close() error {
+	if err := w.Writer.Close(); err != nil {
+		return err
+	}
+
+	// empty files have no compressed content, so the header may not be written yet
+	if err := w.encrypter.writeHeader(); err != nil {
+		return err
+	}
+
+	_, err := w.encrypter.target.Write(w.encrypter.mac.Sum(nil)[:aesAuthCodeLength])
+
+	return err
+}
+
+// decryptAESFile returns the decrypted and decompressed content of the passed WinZip AES encrypted file
+func decryptAESFile(file *zip.File, password string) ([]byte, error) {
+	if password == "" {
+		return nil, fmt.Errorf("file %s is encrypted, a password is required", file.Name)
+	}
+
+	field, err := parseAESExtra(file.Extra)
+	if err != nil {
+		return nil, err
+	}
+
+	keyLength, err := aesKeyLength(field.strength)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := file.OpenRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := io.ReadAll(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	saltLength := keyLength / 2
+	if len(content) < saltLength+aesVerifierLength+aesAuthCodeLength {
+		return nil, fmt.Errorf("encrypted file %s is truncated", file.Name)
+	}
+
+	salt := content[:saltLength]
+	encrypted := content[saltLength+aesVerifierLength : len(content)-aesAuthCodeLength]
+
+	encryptionKey, authKey, verifier, err := deriveAESKeys(password, salt, keyLength)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hmac.Equal(verifier, content[saltLength:saltLength+aesVerifierLength]) {
+		return nil, fmt.Errorf("invalid password for file %s", file.Name)
+	}
+
+	mac := hmac.New(sha1.New, authKey)
+	_, _ = mac.Write(encrypted)
+
+	if !hmac.Equal(mac.Sum(nil)[:aesAuthCodeLength], content[len(content)-aesAuthCodeLength:]) {
+		return nil, fmt.Errorf("authentication of file %s failed, the archive is corrupted", file.Name)
+	}
+
+	ctr, err := newAESCTR(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	decrypted := make([]byte, len(encrypted))
+	ctr.XORKeyStream(decrypted, encrypted)
+
+	switch field.method {
+	case zip.Store:
+	case zip.Deflate:
+		if decrypted, err = io.ReadAll(flate.NewReader(bytes.NewReader(decrypted))); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported compression method %d of file %s", field.method, file.Name)
+	}
+
+	// AE-2 omits the CRC-32 since the authentication code already covers the content
+	if field.vendorVersion == 1 && crc32.ChecksumIEEE(decrypted) != file.CRC32 {
+		return nil, fmt.Errorf("checksum of file %s doesn't match", file.Name)
+	}
+
+	return decrypted, nil
+}
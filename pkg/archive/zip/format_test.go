@@ -0,0 +1,58 @@
+package zip
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/DaRealFreak/watcher-go/pkg/archive"
+	"github.com/DaRealFreak/watcher-go/pkg/archive/archivetest"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFormatRoundTrip tests if the registered format can read the archives written by it
+func TestFormatRoundTrip(t *testing.T) {
+	format := archivetest.RegisteredFormat(t, FileExt)
+	archivetest.RoundTrip(t, format, archive.Options{})
+	archivetest.RoundTrip(t, format, archive.Options{Password: "password"})
+}
+
+// TestEncryptedArchive tests that the files of encrypted archives can only be read with the correct password
+func TestEncryptedArchive(t *testing.T) {
+	var assertion = assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	archivetest.GenerateTestFiles(t, NewEncryptedWriter(buffer, "password"))
+
+	// the file names are not encrypted, the content is
+	for _, password := range []string{"", "wrong password"} {
+		reader, err := NewEncryptedReader(bytes.NewReader(buffer.Bytes()), password)
+		assertion.NoError(err)
+		archivetest.GetFiles(t, reader)
+
+		_, err = reader.GetFile("README")
+		assertion.Error(err)
+	}
+
+	assertion.NotContains(buffer.String(), archivetest.GetSharedTestFiles()["README"])
+
+	// tampering with the encrypted content is detected by the authentication code
+	corrupted := bytes.Clone(buffer.Bytes())
+	corrupted[bytes.Index(corrupted, []byte("README"))+len("README")+30] ^= 0xff
+
+	reader, err := NewEncryptedReader(bytes.NewReader(corrupted), "password")
+	assertion.NoError(err)
+
+	_, err = reader.GetFile("README")
+	assertion.Error(err)
+}
+
+// TestWinZipAESCounter tests the little endian counter of the WinZip AES counter mode
+func TestWinZipAESCounter(t *testing.T) {
+	var assertion = assert.New(t)
+
+	ctr, err := newAESCTR(make([]byte, 32))
+	assertion.NoError(err)
+
+	ctr.XORKeyStream(make([]byte, 16*256+1), make([]byte, 16*256+1))
+	assertion.Equal([]byte{1, 1}, ctr.counter[:2])
+}
@@ -13,10 +13,17 @@ import (
 type zipArchiveReader struct {
 	archive.Reader
 	zipReader *zip.Reader
+	// password decrypts the WinZip AES encrypted files
+	password string
 }
 
 // NewReader initializes the reader and returns the struct
 func NewReader(f io.Reader) (archive.Reader, error) {
+	return NewEncryptedReader(f, "")
+}
+
+// NewEncryptedReader initializes the reader decrypting the encrypted files with the passed password
+func NewEncryptedReader(f io.Reader, password string) (archive.Reader, error) {
 	// we have to convert the io.Reader to an io.ReaderAt for zip files, so copy the whole thing into a new buffer
 	buff := bytes.NewBuffer([]byte{})
 	size, err := io.Copy(buff, f)
@@ -32,6 +39,7 @@ func NewReader(f io.Reader) (archive.Reader, error) {
 
 	return &zipArchiveReader{
 		zipReader: zipReader,
+		password:  password,
 	}, nil
 }
 
@@ -64,6 +72,15 @@ func (a *zipArchiveReader) HasFile(fileName string) (exists bool, err error) {
 func (a *zipArchiveReader) GetFile(fileName string) (reader io.Reader, err error) {
 	for _, f := range a.zipReader.File {
 		if f.Name == fileName {
+			if f.Method == aesMethod {
+				content, err := decryptAESFile(f, a.password)
+				if err != nil {
+					return nil, err
+				}
+
+				return bytes.NewReader(content), nil
+			}
+
 			file, err := f.Open()
 			if err != nil {
 				return nil, err
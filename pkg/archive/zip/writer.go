@@ -14,6 +14,8 @@ import (
 type zipArchiveWriter struct {
 	archive.Writer
 	zipWriter *zip.Writer
+	// encrypted files are compressed with the WinZip AES method
+	encrypted bool
 }
 
 // NewWriter initializes the writers and returns the struct
@@ -23,6 +25,30 @@ func NewWriter(target io.Writer) archive.Writer {
 	}
 }
 
+// NewEncryptedWriter initializes the writers encrypting all files with the passed password (WinZip AES-256)
+func NewEncryptedWriter(target io.Writer, password string) archive.Writer {
+	writer := &zipArchiveWriter{
+		zipWriter: zip.NewWriter(target),
+		encrypted: true,
+	}
+	writer.zipWriter.RegisterCompressor(aesMethod, func(out io.Writer) (io.WriteCloser, error) {
+		return newAESWriter(out, password)
+	})
+
+	return writer
+}
+
+// setMethod sets the compression method of the passed header, deflate for unencrypted files
+func (a *zipArchiveWriter) setMethod(header *zip.FileHeader) {
+	header.Method = zip.Deflate
+
+	if a.encrypted {
+		header.Method = aesMethod
+		header.Flags |= aesEncryptionFlags
+		header.Extra = append(header.Extra, aesExtra(zip.Deflate)...)
+	}
+}
+
 // AddFile adds a file directly from the binary data
 func (a *zipArchiveWriter) AddFile(name string, fileContent []byte) (writtenSize int64, err error) {
 	header := &zip.FileHeader{
@@ -30,7 +56,7 @@ func (a *zipArchiveWriter) AddFile(name string, fileContent []byte) (writtenSize
 		Modified:           time.Now(),
 		UncompressedSize64: uint64(len(fileContent)),
 	}
-	header.Method = zip.Deflate
+	a.setMethod(header)
 	header.SetMode(os.ModePerm)
 
 	writer, err := a.zipWriter.CreateHeader(header)
@@ -67,7 +93,7 @@ func (a *zipArchiveWriter) AddFileByPath(name string, filePath string) (writtenS
 	}
 
 	header.Name = name
-	header.Method = zip.Deflate
+	a.setMethod(header)
 
 	// retrieve the writer from the header
 	w, err := a.zipWriter.CreateHeader(header)
@@ -1,5 +1,28 @@
-// Package zip contains the implementation of the zip archive
+// Package zip contains the implementation of the zip archive, optionally encrypted with WinZip AES
 package zip
 
+import (
+	"io"
+
+	"github.com/DaRealFreak/watcher-go/pkg/archive"
+)
+
 // FileExt is the file extension for zip archives
 const FileExt = ".zip"
+
+func init() {
+	archive.RegisterFormat(archive.Format{
+		Extension:  FileExt,
+		Encryption: true,
+		NewWriter: func(target io.Writer, options archive.Options) (archive.Writer, error) {
+			if options.Password != "" {
+				return NewEncryptedWriter(target, options.Password), nil
+			}
+
+			return NewWriter(target), nil
+		},
+		NewReader: func(source io.Reader, options archive.Options) (archive.Reader, error) {
+			return NewEncryptedReader(source, options.Password)
+		},
+	})
+}
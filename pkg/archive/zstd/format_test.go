@@ -0,0 +1,13 @@
+package zstd
+
+import (
+	"testing"
+
+	"github.com/DaRealFreak/watcher-go/pkg/archive"
+	"github.com/DaRealFreak/watcher-go/pkg/archive/archivetest"
+)
+
+// TestFormatRoundTrip tests if the registered format can read the archives written by it
+func TestFormatRoundTrip(t *testing.T) {
+	archivetest.RoundTrip(t, archivetest.RegisteredFormat(t, FileExt), archive.Options{})
+}
@@ -0,0 +1,114 @@
+package zstd
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/DaRealFreak/watcher-go/pkg/archive"
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstdArchiveReader wrapper for Zstandard compressed tar archives to be used as the other archive types
+type zstdArchiveReader struct {
+	archive.Reader
+	buffer     *bytes.Buffer
+	zstdReader *zstd.Decoder
+	tarReader  *tar.Reader
+}
+
+// NewReader initializes the readers and returns the struct
+func NewReader(f io.Reader) (archive.Reader, error) {
+	buf := new(bytes.Buffer)
+	// copy the stream to the buffer
+	if _, err := io.Copy(buf, f); err != nil {
+		return nil, err
+	}
+
+	// a single decoder without background goroutines is reset for every pass over the archive
+	zstdReader, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+
+	return &zstdArchiveReader{
+		buffer:     buf,
+		zstdReader: zstdReader,
+	}, nil
+}
+
+// GetFiles returns all files in the archive
+func (a *zstdArchiveReader) GetFiles() (files []string, err error) {
+	if err := a.resetReader(); err != nil {
+		return files, err
+	}
+
+	for {
+		hdr, err := a.tarReader.Next()
+		if err == io.EOF {
+			// end of archive
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, hdr.Name)
+	}
+
+	return files, nil
+}
+
+// HasFile checks if the archive has a file with the passed file path
+func (a *zstdArchiveReader) HasFile(fileName string) (exists bool, err error) {
+	files, err := a.GetFiles()
+	if err != nil {
+		return false, err
+	}
+
+	for _, archivedFileName := range files {
+		if fileName == archivedFileName {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// GetFile returns the reader the for the passed archive file
+func (a *zstdArchiveReader) GetFile(fileName string) (reader io.Reader, err error) {
+	if err := a.resetReader(); err != nil {
+		return reader, err
+	}
+
+	for {
+		hdr, err := a.tarReader.Next()
+		if err == io.EOF {
+			// end of archive
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if hdr.Name == fileName {
+			return a.tarReader, nil
+		}
+	}
+
+	return nil, fmt.Errorf("file not found in archive")
+}
+
+// resetReader resets the decoder to the start of the saved buffer and recreates the tar reader
+func (a *zstdArchiveReader) resetReader() (err error) {
+	if err = a.zstdReader.Reset(bytes.NewReader(a.buffer.Bytes())); err != nil {
+		return err
+	}
+
+	a.tarReader = tar.NewReader(a.zstdReader)
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package zstd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/DaRealFreak/watcher-go/pkg/archive/archivetest"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAddFile tests if a file can be added to the archive without errors
+// and that the written size equals to the byte size
+func TestAddFile(t *testing.T) {
+	var assertion = assert.New(t)
+	// create a new archive
+	tmpArchiveFile, err := os.CreateTemp("", "*"+FileExt)
+	assertion.NoError(err)
+
+	archive, err := NewWriter(tmpArchiveFile)
+	assertion.NoError(err)
+	// run the test for the zstd implementation
+	archivetest.AddFile(archive, t)
+}
+
+// TestAddFile tests if a file can be added to the archive without errors
+// and that the written size equals to the byte size
+func TestAddFileByPath(t *testing.T) {
+	var assertion = assert.New(t)
+	// create a new archive
+	tmpArchiveFile, err := os.CreateTemp("", "*"+FileExt)
+	assertion.NoError(err)
+
+	archive, err := NewWriter(tmpArchiveFile)
+	assertion.NoError(err)
+	// run the test for the zstd implementation
+	archivetest.AddFileByPath(archive, t)
+}
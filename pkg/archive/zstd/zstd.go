@@ -0,0 +1,23 @@
+// Package zstd contains the implementation of the Zstandard compressed tar archive
+package zstd
+
+import (
+	"io"
+
+	"github.com/DaRealFreak/watcher-go/pkg/archive"
+)
+
+// FileExt is the file extension for Zstandard compressed tar archives
+const FileExt = ".tar.zst"
+
+func init() {
+	archive.RegisterFormat(archive.Format{
+		Extension: FileExt,
+		NewWriter: func(target io.Writer, _ archive.Options) (archive.Writer, error) {
+			return NewWriter(target)
+		},
+		NewReader: func(source io.Reader, _ archive.Options) (archive.Reader, error) {
+			return NewReader(source)
+		},
+	})
+}
@@ -0,0 +1,146 @@
+package fp
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// PathTemplate is a parsed path template like "{module}/{author}/{post_id}_{index}.{ext}".
+// Placeholders are replaced with the sanitized field values, alternatives can be separated with "|"
+// like "{author|author_id}" to use the first non-empty value. "/" separates the directories
+type PathTemplate struct {
+	template string
+	segments [][]templatePart
+}
+
+// templatePart is either a literal text or a placeholder with its alternative field names
+type templatePart struct {
+	literal string
+	fields  []string
+}
+
+// ParseTemplate parses the passed path template, only the passed field names are allowed as placeholders
+func ParseTemplate(template string, allowedFields []string) (*PathTemplate, error) {
+	allowed := make(map[string]bool, len(allowedFields))
+	for _, field := range allowedFields {
+		allowed[field] = true
+	}
+
+	if strings.TrimSpace(template) == "" {
+		return nil, fmt.Errorf("template is empty")
+	}
+
+	if strings.HasPrefix(template, "/") {
+		return nil, fmt.Errorf("template \"%s\" has to be relative", template)
+	}
+
+	parsed := &PathTemplate{template: template}
+
+	for _, segment := range strings.Split(template, "/") {
+		var parts []templatePart
+
+		for segment != "" {
+			start := strings.Index(segment, "{")
+			if start == -1 {
+				if strings.Contains(segment, "}") {
+					return nil, fmt.Errorf("unexpected \"}\" in template \"%s\"", template)
+				}
+
+				parts = append(parts, templatePart{literal: segment})
+				break
+			}
+
+			if start > 0 {
+				if strings.Contains(segment[:start], "}") {
+					return nil, fmt.Errorf("unexpected \"}\" in template \"%s\"", template)
+				}
+
+				parts = append(parts, templatePart{literal: segment[:start]})
+			}
+
+			end := strings.Index(segment[start:], "}")
+			if end == -1 {
+				return nil, fmt.Errorf("unclosed placeholder in template \"%s\"", template)
+			}
+
+			var fields []string
+			for _, field := range strings.Split(segment[start+1:start+end], "|") {
+				field = strings.ToLower(strings.TrimSpace(field))
+				if !allowed[field] {
+					return nil, fmt.Errorf(
+						"unknown field \"%s\" in template \"%s\", available fields: %s",
+						field, template, strings.Join(allowedFields, ", "),
+					)
+				}
+
+				fields = append(fields, field)
+			}
+
+			parts = append(parts, templatePart{fields: fields})
+			segment = segment[start+end+1:]
+		}
+
+		if len(parts) == 0 {
+			return nil, fmt.Errorf("template \"%s\" contains an empty directory", template)
+		}
+
+		if len(parts) == 1 && (parts[0].literal == "." || parts[0].literal == "..") {
+			return nil, fmt.Errorf("template \"%s\" contains relative directories", template)
+		}
+
+		parsed.segments = append(parsed.segments, parts)
+	}
+
+	return parsed, nil
+}
+
+// FileNameContains checks if the last segment of the template, the file name, contains any of the passed fields
+func (t *PathTemplate) FileNameContains(fields ...string) bool {
+	for _, part := range t.segments[len(t.segments)-1] {
+		for _, field := range part.fields {
+			for _, expected := range fields {
+				if field == expected {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// String returns the unparsed template
+func (t *PathTemplate) String() string {
+	return t.template
+}
+
+// Execute returns the relative path of the template with the passed field values.
+// Every directory is sanitized and truncated, empty directories are removed
+func (t *PathTemplate) Execute(values map[string]string) string {
+	var directories []string
+
+	for _, segment := range t.segments {
+		var builder strings.Builder
+
+		for _, part := range segment {
+			if part.fields == nil {
+				builder.WriteString(part.literal)
+				continue
+			}
+
+			for _, field := range part.fields {
+				if value := SanitizePath(values[field], false); value != "" {
+					builder.WriteString(value)
+					break
+				}
+			}
+		}
+
+		if directory := TruncateMaxLength(SanitizePath(builder.String(), false)); directory != "" {
+			directories = append(directories, directory)
+		}
+	}
+
+	return path.Join(directories...)
+}
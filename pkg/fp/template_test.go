@@ -0,0 +1,66 @@
+package fp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTemplate(t *testing.T) {
+	fields := []string{"module", "author", "author_id", "post_id", "index", "ext"}
+
+	for _, template := range []string{
+		"",
+		"/{module}",
+		"{module}/{unknown}",
+		"{module}/{author",
+		"{module}//{author}",
+		"{module}/../{author}",
+		"{module}/author}",
+	} {
+		_, err := ParseTemplate(template, fields)
+		assert.Error(t, err, template)
+	}
+
+	template, err := ParseTemplate("{module}/{Author|author_id}/{post_id}_{index}.{ext}", fields)
+	assert.NoError(t, err)
+	assert.Equal(t, "{module}/{Author|author_id}/{post_id}_{index}.{ext}", template.String())
+
+	// only the file name is checked for the passed fields
+	assert.True(t, template.FileNameContains("index", "name"))
+	assert.False(t, template.FileNameContains("module", "author"))
+}
+
+func TestPathTemplate_Execute(t *testing.T) {
+	template, err := ParseTemplate(
+		"{module}/{author|author_id}/{post_id}_{index}.{ext}",
+		[]string{"module", "author", "author_id", "post_id", "index", "ext"},
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "twitter.com/user/123_1.jpg", template.Execute(map[string]string{
+		"module":    "twitter.com",
+		"author":    "user",
+		"author_id": "42",
+		"post_id":   "123",
+		"index":     "1",
+		"ext":       "jpg",
+	}))
+
+	// fallbacks are used for empty values, separators and reserved characters are sanitized
+	assert.Equal(t, "twitter.com/42/a_b_1.png", template.Execute(map[string]string{
+		"module":    "twitter.com",
+		"author_id": "42",
+		"post_id":   "a/b",
+		"index":     "1",
+		"ext":       "png",
+	}))
+
+	// directories without any value are skipped instead of creating empty directories
+	assert.Equal(t, "twitter.com/1.png", template.Execute(map[string]string{
+		"module":  "twitter.com",
+		"post_id": "",
+		"index":   "1",
+		"ext":     "png",
+	}))
+}
@@ -0,0 +1,159 @@
+// Package duplication calculates perceptual hashes of pictures to check for similarity
+// between re-compressed or resized copies of the same picture
+package duplication
+
+import (
+	"image"
+	"math"
+	"math/bits"
+	"sort"
+
+	"golang.org/x/image/draw"
+
+	// imports for registering formats to image decoder
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	// more imports for registering formats to image decoder
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/webp"
+)
+
+// HashBits is the amount of bits of the perceptual hashes
+const HashBits = 64
+
+// pHashSize is the size of the scaled down picture used for the discrete cosine transform of the pHash
+const pHashSize = 32
+
+// pHashLowFrequencySize is the size of the low frequency block of the DCT used for the pHash
+const pHashLowFrequencySize = 8
+
+// ImageHash contains the perceptual hashes of a picture
+type ImageHash struct {
+	// PHash is based on the low frequencies of the discrete cosine transform and is robust against compression
+	PHash uint64
+	// DHash is based on the brightness gradient between neighbouring pixels
+	DHash uint64
+}
+
+// HammingDistance returns the amount of differing bits of the passed hashes
+func HammingDistance(hash1 uint64, hash2 uint64) int {
+	return bits.OnesCount64(hash1 ^ hash2)
+}
+
+// Distance returns the higher hamming distance of the pHash and the dHash of the passed image hashes
+func (h ImageHash) Distance(other ImageHash) int {
+	return max(HammingDistance(h.PHash, other.PHash), HammingDistance(h.DHash, other.DHash))
+}
+
+// Similarity returns the similarity of the passed image hashes in numerical percentage, the higher, the more similar
+func (h ImageHash) Similarity(other ImageHash) float64 {
+	return 1 - float64(h.Distance(other))/HashBits
+}
+
+// HashImage calculates the perceptual hashes of the passed picture
+func HashImage(img image.Image) ImageHash {
+	return ImageHash{
+		PHash: PHash(img),
+		DHash: DHash(img),
+	}
+}
+
+// PHash calculates the DCT based perceptual hash of the passed picture.
+// Every bit represents if the coefficient of the 8x8 low frequency block is above the median of the block
+func PHash(img image.Image) uint64 {
+	pixels := grayscale(img, pHashSize, pHashSize)
+	coefficients := dct2D(pixels, pHashSize)
+
+	lowFrequencies := make([]float64, 0, pHashLowFrequencySize*pHashLowFrequencySize)
+	for y := 0; y < pHashLowFrequencySize; y++ {
+		lowFrequencies = append(lowFrequencies, coefficients[y*pHashSize:y*pHashSize+pHashLowFrequencySize]...)
+	}
+
+	// the DC coefficient only represents the average brightness and is excluded from the median
+	sorted := append([]float64{}, lowFrequencies[1:]...)
+	sort.Float64s(sorted)
+	median := sorted[len(sorted)/2]
+
+	var hash uint64
+	for i, coefficient := range lowFrequencies {
+		if coefficient > median {
+			hash |= 1 << uint(i)
+		}
+	}
+
+	return hash
+}
+
+// DHash calculates the difference hash of the passed picture.
+// Every bit represents if a pixel of the 9x8 scaled down picture is brighter than its right neighbour
+func DHash(img image.Image) uint64 {
+	const width, height = 9, 8
+
+	pixels := grayscale(img, width, height)
+
+	var hash uint64
+	for y := 0; y < height; y++ {
+		for x := 0; x < width-1; x++ {
+			if pixels[y*width+x] > pixels[y*width+x+1] {
+				hash |= 1 << uint(y*(width-1)+x)
+			}
+		}
+	}
+
+	return hash
+}
+
+// grayscale scales the passed picture to the passed size ignoring the aspect ratio
+// and returns the luminance of the pixels row by row
+func grayscale(img image.Image, width int, height int) []float64 {
+	scaled := image.NewGray(image.Rect(0, 0, width, height))
+	draw.ApproxBiLinear.Scale(scaled, scaled.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	pixels := make([]float64, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			pixels[y*width+x] = float64(scaled.GrayAt(x, y).Y)
+		}
+	}
+
+	return pixels
+}
+
+// dct2D returns the two-dimensional type-II discrete cosine transform of the passed size x size pixels
+func dct2D(pixels []float64, size int) []float64 {
+	cosines := make([]float64, size*size)
+	for k := 0; k < size; k++ {
+		for n := 0; n < size; n++ {
+			cosines[k*size+n] = math.Cos(math.Pi / float64(size) * (float64(n) + 0.5) * float64(k))
+		}
+	}
+
+	// the DCT is separable, so the rows are transformed first and the columns of the result afterwards
+	rows := make([]float64, size*size)
+	for y := 0; y < size; y++ {
+		for k := 0; k < size; k++ {
+			var sum float64
+			for n := 0; n < size; n++ {
+				sum += pixels[y*size+n] * cosines[k*size+n]
+			}
+
+			rows[y*size+k] = sum
+		}
+	}
+
+	coefficients := make([]float64, size*size)
+	for x := 0; x < size; x++ {
+		for k := 0; k < size; k++ {
+			var sum float64
+			for n := 0; n < size; n++ {
+				sum += rows[n*size+x] * cosines[k*size+n]
+			}
+
+			coefficients[k*size+x] = sum
+		}
+	}
+
+	return coefficients
+}
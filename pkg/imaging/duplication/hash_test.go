@@ -0,0 +1,123 @@
+package duplication
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/draw"
+)
+
+// createTestImage creates a picture with a diagonal gradient and a bright rectangle
+func createTestImage(width int, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			value := uint8((x*255/width + y*255/height) / 2)
+			if x > width/4 && x < width/2 && y > height/3 && y < height*2/3 {
+				value = 255
+			}
+
+			img.Set(x, y, color.RGBA{R: value, G: value / 2, B: 255 - value, A: 255})
+		}
+	}
+
+	return img
+}
+
+// createOtherTestImage creates a picture with a checkerboard pattern
+func createOtherTestImage(width int, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			value := uint8(0)
+			if (x/(width/5)+y/(height/3))%2 == 0 {
+				value = 255
+			}
+
+			img.Set(x, y, color.RGBA{R: value, G: value, B: value, A: 255})
+		}
+	}
+
+	return img
+}
+
+func TestHashImage(t *testing.T) {
+	original := createTestImage(800, 600)
+
+	// re-compressed and resized copy of the original picture
+	scaled := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), original, original.Bounds(), draw.Src, nil)
+
+	compressed := new(bytes.Buffer)
+	assert.NoError(t, jpeg.Encode(compressed, scaled, &jpeg.Options{Quality: 40}))
+
+	copyHash, err := HashReader(compressed)
+	assert.NoError(t, err)
+
+	originalHash := HashImage(original)
+	otherHash := HashImage(createOtherTestImage(800, 600))
+
+	assert.Equal(t, 0, originalHash.Distance(originalHash))
+	assert.LessOrEqual(t, originalHash.Distance(copyHash), 4)
+	assert.GreaterOrEqual(t, originalHash.Similarity(copyHash), 0.95)
+	assert.Greater(t, originalHash.Distance(otherHash), 16)
+}
+
+func TestCheckForSimilarity(t *testing.T) {
+	directory := t.TempDir()
+
+	writePNG := func(name string, img image.Image) string {
+		path := filepath.Join(directory, name)
+		content := new(bytes.Buffer)
+		assert.NoError(t, png.Encode(content, img))
+		assert.NoError(t, os.WriteFile(path, content.Bytes(), 0644))
+
+		return path
+	}
+
+	original := writePNG("original.png", createTestImage(640, 480))
+	duplicate := writePNG("duplicate.png", createTestImage(640, 480))
+	other := writePNG("other.png", createOtherTestImage(640, 480))
+
+	similarity, err := CheckForSimilarity(original, duplicate)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, similarity)
+
+	similarity, err = CheckForSimilarity(original, other)
+	assert.NoError(t, err)
+	assert.Less(t, similarity, 0.95)
+
+	_, err = CheckForSimilarity(original, filepath.Join(directory, "missing.png"))
+	assert.Error(t, err)
+}
+
+func TestHashTree(t *testing.T) {
+	tree := new(HashTree)
+	tree.Add(ImageHash{PHash: 0b0000, DHash: 0b0000}, "a")
+	tree.Add(ImageHash{PHash: 0b0001, DHash: 0b0000}, "b")
+	tree.Add(ImageHash{PHash: 0b0111, DHash: 0b0001}, "c")
+	tree.Add(ImageHash{PHash: 0b0000, DHash: 0b0000}, "d")
+	tree.Add(ImageHash{PHash: ^uint64(0), DHash: ^uint64(0)}, "e")
+
+	assert.Equal(t, 5, tree.Len())
+
+	values := func(matches []HashMatch) (result []string) {
+		for _, match := range matches {
+			result = append(result, match.Value)
+		}
+
+		return result
+	}
+
+	assert.ElementsMatch(t, []string{"a", "d"}, values(tree.Search(ImageHash{}, 0)))
+	assert.ElementsMatch(t, []string{"a", "b", "d"}, values(tree.Search(ImageHash{}, 1)))
+	assert.ElementsMatch(t, []string{"a", "b", "c", "d"}, values(tree.Search(ImageHash{}, 3)))
+	assert.ElementsMatch(t, []string{"e"}, values(tree.Search(ImageHash{PHash: ^uint64(0), DHash: ^uint64(0)}, 10)))
+}
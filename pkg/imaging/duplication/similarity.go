@@ -0,0 +1,75 @@
+package duplication
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/DaRealFreak/watcher-go/internal/raven"
+)
+
+// CheckForSimilarity returns the similarity of the pictures at the passed URLs or local paths
+// in numerical percentage, the higher, the more similar
+func CheckForSimilarity(file1 string, file2 string) (similarity float64, err error) {
+	hash1, err := HashSource(file1)
+	if err != nil {
+		return 0, err
+	}
+
+	hash2, err := HashSource(file2)
+	if err != nil {
+		return 0, err
+	}
+
+	return hash1.Similarity(hash2), nil
+}
+
+// HashSource calculates the perceptual hashes of the picture at the passed URL or local path
+func HashSource(source string) (hash ImageHash, err error) {
+	r, err := getFileResourceReader(source)
+	if err != nil {
+		return hash, err
+	}
+
+	defer raven.CheckClosureNonFatal(r)
+
+	return HashReader(r)
+}
+
+// HashReader decodes the picture of the passed reader and calculates its perceptual hashes
+func HashReader(r io.Reader) (hash ImageHash, err error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return hash, err
+	}
+
+	return HashImage(img), nil
+}
+
+// getFileResourceReader returns a reader of the file resource of either passed URL or local path
+func getFileResourceReader(source string) (r io.ReadCloser, err error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == "http" || u.Scheme == "https" {
+		var resp *http.Response
+		resp, err = http.Get(source)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			raven.CheckClosureNonFatal(resp.Body)
+			return nil, fmt.Errorf("unexpected status code %d for %s", resp.StatusCode, source)
+		}
+
+		return resp.Body, nil
+	}
+
+	return os.Open(source)
+}
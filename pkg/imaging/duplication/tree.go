@@ -0,0 +1,88 @@
+package duplication
+
+// HashTree is a BK-tree of image hashes, allowing to search for similar pictures
+// without comparing the hash against every indexed picture
+type HashTree struct {
+	root *hashTreeNode
+	size int
+}
+
+// hashTreeNode is a node of the BK-tree, the children are keyed by their distance to the node
+type hashTreeNode struct {
+	hash     ImageHash
+	values   []string
+	children map[int]*hashTreeNode
+}
+
+// HashMatch is a search result of the hash tree
+type HashMatch struct {
+	Hash     ImageHash
+	Value    string
+	Distance int
+}
+
+// Len returns the amount of values added to the hash tree
+func (t *HashTree) Len() int {
+	return t.size
+}
+
+// Add adds the passed value with the passed image hash to the tree
+func (t *HashTree) Add(hash ImageHash, value string) {
+	t.size++
+
+	if t.root == nil {
+		t.root = &hashTreeNode{hash: hash, values: []string{value}, children: make(map[int]*hashTreeNode)}
+		return
+	}
+
+	node := t.root
+	for {
+		distance := node.hash.Distance(hash)
+		if distance == 0 {
+			node.values = append(node.values, value)
+			return
+		}
+
+		child, ok := node.children[distance]
+		if !ok {
+			node.children[distance] = &hashTreeNode{
+				hash:     hash,
+				values:   []string{value},
+				children: make(map[int]*hashTreeNode),
+			}
+
+			return
+		}
+
+		node = child
+	}
+}
+
+// Search returns all values with an image hash within the passed maximum distance of the passed image hash
+func (t *HashTree) Search(hash ImageHash, maxDistance int) (matches []HashMatch) {
+	if t.root == nil {
+		return nil
+	}
+
+	nodes := []*hashTreeNode{t.root}
+	for len(nodes) > 0 {
+		node := nodes[len(nodes)-1]
+		nodes = nodes[:len(nodes)-1]
+
+		distance := node.hash.Distance(hash)
+		if distance <= maxDistance {
+			for _, value := range node.values {
+				matches = append(matches, HashMatch{Hash: node.hash, Value: value, Distance: distance})
+			}
+		}
+
+		// due to the triangle inequality only children within the distance range can contain matches
+		for childDistance, child := range node.children {
+			if childDistance >= distance-maxDistance && childDistance <= distance+maxDistance {
+				nodes = append(nodes, child)
+			}
+		}
+	}
+
+	return matches
+}